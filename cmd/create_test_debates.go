@@ -10,6 +10,7 @@ import (
 	"github.com/neo/convinceme_backend/internal/agent"
 	"github.com/neo/convinceme_backend/internal/database"
 	"github.com/neo/convinceme_backend/internal/server"
+	"github.com/neo/convinceme_backend/internal/types"
 )
 
 func main() {
@@ -47,12 +48,12 @@ func main() {
 	}
 
 	// Create agents with OpenAI API key
-	agent1, err := agent.NewAgent(openAIKey, agent1Config)
+	agent1, err := agent.NewAgent(openAIKey, agent1Config, types.APIEndpoints{})
 	if err != nil {
 		log.Fatalf("Failed to create agent1: %v", err)
 	}
 
-	agent2, err := agent.NewAgent(openAIKey, agent2Config)
+	agent2, err := agent.NewAgent(openAIKey, agent2Config, types.APIEndpoints{})
 	if err != nil {
 		log.Fatalf("Failed to create agent2: %v", err)
 	}
@@ -4,6 +4,9 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/neo/convinceme_backend/internal/agent"
@@ -14,8 +17,71 @@ import (
 	// "github.com/neo/convinceme_backend/internal/player" // Removed unused import
 	"github.com/neo/convinceme_backend/internal/server"
 	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
 )
 
+// envDuration reads a duration such as "90m" from the environment, returning 0 (use the default) if it's unset or invalid
+func envDuration(name string) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		logging.Warn("Ignoring invalid duration environment variable", map[string]interface{}{
+			"name":  name,
+			"value": value,
+		})
+		return 0
+	}
+	return d
+}
+
+// envFloat reads a float from the environment, returning 0 (use the default) if it's unset or invalid
+func envFloat(name string) float64 {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 {
+		logging.Warn("Ignoring invalid numeric environment variable", map[string]interface{}{
+			"name":  name,
+			"value": value,
+		})
+		return 0
+	}
+	return f
+}
+
+// envList reads a comma-separated list from the environment, returning nil (use the default) if it's unset
+func envList(name string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// envInt reads a non-negative integer from the environment, returning 0 (use the default) if it's unset or invalid
+func envInt(name string) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil || i < 0 {
+		logging.Warn("Ignoring invalid numeric environment variable", map[string]interface{}{
+			"name":  name,
+			"value": value,
+		})
+		return 0
+	}
+	return i
+}
+
 func main() {
 	// Initialize the comprehensive logging system
 	logLevel := logging.INFO
@@ -23,12 +89,19 @@ func main() {
 		logLevel = logging.DEBUG
 	}
 
+	// LOG_FORMAT=json switches to one JSON object per line for log aggregation
+	logFormat := logging.FormatText
+	if os.Getenv("LOG_FORMAT") == string(logging.FormatJSON) {
+		logFormat = logging.FormatJSON
+	}
+
 	logConfig := logging.Config{
 		Level:       logLevel,
 		Prefix:      "ConvinceMe",
-		Colored:     true,
+		Colored:     logFormat == logging.FormatText,
 		LogToFile:   true,
 		LogFilePath: "logs/app.log",
+		Format:      logFormat,
 	}
 
 	if err := logging.InitDefaultLogger(logConfig); err != nil {
@@ -36,7 +109,7 @@ func main() {
 	}
 
 	logging.Info("Starting ConvinceMe Backend", map[string]interface{}{
-		"version": "1.0.0",
+		"version": server.Version,
 		"env":     os.Getenv("ENV"),
 	})
 
@@ -46,14 +119,20 @@ func main() {
 	}
 	logging.Info("Environment variables loaded successfully")
 
+	// Offline mode runs debates with stub agents and scorer, so no API keys are needed
+	offlineMode := os.Getenv("OFFLINE_MODE") == "true"
+	if offlineMode {
+		logging.Warn("Offline mode: agents give canned responses without audio and arguments get placeholder scores")
+	}
+
 	// Get both API keys
 	openAIKey := os.Getenv("OPENAI_API_KEY")
-	if openAIKey == "" {
+	if openAIKey == "" && !offlineMode {
 		logging.Fatal("OPENAI_API_KEY is not set in the environment variables")
 	}
 
 	elevenLabsKey := os.Getenv("ELEVENLABS_API_KEY")
-	if elevenLabsKey == "" {
+	if elevenLabsKey == "" && !offlineMode {
 		logging.Fatal("ELEVENLABS_API_KEY is not set in the environment variables")
 	}
 
@@ -64,6 +143,20 @@ func main() {
 		"provider": ttsProvider,
 	})
 
+	// Base URLs for routing API calls through a gateway or proxy; a typo would only surface on the first request
+	apiEndpoints := types.APIEndpoints{
+		OpenAIBaseURL:      os.Getenv("OPENAI_BASE_URL"),
+		OpenAIOrganization: os.Getenv("OPENAI_ORGANIZATION"),
+		ElevenLabsBaseURL:  os.Getenv("ELEVENLABS_BASE_URL"),
+	}
+	if err := apiEndpoints.Validate(); err != nil {
+		logging.Fatal("Invalid API endpoint configuration", map[string]interface{}{"error": err})
+	}
+	logging.Info("API endpoints", map[string]interface{}{
+		"openai":     apiEndpoints.OpenAI(),
+		"elevenlabs": apiEndpoints.ElevenLabs(),
+	})
+
 	// Check if HTTPS should be used
 	useHTTPS := os.Getenv("USE_HTTPS") == "true"
 	logging.Info("Server Configuration", map[string]interface{}{
@@ -79,6 +172,29 @@ func main() {
 	logging.Info("Database initialized successfully")
 	defer db.Close()
 
+	// Configure how votes blend into argument scores. A vote factor of 0 is meaningful (rank by LLM score
+	// alone), so it's only defaulted when unset.
+	scoreBlend := database.DefaultScoreBlend()
+	if formula := os.Getenv("SCORE_BLEND_FORMULA"); formula != "" {
+		scoreBlend.Formula = formula
+	}
+	if value := os.Getenv("SCORE_VOTE_FACTOR"); value != "" {
+		if scoreBlend.VoteFactor, err = strconv.ParseFloat(value, 64); err != nil {
+			logging.Fatal("Invalid SCORE_VOTE_FACTOR", map[string]interface{}{"error": err})
+		}
+	}
+	if maxVoteScore := envFloat("SCORE_MAX_VOTE_SCORE"); maxVoteScore > 0 {
+		scoreBlend.MaxVoteScore = maxVoteScore
+	}
+	if err := db.SetScoreBlend(scoreBlend); err != nil {
+		logging.Fatal("Failed to apply score blend", map[string]interface{}{"error": err})
+	}
+	logging.Info("Score blend configured", map[string]interface{}{
+		"formula":        scoreBlend.Formula,
+		"vote_factor":    scoreBlend.VoteFactor,
+		"max_vote_score": scoreBlend.MaxVoteScore,
+	})
+
 	// Ensure HLS directory exists
 	logging.Info("Setting up HLS directory...")
 	hlsDir := filepath.Join("static", "hls")
@@ -103,16 +219,22 @@ func main() {
 		"agent2": agent2Config.Name,
 	})
 
-	// Create agents with OpenAI API key
+	// Create agents with OpenAI API key, or stubs in offline mode
 	logging.Info("Creating AI agents...")
-	agent1, err := agent.NewAgent(openAIKey, agent1Config)
-	if err != nil {
-		logging.Fatal("Failed to create agent1", map[string]interface{}{"error": err, "agent": agent1Config.Name})
-	}
+	var agent1, agent2 *agent.Agent
+	if offlineMode {
+		agent1 = agent.NewOfflineAgent(agent1Config)
+		agent2 = agent.NewOfflineAgent(agent2Config)
+	} else {
+		agent1, err = agent.NewAgent(openAIKey, agent1Config, apiEndpoints)
+		if err != nil {
+			logging.Fatal("Failed to create agent1", map[string]interface{}{"error": err, "agent": agent1Config.Name})
+		}
 
-	agent2, err := agent.NewAgent(openAIKey, agent2Config)
-	if err != nil {
-		logging.Fatal("Failed to create agent2", map[string]interface{}{"error": err, "agent": agent2Config.Name})
+		agent2, err = agent.NewAgent(openAIKey, agent2Config, apiEndpoints)
+		if err != nil {
+			logging.Fatal("Failed to create agent2", map[string]interface{}{"error": err, "agent": agent2Config.Name})
+		}
 	}
 	logging.Info("AI agents created successfully")
 
@@ -173,15 +295,71 @@ func main() {
 		"invitation_required":         requireInvitation,
 	})
 
+	// Optional turn pacing: slow debates down off-peak and while nobody is watching
+	var turnPacing *server.PacingSchedule
+	pacingWindows, err := server.ParsePacingWindows(os.Getenv("TURN_PACING"))
+	if err != nil {
+		logging.Fatal("Invalid TURN_PACING", map[string]interface{}{"error": err})
+	}
+	idleMultiplier := envFloat("TURN_PACING_IDLE_MULTIPLIER")
+	if len(pacingWindows) > 0 || idleMultiplier > 0 {
+		turnPacing = &server.PacingSchedule{Windows: pacingWindows, IdleMultiplier: idleMultiplier}
+		if tz := os.Getenv("TURN_PACING_TIMEZONE"); tz != "" {
+			turnPacing.Location, err = time.LoadLocation(tz)
+			if err != nil {
+				logging.Fatal("Invalid TURN_PACING_TIMEZONE", map[string]interface{}{"error": err})
+			}
+		}
+		logging.Info("Turn pacing enabled", map[string]interface{}{
+			"windows":         len(pacingWindows),
+			"idle_multiplier": idleMultiplier,
+		})
+	}
+
 	// Update server config to include both API keys
 	serverConfig := &server.Config{
-		Port:                     ":8081",
-		OpenAIKey:                openAIKey,
-		ElevenLabsKey:            elevenLabsKey, // Use ElevenLabs key
-		ResponseDelay:            500,
-		JWTSecret:                jwtSecret,
-		RequireEmailVerification: requireEmailVerification,
-		RequireInvitation:        requireInvitation,
+		Port:                      ":8081",
+		OpenAIKey:                 openAIKey,
+		ElevenLabsKey:             elevenLabsKey, // Use ElevenLabs key
+		OpenAIBaseURL:             apiEndpoints.OpenAIBaseURL,
+		OpenAIOrganization:        apiEndpoints.OpenAIOrganization,
+		ElevenLabsBaseURL:         apiEndpoints.ElevenLabsBaseURL,
+		ResponseDelay:             500,
+		JWTSecret:                 jwtSecret,
+		RequireEmailVerification:  requireEmailVerification,
+		RequireInvitation:         requireInvitation,
+		DefaultAgent1:             os.Getenv("DEFAULT_AGENT1"),
+		DefaultAgent2:             os.Getenv("DEFAULT_AGENT2"),
+		AudioCacheTTL:             envDuration("AUDIO_CACHE_TTL"),
+		MaxConcurrentDebates:      envInt("MAX_CONCURRENT_DEBATES"),
+		MaxHistoryInMemory:        envInt("MAX_HISTORY_IN_MEMORY"),
+		OneDebatePerAgent:         os.Getenv("ONE_DEBATE_PER_AGENT") == "true",
+		DuplicateSubmissionWindow: envDuration("DUPLICATE_SUBMISSION_WINDOW"),
+		OfflineMode:               offlineMode,
+		TurnPacing:                turnPacing,
+		TTSFailureThreshold:       envInt("TTS_FAILURE_THRESHOLD"),
+		TTSFailureWindow:          envDuration("TTS_FAILURE_WINDOW"),
+		TTSBreakerCooldown:        envDuration("TTS_BREAKER_COOLDOWN"),
+		LLMOutageThreshold:        envInt("LLM_OUTAGE_THRESHOLD"),
+		LLMProbeInterval:          envDuration("LLM_PROBE_INTERVAL"),
+		AllowedAudioTypes:         envList("ALLOWED_AUDIO_TYPES"),
+		TrustedProxies:            envList("TRUSTED_PROXIES"),
+		UsageRates: usage.Rates{
+			PromptPerMillionTokens:     envFloat("COST_PROMPT_PER_MILLION_TOKENS"),
+			CompletionPerMillionTokens: envFloat("COST_COMPLETION_PER_MILLION_TOKENS"),
+			TTSPerThousandCharacters:   envFloat("COST_TTS_PER_THOUSAND_CHARS"),
+		},
+		UsageModel: os.Getenv("COST_MODEL"),
+	}
+
+	// A bad quick-debate pairing would only surface when someone presses the button, so check it up front
+	if err := serverConfig.ValidateDefaultAgents(agents); err != nil {
+		logging.Fatal("Invalid default agent pairing", map[string]interface{}{"error": err})
+	}
+
+	// Debate budgets are enforced with the estimated costs, so don't estimate them at another model's prices
+	if err := serverConfig.ValidateUsageRates(agents); err != nil {
+		logging.Fatal("Invalid usage prices", map[string]interface{}{"error": err})
 	}
 
 	// Create and start the server
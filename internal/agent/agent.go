@@ -7,26 +7,92 @@ import (
 	"log"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/neo/convinceme_backend/internal/audio"
 	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/openai"
 )
 
+// Defaults for the model settings used when neither the agent nor the debate sets them
+const (
+	DefaultModel       = "gpt-4o-mini"
+	DefaultMaxTokens   = 150
+	DefaultTemperature = 0.7
+)
+
 // AgentConfig holds configuration for an agent
 type AgentConfig struct {
 	Name                string
 	Role                string
+	Description         string // Short player-facing bio shown on agent cards
+	AvatarURL           string // Image shown on agent cards, empty for none
 	SystemPrompt        string
 	DebatePosition      string
 	ExpertiseArea       string
 	KeyArguments        []string
 	Voice               types.Voice
-	Temperature         float32
-	MaxCompletionTokens int
+	Model               string  // OpenAI model for this agent's responses, empty to use DefaultModel
+	MaxTokens           int     // Response token cap, 0 to use the debate's or DefaultMaxTokens
+	Temperature         float32 // Sampling temperature, 0 to use the debate's or DefaultTemperature
+	MaxCompletionTokens int     // Deprecated: older name for MaxTokens, used if MaxTokens is unset
 	TopP                float32
+	Speed               float64 // Default speech rate multiplier, 0 for normal
+	Emotion             string  // Default delivery emotion, empty for neutral
+}
+
+// GenerationSettings are the model settings for a single response.
+// Zero values mean "not set" and fall through to the next source.
+type GenerationSettings struct {
+	Model       string
+	MaxTokens   int
+	Temperature float32
+}
+
+// generationSettings resolves the settings for a response: the agent's own config first,
+// then the debate-level fallback, then the package defaults
+func (a *Agent) generationSettings(fallback GenerationSettings) GenerationSettings {
+	settings := GenerationSettings{
+		Model:       a.config.Model,
+		MaxTokens:   a.config.MaxTokens,
+		Temperature: a.config.Temperature,
+	}
+	if settings.MaxTokens <= 0 {
+		settings.MaxTokens = a.config.MaxCompletionTokens
+	}
+
+	if settings.Model == "" {
+		settings.Model = fallback.Model
+	}
+	if settings.MaxTokens <= 0 {
+		settings.MaxTokens = fallback.MaxTokens
+	}
+	if settings.Temperature <= 0 {
+		settings.Temperature = fallback.Temperature
+	}
+
+	if settings.Model == "" {
+		settings.Model = DefaultModel
+	}
+	if settings.MaxTokens <= 0 {
+		settings.MaxTokens = DefaultMaxTokens
+	}
+	if settings.Temperature <= 0 {
+		settings.Temperature = DefaultTemperature
+	}
+	return settings
+}
+
+// callOptions converts the settings into langchaingo call options
+func (s GenerationSettings) callOptions() []llms.CallOption {
+	return []llms.CallOption{
+		llms.WithModel(s.Model),
+		llms.WithMaxTokens(s.MaxTokens),
+		llms.WithTemperature(float64(s.Temperature)),
+	}
 }
 
 // MemoryEntry represents a single memory entry with context
@@ -41,24 +107,55 @@ type MemoryEntry struct {
 	} `json:"context"`
 }
 
-// Agent represents an AI agent that can engage in conversation
+// maxMemoryEntries caps how many responses an agent remembers per memory scope
+const maxMemoryEntries = 50
+
+// Agent represents an AI agent that can engage in conversation.
+// One Agent is shared by every debate it takes part in, so its methods are safe for concurrent use:
+// the config, LLM client and TTS service are never modified after creation, and memory is kept per scope.
 type Agent struct {
 	config AgentConfig
 	llm    llms.LLM
-	memory []MemoryEntry
 	tts    *audio.TTSService
+
+	memoryMu sync.Mutex
+	memory   map[string][]MemoryEntry // Keyed by the memory scope of the context each response was generated in
+}
+
+// memoryScopeKey is the context key for an agent's memory scope
+type memoryScopeKey struct{}
+
+// WithMemoryScope returns a context whose responses an agent remembers apart from those generated in other
+// scopes, e.g. so a debate's prompts never carry context from another debate the same agent is in
+func WithMemoryScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, memoryScopeKey{}, scope)
+}
+
+// memoryScope returns the memory scope of ctx, empty if it has none
+func memoryScope(ctx context.Context) string {
+	scope, _ := ctx.Value(memoryScopeKey{}).(string)
+	return scope
 }
 
-// NewAgent creates a new AI agent with the specified configuration
-func NewAgent(openAIKey string, config AgentConfig) (*Agent, error) {
+// NewAgent creates a new AI agent with the specified configuration, whose LLM and TTS requests go to endpoints
+func NewAgent(openAIKey string, config AgentConfig, endpoints types.APIEndpoints) (*Agent, error) {
 	if !config.Voice.IsValid() {
 		config.Voice = types.VoiceMark // fallback to alloy if invalid
 	}
 
+	model := config.Model
+	if model == "" {
+		model = DefaultModel
+	}
+
 	// Configure OpenAI client options
 	opts := []openai.Option{
 		openai.WithToken(openAIKey),
-		openai.WithModel("gpt-4o-mini"),
+		openai.WithModel(model),
+		openai.WithBaseURL(endpoints.OpenAI()),
+	}
+	if endpoints.OpenAIOrganization != "" {
+		opts = append(opts, openai.WithOrganization(endpoints.OpenAIOrganization))
 	}
 
 	// Create LLM client with configuration
@@ -68,7 +165,7 @@ func NewAgent(openAIKey string, config AgentConfig) (*Agent, error) {
 	}
 
 	// Create TTS service - API keys are loaded from environment variables
-	tts, err := audio.NewTTSService(config.Voice.String())
+	tts, err := audio.NewTTSService(config.Voice.String(), endpoints)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create TTS service: %v", err)
 	}
@@ -76,15 +173,19 @@ func NewAgent(openAIKey string, config AgentConfig) (*Agent, error) {
 	return &Agent{
 		config: config,
 		llm:    llm,
-		memory: make([]MemoryEntry, 0),
+		memory: make(map[string][]MemoryEntry),
 		tts:    tts,
 	}, nil
 }
 
-// GenerateResponse generates a response based on the conversation history and topic
-func (a *Agent) GenerateResponse(ctx context.Context, topic string, previousMessage string) (string, error) {
-	// Create context from recent memory
-	recentContext := a.buildContextFromMemory(5) // Get context from last 5 interactions
+// GenerateResponse generates a response based on the conversation history and topic.
+// The agent's own model settings take precedence over the debate-level fallback.
+func (a *Agent) GenerateResponse(ctx context.Context, topic string, previousMessage string, fallback GenerationSettings) (string, error) {
+	settings := a.generationSettings(fallback)
+
+	// Create context from recent memory, only from the same scope
+	scope := memoryScope(ctx)
+	recentContext := a.buildContextFromMemory(scope, 5) // Get context from last 5 interactions
 
 	prompt := fmt.Sprintf(`You are %s with the role of %s. 
 Recent conversation context: %s
@@ -108,16 +209,20 @@ WHEN RESPONDING:
 
 `,
 		a.config.Name, a.config.Role, recentContext, topic, previousMessage,
-		a.config.Temperature, getCreativityLevel(a.config.Temperature))
+		settings.Temperature, getCreativityLevel(settings.Temperature))
 
-	completion, err := a.llm.Call(ctx, prompt)
+	completion, err := a.llm.Call(ctx, prompt, settings.callOptions()...)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate response: %v", err)
 	}
+	usage.Record(ctx, usage.LLMCall(prompt, completion))
 
 	// Analyze response for context
 	emotionPrompt := fmt.Sprintf("Analyze this response and return one word describing the emotional tone: %s", completion)
-	emotion, _ := a.llm.Call(ctx, emotionPrompt)
+	emotion, err := a.llm.Call(ctx, emotionPrompt, llms.WithModel(settings.Model))
+	if err == nil {
+		usage.Record(ctx, usage.LLMCall(emotionPrompt, emotion))
+	}
 
 	// Create memory entry
 	entry := MemoryEntry{
@@ -130,7 +235,7 @@ WHEN RESPONDING:
 	entry.Context.Importance = 1.0 // Can be adjusted based on content analysis
 
 	// Store in memory
-	a.memory = append(a.memory, entry)
+	a.remember(scope, entry)
 
 	// Log the generated response
 	log.Printf("Generated response by %s: %s", a.config.Name, completion)
@@ -138,19 +243,68 @@ WHEN RESPONDING:
 	return completion, nil
 }
 
-// buildContextFromMemory creates a context summary from recent memory entries
-func (a *Agent) buildContextFromMemory(n int) string {
-	if len(a.memory) == 0 {
+// summaryPromptPrefix starts SummarizeDebate's prompt
+const summaryPromptPrefix = "Summarize this debate so far"
+
+// SummarizeDebate condenses a debate transcript, folded into any earlier summary, into a short running summary
+// that later prompts can carry instead of the full history. It doesn't touch the agent's memory.
+func (a *Agent) SummarizeDebate(ctx context.Context, topic, previousSummary, transcript string, fallback GenerationSettings) (string, error) {
+	settings := a.generationSettings(fallback)
+	if previousSummary == "" {
+		previousSummary = "(none)"
+	}
+
+	prompt := fmt.Sprintf(`%s in at most 4 sentences, neutrally and without taking sides.
+Keep each side's main arguments, any concessions and any points left unanswered.
+%s%s
+Earlier summary: %s
+New messages:
+%s`, summaryPromptPrefix, topicPromptPrefix, topic, previousSummary, transcript)
+
+	summary, err := a.llm.Call(ctx, prompt, settings.callOptions()...)
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize debate: %v", err)
+	}
+	usage.Record(ctx, usage.LLMCall(prompt, summary))
+	return strings.TrimSpace(summary), nil
+}
+
+// remember adds a response to the memory of a scope, dropping the oldest entries beyond maxMemoryEntries
+func (a *Agent) remember(scope string, entry MemoryEntry) {
+	a.memoryMu.Lock()
+	defer a.memoryMu.Unlock()
+
+	if a.memory == nil {
+		a.memory = make(map[string][]MemoryEntry)
+	}
+	entries := append(a.memory[scope], entry)
+	if len(entries) > maxMemoryEntries {
+		entries = append([]MemoryEntry(nil), entries[len(entries)-maxMemoryEntries:]...)
+	}
+	a.memory[scope] = entries
+}
+
+// ForgetMemory drops everything the agent remembers from a scope, e.g. once its debate is over
+func (a *Agent) ForgetMemory(scope string) {
+	a.memoryMu.Lock()
+	defer a.memoryMu.Unlock()
+	delete(a.memory, scope)
+}
+
+// buildContextFromMemory creates a context summary from a scope's recent memory entries
+func (a *Agent) buildContextFromMemory(scope string, n int) string {
+	memory := a.GetMemory(scope)
+	if len(memory) == 0 {
 		return "No previous context"
 	}
 
-	start := len(a.memory) - n
+	start := len(memory) - n
 	if start < 0 {
 		start = 0
 	}
 
 	var context string
-	for _, entry := range a.memory[start:] {
+	for _, entry := range memory[start:] {
 		context += fmt.Sprintf("- %s (Emotion: %s, Topics: %v)\n",
 			entry.Message, entry.Context.Emotion, entry.Context.Topics)
 	}
@@ -178,21 +332,93 @@ func (a *Agent) GetRole() string {
 	return a.config.Role
 }
 
-// GetMemory returns the agent's conversation memory
-func (a *Agent) GetMemory() []MemoryEntry {
-	return a.memory
+// GetModel returns the LLM model the agent's config sets, or DefaultModel
+func (a *Agent) GetModel() string {
+	if a.config.Model == "" {
+		return DefaultModel
+	}
+	return a.config.Model
+}
+
+// AgentProfile is the public, player-facing description of an agent. It never includes the system prompt.
+type AgentProfile struct {
+	Name           string      `json:"name"`
+	Role           string      `json:"role"`
+	Description    string      `json:"description"`
+	AvatarURL      string      `json:"avatar_url"`
+	Voice          types.Voice `json:"voice"`
+	DebatePosition string      `json:"debate_position"`
+	ExpertiseArea  string      `json:"expertise_area"`
+}
+
+// GetProfile returns the agent's public profile
+func (a *Agent) GetProfile() AgentProfile {
+	return AgentProfile{
+		Name:           a.config.Name,
+		Role:           a.config.Role,
+		Description:    a.config.Description,
+		AvatarURL:      a.config.AvatarURL,
+		Voice:          a.config.Voice,
+		DebatePosition: a.config.DebatePosition,
+		ExpertiseArea:  a.config.ExpertiseArea,
+	}
+}
+
+// GetMemory returns a copy of the agent's conversation memory from a scope, oldest first
+func (a *Agent) GetMemory(scope string) []MemoryEntry {
+	a.memoryMu.Lock()
+	defer a.memoryMu.Unlock()
+	return append([]MemoryEntry(nil), a.memory[scope]...)
 }
 
 // GenerateAndStreamAudio generates audio from text and returns the audio data
 func (a *Agent) GenerateAndStreamAudio(ctx context.Context, text string) ([]byte, error) {
+	if a.tts == nil {
+		return nil, ErrTTSUnavailable
+	}
 	audioData, err := a.tts.GenerateAudio(ctx, text)
 	if err != nil {
 		return nil, err
 	}
+	usage.Record(ctx, usage.TTS(text))
 
 	return audioData, nil
 }
 
+// GenerateAndStreamAudioInLanguage generates audio for text spoken in the given language
+func (a *Agent) GenerateAndStreamAudioInLanguage(ctx context.Context, text string, language types.Language) ([]byte, error) {
+	if a.tts == nil {
+		return nil, ErrTTSUnavailable
+	}
+	audioData, err := a.tts.GenerateAudioInLanguage(ctx, text, language)
+	if err != nil {
+		return nil, err
+	}
+	usage.Record(ctx, usage.TTS(text))
+	return audioData, nil
+}
+
+// VoiceSettings returns the agent's configured default delivery
+func (a *Agent) VoiceSettings() audio.VoiceSettings {
+	return audio.VoiceSettings{
+		Speed:   a.config.Speed,
+		Emotion: a.config.Emotion,
+	}
+}
+
+// GenerateAudioWithSettings generates audio for text in the given language with per-message delivery settings
+func (a *Agent) GenerateAudioWithSettings(ctx context.Context, text string, language types.Language, settings audio.VoiceSettings) ([]byte, error) {
+	if a.tts == nil {
+		return nil, ErrTTSUnavailable
+	}
+	audioData, err := a.tts.GenerateAudioWithSettings(ctx, text, language, settings)
+	if err != nil {
+		return nil, err
+	}
+	usage.Record(ctx, usage.TTS(text))
+	return audioData, nil
+}
+
 // LoadAgentConfig loads an agent configuration from a JSON file
 func LoadAgentConfig(configPath string) (AgentConfig, error) {
 	data, err := os.ReadFile(configPath)
@@ -205,6 +431,24 @@ func LoadAgentConfig(configPath string) (AgentConfig, error) {
 		return AgentConfig{}, err
 	}
 
+	// Leaving the model out uses the default, but an explicitly blank one is a mistake
+	var explicit struct {
+		Model *string
+	}
+	if err := json.Unmarshal(data, &explicit); err != nil {
+		return AgentConfig{}, err
+	}
+	if explicit.Model != nil && strings.TrimSpace(*explicit.Model) == "" {
+		return AgentConfig{}, fmt.Errorf("agent config %s: model cannot be empty", configPath)
+	}
+	config.Model = strings.TrimSpace(config.Model)
+	if config.MaxTokens < 0 {
+		return AgentConfig{}, fmt.Errorf("agent config %s: maxTokens cannot be negative", configPath)
+	}
+	if config.Temperature < 0 || config.Temperature > 2 {
+		return AgentConfig{}, fmt.Errorf("agent config %s: temperature must be between 0 and 2", configPath)
+	}
+
 	return config, nil
 }
 
@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/audio"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentProfileFromConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "agent.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{
+		"name": "Pepito",
+		"role": "Messi Devotee",
+		"description": "Has never missed a Messi match.",
+		"avatarUrl": "https://cdn.example.com/pepito.png",
+		"systemPrompt": "SECRET: always argue for Messi",
+		"debatePosition": "pro-messi",
+		"expertiseArea": "Dribbling",
+		"voice": "finn"
+	}`), 0o644))
+
+	config, err := LoadAgentConfig(configPath)
+	require.NoError(t, err)
+	profile := NewOfflineAgent(config).GetProfile()
+
+	assert.Equal(t, AgentProfile{
+		Name:           "Pepito",
+		Role:           "Messi Devotee",
+		Description:    "Has never missed a Messi match.",
+		AvatarURL:      "https://cdn.example.com/pepito.png",
+		Voice:          types.VoiceFinn,
+		DebatePosition: "pro-messi",
+		ExpertiseArea:  "Dribbling",
+	}, profile)
+
+	// The system prompt stays private
+	encoded, err := json.Marshal(profile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "SECRET")
+}
+
+// Run with -race: debates running at once share the same Agent
+func TestAgentSharedByConcurrentDebates(t *testing.T) {
+	shared := NewOfflineAgent(AgentConfig{Name: "Pepito", KeyArguments: []string{"Messi won the World Cup."}})
+	const turns = 20
+
+	var wg sync.WaitGroup
+	for _, debateID := range []string{"debate-1", "debate-2"} {
+		wg.Add(1)
+		go func(debateID string) {
+			defer wg.Done()
+			ctx := WithMemoryScope(context.Background(), debateID)
+			for i := 0; i < turns; i++ {
+				_, err := shared.GenerateResponse(ctx, "Topic of "+debateID, "", GenerationSettings{})
+				assert.NoError(t, err)
+				_, err = shared.GenerateAudioWithSettings(ctx, "Hello", types.LanguageEnglish, audio.VoiceSettings{})
+				assert.ErrorIs(t, err, ErrTTSUnavailable)
+			}
+		}(debateID)
+	}
+	wg.Wait()
+
+	// Each debate only remembers its own turns
+	for debateID, other := range map[string]string{"debate-1": "debate-2", "debate-2": "debate-1"} {
+		memory := shared.GetMemory(debateID)
+		require.Len(t, memory, turns)
+		for _, entry := range memory {
+			assert.Equal(t, []string{"Topic of " + debateID}, entry.Context.Topics)
+		}
+		assert.NotContains(t, shared.buildContextFromMemory(debateID, 5), "Topic of "+other)
+	}
+
+	shared.ForgetMemory("debate-1")
+	assert.Empty(t, shared.GetMemory("debate-1"))
+	assert.Len(t, shared.GetMemory("debate-2"), turns)
+}
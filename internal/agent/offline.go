@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ErrTTSUnavailable is returned by the audio methods of an agent created without a TTS service, e.g. in offline mode
+var ErrTTSUnavailable = errors.New("text-to-speech is not available for this agent")
+
+// topicPromptPrefix marks the line of GenerateResponse's prompt that holds the topic
+const topicPromptPrefix = "Current topic of discussion: "
+
+// offlineResponses are the canned sentences an offline agent rotates through.
+// Each is formatted with the topic and one of the agent's points.
+var offlineResponses = []string{
+	"On %[1]s, I'll say it plainly: %[2]s.",
+	"You keep dodging the real question about %[1]s: %[2]s, and that settles it.",
+	"Nice try, but anyone who has looked at %[1]s knows %[2]s.",
+	"Let's get back to %[1]s: %[2]s. My opponent has no answer to that.",
+}
+
+// offlineLLM stands in for the OpenAI model in offline mode, so debates run locally without API keys or cost.
+// It answers GenerateResponse's prompts with canned sentences about the topic and its emotion prompts with a fixed tone.
+type offlineLLM struct {
+	config AgentConfig
+
+	mu   sync.Mutex
+	turn int
+}
+
+var _ llms.LLM = (*offlineLLM)(nil)
+
+// Call returns the next canned response for the topic found in the prompt
+func (l *offlineLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	if strings.HasPrefix(prompt, "Analyze this response") {
+		return "confident", nil
+	}
+	if strings.HasPrefix(prompt, summaryPromptPrefix) {
+		return offlineSummary(prompt), nil
+	}
+
+	l.mu.Lock()
+	turn := l.turn
+	l.turn++
+	l.mu.Unlock()
+
+	topic := "this topic"
+	for _, line := range strings.Split(prompt, "\n") {
+		if strings.HasPrefix(line, topicPromptPrefix) {
+			if t := strings.TrimSpace(strings.TrimPrefix(line, topicPromptPrefix)); t != "" {
+				topic = t
+			}
+			break
+		}
+	}
+
+	points := l.config.KeyArguments
+	if len(points) == 0 {
+		position := l.config.DebatePosition
+		if position == "" {
+			position = "my side"
+		}
+		points = []string{fmt.Sprintf("the evidence is firmly on %s", position)}
+	}
+
+	template := offlineResponses[turn%len(offlineResponses)]
+	return fmt.Sprintf(template, topic, strings.TrimSuffix(points[turn%len(points)], ".")), nil
+}
+
+// offlineSummary answers a SummarizeDebate prompt by listing who has spoken so far
+func offlineSummary(prompt string) string {
+	topic := "the topic"
+	var speakers []string
+	seen := make(map[string]bool)
+	inTranscript := false
+	for _, line := range strings.Split(prompt, "\n") {
+		switch {
+		case strings.HasPrefix(line, topicPromptPrefix):
+			topic = strings.TrimSpace(strings.TrimPrefix(line, topicPromptPrefix))
+		case line == "New messages:":
+			inTranscript = true
+		case inTranscript:
+			if speaker, _, found := strings.Cut(line, ": "); found && !seen[speaker] {
+				seen[speaker] = true
+				speakers = append(speakers, speaker)
+			}
+		}
+	}
+	if len(speakers) == 0 {
+		return fmt.Sprintf("Nothing has been said about %s yet.", topic)
+	}
+	return fmt.Sprintf("Earlier in the debate on %s, %s made their cases.", topic, strings.Join(speakers, ", "))
+}
+
+// Generate calls Call for each prompt
+func (l *offlineLLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) {
+	generations := make([]*llms.Generation, 0, len(prompts))
+	for _, prompt := range prompts {
+		text, err := l.Call(ctx, prompt, options...)
+		if err != nil {
+			return nil, err
+		}
+		generations = append(generations, &llms.Generation{Text: text})
+	}
+	return generations, nil
+}
+
+// NewOfflineAgent creates an agent for local development that needs no API keys: its responses are canned
+// sentences about the debate topic and it has no voice, so its audio methods return ErrTTSUnavailable
+func NewOfflineAgent(config AgentConfig) *Agent {
+	if !config.Voice.IsValid() {
+		config.Voice = types.VoiceMark
+	}
+	return &Agent{
+		config: config,
+		llm:    &offlineLLM{config: config},
+		memory: make(map[string][]MemoryEntry),
+	}
+}
@@ -0,0 +1,30 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/audio"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineAgent(t *testing.T) {
+	agent := NewOfflineAgent(AgentConfig{Name: "Pepito", KeyArguments: []string{"Messi won the World Cup."}})
+	ctx := context.Background()
+
+	first, err := agent.GenerateResponse(ctx, "Who's the GOAT?", "", GenerationSettings{})
+	require.NoError(t, err)
+	assert.Contains(t, first, "Who's the GOAT?")
+	assert.Contains(t, first, "Messi won the World Cup")
+
+	// Responses rotate so the debate doesn't repeat itself every turn
+	second, err := agent.GenerateResponse(ctx, "Who's the GOAT?", first, GenerationSettings{})
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.Len(t, agent.GetMemory(""), 2)
+
+	_, err = agent.GenerateAudioWithSettings(ctx, first, types.LanguageEnglish, audio.VoiceSettings{})
+	assert.ErrorIs(t, err, ErrTTSUnavailable)
+}
@@ -2,13 +2,16 @@ package audio
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/neo/convinceme_backend/internal/types"
 	"github.com/sashabaranov/go-openai"
 )
 
@@ -22,29 +25,122 @@ func NewSTTService(apiKey string) *STTService {
 	}
 }
 
+// Transcription is the result of speech recognition
+type Transcription struct {
+	Text     string
+	Language types.Language // ISO 639-1 code of the spoken language, empty if it isn't one debates support
+	Duration float64        // Length of the audio in seconds, as detected by the STT model
+}
+
+// sttLanguages maps the language names Whisper reports, e.g. "english", to the codes of the supported languages
+var sttLanguages = map[string]types.Language{
+	"english":    types.LanguageEnglish,
+	"spanish":    types.LanguageSpanish,
+	"portuguese": types.LanguagePortuguese,
+	"french":     types.LanguageFrench,
+	"german":     types.LanguageGerman,
+	"italian":    types.LanguageItalian,
+}
+
+// sttLanguage converts the language reported by Whisper to its ISO 639-1 code, or empty if it isn't supported
+func sttLanguage(reported string) types.Language {
+	reported = strings.ToLower(strings.TrimSpace(reported))
+	if language := types.Language(reported); language.IsValid() {
+		return language
+	}
+	return sttLanguages[reported]
+}
+
+// Content types accepted for STT uploads besides audio/*: browsers label some recordings as video, and Ogg
+// and formats the sniffer doesn't know come through as generic application types
+var sttContentTypes = map[string]bool{
+	"video/webm":               true,
+	"video/mp4":                true,
+	"application/ogg":          true,
+	"application/octet-stream": true,
+}
+
+// isAudioContentType reports whether a declared or sniffed content type can be an audio recording
+func isAudioContentType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(contentType)), ";")
+	mediaType = strings.TrimSpace(mediaType)
+	return strings.HasPrefix(mediaType, "audio/") || sttContentTypes[mediaType]
+}
+
 func (s *STTService) RecognizeSpeech(ctx context.Context, audioFilePath string) (string, error) {
+	transcription, err := s.RecognizeSpeechInLanguage(ctx, audioFilePath, "")
+	if err != nil {
+		return "", err
+	}
+	return transcription.Text, nil
+}
+
+// RecognizeSpeechInLanguage transcribes audio in the given language, or auto-detects it when language is empty
+func (s *STTService) RecognizeSpeechInLanguage(ctx context.Context, audioFilePath string, language types.Language) (*Transcription, error) {
 	req := openai.AudioRequest{
 		Model:    "whisper-1",
 		FilePath: audioFilePath,
+		Language: language.String(),
+		Format:   openai.AudioResponseFormatVerboseJSON, // Includes the detected language
 	}
 
 	resp, err := s.client.CreateTranscription(ctx, req)
 	if err != nil {
-		return "", fmt.Errorf("failed to recognize speech: %v", err)
+		return nil, fmt.Errorf("failed to recognize speech: %v", err)
 	}
 
-	return resp.Text, nil
+	return &Transcription{
+		Text:     resp.Text,
+		Language: sttLanguage(resp.Language),
+		Duration: resp.Duration,
+	}, nil
 }
 
-// HandleSTT processes audio files and returns transcribed text
+// HandleSTT processes audio files and returns transcribed text and the audio's duration.
+// An optional "language" form field (ISO 639-1 code) selects the spoken language; it is auto-detected when unset.
+// The response's language is the ISO 639-1 code of the detected language, omitted if it isn't a supported one.
+// Uploads that aren't audio, by their declared or sniffed content type, are rejected with 415 before anything is
+// sent to the transcription API.
 func HandleSTT(c *gin.Context) {
-	file, _, err := c.Request.FormFile("audio")
+	file, header, err := c.Request.FormFile("audio")
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Audio file too large"})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audio file"})
 		return
 	}
 	defer file.Close()
 
+	language := types.Language(c.PostForm("language"))
+	if language != "" && !language.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported language '%s'", language)})
+		return
+	}
+
+	// Check the declared type, then sniff the content so a mislabeled text or image upload is caught too
+	sniffed := make([]byte, 512)
+	n, err := io.ReadFull(file, sniffed)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audio file"})
+		return
+	}
+	sniffedType := http.DetectContentType(sniffed[:n])
+	if declared := header.Header.Get("Content-Type"); !isAudioContentType(declared) || !isAudioContentType(sniffedType) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error":         "The upload is not an audio file",
+			"content_type":  declared,
+			"detected_type": sniffedType,
+		})
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read audio file"})
+		return
+	}
+
 	tempFile, err := os.CreateTemp("", "audio-*.wav")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create temp file"})
@@ -69,14 +165,19 @@ func HandleSTT(c *gin.Context) {
 	}
 
 	sttService := NewSTTService(apiKey)
-	text, err := sttService.RecognizeSpeech(c.Request.Context(), tempFile.Name())
+	transcription, err := sttService.RecognizeSpeechInLanguage(c.Request.Context(), tempFile.Name(), language)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"text":    text,
-	})
+	response := gin.H{
+		"success":  true,
+		"text":     transcription.Text,
+		"duration": transcription.Duration,
+	}
+	if transcription.Language != "" {
+		response["language"] = transcription.Language
+	}
+	c.JSON(http.StatusOK, response)
 }
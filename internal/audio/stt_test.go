@@ -0,0 +1,91 @@
+package audio
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSTTLanguageValidation(t *testing.T) {
+	// Set Gin to test mode
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/api/stt", HandleSTT)
+
+	// Build a multipart upload carrying an unsupported language code
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("audio", "argument.wav")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("RIFF"))
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteField("language", "xx"))
+	require.NoError(t, writer.Close())
+
+	req, err := http.NewRequest("POST", "/api/stt", &body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Unsupported language")
+}
+
+func TestHandleSTTRejectsNonAudio(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/api/stt", HandleSTT)
+
+	testCases := []struct {
+		name        string
+		contentType string
+		content     string
+	}{
+		{name: "Declared as text", contentType: "text/plain", content: "RIFF"},
+		{name: "Declared as audio but HTML", contentType: "audio/wav", content: "<html><body>not audio</body></html>"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Disposition", `form-data; name="audio"; filename="argument.wav"`)
+			header.Set("Content-Type", tc.contentType)
+			part, err := writer.CreatePart(header)
+			require.NoError(t, err)
+			_, err = part.Write([]byte(tc.content))
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			req, err := http.NewRequest("POST", "/api/stt", &body)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+		})
+	}
+}
+
+func TestSTTLanguage(t *testing.T) {
+	assert.Equal(t, types.LanguageEnglish, sttLanguage("english"))
+	assert.Equal(t, types.LanguagePortuguese, sttLanguage("Portuguese"))
+	assert.Equal(t, types.LanguageFrench, sttLanguage("fr"), "codes are passed through")
+	assert.Empty(t, sttLanguage("japanese"), "languages debates don't support have no code")
+	assert.Empty(t, sttLanguage(""))
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/neo/convinceme_backend/internal/logging"
+	"github.com/neo/convinceme_backend/internal/types"
 )
 
 // TTSProvider represents the text-to-speech provider
@@ -26,9 +27,10 @@ const (
 
 // TTSService handles text-to-speech conversion
 type TTSService struct {
-	apiKey   string
-	voice    string
-	provider TTSProvider
+	apiKey    string
+	voice     string
+	provider  TTSProvider
+	endpoints types.APIEndpoints
 }
 
 // ElevenLabsRequest represents the request body for ElevenLabs API
@@ -43,6 +45,80 @@ type ElevenLabsRequest struct {
 type ElevenLabsVoiceConfig struct {
 	Stability       float32 `json:"stability"`
 	SimilarityBoost float32 `json:"similarity_boost"`
+	Style           float32 `json:"style,omitempty"` // Style exaggeration, 0 for neutral
+	Speed           float32 `json:"speed,omitempty"` // Speech rate, 1.0 for normal
+}
+
+// Emotions supported for agent delivery
+const (
+	EmotionNeutral  = "neutral"
+	EmotionCalm     = "calm"
+	EmotionExcited  = "excited"
+	EmotionAgitated = "agitated"
+)
+
+// IsValidEmotion reports whether emotion is a known delivery emotion
+func IsValidEmotion(emotion string) bool {
+	switch emotion {
+	case EmotionNeutral, EmotionCalm, EmotionExcited, EmotionAgitated:
+		return true
+	}
+	return false
+}
+
+// VoiceSettings controls how generated speech is delivered. Zero values mean neutral delivery.
+type VoiceSettings struct {
+	Speed   float64 `json:"speed"`   // Speech rate multiplier, 1.0 for normal
+	Emotion string  `json:"emotion"` // One of the Emotion constants
+}
+
+// Resolve fills in neutral defaults and clamps the speed to a range both providers accept
+func (v VoiceSettings) Resolve() VoiceSettings {
+	if v.Speed <= 0 {
+		v.Speed = 1.0
+	}
+	if v.Speed < 0.7 {
+		v.Speed = 0.7
+	}
+	if v.Speed > 1.2 {
+		v.Speed = 1.2
+	}
+	if !IsValidEmotion(v.Emotion) {
+		v.Emotion = EmotionNeutral
+	}
+	return v
+}
+
+// Override returns v with any non-zero fields of override applied on top
+func (v VoiceSettings) Override(override VoiceSettings) VoiceSettings {
+	if override.Speed > 0 {
+		v.Speed = override.Speed
+	}
+	if override.Emotion != "" {
+		v.Emotion = override.Emotion
+	}
+	return v
+}
+
+// elevenLabsVoiceConfig maps resolved voice settings onto ElevenLabs stability and style
+func (v VoiceSettings) elevenLabsVoiceConfig() *ElevenLabsVoiceConfig {
+	config := &ElevenLabsVoiceConfig{
+		Stability:       0.5,
+		SimilarityBoost: 0.75,
+		Speed:           float32(v.Speed),
+	}
+	switch v.Emotion {
+	case EmotionCalm:
+		config.Stability = 0.7
+		config.Style = 0.1
+	case EmotionExcited:
+		config.Stability = 0.35
+		config.Style = 0.45
+	case EmotionAgitated:
+		config.Stability = 0.3
+		config.Style = 0.6
+	}
+	return config
 }
 
 // Voice IDs for ElevenLabs
@@ -51,8 +127,8 @@ const (
 	VoiceFinnID = "vBKc2FfBKJfcZNyEt1n6" // Finn's voice ID
 )
 
-// NewTTSService creates a new TTS service instance
-func NewTTSService(voice string) (*TTSService, error) {
+// NewTTSService creates a new TTS service instance that sends its requests to the given endpoints
+func NewTTSService(voice string, endpoints types.APIEndpoints) (*TTSService, error) {
 	// Determine provider from environment variable
 	provider := ProviderElevenLabs
 
@@ -75,12 +151,26 @@ func NewTTSService(voice string) (*TTSService, error) {
 	}
 
 	return &TTSService{
-		apiKey:   apiKey,
-		voice:    voice,
-		provider: provider,
+		apiKey:    apiKey,
+		voice:     voice,
+		provider:  provider,
+		endpoints: endpoints,
 	}, nil
 }
 
+// getVoiceIDForLanguage returns the ElevenLabs voice ID to use for a language.
+// A native voice can be configured per language with ELEVENLABS_VOICE_<VOICE>_<LANG>, e.g. ELEVENLABS_VOICE_MARK_ES;
+// otherwise the default voice is used, which the multilingual model can speak in any supported language.
+func (s *TTSService) getVoiceIDForLanguage(voice string, language types.Language) string {
+	if language != "" && language != types.LanguageEnglish {
+		envKey := fmt.Sprintf("ELEVENLABS_VOICE_%s_%s", strings.ToUpper(strings.TrimSpace(voice)), strings.ToUpper(language.String()))
+		if id := os.Getenv(envKey); id != "" {
+			return id
+		}
+	}
+	return s.getVoiceID(voice)
+}
+
 // getVoiceID maps voice names to ElevenLabs voice IDs
 func (s *TTSService) getVoiceID(voice string) string {
 
@@ -101,6 +191,18 @@ func (s *TTSService) getVoiceID(voice string) string {
 
 // GenerateAudio generates audio from text using the configured provider
 func (s *TTSService) GenerateAudio(ctx context.Context, text string) ([]byte, error) {
+	return s.GenerateAudioInLanguage(ctx, text, types.LanguageEnglish)
+}
+
+// GenerateAudioInLanguage generates audio for text in the given language, using a matching voice when one is configured.
+// Only ElevenLabs is told the language; OpenAI's speech API has no language parameter and infers it from the text.
+func (s *TTSService) GenerateAudioInLanguage(ctx context.Context, text string, language types.Language) ([]byte, error) {
+	return s.GenerateAudioWithSettings(ctx, text, language, VoiceSettings{})
+}
+
+// GenerateAudioWithSettings generates audio for text in the given language with the given delivery settings
+func (s *TTSService) GenerateAudioWithSettings(ctx context.Context, text string, language types.Language, settings VoiceSettings) ([]byte, error) {
+	settings = settings.Resolve()
 	textPreview := text
 	if len(text) > 50 {
 		textPreview = text[:50]
@@ -108,6 +210,9 @@ func (s *TTSService) GenerateAudio(ctx context.Context, text string) ([]byte, er
 
 	logging.LogTTSEvent("audio_generation_start", s.voice, map[string]interface{}{
 		"provider":     string(s.provider),
+		"language":     language.String(),
+		"speed":        settings.Speed,
+		"emotion":      settings.Emotion,
 		"text_length":  len(text),
 		"text_preview": textPreview,
 	})
@@ -117,9 +222,9 @@ func (s *TTSService) GenerateAudio(ctx context.Context, text string) ([]byte, er
 
 	switch s.provider {
 	case ProviderOpenAI:
-		audioData, err = s.generateAudioOpenAI(ctx, text)
+		audioData, err = s.generateAudioOpenAI(ctx, text, settings)
 	case ProviderElevenLabs:
-		audioData, err = s.generateAudioElevenLabs(ctx, text)
+		audioData, err = s.generateAudioElevenLabs(ctx, text, language, settings)
 	default:
 		err = fmt.Errorf("unsupported TTS provider: %s", s.provider)
 	}
@@ -141,8 +246,10 @@ func (s *TTSService) GenerateAudio(ctx context.Context, text string) ([]byte, er
 }
 
 // generateAudioOpenAI converts text to speech using OpenAI's TTS API
-func (s *TTSService) generateAudioOpenAI(ctx context.Context, text string) ([]byte, error) {
-	url := "https://api.openai.com/v1/audio/speech"
+// OpenAI's tts-1 model has no emotion control, so only the speed is applied. It has no language parameter
+// either: it speaks whatever language the text is in, with the same voice for every language.
+func (s *TTSService) generateAudioOpenAI(ctx context.Context, text string, settings VoiceSettings) ([]byte, error) {
+	url := s.endpoints.OpenAI() + "/audio/speech"
 
 	voice := "fable"
 	if s.voice == "mark" {
@@ -154,6 +261,7 @@ func (s *TTSService) generateAudioOpenAI(ctx context.Context, text string) ([]by
 		"input":           text,
 		"voice":           voice,
 		"response_format": "mp3",
+		"speed":           settings.Speed,
 	}
 
 	jsonBody, err := json.Marshal(requestBody)
@@ -168,6 +276,9 @@ func (s *TTSService) generateAudioOpenAI(ctx context.Context, text string) ([]by
 
 	req.Header.Set("Authorization", "Bearer "+s.apiKey)
 	req.Header.Set("Content-Type", "application/json")
+	if s.endpoints.OpenAIOrganization != "" {
+		req.Header.Set("OpenAI-Organization", s.endpoints.OpenAIOrganization)
+	}
 
 	client := &http.Client{}
 	resp, err := client.Do(req)
@@ -192,19 +303,16 @@ func (s *TTSService) generateAudioOpenAI(ctx context.Context, text string) ([]by
 }
 
 // generateAudioElevenLabs generates audio from text using ElevenLabs
-func (s *TTSService) generateAudioElevenLabs(ctx context.Context, text string) ([]byte, error) {
+func (s *TTSService) generateAudioElevenLabs(ctx context.Context, text string, language types.Language, settings VoiceSettings) ([]byte, error) {
 	// Preprocess text to fix pronunciation
 	text = s.preprocessTextForPronunciation(text)
 
-	url := fmt.Sprintf("https://api.elevenlabs.io/v1/text-to-speech/%s", s.getVoiceID(s.voice))
+	url := fmt.Sprintf("%s/text-to-speech/%s", s.endpoints.ElevenLabs(), s.getVoiceIDForLanguage(s.voice, language))
 
 	requestBody := ElevenLabsRequest{
-		Text:    text,
-		ModelID: "eleven_multilingual_v2",
-		Settings: &ElevenLabsVoiceConfig{
-			Stability:       0.5,
-			SimilarityBoost: 0.75,
-		},
+		Text:     text,
+		ModelID:  "eleven_multilingual_v2",
+		Settings: settings.elevenLabsVoiceConfig(),
 	}
 
 	jsonBody, err := json.Marshal(requestBody)
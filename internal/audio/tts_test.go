@@ -0,0 +1,87 @@
+package audio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoiceSettingsResolve(t *testing.T) {
+	testCases := []struct {
+		name     string
+		settings VoiceSettings
+		expected VoiceSettings
+	}{
+		{
+			name:     "Unset settings are neutral",
+			settings: VoiceSettings{},
+			expected: VoiceSettings{Speed: 1.0, Emotion: EmotionNeutral},
+		},
+		{
+			name:     "Speed is clamped",
+			settings: VoiceSettings{Speed: 3.0, Emotion: EmotionAgitated},
+			expected: VoiceSettings{Speed: 1.2, Emotion: EmotionAgitated},
+		},
+		{
+			name:     "Unknown emotion falls back to neutral",
+			settings: VoiceSettings{Speed: 0.9, Emotion: "furious"},
+			expected: VoiceSettings{Speed: 0.9, Emotion: EmotionNeutral},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.settings.Resolve())
+		})
+	}
+}
+
+func TestVoiceSettingsOverride(t *testing.T) {
+	base := VoiceSettings{Speed: 0.9, Emotion: EmotionCalm}
+
+	// Only the fields set on the override replace the agent's defaults
+	assert.Equal(t, VoiceSettings{Speed: 0.9, Emotion: EmotionExcited}, base.Override(VoiceSettings{Emotion: EmotionExcited}))
+	assert.Equal(t, VoiceSettings{Speed: 1.1, Emotion: EmotionCalm}, base.Override(VoiceSettings{Speed: 1.1}))
+}
+
+func TestTTSServiceCustomEndpoints(t *testing.T) {
+	var requests []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Write([]byte("audio"))
+	}))
+	defer srv.Close()
+
+	endpoints := types.APIEndpoints{
+		OpenAIBaseURL:      srv.URL + "/openai/v1/",
+		OpenAIOrganization: "org-test",
+		ElevenLabsBaseURL:  srv.URL + "/elevenlabs/v1",
+	}
+	t.Setenv("OPENAI_API_KEY", "openai-key")
+	t.Setenv("ELEVENLABS_API_KEY", "elevenlabs-key")
+
+	t.Setenv("TTS_PROVIDER", "elevenlabs")
+	tts, err := NewTTSService("mark", endpoints)
+	require.NoError(t, err)
+	audio, err := tts.GenerateAudio(context.Background(), "Hello")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("audio"), audio)
+
+	t.Setenv("TTS_PROVIDER", "openai")
+	tts, err = NewTTSService("mark", endpoints)
+	require.NoError(t, err)
+	_, err = tts.GenerateAudio(context.Background(), "Hello")
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, "/elevenlabs/v1/text-to-speech/"+VoiceMarkID, requests[0].URL.Path)
+	assert.Equal(t, "elevenlabs-key", requests[0].Header.Get("xi-api-key"))
+	assert.Equal(t, "/openai/v1/audio/speech", requests[1].URL.Path)
+	assert.Equal(t, "Bearer openai-key", requests[1].Header.Get("Authorization"))
+	assert.Equal(t, "org-test", requests[1].Header.Get("OpenAI-Organization"))
+}
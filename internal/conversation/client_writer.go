@@ -0,0 +1,159 @@
+package conversation
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/neo/convinceme_backend/internal/logging"
+)
+
+// How much a client may lag behind its debate before it's dropped as a slow consumer
+const (
+	clientSendBuffer   = 256              // Messages queued for a client's writer
+	clientWriteTimeout = 10 * time.Second // Longest a single write may block a client's writer
+)
+
+// ErrClientClosed is returned when sending to a client that has left the debate or was dropped
+var ErrClientClosed = errors.New("client connection is closed")
+
+// errClientSlow is returned when a client's queue is full because it isn't reading its messages
+var errClientSlow = errors.New("client is not keeping up with the debate")
+
+// droppedSlowClients counts clients disconnected since the server started because their queue filled up
+var droppedSlowClients atomic.Int64
+
+// DroppedSlowClients returns how many clients have been disconnected for not keeping up with their debate's
+// broadcasts since the server started
+func DroppedSlowClients() int64 {
+	return droppedSlowClients.Load()
+}
+
+// clientWriter owns the writes to one client connection. Messages are queued without blocking and written in
+// order by its own goroutine, so a slow client never holds up the rest of the debate. A websocket connection
+// allows only one writer at a time, so once a client has a writer every write to it must go through the queue.
+type clientWriter struct {
+	conn      *websocket.Conn
+	send      chan []byte
+	done      chan struct{} // Closed when the writer stops
+	closeOnce sync.Once
+}
+
+// newClientWriter starts a writer for conn
+func newClientWriter(conn *websocket.Conn) *clientWriter {
+	w := &clientWriter{
+		conn: conn,
+		send: make(chan []byte, clientSendBuffer),
+		done: make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// enqueue queues an encoded message for the client without blocking
+func (w *clientWriter) enqueue(data []byte) error {
+	select {
+	case <-w.done:
+		return ErrClientClosed
+	default:
+	}
+	select {
+	case w.send <- data:
+		return nil
+	default:
+		return errClientSlow
+	}
+}
+
+// stop stops the writer, dropping any messages still queued
+func (w *clientWriter) stop() {
+	w.closeOnce.Do(func() { close(w.done) })
+}
+
+// run writes queued messages until the writer is stopped or a write fails. A failed write closes the
+// connection, which ends the client's read loop so it leaves the debate.
+func (w *clientWriter) run() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case data := <-w.send:
+			w.conn.SetWriteDeadline(time.Now().Add(clientWriteTimeout))
+			if err := w.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				logging.LogWebSocketEvent("client_write_error", "", "", map[string]interface{}{
+					"error": err,
+				})
+				w.stop()
+				w.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// Send queues a message for one client, in order with the debate's broadcasts. A client that isn't keeping up
+// is dropped from the debate. Connections that aren't in the session are written to directly.
+func (d *DebateSession) Send(conn *websocket.Conn, message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return d.sendData(conn, data)
+}
+
+// sendData queues encoded data for one client like Send
+func (d *DebateSession) sendData(conn *websocket.Conn, data []byte) error {
+	d.debateMutex.RLock()
+	writer := d.writers[conn]
+	d.debateMutex.RUnlock()
+
+	if writer == nil {
+		return conn.WriteMessage(websocket.TextMessage, data)
+	}
+	err := writer.enqueue(data)
+	if err == errClientSlow {
+		d.dropSlowClient(conn)
+	}
+	return err
+}
+
+// addWriterLocked starts the writer for a client joining the session; the caller must hold debateMutex
+func (d *DebateSession) addWriterLocked(conn *websocket.Conn) {
+	if d.writers == nil {
+		d.writers = make(map[*websocket.Conn]*clientWriter)
+	}
+	if previous := d.writers[conn]; previous != nil {
+		previous.stop()
+	}
+	d.writers[conn] = newClientWriter(conn)
+}
+
+// removeWriterLocked stops the writer of a client leaving the session; the caller must hold debateMutex
+func (d *DebateSession) removeWriterLocked(conn *websocket.Conn) {
+	if writer := d.writers[conn]; writer != nil {
+		writer.stop()
+		delete(d.writers, conn)
+	}
+}
+
+// dropSlowClient removes a client whose queue filled up and closes its connection, which ends its read loop
+func (d *DebateSession) dropSlowClient(conn *websocket.Conn) {
+	d.debateMutex.Lock()
+	playerID, removed := d.removeClientLocked(conn)
+	remaining := len(d.Clients)
+	d.debateMutex.Unlock()
+	// Another send may have dropped it first
+	if !removed {
+		return
+	}
+
+	conn.Close()
+	droppedSlowClients.Add(1)
+	logging.LogWebSocketEvent("slow_client_dropped", d.DebateID, playerID, map[string]interface{}{
+		"remaining_clients": remaining,
+		"queued_messages":   clientSendBuffer,
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -14,16 +16,206 @@ import (
 	"github.com/neo/convinceme_backend/internal/player"
 	"github.com/neo/convinceme_backend/internal/tools"
 	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
 )
 
 // DebateConfig holds configuration for the debate session
 type DebateConfig struct {
 	Topic               string
-	MaxTurns            int           // Might be less relevant if debates run until a winner or manually stopped
+	MaxTurns            int           // Agent turns before the debate is concluded on HP (or by the judge); <= 0 means unlimited
 	TurnDelay           time.Duration // Delay between agent turns
 	ResponseStyle       types.ResponseStyle
 	MaxCompletionTokens int
 	TemperatureHigh     bool
+	MaxParticipants     int            // Maximum participant connections, 0 for unlimited
+	MaxSpectators       int            // Maximum spectator connections, 0 for unlimited
+	ChatRateLimit       int            // Maximum chat messages per client per minute, 0 for unlimited
+	PersistChat         bool           // Whether chat messages are stored in the database
+	WinCondition        string         // How the winner is decided: WinConditionHP (default) or WinConditionJudge
+	HPTuning            HPTuning       // How argument scores translate into HP changes
+	CloseMatchMargin    int            // HP gap at or below which the match is reported as close
+	MomentumLookback    int            // Number of recent scored turns per agent used for momentum
+	Language            types.Language // Language the agents debate, score and speak in
+	MaxDuration         time.Duration  // How long the debate loop may run before it times out with no winner
+	MaxInactivity       time.Duration  // How long the loop may go without progress before the debate is ended as stuck
+	MaxCostUSD          float64        // Estimated LLM and TTS spend at which the debate is concluded on HP, 0 for unlimited
+	FirstSpeaker        string         // Which agent opens: FirstSpeakerAgent1 (default), FirstSpeakerAgent2 or FirstSpeakerRandom
+	TurnOrder           string         // Who speaks after the opening: TurnOrderAlternate (default), TurnOrderChallenged, TurnOrderRandom or TurnOrderComeback
+	ContextWindow       int            // History entries given to an agent as context for its turn; <= 0 means DefaultContextWindow
+	SummaryInterval     int            // Agent turns between refreshes of the running summary of older history, 0 to not summarize
+	CrowdFactor         float64        // How much crowd support scales player arguments' HP swings, 0 to ignore the crowd
+	MinArgumentLength   int            // Fewest characters in a player argument; <= 0 means DefaultMinArgumentLength
+	MaxArgumentLength   int            // Most characters in a player argument; <= 0 means DefaultMaxArgumentLength
+	MaxHistoryInMemory  int            // History entries kept in the session, older ones are trimmed; <= 0 means DefaultMaxHistoryInMemory
+	// Seeds every random choice in the debate: the first speaker coin flip and the random and comeback turn
+	// orders. 0 picks a seed when the session is created, so it can be recorded and replayed.
+	Seed int64
+
+	// Extra persona instructions keyed by agent name, appended to that agent's generated prompt
+	AgentPromptOverrides map[string]string
+
+	// Tone an agent takes as it falls behind or pulls ahead on HP, none by default
+	HPTone HPTone
+
+	// How the debate opens, before the free-form turns
+	IntroMessage      string            // Welcome broadcast when the debate starts, empty for the default naming the topic
+	IntroDelay        time.Duration     // Pause after the welcome before anyone speaks
+	OpeningStatements map[string]string // Scripted, unscored statements keyed by agent name, delivered in speaking order
+}
+
+// HPTuning controls how argument scores, averages of the 0-100 criteria scores, are converted into HP swings.
+// Both the agent-turn path and the player-intervention path use the same tuning.
+type HPTuning struct {
+	MinScoreForDamage float64 `json:"min_score_for_damage"` // Minimum average score before an argument moves HP at all
+	DamageDeadband    float64 `json:"damage_deadband"`      // Score differences against the opponent's last argument below this deal no damage
+	AgentMultiplier   float64 `json:"agent_multiplier"`     // Points per score point for agent arguments
+	PlayerMultiplier  float64 `json:"player_multiplier"`    // Points per score point for player arguments
+	MaxLoss           int     `json:"max_loss"`             // Maximum HP swing from a single argument, 0 for uncapped
+	MinLoss           int     `json:"min_loss"`             // Minimum HP swing once an argument clears the threshold
+}
+
+// UnmarshalJSON also accepts threshold, what min_score_for_damage was called before the damage deadband was
+// added, so existing clients keep working. min_score_for_damage wins when both are set.
+func (t *HPTuning) UnmarshalJSON(data []byte) error {
+	type hpTuning HPTuning // Without the method, so decoding it doesn't recurse
+	decoded := struct {
+		hpTuning
+		Threshold *float64 `json:"threshold"`
+	}{hpTuning: hpTuning(*t)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	var renamed struct {
+		MinScoreForDamage *float64 `json:"min_score_for_damage"`
+	}
+	if err := json.Unmarshal(data, &renamed); err != nil {
+		return err
+	}
+	if decoded.Threshold != nil && renamed.MinScoreForDamage == nil {
+		decoded.MinScoreForDamage = *decoded.Threshold
+	}
+	*t = HPTuning(decoded.hpTuning)
+	return nil
+}
+
+// Validate checks that the tuning values are usable
+func (t HPTuning) Validate() error {
+	if t.MinScoreForDamage < 0 || t.DamageDeadband < 0 || t.AgentMultiplier < 0 || t.PlayerMultiplier < 0 || t.MaxLoss < 0 || t.MinLoss < 0 {
+		return fmt.Errorf("hp tuning values cannot be negative")
+	}
+	if t.MaxLoss > 0 && t.MinLoss > t.MaxLoss {
+		return fmt.Errorf("hp tuning min_loss (%d) cannot exceed max_loss (%d)", t.MinLoss, t.MaxLoss)
+	}
+	return nil
+}
+
+// DefaultHPTuning returns the tuning matching the original direct-scoring behavior,
+// where an argument's truncated average score is its HP swing
+func DefaultHPTuning() HPTuning {
+	return HPTuning{
+		MinScoreForDamage: 0,
+		DamageDeadband:    0,
+		AgentMultiplier:   1,
+		PlayerMultiplier:  1,
+		MaxLoss:           0,
+		MinLoss:           0,
+	}
+}
+
+// Reasons an argument dealt no damage
+const (
+	NoDamageNoScore       = "no_score"        // The argument scored zero
+	NoDamageBelowMinScore = "below_min_score" // The argument fell short of MinScoreForDamage
+	NoDamageDeadband      = "deadband"        // The argument was within DamageDeadband of the opponent's last score
+)
+
+// AgentPoints returns the HP swing for an agent argument with the given average score.
+// baseline is the opposing agent's last score, or nil if they haven't been scored yet.
+// When no damage is dealt, the reason is one of the NoDamage constants.
+func (t HPTuning) AgentPoints(score float64, baseline *float64) (int, string) {
+	return t.points(score, baseline, t.AgentMultiplier)
+}
+
+// PlayerPoints returns the HP swing for a player argument with the given average score.
+// baseline is the opposed agent's last score, or nil if they haven't been scored yet.
+func (t HPTuning) PlayerPoints(score float64, baseline *float64) (int, string) {
+	return t.points(score, baseline, t.PlayerMultiplier)
+}
+
+// points applies the minimum score, deadband, multiplier and caps to a score
+func (t HPTuning) points(score float64, baseline *float64, multiplier float64) (int, string) {
+	if score <= 0 {
+		return 0, NoDamageNoScore
+	}
+	if score < t.MinScoreForDamage {
+		return 0, NoDamageBelowMinScore
+	}
+	if baseline != nil && math.Abs(score-*baseline) < t.DamageDeadband {
+		return 0, NoDamageDeadband
+	}
+
+	points := int(score * multiplier)
+	if points < t.MinLoss {
+		points = t.MinLoss
+	}
+	if t.MaxLoss > 0 && points > t.MaxLoss {
+		points = t.MaxLoss
+	}
+	return points, ""
+}
+
+// Win conditions for a debate
+const (
+	WinConditionHP    = "hp"    // The first agent whose HP reaches zero loses
+	WinConditionJudge = "judge" // The conviction judge picks the winner after MaxTurns agent turns
+)
+
+// IsValidWinCondition reports whether condition is a known win condition
+func IsValidWinCondition(condition string) bool {
+	return condition == WinConditionHP || condition == WinConditionJudge
+}
+
+// First speaker choices for a debate
+const (
+	FirstSpeakerAgent1 = "agent1" // Agent1 opens, the default
+	FirstSpeakerAgent2 = "agent2" // Agent2 opens
+	FirstSpeakerRandom = "random" // A coin flip picks the opening agent
+)
+
+// IsValidFirstSpeaker reports whether choice is a known first speaker choice
+func IsValidFirstSpeaker(choice string) bool {
+	return choice == FirstSpeakerAgent1 || choice == FirstSpeakerAgent2 || choice == FirstSpeakerRandom
+}
+
+// IsJudged reports whether the debate is decided by the judge rather than by HP
+func (c DebateConfig) IsJudged() bool {
+	return c.WinCondition == WinConditionJudge
+}
+
+// Client roles for debate WebSocket connections
+const (
+	ClientRoleParticipant = "participant" // Can submit scored arguments
+	ClientRoleSpectator   = "spectator"   // Read-only observer
+)
+
+// IsValidClientRole reports whether role is a known client role
+func IsValidClientRole(role string) bool {
+	return role == ClientRoleParticipant || role == ClientRoleSpectator
+}
+
+// Presence summarizes the clients connected to a debate, broken down by role
+type Presence struct {
+	Total        int `json:"total"`
+	Participants int `json:"participants"`
+	Spectators   int `json:"spectators"`
+}
+
+// TurnPace is how much the pause between agent turns is currently stretched
+type TurnPace struct {
+	Multiplier float64       `json:"multiplier"`  // Applied to TurnDelay, 1 for the normal pace
+	TurnDelay  time.Duration `json:"-"`           // The resulting pause between turns
+	QuietHours bool          `json:"quiet_hours"` // An off-peak window of the pacing schedule applies
+	NoViewers  bool          `json:"no_viewers"`  // Nobody is watching, so the idle slowdown applies
 }
 
 // DefaultConfig returns a default configuration for a debate
@@ -35,9 +227,72 @@ func DefaultConfig() DebateConfig {
 		ResponseStyle:       types.ResponseStyleDebate,
 		MaxCompletionTokens: 150,
 		TemperatureHigh:     true,
+		MaxParticipants:     50,
+		MaxSpectators:       500,
+		ChatRateLimit:       20,
+		PersistChat:         true,
+		WinCondition:        WinConditionHP,
+		HPTuning:            DefaultHPTuning(),
+		CloseMatchMargin:    10,
+		MomentumLookback:    3,
+		Language:            types.LanguageEnglish,
+		MaxDuration:         15 * time.Minute,
+		MaxInactivity:       5 * time.Minute,
+		IntroDelay:          2 * time.Second,
+		ContextWindow:       DefaultContextWindow,
+		MinArgumentLength:   DefaultMinArgumentLength,
+		MaxArgumentLength:   DefaultMaxArgumentLength,
 	}
 }
 
+// DefaultContextWindow is how many history entries an agent sees as context when the config doesn't say
+const DefaultContextWindow = 5
+
+// HistoryWindow returns how many history entries an agent sees as context for its turn
+func (c DebateConfig) HistoryWindow() int {
+	if c.ContextWindow <= 0 {
+		return DefaultContextWindow
+	}
+	return c.ContextWindow
+}
+
+// How many history entries a session keeps in memory. The transcript is persisted as it happens, so trimmed
+// entries are only dropped from the session. MinHistoryInMemory is kept however low the limit is set: it's well
+// over the largest context window, so agents, the running summary and momentum always have the entries they need.
+const (
+	DefaultMaxHistoryInMemory = 500
+	MinHistoryInMemory        = 100
+)
+
+// HistoryLimit returns how many history entries the session keeps in memory
+func (c DebateConfig) HistoryLimit() int {
+	if c.MaxHistoryInMemory <= 0 {
+		return DefaultMaxHistoryInMemory
+	}
+	if c.MaxHistoryInMemory < MinHistoryInMemory {
+		return MinHistoryInMemory
+	}
+	return c.MaxHistoryInMemory
+}
+
+// Bounds on a player argument's length in characters when the config doesn't say
+const (
+	DefaultMinArgumentLength = 10
+	DefaultMaxArgumentLength = 1000
+)
+
+// ArgumentLengthLimits returns the fewest and most characters a player argument may have
+func (c DebateConfig) ArgumentLengthLimits() (min, max int) {
+	min, max = c.MinArgumentLength, c.MaxArgumentLength
+	if min <= 0 {
+		min = DefaultMinArgumentLength
+	}
+	if max <= 0 {
+		max = DefaultMaxArgumentLength
+	}
+	return min, max
+}
+
 // DebateEntry represents a single message in the debate history
 type DebateEntry struct {
 	Speaker      string    `json:"speaker"` // Agent name or Player ID
@@ -45,6 +300,18 @@ type DebateEntry struct {
 	Time         time.Time `json:"time"`
 	IsPlayer     bool      `json:"is_player"`
 	AverageScore *float64  `json:"average_score,omitempty"` // Average score for this message (agents only)
+	// HP change this turn caused, e.g. so an agent turn can be reversed if it is regenerated
+	Agent1Delta int    `json:"agent1_delta"`
+	Agent2Delta int    `json:"agent2_delta"`
+	MessageID   int64  `json:"-"` // ID of the persisted transcript entry, 0 if it wasn't saved
+	Seq         uint64 `json:"-"` // Seq of the "message" broadcast that announced the turn
+}
+
+// ScorePoint is a single scored agent argument in a debate's history
+type ScorePoint struct {
+	Turn  int       `json:"turn"` // 1-based index of the agent's scored arguments
+	Score float64   `json:"score"`
+	Time  time.Time `json:"time"`
 }
 
 // GameScore tracks the scores within a debate session
@@ -60,16 +327,77 @@ type DebateSession struct {
 	Agent1      *agent.Agent               `json:"-"` // Exclude agents from JSON serialization
 	Agent2      *agent.Agent               `json:"-"`
 	Config      DebateConfig               `json:"config"`
-	Status      string                     `json:"status"` // e.g., "waiting", "active", "finished"
+	Status      types.DebateStatus         `json:"status"` // Changed through UpdateStatus, which rejects invalid transitions
 	Clients     map[*websocket.Conn]string `json:"-"`      // Map of client connections to Player IDs for this debate
+	ClientRoles map[*websocket.Conn]string `json:"-"`      // Map of client connections to their role (participant/spectator)
 	UserNames   map[string]string          `json:"-"`      // Map of Player IDs to display names (e.g., Twitter usernames)
+	clientSides map[*websocket.Conn]string // Agent each connected player's latest argument supported, maintained by SetClientSide
 	History     []DebateEntry              `json:"history"`
 	GameScore   GameScore                  `json:"game_score"`
 	Judge       *tools.ConvictionJudge     `json:"-"` // Optional: Judge for analysis
 	debateMutex sync.RWMutex               // Mutex for protecting session state (Clients, History, Status, GameScore)
 	// Add other necessary fields like stopChannel, lastSpeaker, etc.
 	stopChannel chan struct{}
+	stopOnce    sync.Once // Closes stopChannel at most once
 	lastSpeaker string
+	// Turn order state, maintained by NextSpeaker
+	consecutiveTurns int    // Turns in a row taken by lastSpeaker, 0 before the opening turn
+	lastChallenged   string // Agent a player argued against since the last agent turn, for TurnOrderChallenged
+	// Which agent opens, FirstSpeakerAgent1 or FirstSpeakerAgent2, with any coin flip already resolved
+	firstSpeaker string
+	// Source of the debate's randomness, seeded from Config.Seed; guarded by debateMutex, see rng
+	random *rand.Rand
+	// Queued writer of each connected client, so a slow client can't hold up broadcasts; see client_writer.go
+	writers map[*websocket.Conn]*clientWriter
+	// Active-time tracking, maintained by UpdateStatus
+	startedAt   time.Time     // When the debate first became active
+	endedAt     time.Time     // When the debate finished
+	pausedAt    time.Time     // When the current pause began, zero if not paused
+	pausedTotal time.Duration // Accumulated time spent paused
+	deadline    time.Time     // When the debate loop times out, zero until the loop starts
+	turnCount   int           // Agent turns taken so far, maintained by the debate loop
+	usage       usage.Usage   // LLM and TTS usage attributed to this debate
+	// Debate loop tracking, maintained by the server's debate loop
+	loopRunning bool     // Whether a debate loop goroutine is driving the session
+	loopCrashes int      // Times the debate loop stopped on a panic
+	turnPace    TurnPace // Current pace of the debate loop, zero until it first paces a turn
+	// Running summary of the history older than the context window, maintained by the debate loop
+	historySummary string
+	summarizedUpTo int // History entries covered by historySummary, counting trimmed ones
+	// What trimHistory dropped from History: how many entries, and how many scored turns per agent, so the
+	// summary and score history keep counting from the start of the debate
+	trimmedEntries int
+	trimmedScores  map[string]int
+	// Broadcast sequencing, so reconnecting clients can catch up on events they missed
+	broadcastMutex sync.Mutex                             // Serializes broadcasts so every client sees them in seq order
+	seq            uint64                                 // Sequence number of the last broadcast
+	recent         [broadcastBufferSize]bufferedBroadcast // Ring buffer of recent broadcasts, indexed by seq
+}
+
+// broadcastBufferSize is how many recent broadcasts each session keeps for replay to reconnecting clients
+const broadcastBufferSize = 100
+
+// bufferedBroadcast is an encoded broadcast kept for replay
+type bufferedBroadcast struct {
+	seq  uint64
+	data []byte
+}
+
+// stampSeq encodes a JSON object message with a "seq" field added
+func stampSeq(message interface{}, seq uint64) ([]byte, error) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 2 || data[0] != '{' {
+		return nil, fmt.Errorf("broadcast message must be a JSON object, got %T", message)
+	}
+
+	stamped := []byte(fmt.Sprintf(`{"seq":%d`, seq))
+	if string(data) != "{}" {
+		stamped = append(stamped, ',')
+	}
+	return append(stamped, data[1:]...), nil
 }
 
 // NewDebateSession creates a new debate session
@@ -85,37 +413,150 @@ func NewDebateSession(id string, agent1, agent2 *agent.Agent, config DebateConfi
 		// Decide if judge is critical or optional
 	}
 
-	// Initialize GameScore (starting at 100 HP each)
-	initialScore := 100 // Start with 100 HP for both agents
+	// Initialize GameScore (starting at InitialHP each)
+	initialScore := InitialHP
+
+	// Every random choice comes from the debate's seed, so with the same seed and the same agent responses
+	// and scores a debate plays out the same way
+	if config.Seed == 0 {
+		config.Seed = NewSeed()
+	}
+	random := rand.New(rand.NewSource(config.Seed))
+
+	// The opening turn alternates from the last speaker, so pretend agent1 spoke last for agent2 to open
+	firstSpeaker := config.FirstSpeaker
+	if firstSpeaker == FirstSpeakerRandom {
+		firstSpeaker = FirstSpeakerAgent1
+		if random.Intn(2) == 1 {
+			firstSpeaker = FirstSpeakerAgent2
+		}
+	}
+	var lastSpeaker string
+	if firstSpeaker == FirstSpeakerAgent2 {
+		lastSpeaker = agent1.GetName()
+	} else {
+		firstSpeaker = FirstSpeakerAgent1
+	}
 
 	return &DebateSession{
 		DebateID:    id,
 		Agent1:      agent1,
 		Agent2:      agent2,
 		Config:      config,
-		Status:      "waiting", // Initial status
+		Status:      types.DebateStatusWaiting, // Initial status
 		Clients:     make(map[*websocket.Conn]string),
+		ClientRoles: make(map[*websocket.Conn]string),
 		UserNames:   make(map[string]string),
 		History:     make([]DebateEntry, 0),
 		GameScore:   GameScore{Agent1Score: initialScore, Agent2Score: initialScore},
 		Judge:       judge,
 		stopChannel: make(chan struct{}),
+
+		lastSpeaker:  lastSpeaker,
+		firstSpeaker: firstSpeaker,
+		random:       random,
 	}, nil
 }
 
+// InitialHP is the HP each agent starts a debate with
+const InitialHP = 100
+
+// MaxSeed is the largest debate seed, so seeds survive JSON numbers in JavaScript clients intact
+const MaxSeed = 1<<53 - 1
+
+// NewSeed picks a random seed for a debate, between 1 and MaxSeed
+func NewSeed() int64 {
+	return rand.Int63n(MaxSeed) + 1
+}
+
+// rng returns the debate's random source, seeding it from Config.Seed for sessions not built by
+// NewDebateSession; caller must hold debateMutex
+func (d *DebateSession) rng() *rand.Rand {
+	if d.random == nil {
+		if d.Config.Seed == 0 {
+			d.Config.Seed = NewSeed()
+		}
+		d.random = rand.New(rand.NewSource(d.Config.Seed))
+	}
+	return d.random
+}
+
 // Start method might be removed or repurposed.
 // The core agent discussion loop will likely be managed by the server/manager
 // and triggered when the status becomes 'active'.
 // func (d *DebateSession) Start(ctx context.Context) error { ... }
 
-// AddClient adds a WebSocket client to the session
+// AddClient adds a WebSocket client to the session as a participant
 func (d *DebateSession) AddClient(conn *websocket.Conn, playerID string) {
 	d.debateMutex.Lock()
 	defer d.debateMutex.Unlock()
 	d.Clients[conn] = playerID
+	d.ClientRoles[conn] = ClientRoleParticipant
+	d.addWriterLocked(conn)
 	log.Printf("Player %s joined debate %s. Total clients: %d", playerID, d.DebateID, len(d.Clients))
 }
 
+// AddClientWithRole adds a WebSocket client with the given role, enforcing the per-role connection caps
+func (d *DebateSession) AddClientWithRole(conn *websocket.Conn, playerID, role string) error {
+	if !IsValidClientRole(role) {
+		return fmt.Errorf("invalid client role: %s", role)
+	}
+
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+
+	limit := d.Config.MaxParticipants
+	if role == ClientRoleSpectator {
+		limit = d.Config.MaxSpectators
+	}
+	if limit > 0 && d.countRoleLocked(role) >= limit {
+		return fmt.Errorf("debate %s has reached its %s limit of %d", d.DebateID, role, limit)
+	}
+
+	d.Clients[conn] = playerID
+	d.ClientRoles[conn] = role
+	d.addWriterLocked(conn)
+	log.Printf("Player %s joined debate %s as %s. Total clients: %d", playerID, d.DebateID, role, len(d.Clients))
+	return nil
+}
+
+// GetClientRole returns the role of a connected client, defaulting to participant
+func (d *DebateSession) GetClientRole(conn *websocket.Conn) string {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+	if role, exists := d.ClientRoles[conn]; exists {
+		return role
+	}
+	return ClientRoleParticipant
+}
+
+// GetPresence returns the number of connected clients broken down by role
+func (d *DebateSession) GetPresence() Presence {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+	spectators := d.countRoleLocked(ClientRoleSpectator)
+	return Presence{
+		Total:        len(d.Clients),
+		Participants: len(d.Clients) - spectators,
+		Spectators:   spectators,
+	}
+}
+
+// countRoleLocked counts clients with the given role; caller must hold debateMutex
+func (d *DebateSession) countRoleLocked(role string) int {
+	count := 0
+	for conn := range d.Clients {
+		clientRole, exists := d.ClientRoles[conn]
+		if !exists {
+			clientRole = ClientRoleParticipant
+		}
+		if clientRole == role {
+			count++
+		}
+	}
+	return count
+}
+
 // SetUserName sets the display name for a player ID
 func (d *DebateSession) SetUserName(playerID string, displayName string) {
 	d.debateMutex.Lock()
@@ -134,21 +575,160 @@ func (d *DebateSession) GetUserName(playerID string) string {
 	return playerID // Return original playerID if no display name is set
 }
 
+// HasParticipant reports whether a player is currently connected to the session as a participant
+func (d *DebateSession) HasParticipant(playerID string) bool {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+	for conn, id := range d.Clients {
+		if id == playerID && d.ClientRoles[conn] == ClientRoleParticipant {
+			return true
+		}
+	}
+	return false
+}
+
 // RemoveClient removes a WebSocket client from the session
 func (d *DebateSession) RemoveClient(conn *websocket.Conn) (playerID string, remaining int) {
 	d.debateMutex.Lock()
 	defer d.debateMutex.Unlock()
-	playerID = d.Clients[conn]
-	delete(d.Clients, conn)
+	playerID, _ = d.removeClientLocked(conn)
 	remaining = len(d.Clients)
 	log.Printf("Player %s left debate %s. Remaining clients: %d", playerID, d.DebateID, remaining)
 	return playerID, remaining
 }
 
-// Broadcast sends a message to all clients in this debate session
-func (d *DebateSession) Broadcast(message interface{}) {
+// removeClientLocked removes a client and stops its writer, reporting whether it was in the session; the
+// caller must hold debateMutex
+func (d *DebateSession) removeClientLocked(conn *websocket.Conn) (playerID string, removed bool) {
+	playerID, removed = d.Clients[conn]
+	delete(d.Clients, conn)
+	delete(d.ClientRoles, conn)
+	delete(d.clientSides, conn)
+	d.removeWriterLocked(conn)
+	return playerID, removed
+}
+
+// CrowdSupport counts the connected players backing each agent, by the side of their latest argument
+type CrowdSupport struct {
+	Agent1 int `json:"agent1"`
+	Agent2 int `json:"agent2"`
+}
+
+// SetClientSide records the agent a connected player's latest argument supported. An empty agentName, for a
+// neutral argument, stops counting the player toward either side.
+func (d *DebateSession) SetClientSide(conn *websocket.Conn, agentName string) {
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+	if agentName == "" {
+		delete(d.clientSides, conn)
+		return
+	}
+	if d.clientSides == nil {
+		d.clientSides = make(map[*websocket.Conn]string)
+	}
+	d.clientSides[conn] = agentName
+}
+
+// GetCrowdSupport tallies the connected players backing each agent
+func (d *DebateSession) GetCrowdSupport() CrowdSupport {
 	d.debateMutex.RLock()
 	defer d.debateMutex.RUnlock()
+	return d.crowdSupportLocked()
+}
+
+// crowdSupportLocked tallies the supporters; the caller must hold debateMutex
+func (d *DebateSession) crowdSupportLocked() CrowdSupport {
+	var support CrowdSupport
+	for _, agentName := range d.clientSides {
+		switch agentName {
+		case d.Agent1.GetName():
+			support.Agent1++
+		case d.Agent2.GetName():
+			support.Agent2++
+		}
+	}
+	return support
+}
+
+// CrowdAdjustedPoints scales a player argument's HP swing for supportedAgent by the crowd behind it. The swing
+// grows by up to CrowdFactor when every supporter backs that agent and shrinks by as much when none do, and
+// stays within the HP tuning's MaxLoss. With a CrowdFactor of 0 or no supporters, points are returned unchanged.
+func (d *DebateSession) CrowdAdjustedPoints(points int, supportedAgent string) int {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+
+	if d.Config.CrowdFactor <= 0 || points == 0 {
+		return points
+	}
+	support := d.crowdSupportLocked()
+	total := support.Agent1 + support.Agent2
+	if total == 0 {
+		return points
+	}
+	backing, against := support.Agent1, support.Agent2
+	if supportedAgent == d.Agent2.GetName() {
+		backing, against = against, backing
+	}
+
+	lead := float64(backing-against) / float64(total)
+	adjusted := int(math.Round(float64(points) * math.Max(0, 1+d.Config.CrowdFactor*lead)))
+	if maxLoss := d.Config.HPTuning.MaxLoss; maxLoss > 0 && adjusted > maxLoss {
+		adjusted = maxLoss
+	}
+	return adjusted
+}
+
+// Broadcast sends a message to all clients in this debate session.
+// Each message is stamped with the next "seq" number and kept for ReplaySince.
+// Messages are queued on each client's writer, so Broadcast never waits on a slow client; a client whose queue
+// is full is dropped from the debate instead.
+// It returns the message's seq, or 0 if the message couldn't be encoded.
+func (d *DebateSession) Broadcast(message interface{}) uint64 {
+	d.broadcastMutex.Lock()
+	defer d.broadcastMutex.Unlock()
+
+	data, err := stampSeq(message, d.seq+1)
+	if err != nil {
+		logging.LogWebSocketEvent("broadcast_encode_error", d.DebateID, "", map[string]interface{}{
+			"error": err,
+		})
+		return 0
+	}
+	d.seq++
+	d.recent[d.seq%broadcastBufferSize] = bufferedBroadcast{seq: d.seq, data: data}
+
+	d.fanOut(message, data)
+	return d.seq
+}
+
+// BroadcastEphemeral sends a message to all clients in this debate session like Broadcast, but without a seq
+// and without keeping it for ReplaySince, for frequent messages that aren't worth replaying to a reconnecting
+// client and would push the ones that are out of the replay buffer, e.g. reactions
+func (d *DebateSession) BroadcastEphemeral(message interface{}) {
+	d.broadcastMutex.Lock()
+	defer d.broadcastMutex.Unlock()
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		logging.LogWebSocketEvent("broadcast_encode_error", d.DebateID, "", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	d.fanOut(message, data)
+}
+
+// fanOut writes an encoded broadcast to every client, dropping those too slow to keep up.
+// The caller must hold broadcastMutex.
+func (d *DebateSession) fanOut(message interface{}, data []byte) {
+	d.debateMutex.RLock()
+	var slow []*websocket.Conn
+	defer func() {
+		d.debateMutex.RUnlock()
+		for _, conn := range slow {
+			d.dropSlowClient(conn)
+		}
+	}()
 
 	clientCount := len(d.Clients)
 
@@ -166,14 +746,22 @@ func (d *DebateSession) Broadcast(message interface{}) {
 	errorCount := 0
 
 	for client := range d.Clients {
-		// Write synchronously to avoid concurrent writes to the same connection
-		// Each WebSocket connection can only have one writer at a time
-		if err := client.WriteJSON(message); err != nil {
+		var err error
+		writer := d.writers[client]
+		if writer == nil {
+			// Clients added to the map directly have no writer and are written to synchronously
+			err = client.WriteMessage(websocket.TextMessage, data)
+		} else {
+			err = writer.enqueue(data)
+		}
+		if err != nil {
 			errorCount++
 			logging.LogWebSocketEvent("broadcast_client_error", d.DebateID, "", map[string]interface{}{
 				"error": err,
 			})
-			// Optional: Consider removing the client if write fails repeatedly
+			if err == errClientSlow {
+				slow = append(slow, client)
+			}
 		} else {
 			successCount++
 		}
@@ -185,6 +773,45 @@ func (d *DebateSession) Broadcast(message interface{}) {
 	})
 }
 
+// LastSeq returns the sequence number of the most recent broadcast, 0 if nothing has been broadcast
+func (d *DebateSession) LastSeq() uint64 {
+	d.broadcastMutex.Lock()
+	defer d.broadcastMutex.Unlock()
+	return d.seq
+}
+
+// ReplaySince resends the buffered broadcasts after lastSeq to a single client, in order.
+// complete is false if some of those broadcasts have already dropped out of the buffer,
+// in which case the client should fetch the full state instead.
+func (d *DebateSession) ReplaySince(conn *websocket.Conn, lastSeq uint64) (replayed int, complete bool, err error) {
+	d.broadcastMutex.Lock()
+	defer d.broadcastMutex.Unlock()
+
+	if lastSeq >= d.seq {
+		return 0, true, nil
+	}
+
+	from := lastSeq + 1
+	complete = true
+	if d.seq >= broadcastBufferSize && from <= d.seq-broadcastBufferSize {
+		from = d.seq - broadcastBufferSize + 1
+		complete = false
+	}
+
+	for seq := from; seq <= d.seq; seq++ {
+		entry := d.recent[seq%broadcastBufferSize]
+		if entry.seq != seq {
+			complete = false
+			continue
+		}
+		if err := d.sendData(conn, entry.data); err != nil {
+			return replayed, complete, err
+		}
+		replayed++
+	}
+	return replayed, complete, nil
+}
+
 // AddHistoryEntry adds a message to the debate history safely
 func (d *DebateSession) AddHistoryEntry(speaker string, message string, isPlayer bool) {
 	d.debateMutex.Lock()
@@ -197,7 +824,61 @@ func (d *DebateSession) AddHistoryEntry(speaker string, message string, isPlayer
 		AverageScore: nil, // Will be populated later for agent messages
 	}
 	d.History = append(d.History, entry)
-	// Optional: Limit history size if needed
+	d.trimHistory()
+}
+
+// RestoreHistory starts a new session from history carried over from a stored debate, when forking it or
+// reloading it after a restart.
+// Each entry's HP change is applied to the starting HP, scored agent entries count as turns taken, and the next
+// turn goes to the opponent of the agent that spoke last. It returns the resulting game score.
+func (d *DebateSession) RestoreHistory(entries []DebateEntry) GameScore {
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+
+	d.History = append(make([]DebateEntry, 0, len(entries)), entries...)
+	for _, entry := range entries {
+		d.GameScore.Agent1Score += entry.Agent1Delta
+		d.GameScore.Agent2Score += entry.Agent2Delta
+		if entry.IsPlayer {
+			continue
+		}
+		if entry.AverageScore != nil {
+			d.turnCount++
+		}
+		if entry.Speaker == d.lastSpeaker {
+			d.consecutiveTurns++
+		} else {
+			d.lastSpeaker, d.consecutiveTurns = entry.Speaker, 1
+		}
+	}
+	d.trimHistory()
+	return d.GameScore
+}
+
+// trimHistory drops the oldest history entries beyond the config's HistoryLimit. Entries are shifted down in
+// place, so the slice never grows past the limit. The caller must hold debateMutex.
+func (d *DebateSession) trimHistory() {
+	excess := len(d.History) - d.Config.HistoryLimit()
+	if excess <= 0 {
+		return
+	}
+
+	if d.trimmedScores == nil {
+		d.trimmedScores = make(map[string]int)
+	}
+	for _, entry := range d.History[:excess] {
+		if !entry.IsPlayer && entry.AverageScore != nil {
+			d.trimmedScores[entry.Speaker]++
+		}
+	}
+
+	kept := copy(d.History, d.History[excess:])
+	// Clear the vacated tail so the trimmed messages can be freed
+	for i := kept; i < len(d.History); i++ {
+		d.History[i] = DebateEntry{}
+	}
+	d.History = d.History[:kept]
+	d.trimmedEntries += excess
 }
 
 // UpdateLastHistoryEntryScore updates the score of the most recent history entry
@@ -211,6 +892,109 @@ func (d *DebateSession) UpdateLastHistoryEntryScore(score float64) {
 	}
 }
 
+// RecordTurn stores the outcome of the speaker's most recent agent turn or player argument on its history entry:
+// the HP it moved, where it was persisted and the seq it was broadcast with
+func (d *DebateSession) RecordTurn(speaker string, isPlayer bool, agent1Delta, agent2Delta int, messageID int64, seq uint64) {
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+
+	for i := len(d.History) - 1; i >= 0; i-- {
+		if d.History[i].IsPlayer == isPlayer && d.History[i].Speaker == speaker {
+			d.History[i].Agent1Delta = agent1Delta
+			d.History[i].Agent2Delta = agent2Delta
+			d.History[i].MessageID = messageID
+			d.History[i].Seq = seq
+			return
+		}
+	}
+}
+
+// LastAgentEntry returns the most recent agent turn in the history with its index, skipping player arguments
+func (d *DebateSession) LastAgentEntry() (DebateEntry, int, bool) {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+
+	for i := len(d.History) - 1; i >= 0; i-- {
+		if !d.History[i].IsPlayer {
+			return d.History[i], i, true
+		}
+	}
+	return DebateEntry{}, -1, false
+}
+
+// HistoryBefore returns up to n history entries preceding index, oldest first
+func (d *DebateSession) HistoryBefore(index, n int) []DebateEntry {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+
+	if index > len(d.History) {
+		index = len(d.History)
+	}
+	start := index - n
+	if start < 0 {
+		start = 0
+	}
+	historyCopy := make([]DebateEntry, index-start)
+	copy(historyCopy, d.History[start:index])
+	return historyCopy
+}
+
+// ReplaceAgentEntry swaps the agent turn at index for a regenerated one and moves HP by the difference between
+// the two turns' deltas. It fails if the entry at index is no longer previous, e.g. because history changed.
+func (d *DebateSession) ReplaceAgentEntry(index int, previous, replacement DebateEntry) (GameScore, error) {
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+
+	if index < 0 || index >= len(d.History) || d.History[index].IsPlayer || !d.History[index].Time.Equal(previous.Time) {
+		return d.GameScore, fmt.Errorf("history of debate %s changed while the turn was regenerated", d.DebateID)
+	}
+	d.History[index] = replacement
+	d.GameScore.Agent1Score += replacement.Agent1Delta - previous.Agent1Delta
+	d.GameScore.Agent2Score += replacement.Agent2Delta - previous.Agent2Delta
+	return d.GameScore, nil
+}
+
+// HistorySummary returns the running summary of the history older than the context window, empty if there is none
+func (d *DebateSession) HistorySummary() string {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+	return d.historySummary
+}
+
+// HistoryToSummarize returns the current summary and the entries that have since fallen out of a context window
+// of the given size. upTo is the number of history entries the summary covers once those are folded in, counting
+// entries trimmed from memory, so it stays right if the history is trimmed while the summary is written.
+func (d *DebateSession) HistoryToSummarize(window int) (summary string, entries []DebateEntry, upTo int) {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+
+	end := len(d.History) - window
+	upTo = d.trimmedEntries + end
+	if upTo <= d.summarizedUpTo {
+		return d.historySummary, nil, d.summarizedUpTo
+	}
+	// Entries trimmed before they were summarized are gone
+	start := d.summarizedUpTo - d.trimmedEntries
+	if start < 0 {
+		start = 0
+	}
+	entries = make([]DebateEntry, end-start)
+	copy(entries, d.History[start:end])
+	return d.historySummary, entries, upTo
+}
+
+// SetHistorySummary stores a summary covering the first upTo history entries. A summary covering less of the
+// history than the current one is ignored.
+func (d *DebateSession) SetHistorySummary(summary string, upTo int) {
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+	if upTo <= d.summarizedUpTo {
+		return
+	}
+	d.historySummary = summary
+	d.summarizedUpTo = upTo
+}
+
 // GetRecentHistory retrieves the last N entries safely
 func (d *DebateSession) GetRecentHistory(n int) []DebateEntry {
 	d.debateMutex.RLock()
@@ -225,18 +1009,225 @@ func (d *DebateSession) GetRecentHistory(n int) []DebateEntry {
 	return historyCopy
 }
 
-// UpdateStatus updates the debate status safely
-func (d *DebateSession) UpdateStatus(newStatus string) {
+// ContextHistory returns the history an agent sees verbatim for its turn: the last window entries and, when the
+// debate summarizes older history, every entry the running summary doesn't cover yet. Between refreshes entries
+// leave the window before they're summarized, and this keeps them in the prompt until they are.
+func (d *DebateSession) ContextHistory(window int) []DebateEntry {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+	start := len(d.History) - window
+	if d.Config.SummaryInterval > 0 {
+		if unsummarized := d.summarizedUpTo - d.trimmedEntries; unsummarized < start {
+			start = unsummarized
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+	historyCopy := make([]DebateEntry, len(d.History[start:]))
+	copy(historyCopy, d.History[start:])
+	return historyCopy
+}
+
+// SetDeadline records when the debate loop will time out
+func (d *DebateSession) SetDeadline(deadline time.Time) {
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+	d.deadline = deadline
+}
+
+// IncrementTurnCount records that an agent took a turn and returns the new count
+func (d *DebateSession) IncrementTurnCount() int {
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+	d.turnCount++
+	return d.turnCount
+}
+
+// GetTurnCount returns the number of agent turns taken so far
+func (d *DebateSession) GetTurnCount() int {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+	return d.turnCount
+}
+
+// FirstSpeaker returns which agent opens the debate, FirstSpeakerAgent1 or FirstSpeakerAgent2
+func (d *DebateSession) FirstSpeaker() string {
+	if d.firstSpeaker == "" {
+		return FirstSpeakerAgent1
+	}
+	return d.firstSpeaker
+}
+
+// FirstSpeakerAgent returns the agent that opens the debate
+func (d *DebateSession) FirstSpeakerAgent() *agent.Agent {
+	if d.FirstSpeaker() == FirstSpeakerAgent2 {
+		return d.Agent2
+	}
+	return d.Agent1
+}
+
+// AddUsage accumulates LLM or TTS usage for the debate, implementing usage.Recorder
+func (d *DebateSession) AddUsage(u usage.Usage) {
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+	d.usage.Add(u)
+}
+
+// GetUsage returns the LLM and TTS usage recorded for the debate so far
+func (d *DebateSession) GetUsage() usage.Usage {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+	return d.usage
+}
+
+// TryStartLoop marks the debate loop as running. It returns false if a loop is already running.
+func (d *DebateSession) TryStartLoop() bool {
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+	if d.loopRunning {
+		return false
+	}
+	d.loopRunning = true
+	return true
+}
+
+// LoopStopped records that the debate loop has exited
+func (d *DebateSession) LoopStopped() {
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+	d.loopRunning = false
+}
+
+// IsLoopRunning reports whether a debate loop is driving the session
+func (d *DebateSession) IsLoopRunning() bool {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+	return d.loopRunning
+}
+
+// RecordLoopCrash records that the debate loop stopped on a panic and returns how many times it has
+func (d *DebateSession) RecordLoopCrash() int {
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+	d.loopCrashes++
+	return d.loopCrashes
+}
+
+// SetTurnPace records the debate loop's current pace and reports whether it changed
+func (d *DebateSession) SetTurnPace(pace TurnPace) bool {
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+	changed := d.turnPace != pace
+	d.turnPace = pace
+	return changed
+}
+
+// GetTurnPace returns the debate loop's current pace, the normal pace if it hasn't been set
+func (d *DebateSession) GetTurnPace() TurnPace {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+	if d.turnPace.Multiplier == 0 {
+		return TurnPace{Multiplier: 1, TurnDelay: d.Config.TurnDelay}
+	}
+	return d.turnPace
+}
+
+// GetDeadline returns when the debate loop will time out, or the zero time if it hasn't started
+func (d *DebateSession) GetDeadline() time.Time {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+	return d.deadline
+}
+
+// UpdateStatus updates the debate status safely. Unknown statuses and transitions the lifecycle doesn't allow,
+// e.g. from finished back to active, are logged and ignored. It reports whether the debate is now in newStatus.
+func (d *DebateSession) UpdateStatus(newStatus types.DebateStatus) bool {
 	d.debateMutex.Lock()
 	defer d.debateMutex.Unlock()
-	if d.Status != newStatus {
-		log.Printf("Debate %s status changed from %s to %s", d.DebateID, d.Status, newStatus)
-		d.Status = newStatus
+	if d.Status == newStatus {
+		return true
 	}
+	// A session built without a status, e.g. in tests, may start in any valid one
+	if !newStatus.IsValid() || (d.Status != "" && !d.Status.CanTransitionTo(newStatus)) {
+		logging.Warn("Rejected invalid debate status transition", map[string]interface{}{
+			"debate_id": d.DebateID,
+			"from":      d.Status,
+			"to":        newStatus,
+		})
+		return false
+	}
+	log.Printf("Debate %s status changed from %s to %s", d.DebateID, d.Status, newStatus)
+	d.trackStatusTimeLocked(d.Status, newStatus, time.Now())
+	d.Status = newStatus
+	return true
+}
+
+// RestoreStatus sets the status of a session rebuilt from a stored debate, which starts out waiting, without
+// applying the transition rules. Unknown statuses are logged and ignored.
+func (d *DebateSession) RestoreStatus(status types.DebateStatus) {
+	if !status.IsValid() {
+		logging.Warn("Ignored unknown stored debate status", map[string]interface{}{
+			"debate_id": d.DebateID,
+			"status":    status,
+		})
+		return
+	}
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+	d.trackStatusTimeLocked(d.Status, status, time.Now())
+	d.Status = status
+}
+
+// trackStatusTimeLocked records start, pause and end times for a status transition; caller must hold debateMutex
+func (d *DebateSession) trackStatusTimeLocked(from, to types.DebateStatus, now time.Time) {
+	if from == types.DebateStatusPaused && !d.pausedAt.IsZero() {
+		d.pausedTotal += now.Sub(d.pausedAt)
+		d.pausedAt = time.Time{}
+	}
+
+	switch to {
+	case types.DebateStatusActive:
+		if d.startedAt.IsZero() {
+			d.startedAt = now
+		}
+	case types.DebateStatusPaused:
+		d.pausedAt = now
+	case types.DebateStatusFinished:
+		if d.endedAt.IsZero() {
+			d.endedAt = now
+		}
+	}
+}
+
+// GetActiveDuration returns how long the debate has been running, excluding paused periods
+func (d *DebateSession) GetActiveDuration() time.Duration {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+
+	if d.startedAt.IsZero() {
+		return 0
+	}
+
+	end := time.Now()
+	if !d.endedAt.IsZero() {
+		end = d.endedAt
+	}
+
+	paused := d.pausedTotal
+	if !d.pausedAt.IsZero() {
+		paused += end.Sub(d.pausedAt)
+	}
+
+	active := end.Sub(d.startedAt) - paused
+	if active < 0 {
+		return 0
+	}
+	return active
 }
 
 // GetStatus retrieves the current status safely
-func (d *DebateSession) GetStatus() string {
+func (d *DebateSession) GetStatus() types.DebateStatus {
 	d.debateMutex.RLock()
 	defer d.debateMutex.RUnlock()
 	return d.Status
@@ -260,6 +1251,59 @@ func (d *DebateSession) GetGameScore() GameScore {
 	return d.GameScore
 }
 
+// HasAgent reports whether the named agent is one of the debaters
+func (d *DebateSession) HasAgent(agentName string) bool {
+	return d.Agent1.GetName() == agentName || d.Agent2.GetName() == agentName
+}
+
+// GetAgentScoreHistory returns the agent's argument scores in the order they were made. Only the scores still in
+// the in-memory history are returned, numbered from the agent's first turn.
+// The returned slice is a copy and safe to use while the debate loop is running.
+func (d *DebateSession) GetAgentScoreHistory(agentName string) []ScorePoint {
+	d.debateMutex.RLock()
+	defer d.debateMutex.RUnlock()
+
+	trimmed := d.trimmedScores[agentName]
+	scores := make([]ScorePoint, 0)
+	for _, entry := range d.History {
+		if !entry.IsPlayer && entry.Speaker == agentName && entry.AverageScore != nil {
+			scores = append(scores, ScorePoint{
+				Turn:  trimmed + len(scores) + 1,
+				Score: *entry.AverageScore,
+				Time:  entry.Time,
+			})
+		}
+	}
+	return scores
+}
+
+// GetLastAgentScore returns the agent's most recent argument score, or nil if none has been scored yet
+func (d *DebateSession) GetLastAgentScore(agentName string) *float64 {
+	scores := d.GetAgentScoreHistory(agentName)
+	if len(scores) == 0 {
+		return nil
+	}
+	last := scores[len(scores)-1].Score
+	return &last
+}
+
+// GetMomentum compares the agents' recent argument quality.
+// A positive value means Agent1 is surging, a negative value means Agent2 is, on the 0-100 score scale.
+func (d *DebateSession) GetMomentum() float64 {
+	lookback := d.Config.MomentumLookback
+	return d.GetRecentAgentScore(d.Agent1.GetName(), lookback) - d.GetRecentAgentScore(d.Agent2.GetName(), lookback)
+}
+
+// IsCloseMatch reports whether the agents' HP are within the configured close-match margin
+func (d *DebateSession) IsCloseMatch() bool {
+	gameScore := d.GetGameScore()
+	gap := gameScore.Agent1Score - gameScore.Agent2Score
+	if gap < 0 {
+		gap = -gap
+	}
+	return gap <= d.Config.CloseMatchMargin
+}
+
 // GetRecentAgentAverageScore calculates the average score of recent agent messages
 // Used as baseline for dynamic player message scoring
 func (d *DebateSession) GetRecentAgentAverageScore(lookbackCount int) float64 {
@@ -331,25 +1375,37 @@ func (d *DebateSession) GetStopChannel() chan struct{} {
 	return d.stopChannel
 }
 
+// Finish marks the debate finished and signals its loop to stop, cancelling any turn in flight.
+// It reports false if the debate had already finished, so only one caller ends it.
+func (d *DebateSession) Finish() bool {
+	d.debateMutex.Lock()
+	if d.Status == types.DebateStatusFinished {
+		d.debateMutex.Unlock()
+		return false
+	}
+	log.Printf("Debate %s status changed from %s to finished", d.DebateID, d.Status)
+	d.trackStatusTimeLocked(d.Status, types.DebateStatusFinished, time.Now())
+	d.Status = types.DebateStatusFinished
+	d.debateMutex.Unlock()
+
+	d.stopOnce.Do(func() {
+		if d.stopChannel != nil {
+			close(d.stopChannel)
+		}
+	})
+	return true
+}
+
 // CheckStatusAndClients returns the current status and client count safely
-func (d *DebateSession) CheckStatusAndClients() (status string, clientCount int) {
+func (d *DebateSession) CheckStatusAndClients() (status types.DebateStatus, clientCount int) {
 	d.debateMutex.RLock()
 	defer d.debateMutex.RUnlock()
 	return d.Status, len(d.Clients)
 }
 
-// GetNextAgent determines which agent should speak next
+// GetNextAgent determines which agent should speak next, by the debate's TurnOrder; see NextSpeaker
 func (d *DebateSession) GetNextAgent() *agent.Agent {
-	d.debateMutex.Lock() // Lock needed to safely read and write lastSpeaker
-	defer d.debateMutex.Unlock()
-
-	if d.lastSpeaker == d.Agent1.GetName() {
-		d.lastSpeaker = d.Agent2.GetName()
-		return d.Agent2
-	}
-	// Default to Agent1 if no last speaker or if Agent2 spoke last
-	d.lastSpeaker = d.Agent1.GetName()
-	return d.Agent1
+	return NextSpeaker(d)
 }
 
 // HandlePlayerInterruption processes a player message and determines if it should interrupt the agent conversation
@@ -368,11 +1424,11 @@ func (d *DebateSession) HandlePlayerInterruption(playerID, message string) bool
 // Start begins the conversation between the agents (Likely to be removed/refactored)
 func (d *DebateSession) Start(ctx context.Context) error {
 	d.debateMutex.Lock()
-	if d.Status != "waiting" {
+	if d.Status != types.DebateStatusWaiting {
 		d.debateMutex.Unlock()
 		return fmt.Errorf("debate %s already started or finished", d.DebateID)
 	}
-	d.Status = "active"
+	d.Status = types.DebateStatusActive
 	d.debateMutex.Unlock()
 
 	log.Printf("Starting debate %s on topic: %s", d.DebateID, d.Config.Topic)
@@ -392,22 +1448,38 @@ func (d *DebateSession) Start(ctx context.Context) error {
 		select {
 		case <-d.stopChannel:
 			log.Printf("Debate %s received stop signal during placeholder loop.", d.DebateID)
-			d.UpdateStatus("finished") // Or another appropriate status
+			d.UpdateStatus(types.DebateStatusFinished) // Or another appropriate status
 			return nil
 		default:
 			// Continue
 		}
 	}
 
-	d.UpdateStatus("finished") // Mark as finished after placeholder turns
+	d.UpdateStatus(types.DebateStatusFinished) // Mark as finished after placeholder turns
 	log.Printf("Debate %s finished (Placeholder)", d.DebateID)
 	return nil
 }
 
+// JudgeWinner asks the ConvictionJudge to decide the debate, returning the winning agent's name and the judge's metrics
+func (d *DebateSession) JudgeWinner(ctx context.Context) (string, *tools.ConvictionMetrics, error) {
+	metrics, err := d.analyzeConviction(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Only accept a verdict naming one of the two debaters
+	switch metrics.DominantAgent {
+	case d.Agent1.GetName(), d.Agent2.GetName():
+		return metrics.DominantAgent, metrics, nil
+	default:
+		return "", metrics, fmt.Errorf("judge picked unknown agent %q for debate %s", metrics.DominantAgent, d.DebateID)
+	}
+}
+
 // analyzeConviction uses the ConvictionJudge to analyze the current debate (Refactored)
-func (d *DebateSession) analyzeConviction(ctx context.Context) error {
+func (d *DebateSession) analyzeConviction(ctx context.Context) (*tools.ConvictionMetrics, error) {
 	if d.Judge == nil {
-		return fmt.Errorf("conviction judge not initialized for debate %s", d.DebateID)
+		return nil, fmt.Errorf("conviction judge not initialized for debate %s", d.DebateID)
 	}
 
 	d.debateMutex.RLock()
@@ -436,17 +1508,17 @@ func (d *DebateSession) analyzeConviction(ctx context.Context) error {
 
 	conversationJSON, err := json.Marshal(conversationData)
 	if err != nil {
-		return fmt.Errorf("failed to marshal conversation data for debate %s: %v", d.DebateID, err)
+		return nil, fmt.Errorf("failed to marshal conversation data for debate %s: %v", d.DebateID, err)
 	}
 
 	metricsJSON, err := d.Judge.Call(ctx, string(conversationJSON))
 	if err != nil {
-		return fmt.Errorf("failed to analyze conviction for debate %s: %v", d.DebateID, err)
+		return nil, fmt.Errorf("failed to analyze conviction for debate %s: %v", d.DebateID, err)
 	}
 
 	var metrics tools.ConvictionMetrics
 	if err := json.Unmarshal([]byte(metricsJSON), &metrics); err != nil {
-		return fmt.Errorf("failed to parse conviction metrics for debate %s: %v", d.DebateID, err)
+		return nil, fmt.Errorf("failed to parse conviction metrics for debate %s: %v", d.DebateID, err)
 	}
 
 	log.Printf("\n=== Conviction Analysis (Debate: %s) ===\n"+
@@ -463,8 +1535,7 @@ func (d *DebateSession) analyzeConviction(ctx context.Context) error {
 		metrics.DominantAgent,
 		metrics.AnalysisSummary)
 
-	// Optional: Store metrics or update debate state based on analysis
-	return nil
+	return &metrics, nil
 }
 
 // handlePlayerInput processes player input within the context of this debate session
@@ -500,8 +1571,8 @@ func (d *DebateSession) handlePlayerInput(ctx context.Context, playerID string,
 	return fmt.Errorf("handlePlayerInput logic needs further implementation")
 }
 
-// getPromptStyle returns the prompt modification based on response style (Refactored)
-func (d *DebateSession) getPromptStyle() string {
+// PromptStyle returns the tone instruction for the debate's response style
+func (d *DebateSession) PromptStyle() string {
 	switch d.Config.ResponseStyle {
 	case types.ResponseStyleFormal:
 		return "Maintain a formal and professional tone."
@@ -0,0 +1,91 @@
+package conversation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Bounds on a debate's HPTone
+const (
+	MaxToneThresholds = 10  // Thresholds per side
+	MaxToneLength     = 300 // Characters in one tone
+)
+
+// ToneThreshold sets an agent's tone once its HP gap to its opponent reaches Gap
+type ToneThreshold struct {
+	Gap  int    `json:"gap"`  // HP gap at which the tone starts, at least 1
+	Tone string `json:"tone"` // Instruction added to the agent's prompt, e.g. "Get more aggressive and take risks."
+}
+
+// HPTone maps how far an agent trails or leads its opponent on HP to a tone added to its turn prompt, so an
+// agent can grow desperate as it loses and confident as it wins. The zero value leaves prompts unchanged.
+type HPTone struct {
+	Behind []ToneThreshold `json:"behind,omitempty"` // Tones for the agent with less HP
+	Ahead  []ToneThreshold `json:"ahead,omitempty"`  // Tones for the agent with more HP
+}
+
+// IsZero reports whether the mapping sets no tones
+func (t HPTone) IsZero() bool {
+	return len(t.Behind) == 0 && len(t.Ahead) == 0
+}
+
+// Validate checks that every threshold has a positive gap, distinct within its side, and a tone of reasonable length
+func (t HPTone) Validate() error {
+	sides := []struct {
+		name       string
+		thresholds []ToneThreshold
+	}{{"behind", t.Behind}, {"ahead", t.Ahead}}
+	for _, side := range sides {
+		if len(side.thresholds) > MaxToneThresholds {
+			return fmt.Errorf("%s has %d thresholds, at most %d are allowed", side.name, len(side.thresholds), MaxToneThresholds)
+		}
+		gaps := make(map[int]bool, len(side.thresholds))
+		for _, threshold := range side.thresholds {
+			if threshold.Gap < 1 {
+				return fmt.Errorf("%s gap must be at least 1, got %d", side.name, threshold.Gap)
+			}
+			if gaps[threshold.Gap] {
+				return fmt.Errorf("%s has more than one threshold at gap %d", side.name, threshold.Gap)
+			}
+			gaps[threshold.Gap] = true
+			tone := strings.TrimSpace(threshold.Tone)
+			if tone == "" {
+				return fmt.Errorf("%s tone at gap %d must not be empty", side.name, threshold.Gap)
+			}
+			if len(tone) > MaxToneLength {
+				return fmt.Errorf("%s tone at gap %d must be at most %d characters", side.name, threshold.Gap, MaxToneLength)
+			}
+		}
+	}
+	return nil
+}
+
+// ToneFor returns the tone for an agent leading its opponent by lead HP, negative when it's behind: the tone of
+// the largest threshold on that side the gap reaches, or empty when it reaches none or HP is tied
+func (t HPTone) ToneFor(lead int) string {
+	thresholds := t.Ahead
+	if lead < 0 {
+		thresholds, lead = t.Behind, -lead
+	}
+
+	tone, reached := "", 0
+	for _, threshold := range thresholds {
+		if threshold.Gap <= lead && threshold.Gap > reached {
+			tone, reached = strings.TrimSpace(threshold.Tone), threshold.Gap
+		}
+	}
+	return tone
+}
+
+// AgentTone returns the tone for the named agent's next turn, from its HP against its opponent's
+func (d *DebateSession) AgentTone(agentName string) string {
+	if d.Config.HPTone.IsZero() {
+		return ""
+	}
+	gameScore := d.GetGameScore()
+	lead := gameScore.Agent1Score - gameScore.Agent2Score
+	if agentName == d.Agent2.GetName() {
+		lead = -lead
+	}
+	return d.Config.HPTone.ToneFor(lead)
+}
@@ -0,0 +1,128 @@
+package conversation
+
+import (
+	"math"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+)
+
+// Turn orders for a debate's agents
+const (
+	TurnOrderAlternate  = "alternate"  // Agents strictly take turns, the default
+	TurnOrderChallenged = "challenged" // The agent a player last argued against responds, otherwise agents take turns
+	TurnOrderRandom     = "random"     // A coin flip picks each speaker
+	TurnOrderComeback   = "comeback"   // The agent behind on HP is more likely to speak, to create comebacks
+)
+
+// IsValidTurnOrder reports whether order is a known turn order
+func IsValidTurnOrder(order string) bool {
+	switch order {
+	case TurnOrderAlternate, TurnOrderChallenged, TurnOrderRandom, TurnOrderComeback:
+		return true
+	}
+	return false
+}
+
+// maxConsecutiveTurns caps how many turns in a row one agent can take under the orders that don't alternate,
+// so the other agent always gets a reply in
+const maxConsecutiveTurns = 2
+
+// How strongly TurnOrderComeback favors the agent behind: the chance it speaks grows from even at an HP tie to
+// 0.5+comebackBias at a gap of comebackGapCap or more
+const (
+	comebackBias   = 0.25
+	comebackGapCap = 50
+)
+
+// turnOrderStrategy picks the next speaker; it's called with the session mutex held
+type turnOrderStrategy func(d *DebateSession) *agent.Agent
+
+// turnOrderStrategies maps each turn order to its strategy. TurnOrderAlternate is also the fallback.
+var turnOrderStrategies = map[string]turnOrderStrategy{
+	TurnOrderAlternate:  alternateSpeaker,
+	TurnOrderChallenged: challengedSpeaker,
+	TurnOrderRandom:     randomSpeaker,
+	TurnOrderComeback:   comebackSpeaker,
+}
+
+// NextSpeaker picks the agent to take the next turn according to the debate's TurnOrder, and records it as
+// the last speaker. The opening turn always goes to the first speaker, whatever the order.
+func NextSpeaker(d *DebateSession) *agent.Agent {
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+
+	strategy, ok := turnOrderStrategies[d.Config.TurnOrder]
+	if !ok || d.consecutiveTurns == 0 {
+		strategy = alternateSpeaker
+	}
+	next := strategy(d)
+	if d.consecutiveTurns >= maxConsecutiveTurns && next.GetName() == d.lastSpeaker {
+		next = alternateSpeaker(d)
+	}
+
+	if next.GetName() == d.lastSpeaker {
+		d.consecutiveTurns++
+	} else {
+		d.consecutiveTurns = 1
+	}
+	d.lastSpeaker = next.GetName()
+	d.lastChallenged = ""
+	return next
+}
+
+// RecordChallenge notes that a player argued against the named agent, for TurnOrderChallenged to have it
+// respond next. The challenge is answered by the next agent turn, whoever takes it.
+func (d *DebateSession) RecordChallenge(agentName string) {
+	if agentName == "" {
+		return
+	}
+	d.debateMutex.Lock()
+	defer d.debateMutex.Unlock()
+	d.lastChallenged = agentName
+}
+
+// alternateSpeaker returns the agent that didn't speak last
+func alternateSpeaker(d *DebateSession) *agent.Agent {
+	if d.lastSpeaker == d.Agent1.GetName() {
+		return d.Agent2
+	}
+	return d.Agent1
+}
+
+// challengedSpeaker returns the agent a player last argued against, or the agent that didn't speak last
+func challengedSpeaker(d *DebateSession) *agent.Agent {
+	switch d.lastChallenged {
+	case d.Agent1.GetName():
+		return d.Agent1
+	case d.Agent2.GetName():
+		return d.Agent2
+	}
+	return alternateSpeaker(d)
+}
+
+// randomSpeaker returns either agent with equal chance
+func randomSpeaker(d *DebateSession) *agent.Agent {
+	if d.rng().Intn(2) == 0 {
+		return d.Agent1
+	}
+	return d.Agent2
+}
+
+// comebackSpeaker favors the agent with less HP, more strongly the further behind it is. At an HP tie agents
+// take turns.
+func comebackSpeaker(d *DebateSession) *agent.Agent {
+	gap := d.GameScore.Agent1Score - d.GameScore.Agent2Score
+	if gap == 0 {
+		return alternateSpeaker(d)
+	}
+
+	behind, ahead := d.Agent2, d.Agent1
+	if gap < 0 {
+		behind, ahead = d.Agent1, d.Agent2
+	}
+	chance := 0.5 + comebackBias*math.Min(math.Abs(float64(gap)), comebackGapCap)/comebackGapCap
+	if d.rng().Float64() < chance {
+		return behind
+	}
+	return ahead
+}
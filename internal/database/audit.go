@@ -0,0 +1,111 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultAuditLimit caps how many audit events ListAuditEvents returns when no limit is given
+const defaultAuditLimit = 50
+
+// AuditEvent is a record of an admin or moderator action
+type AuditEvent struct {
+	ID        int64           `json:"id"`
+	ActorID   string          `json:"actor_id"`
+	Action    string          `json:"action"`
+	Target    string          `json:"target,omitempty"` // What the action was applied to, e.g. a debate or user ID
+	Details   json.RawMessage `json:"details"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AuditFilter selects audit events for ListAuditEvents. Empty fields match everything.
+type AuditFilter struct {
+	ActorID string
+	Action  string
+	Target  string
+	Since   time.Time // Events at or after this time
+	Until   time.Time // Events before this time
+	Offset  int
+	Limit   int
+}
+
+// LogAuditEvent records an admin or moderator action. details is stored as JSON.
+func (d *Database) LogAuditEvent(actorID, action, target string, details interface{}) error {
+	encoded, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit details: %v", err)
+	}
+
+	_, err = d.db.Exec(`INSERT INTO audit_log (actor_id, action, target, details, created_at) VALUES (?, ?, ?, ?, ?)`,
+		actorID, action, target, string(encoded), time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to log audit event: %v", err)
+	}
+	return nil
+}
+
+// ListAuditEvents returns the audit events matching the filter, newest first, along with how many match in total.
+// A limit <= 0 uses defaultAuditLimit.
+func (d *Database) ListAuditEvents(filter AuditFilter) ([]*AuditEvent, int, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+
+	var conditions []string
+	var args []interface{}
+	if filter.ActorID != "" {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, filter.ActorID)
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.Target != "" {
+		conditions = append(conditions, "target = ?")
+		args = append(args, filter.Target)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.Until.UTC())
+	}
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM audit_log`+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit events: %v", err)
+	}
+
+	query := `SELECT id, actor_id, action, target, details, created_at FROM audit_log` + whereClause +
+		` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+	rows, err := d.db.Query(query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit events: %v", err)
+	}
+	defer rows.Close()
+
+	events := make([]*AuditEvent, 0)
+	for rows.Next() {
+		var event AuditEvent
+		var details string
+		if err := rows.Scan(&event.ID, &event.ActorID, &event.Action, &event.Target, &details, &event.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit event: %v", err)
+		}
+		event.Details = json.RawMessage(details)
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating audit events: %v", err)
+	}
+
+	return events, total, nil
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	db := setupMigratedTestDB(t)
+	start := time.Now().Add(-time.Second)
+
+	require.NoError(t, db.LogAuditEvent("admin-1", "debate.end", "debate-1", map[string]interface{}{"request_id": "42", "winner": "Messi"}))
+	require.NoError(t, db.LogAuditEvent("mod-1", "debate.end", "debate-2", nil))
+	require.NoError(t, db.LogAuditEvent("admin-1", "feature_flags.update", "", map[string]interface{}{"request_id": "43"}))
+
+	events, total, err := db.ListAuditEvents(AuditFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, events, 3)
+	assert.Equal(t, "feature_flags.update", events[0].Action, "newest first")
+	assert.Equal(t, "admin-1", events[2].ActorID)
+	assert.Equal(t, "debate-1", events[2].Target)
+	assert.JSONEq(t, `{"request_id":"42","winner":"Messi"}`, string(events[2].Details))
+	assert.JSONEq(t, `null`, string(events[1].Details))
+
+	events, total, err = db.ListAuditEvents(AuditFilter{ActorID: "admin-1", Action: "debate.end"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "debate-1", events[0].Target)
+
+	events, total, err = db.ListAuditEvents(AuditFilter{Target: "debate-2"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "mod-1", events[0].ActorID)
+
+	// Pages share the total
+	events, total, err = db.ListAuditEvents(AuditFilter{Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, events, 1)
+	assert.Equal(t, "debate-1", events[0].Target)
+
+	// Time ranges
+	_, total, err = db.ListAuditEvents(AuditFilter{Since: start, Until: time.Now().Add(time.Second)})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	_, total, err = db.ListAuditEvents(AuditFilter{Since: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	_, total, err = db.ListAuditEvents(AuditFilter{Until: start})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultBookmarkLimit caps how many bookmarks ListBookmarks returns when no limit is given
+const defaultBookmarkLimit = 50
+
+// Bookmark is a debate a user follows
+type Bookmark struct {
+	UserID    string    `json:"user_id"`
+	DebateID  string    `json:"debate_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddBookmark bookmarks a debate for a user, returning ErrBookmarkExists if they already have
+func (d *Database) AddBookmark(userID, debateID string) error {
+	result, err := d.db.Exec(`INSERT OR IGNORE INTO bookmarks (user_id, debate_id, created_at) VALUES (?, ?, ?)`,
+		userID, debateID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to add bookmark: %v", err)
+	}
+	if added, err := result.RowsAffected(); err == nil && added == 0 {
+		return ErrBookmarkExists
+	}
+	return nil
+}
+
+// RemoveBookmark removes a user's bookmark of a debate, returning ErrBookmarkNotFound if they don't have one
+func (d *Database) RemoveBookmark(userID, debateID string) error {
+	result, err := d.db.Exec(`DELETE FROM bookmarks WHERE user_id = ? AND debate_id = ?`, userID, debateID)
+	if err != nil {
+		return fmt.Errorf("failed to remove bookmark: %v", err)
+	}
+	if removed, err := result.RowsAffected(); err == nil && removed == 0 {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
+
+// ListBookmarks returns a user's bookmarks, newest first, along with how many they have in total.
+// A limit <= 0 uses defaultBookmarkLimit.
+func (d *Database) ListBookmarks(userID string, offset, limit int) ([]*Bookmark, int, error) {
+	if limit <= 0 {
+		limit = defaultBookmarkLimit
+	}
+
+	var total int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM bookmarks WHERE user_id = ?`, userID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count bookmarks: %v", err)
+	}
+
+	rows, err := d.db.Query(`SELECT user_id, debate_id, created_at FROM bookmarks WHERE user_id = ?
+		ORDER BY created_at DESC, debate_id LIMIT ? OFFSET ?`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list bookmarks: %v", err)
+	}
+	defer rows.Close()
+
+	bookmarks := make([]*Bookmark, 0)
+	for rows.Next() {
+		var bookmark Bookmark
+		if err := rows.Scan(&bookmark.UserID, &bookmark.DebateID, &bookmark.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan bookmark: %v", err)
+		}
+		bookmarks = append(bookmarks, &bookmark)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating bookmarks: %v", err)
+	}
+
+	return bookmarks, total, nil
+}
+
+// CountBookmarks returns how many users have bookmarked a debate
+func (d *Database) CountBookmarks(debateID string) (int, error) {
+	var count int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM bookmarks WHERE debate_id = ?`, debateID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count bookmarks: %v", err)
+	}
+	return count, nil
+}
+
+// GetDebateBookmarkers returns the users who have bookmarked a debate
+func (d *Database) GetDebateBookmarkers(debateID string) ([]string, error) {
+	rows, err := d.db.Query(`SELECT user_id FROM bookmarks WHERE debate_id = ? ORDER BY user_id`, debateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get debate bookmarkers: %v", err)
+	}
+	defer rows.Close()
+
+	users := make([]string, 0)
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan debate bookmarker: %v", err)
+		}
+		users = append(users, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating debate bookmarkers: %v", err)
+	}
+	return users, nil
+}
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookmarks(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	require.NoError(t, db.AddBookmark("user-alice", "debate-1"))
+	require.NoError(t, db.AddBookmark("user-alice", "debate-2"))
+	require.NoError(t, db.AddBookmark("user-bob", "debate-1"))
+	assert.ErrorIs(t, db.AddBookmark("user-alice", "debate-1"), ErrBookmarkExists)
+
+	bookmarks, total, err := db.ListBookmarks("user-alice", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, bookmarks, 2)
+	assert.Equal(t, "debate-2", bookmarks[0].DebateID, "newest first")
+
+	bookmarks, total, err = db.ListBookmarks("user-alice", 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, bookmarks, 1)
+	assert.Equal(t, "debate-1", bookmarks[0].DebateID)
+
+	count, err := db.CountBookmarks("debate-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	bookmarkers, err := db.GetDebateBookmarkers("debate-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user-alice", "user-bob"}, bookmarkers)
+
+	require.NoError(t, db.RemoveBookmark("user-alice", "debate-1"))
+	assert.ErrorIs(t, db.RemoveBookmark("user-alice", "debate-1"), ErrBookmarkNotFound)
+	count, err = db.CountBookmarks("debate-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
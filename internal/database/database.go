@@ -2,32 +2,157 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 
 	"github.com/neo/convinceme_backend/internal/logging"
 	"github.com/neo/convinceme_backend/internal/scoring"
+	"github.com/neo/convinceme_backend/internal/types"
 )
 
 type Database struct {
-	db *sql.DB
+	db         *sql.DB
+	scoreBlend ScoreBlend // How votes blend into argument scores, see score_blend.go
 }
 
+// ErrNotArgumentOwner is returned when a user tries to modify an argument they did not submit
+var ErrNotArgumentOwner = errors.New("argument belongs to another player")
+
+// ErrUserNotFound is returned when a user lookup matches no account
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrUserModified is returned when a user update is based on a stale read, because the user changed since
+var ErrUserModified = errors.New("user was modified since it was read")
+
+// ErrReplyParentNotFound is returned when an argument replies to an argument or agent turn that isn't in its debate
+var ErrReplyParentNotFound = errors.New("replied-to argument or agent turn is not in this debate")
+
+// ErrBookmarkExists is returned when a user bookmarks a debate they've already bookmarked
+var ErrBookmarkExists = errors.New("debate is already bookmarked")
+
+// ErrBookmarkNotFound is returned when removing a bookmark the user doesn't have
+var ErrBookmarkNotFound = errors.New("bookmark not found")
+
+// ErrReactionTargetNotFound is returned when reacting to a transcript entry that isn't in the debate
+var ErrReactionTargetNotFound = errors.New("reacted-to message is not in this debate")
+
+// ErrDebateAlreadyFinished is returned when ending a debate that has already finished
+var ErrDebateAlreadyFinished = errors.New("debate has already finished")
+
+// ErrArgumentNotFound is returned when an argument lookup matches no argument
+var ErrArgumentNotFound = errors.New("argument not found")
+
 // Debate represents a debate session in the database
 type Debate struct {
-	ID         string     `json:"id"`
-	Topic      string     `json:"topic"`
-	Status     string     `json:"status"`
-	Agent1Name string     `json:"agent1_name"`
-	Agent2Name string     `json:"agent2_name"`
-	CreatedAt  time.Time  `json:"created_at"`
-	EndedAt    *time.Time `json:"ended_at,omitempty"` // Use pointer for nullable timestamp
-	Winner     *string    `json:"winner,omitempty"`   // Use pointer for nullable string
+	ID         string             `json:"id"`
+	Topic      string             `json:"topic"`
+	Status     types.DebateStatus `json:"status"`
+	Agent1Name string             `json:"agent1_name"`
+	Agent2Name string             `json:"agent2_name"`
+	CreatedAt  time.Time          `json:"created_at"`
+	EndedAt    *time.Time         `json:"ended_at,omitempty"` // Use pointer for nullable timestamp
+	Winner     *string            `json:"winner,omitempty"`   // Use pointer for nullable string
+	Visibility string             `json:"visibility"`
+	CreatedBy  *string            `json:"created_by,omitempty"`
+	// Wall-clock duration from creation to end, only set once the debate has ended
+	DurationSeconds *int64 `json:"duration_seconds,omitempty"`
+	// Time the debate was actually running, excluding paused periods
+	ActiveSeconds *int64 `json:"active_seconds,omitempty"`
+	// When a scheduled debate opens for joining, nil for debates that open immediately
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	// Why the debate ended (one of the DebateEndReason values), only set once the debate has ended
+	EndReason *string `json:"end_reason,omitempty"`
+	// Which agent opened the debate (agent1 or agent2), nil for debates created before it was recorded
+	FirstSpeaker *string `json:"first_speaker,omitempty"`
+	// Whether the first speaker was picked by a coin flip
+	FirstSpeakerCoinFlip bool `json:"first_speaker_coin_flip,omitempty"`
+	// Whether an admin featured the debate on the homepage, and since when
+	Featured   bool       `json:"featured"`
+	FeaturedAt *time.Time `json:"featured_at,omitempty"`
+	// Seed of the debate's random choices, to reproduce it; nil for debates created before it was recorded
+	Seed *int64 `json:"seed,omitempty"`
+	// The debate this one was forked from, and how many of its transcript entries it starts with; nil unless forked
+	ParentDebateID *string `json:"parent_debate_id,omitempty"`
+	ForkedFromTurn *int    `json:"forked_from_turn,omitempty"`
+	// The debate's validated config as JSON, to restore or fork it with the same settings; nil for debates
+	// created before it was recorded. Not served, since it includes the agents' prompt overrides.
+	Config *string `json:"-"`
+	// When the debate loop times out, nil until the loop first starts
+	Deadline *time.Time `json:"-"`
+}
+
+// setDuration computes DurationSeconds from CreatedAt and EndedAt
+func (debate *Debate) setDuration() {
+	if debate.EndedAt == nil {
+		return
+	}
+	seconds := int64(debate.EndedAt.Sub(debate.CreatedAt).Seconds())
+	if seconds < 0 {
+		seconds = 0
+	}
+	debate.DurationSeconds = &seconds
+}
+
+// setRestoreState sets the config and deadline a debate is restored with from their nullable columns
+func (debate *Debate) setRestoreState(config sql.NullString, deadline sql.NullTime) {
+	if config.Valid {
+		debate.Config = &config.String
+	}
+	if deadline.Valid {
+		debate.Deadline = &deadline.Time
+	}
+}
+
+// Debate visibility levels
+const (
+	DebateVisibilityPublic   = "public"   // Listed and joinable by anyone
+	DebateVisibilityUnlisted = "unlisted" // Joinable by anyone with the ID, but not listed
+	DebateVisibilityPrivate  = "private"  // Not listed, joinable only by invited users
+)
+
+// Reasons a debate ended
+const (
+	DebateEndReasonKnockout   = "knockout"        // An agent's HP was depleted
+	DebateEndReasonTimeout    = "timeout"         // The debate ran past its maximum duration
+	DebateEndReasonInactivity = "inactivity"      // The debate made no progress for too long
+	DebateEndReasonMaxTurns   = "max_turns"       // The debate reached its maximum number of agent turns and was decided on HP
+	DebateEndReasonJudge      = "judge"           // The conviction judge picked the winner
+	DebateEndReasonBudget     = "budget_exceeded" // The debate's estimated cost reached its budget and was decided on HP
+	DebateEndReasonError      = "error"           // The debate loop kept crashing
+	DebateEndReasonAdmin      = "admin_ended"     // A moderator ended the debate
+)
+
+// IsValidDebateVisibility reports whether visibility is a known visibility level
+func IsValidDebateVisibility(visibility string) bool {
+	switch visibility {
+	case DebateVisibilityPublic, DebateVisibilityUnlisted, DebateVisibilityPrivate:
+		return true
+	}
+	return false
+}
+
+// DebateSettings holds optional debate attributes stored alongside the core debate row
+type DebateSettings struct {
+	Visibility  string
+	CreatedBy   string
+	ScheduledAt *time.Time // Optional start time; the debate stays 'scheduled' until then
+	// Which agent opens (agent1 or agent2), empty if not recorded
+	FirstSpeaker         string
+	FirstSpeakerCoinFlip bool // The first speaker was picked by a coin flip
+	// Seed of the debate's random choices, 0 if not recorded
+	Seed int64
+	// The debate this one was forked from and the transcript entries it starts with, empty if not forked
+	ParentDebateID string
+	ForkedFromTurn int
+	// The debate's validated config as JSON, empty if not recorded
+	Config string
 }
 
 // Topic represents a pre-generated debate topic with agent pairings
@@ -57,6 +182,16 @@ type Argument struct {
 	Downvotes int                    `json:"downvotes"`
 	VoteScore float64                `json:"vote_score"`
 	UserVote  string                 `json:"user_vote,omitempty"` // Current user's vote on this argument
+	// The argument or agent turn (by its transcript message ID) this argument replies to, if any
+	ParentID        *int64 `json:"parent_id,omitempty"`
+	ParentMessageID *int64 `json:"parent_message_id,omitempty"`
+}
+
+// ArgumentParent is what an argument replies to: an earlier argument, or an agent turn by its transcript message
+// ID. The zero value is a standalone argument.
+type ArgumentParent struct {
+	ArgumentID int64
+	MessageID  int64
 }
 
 // New creates a new database connection and initializes the schema
@@ -130,8 +265,10 @@ func (d *Database) Close() error {
 	return d.db.Close()
 }
 
-// SaveArgument saves a new argument to the database, linking it to a debate
-func (d *Database) SaveArgument(playerID, topic, content, side, debateID string) (int64, error) {
+// SaveArgument saves a new argument to the database, linking it to a debate and to the argument or agent turn it
+// replies to. userID is the authenticated user who made it, or empty for an anonymous player. The parent is
+// expected to have been checked with GetReplyParent.
+func (d *Database) SaveArgument(playerID, userID, topic, content, side, debateID string, parent ArgumentParent) (int64, error) {
 	logging.LogDatabaseEvent("INSERT", "arguments", map[string]interface{}{
 		"player_id":      playerID,
 		"topic":          topic,
@@ -140,8 +277,22 @@ func (d *Database) SaveArgument(playerID, topic, content, side, debateID string)
 		"content_length": len(content),
 	})
 
-	query := `INSERT INTO arguments (player_id, topic, content, side, debate_id) VALUES (?, ?, ?, ?, ?)`
-	result, err := d.db.Exec(query, playerID, topic, content, side, debateID)
+	var user sql.NullString
+	if userID != "" {
+		user = sql.NullString{String: userID, Valid: true}
+	}
+
+	var parentID, parentMessageID sql.NullInt64
+	if parent.ArgumentID != 0 {
+		parentID = sql.NullInt64{Int64: parent.ArgumentID, Valid: true}
+	}
+	if parent.MessageID != 0 {
+		parentMessageID = sql.NullInt64{Int64: parent.MessageID, Valid: true}
+	}
+
+	query := `INSERT INTO arguments (player_id, user_id, topic, content, side, debate_id, parent_id, parent_message_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := d.db.Exec(query, playerID, user, topic, content, side, debateID, parentID, parentMessageID)
 	if err != nil {
 		logging.Error("Failed to save argument", map[string]interface{}{
 			"error":     err,
@@ -161,6 +312,32 @@ func (d *Database) SaveArgument(playerID, topic, content, side, debateID string)
 	return id, nil
 }
 
+// GetReplyParent returns the content of the argument or agent turn a new argument of the debate replies to, or
+// ErrReplyParentNotFound if it isn't in that debate. A standalone argument has no parent and gets empty content.
+func (d *Database) GetReplyParent(debateID string, parent ArgumentParent) (string, error) {
+	var query string
+	var id int64
+	switch {
+	case parent.ArgumentID != 0 && parent.MessageID != 0:
+		return "", fmt.Errorf("an argument can reply to an argument or an agent turn, not both")
+	case parent.ArgumentID != 0:
+		query, id = `SELECT content FROM arguments WHERE id = ? AND debate_id = ?`, parent.ArgumentID
+	case parent.MessageID != 0:
+		query, id = `SELECT message FROM debate_messages WHERE id = ? AND debate_id = ? AND is_player = 0`, parent.MessageID
+	default:
+		return "", nil
+	}
+
+	var content string
+	err := d.db.QueryRow(query, id, debateID).Scan(&content)
+	if err == sql.ErrNoRows {
+		return "", ErrReplyParentNotFound
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get reply parent: %v", err)
+	}
+	return content, nil
+}
+
 // SaveScore saves a score for an argument, linking it to a debate
 func (d *Database) SaveScore(argumentID int64, debateID string, score *scoring.ArgumentScore) error {
 	logging.LogDatabaseEvent("INSERT", "scores", map[string]interface{}{
@@ -169,11 +346,12 @@ func (d *Database) SaveScore(argumentID int64, debateID string, score *scoring.A
 		"average":     score.Average,
 	})
 
-	query := `INSERT INTO scores (argument_id, debate_id, strength, relevance, logic, truth, humor, average, explanation)
-             VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	// The stored average blends in any votes the argument already has
+	query := `INSERT INTO scores (argument_id, debate_id, strength, relevance, logic, truth, humor, llm_average, average, explanation)
+             VALUES (?, ?, ?, ?, ?, ?, ?, ?, ` + d.blend().averageSQL("?", "?") + `, ?)`
 
 	_, err := d.db.Exec(query, argumentID, debateID, score.Strength, score.Relevance, score.Logic,
-		score.Truth, score.Humor, score.Average, score.Explanation)
+		score.Truth, score.Humor, score.Average, score.Average, argumentID, score.Explanation)
 
 	if err != nil {
 		logging.Error("Failed to save score", map[string]interface{}{
@@ -193,6 +371,152 @@ func (d *Database) SaveScore(argumentID int64, debateID string, score *scoring.A
 	return nil
 }
 
+// UpdateArgument replaces the content of an argument owned by userID
+func (d *Database) UpdateArgument(id int64, userID, content string) error {
+	logging.LogDatabaseEvent("UPDATE", "arguments", map[string]interface{}{
+		"argument_id":    id,
+		"user_id":        userID,
+		"content_length": len(content),
+	})
+
+	if err := d.checkArgumentOwner(d.db, id, userID); err != nil {
+		return err
+	}
+
+	_, err := d.db.Exec(`UPDATE arguments SET content = ? WHERE id = ? AND player_id = ?`, content, id, userID)
+	if err != nil {
+		logging.Error("Failed to update argument", map[string]interface{}{
+			"error":       err,
+			"argument_id": id,
+		})
+		return fmt.Errorf("failed to update argument: %v", err)
+	}
+
+	return nil
+}
+
+// UpdateScore replaces the LLM score of an argument, keeping its accumulated vote impact
+func (d *Database) UpdateScore(argumentID int64, score *scoring.ArgumentScore) error {
+	logging.LogDatabaseEvent("UPDATE", "scores", map[string]interface{}{
+		"argument_id": argumentID,
+		"average":     score.Average,
+	})
+
+	// The cached critique explained the old score, so it goes too
+	query := `UPDATE scores SET strength = ?, relevance = ?, logic = ?, truth = ?, humor = ?, llm_average = ?,
+             average = ` + d.blend().averageSQL("?", "?") + `, explanation = ?, critique = NULL
+             WHERE argument_id = ?`
+
+	result, err := d.db.Exec(query, score.Strength, score.Relevance, score.Logic, score.Truth, score.Humor,
+		score.Average, score.Average, argumentID, score.Explanation, argumentID)
+	if err != nil {
+		return fmt.Errorf("failed to update score for argument %d: %v", argumentID, err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("no score found for argument %d", argumentID)
+	}
+
+	return nil
+}
+
+// GetScoreCritique returns the cached detailed critique of an argument's score, nil if none has been generated
+func (d *Database) GetScoreCritique(argumentID int64) (*scoring.ScoreCritique, error) {
+	var data sql.NullString
+	err := d.db.QueryRow(`SELECT critique FROM scores WHERE argument_id = ?`, argumentID).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("no score found for argument %d", argumentID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get score critique: %v", err)
+	}
+	if !data.Valid || data.String == "" {
+		return nil, nil
+	}
+
+	var critique scoring.ScoreCritique
+	if err := json.Unmarshal([]byte(data.String), &critique); err != nil {
+		return nil, fmt.Errorf("failed to decode score critique: %v", err)
+	}
+	return &critique, nil
+}
+
+// SaveScoreCritique caches the detailed critique of an argument's score until the argument is re-scored
+func (d *Database) SaveScoreCritique(argumentID int64, critique *scoring.ScoreCritique) error {
+	data, err := json.Marshal(critique)
+	if err != nil {
+		return fmt.Errorf("failed to encode score critique: %v", err)
+	}
+
+	result, err := d.db.Exec(`UPDATE scores SET critique = ? WHERE argument_id = ?`, string(data), argumentID)
+	if err != nil {
+		return fmt.Errorf("failed to save score critique: %v", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("no score found for argument %d", argumentID)
+	}
+	return nil
+}
+
+// DeleteArgument removes an argument owned by userID together with its score and votes
+func (d *Database) DeleteArgument(id int64, userID string) error {
+	logging.LogDatabaseEvent("DELETE", "arguments", map[string]interface{}{
+		"argument_id": id,
+		"user_id":     userID,
+	})
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := d.checkArgumentOwner(tx, id, userID); err != nil {
+		return err
+	}
+
+	// Foreign keys aren't enforced by SQLite by default, so remove dependents explicitly
+	if _, err := tx.Exec(`DELETE FROM votes WHERE argument_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete votes: %v", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM scores WHERE argument_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete score: %v", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM arguments WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete argument: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// checkArgumentOwner verifies that the argument exists and was submitted by userID
+func (d *Database) checkArgumentOwner(q queryRower, id int64, userID string) error {
+	var playerID string
+	err := q.QueryRow(`SELECT player_id FROM arguments WHERE id = ?`, id).Scan(&playerID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("argument %d not found", id)
+	} else if err != nil {
+		return fmt.Errorf("failed to get argument: %v", err)
+	}
+
+	if playerID != userID {
+		return ErrNotArgumentOwner
+	}
+
+	return nil
+}
+
 // GetArgumentWithScore retrieves an argument and its score by ID
 func (d *Database) GetArgumentWithScore(id int64) (*Argument, error) {
 	query := `
@@ -214,7 +538,7 @@ func (d *Database) GetArgumentWithScore(id int64) (*Argument, error) {
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("argument %d not found", id)
+		return nil, fmt.Errorf("argument %d not found: %w", id, ErrArgumentNotFound)
 	} else if err != nil {
 		return nil, fmt.Errorf("failed to get argument: %v", err)
 	}
@@ -318,6 +642,156 @@ func (d *Database) GetLeaderboard(debateID string, limit int) ([]*Argument, erro
 	return arguments, nil
 }
 
+// ArgumentFilter represents filtering and pagination options for a debate's arguments
+type ArgumentFilter struct {
+	SortBy  string // One of the keys in argumentSortColumns; empty means created_at
+	SortDir string
+	Offset  int
+	Limit   int
+}
+
+// argumentSortColumns maps the supported ArgumentFilter.SortBy values to their columns
+var argumentSortColumns = map[string]string{
+	"created_at": "a.created_at",
+	"score":      "s.average",
+	"vote_score": "a.vote_score",
+}
+
+// IsValidArgumentSort reports whether sortBy is a supported ArgumentFilter.SortBy value
+func IsValidArgumentSort(sortBy string) bool {
+	_, ok := argumentSortColumns[sortBy]
+	return sortBy == "" || ok
+}
+
+// GetArgumentsByDebate retrieves a page of a debate's arguments with their scores and vote counts
+func (d *Database) GetArgumentsByDebate(debateID string, filter ArgumentFilter) ([]*Argument, int, error) {
+	sortColumn, ok := argumentSortColumns[filter.SortBy]
+	if !ok {
+		sortColumn = argumentSortColumns["created_at"]
+	}
+	direction := "ASC"
+	if filter.SortDir == "desc" {
+		direction = "DESC"
+	}
+
+	var total int
+	err := d.db.QueryRow(`SELECT COUNT(*) FROM arguments WHERE debate_id = ?`, debateID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count arguments for debate %s: %v", debateID, err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT a.id, a.player_id, a.topic, a.content, a.side, a.debate_id, a.created_at,
+			   s.id, s.strength, s.relevance, s.logic, s.truth, s.humor, s.average, s.explanation,
+			   COALESCE(a.upvotes, 0), COALESCE(a.downvotes, 0), COALESCE(a.vote_score, 0.0),
+			   a.parent_id, a.parent_message_id
+		FROM arguments a
+		LEFT JOIN scores s ON a.id = s.argument_id
+		WHERE a.debate_id = ?
+		ORDER BY %s %s, a.id ASC
+		LIMIT ? OFFSET ?`, sortColumn, direction)
+
+	rows, err := d.db.Query(query, debateID, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query arguments for debate %s: %v", debateID, err)
+	}
+	defer rows.Close()
+
+	arguments := make([]*Argument, 0)
+	for rows.Next() {
+		arg, err := scanScoredArgument(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan debate argument row: %v", err)
+		}
+		arguments = append(arguments, arg)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating debate argument rows: %v", err)
+	}
+
+	return arguments, total, nil
+}
+
+// GetArgumentsByUser gets every argument a signed-in user has submitted, by their user ID rather than the display
+// name they argued under, oldest first, with scores where available
+func (d *Database) GetArgumentsByUser(userID string) ([]*Argument, error) {
+	query := `
+		SELECT a.id, a.player_id, a.topic, a.content, a.side, a.debate_id, a.created_at,
+			   s.id, s.strength, s.relevance, s.logic, s.truth, s.humor, s.average, s.explanation,
+			   COALESCE(a.upvotes, 0), COALESCE(a.downvotes, 0), COALESCE(a.vote_score, 0.0),
+			   a.parent_id, a.parent_message_id
+		FROM arguments a
+		LEFT JOIN scores s ON a.id = s.argument_id
+		WHERE a.user_id = ?
+		ORDER BY a.created_at ASC, a.id ASC`
+
+	rows, err := d.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query arguments for user %s: %v", userID, err)
+	}
+	defer rows.Close()
+
+	arguments := make([]*Argument, 0)
+	for rows.Next() {
+		arg, err := scanScoredArgument(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user argument row: %v", err)
+		}
+		arguments = append(arguments, arg)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user argument rows: %v", err)
+	}
+
+	return arguments, nil
+}
+
+// scanScoredArgument scans an argument row that was LEFT JOINed to its score and vote totals, followed by what
+// it replies to
+func scanScoredArgument(rows *sql.Rows) (*Argument, error) {
+	arg := &Argument{}
+	var debateIDStr, explanation sql.NullString
+	var scoreID, strength, relevance, logic, truth, humor sql.NullInt64
+	var average sql.NullFloat64
+	var parentID, parentMessageID sql.NullInt64
+
+	err := rows.Scan(
+		&arg.ID, &arg.PlayerID, &arg.Topic, &arg.Content, &arg.Side, &debateIDStr, &arg.CreatedAt,
+		&scoreID, &strength, &relevance, &logic, &truth, &humor, &average, &explanation,
+		&arg.Upvotes, &arg.Downvotes, &arg.VoteScore,
+		&parentID, &parentMessageID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if debateIDStr.Valid {
+		arg.DebateID = &debateIDStr.String
+	}
+	if parentID.Valid {
+		arg.ParentID = &parentID.Int64
+	}
+	if parentMessageID.Valid {
+		arg.ParentMessageID = &parentMessageID.Int64
+	}
+
+	// Arguments that haven't been scored yet have no score row
+	if scoreID.Valid {
+		arg.Score = &scoring.ArgumentScore{
+			Strength:    int(strength.Int64),
+			Relevance:   int(relevance.Int64),
+			Logic:       int(logic.Int64),
+			Truth:       int(truth.Int64),
+			Humor:       int(humor.Int64),
+			Average:     average.Float64,
+			Explanation: explanation.String,
+		}
+	}
+	return arg, nil
+}
+
 // SubmitVote submits a vote for an argument and updates the argument's score
 func (d *Database) SubmitVote(userID string, argumentID int64, debateID string, voteType string) error {
 	// Validate vote type
@@ -345,36 +819,26 @@ func (d *Database) SubmitVote(userID string, argumentID int64, debateID string,
 		return fmt.Errorf("failed to insert vote: %v", err)
 	}
 
-	// Update argument vote counts
+	// Update argument vote counts. Each vote is weighted by the voter's reputation tier,
+	// and the total is bounded by the score blend (see reputation.go and score_blend.go).
+	blend := d.blend()
 	updateCountsQuery := `
 		UPDATE arguments SET
 			upvotes = (SELECT COUNT(*) FROM votes WHERE argument_id = ? AND vote_type = 'upvote'),
 			downvotes = (SELECT COUNT(*) FROM votes WHERE argument_id = ? AND vote_type = 'downvote'),
-			vote_score = CASE 
-				WHEN (SELECT COUNT(*) FROM votes WHERE argument_id = ? AND vote_type = 'upvote') > 
-					 (SELECT COUNT(*) FROM votes WHERE argument_id = ? AND vote_type = 'downvote') 
-				THEN ((SELECT COUNT(*) FROM votes WHERE argument_id = ? AND vote_type = 'upvote') - 
-					  (SELECT COUNT(*) FROM votes WHERE argument_id = ? AND vote_type = 'downvote')) * 0.2
-				ELSE -((SELECT COUNT(*) FROM votes WHERE argument_id = ? AND vote_type = 'downvote') - 
-					   (SELECT COUNT(*) FROM votes WHERE argument_id = ? AND vote_type = 'upvote')) * 0.2
-			END
+			vote_score = ` + blend.voteScoreSQL("?") + `
 		WHERE id = ?`
 
-	_, err = tx.Exec(updateCountsQuery, argumentID, argumentID, argumentID, argumentID, argumentID, argumentID, argumentID, argumentID, argumentID)
+	_, err = tx.Exec(updateCountsQuery, argumentID, argumentID, argumentID, argumentID)
 	if err != nil {
 		return fmt.Errorf("failed to update argument vote counts: %v", err)
 	}
 
-	// Update the score in the scores table as well
+	// Reblend the argument's LLM score with its new vote_score. Scores saved before the LLM score was kept
+	// separately fall back to the mean of their subscores.
 	updateScoreQuery := `
 		UPDATE scores SET
-			average = (
-				SELECT 
-					(s.strength + s.relevance + s.logic + s.truth + s.humor) / 5.0 + COALESCE(a.vote_score, 0)
-				FROM scores s
-				JOIN arguments a ON s.argument_id = a.id
-				WHERE s.argument_id = ?
-			)
+			average = ` + blend.averageSQL("COALESCE(llm_average, (strength + relevance + logic + truth + humor) / 5.0)", "?") + `
 		WHERE argument_id = ?`
 
 	_, err = tx.Exec(updateScoreQuery, argumentID, argumentID)
@@ -435,6 +899,67 @@ func (d *Database) GetUserVoteForArgument(userID string, argumentID int64) (stri
 	return voteType, nil
 }
 
+// GetUserVotesForDebate gets the user's votes on a debate's arguments in one query, as a map of argument ID to
+// vote type. The map is empty if the user hasn't voted in the debate.
+func (d *Database) GetUserVotesForDebate(userID, debateID string) (map[int64]string, error) {
+	rows, err := d.db.Query(`SELECT argument_id, vote_type FROM votes WHERE user_id = ? AND debate_id = ?`, userID, debateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user votes for debate: %v", err)
+	}
+	defer rows.Close()
+
+	votes := make(map[int64]string)
+	for rows.Next() {
+		var argumentID int64
+		var voteType string
+		if err := rows.Scan(&argumentID, &voteType); err != nil {
+			return nil, fmt.Errorf("failed to scan vote row: %v", err)
+		}
+		votes[argumentID] = voteType
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating vote rows: %v", err)
+	}
+
+	return votes, nil
+}
+
+// UserVote is a single vote a user has cast on an argument
+type UserVote struct {
+	ArgumentID int64     `json:"argument_id"`
+	DebateID   string    `json:"debate_id"`
+	VoteType   string    `json:"vote_type"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// GetVotesByUser gets every vote a user has cast, oldest first
+func (d *Database) GetVotesByUser(userID string) ([]*UserVote, error) {
+	rows, err := d.db.Query(`
+		SELECT argument_id, debate_id, vote_type, created_at
+		FROM votes
+		WHERE user_id = ?
+		ORDER BY created_at ASC, id ASC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query votes for user %s: %v", userID, err)
+	}
+	defer rows.Close()
+
+	votes := make([]*UserVote, 0)
+	for rows.Next() {
+		vote := &UserVote{}
+		if err := rows.Scan(&vote.ArgumentID, &vote.DebateID, &vote.VoteType, &vote.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan vote row: %v", err)
+		}
+		votes = append(votes, vote)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating vote rows: %v", err)
+	}
+
+	return votes, nil
+}
+
 // CanUserVote checks if a user can vote on an argument
 func (d *Database) CanUserVote(userID string, argumentID int64, debateID string) (bool, string, error) {
 	// Check if user has paid for a comment in this debate
@@ -480,7 +1005,10 @@ func (d *Database) RunMigrations() error {
 // --- Debate Management Functions ---
 
 // CreateDebate adds a new debate session to the database
-func (d *Database) CreateDebate(id, topic, status, agent1Name, agent2Name string) error {
+func (d *Database) CreateDebate(id, topic string, status types.DebateStatus, agent1Name, agent2Name string) error {
+	if !status.IsValid() {
+		return fmt.Errorf("invalid debate status: %s", status)
+	}
 	query := `INSERT INTO debates (id, topic, status, agent1_name, agent2_name) VALUES (?, ?, ?, ?, ?)`
 	_, err := d.db.Exec(query, id, topic, status, agent1Name, agent2Name)
 	if err != nil {
@@ -489,8 +1017,106 @@ func (d *Database) CreateDebate(id, topic, status, agent1Name, agent2Name string
 	return nil
 }
 
+// SaveDebateSettings stores the optional settings of an existing debate
+func (d *Database) SaveDebateSettings(id string, settings DebateSettings) error {
+	if settings.Visibility == "" {
+		settings.Visibility = DebateVisibilityPublic
+	}
+	if !IsValidDebateVisibility(settings.Visibility) {
+		return fmt.Errorf("invalid debate visibility: %s", settings.Visibility)
+	}
+
+	var createdBy sql.NullString
+	if settings.CreatedBy != "" {
+		createdBy = sql.NullString{String: settings.CreatedBy, Valid: true}
+	}
+
+	// Stored in UTC so GetDueScheduledDebates' text comparison holds whatever zone the time was given in
+	var scheduledAt sql.NullTime
+	if settings.ScheduledAt != nil {
+		scheduledAt = sql.NullTime{Time: settings.ScheduledAt.UTC(), Valid: true}
+	}
+
+	var firstSpeaker sql.NullString
+	if settings.FirstSpeaker != "" {
+		firstSpeaker = sql.NullString{String: settings.FirstSpeaker, Valid: true}
+	}
+
+	var seed sql.NullInt64
+	if settings.Seed != 0 {
+		seed = sql.NullInt64{Int64: settings.Seed, Valid: true}
+	}
+
+	var parentDebateID sql.NullString
+	var forkedFromTurn sql.NullInt64
+	if settings.ParentDebateID != "" {
+		parentDebateID = sql.NullString{String: settings.ParentDebateID, Valid: true}
+		forkedFromTurn = sql.NullInt64{Int64: int64(settings.ForkedFromTurn), Valid: true}
+	}
+
+	var config sql.NullString
+	if settings.Config != "" {
+		config = sql.NullString{String: settings.Config, Valid: true}
+	}
+
+	query := `UPDATE debates SET visibility = ?, created_by = ?, scheduled_at = ?, first_speaker = ?, first_speaker_coin_flip = ?, seed = ?,
+		parent_debate_id = ?, forked_from_turn = ?, config = ? WHERE id = ?`
+	result, err := d.db.Exec(query, settings.Visibility, createdBy, scheduledAt, firstSpeaker, settings.FirstSpeakerCoinFlip, seed,
+		parentDebateID, forkedFromTurn, config, id)
+	if err != nil {
+		return fmt.Errorf("failed to save settings for debate %s: %v", id, err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("debate %s not found for settings update", id)
+	}
+	return nil
+}
+
+// SetDebateFeatured features a debate on the homepage, or unfeatures it. Featuring an already featured debate
+// moves it to the front, since featured debates are listed newest first.
+func (d *Database) SetDebateFeatured(id string, featured bool) error {
+	query := `UPDATE debates SET featured = 0, featured_at = NULL WHERE id = ?`
+	if featured {
+		query = `UPDATE debates SET featured = 1, featured_at = CURRENT_TIMESTAMP WHERE id = ?`
+	}
+	result, err := d.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to update featured flag for debate %s: %v", id, err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("debate %s not found for featured update", id)
+	}
+	return nil
+}
+
+// AddDebateParticipant adds a user to a private debate's allowlist
+func (d *Database) AddDebateParticipant(debateID, userID, invitedBy string) error {
+	query := `INSERT INTO debate_participants (debate_id, user_id, invited_by) VALUES (?, ?, ?)
+		ON CONFLICT(debate_id, user_id) DO NOTHING`
+	_, err := d.db.Exec(query, debateID, userID, invitedBy)
+	if err != nil {
+		return fmt.Errorf("failed to add participant to debate %s: %v", debateID, err)
+	}
+	return nil
+}
+
+// IsDebateParticipant checks whether a user is on a debate's allowlist
+func (d *Database) IsDebateParticipant(debateID, userID string) (bool, error) {
+	query := `SELECT COUNT(*) FROM debate_participants WHERE debate_id = ? AND user_id = ?`
+	var count int
+	if err := d.db.QueryRow(query, debateID, userID).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check participant for debate %s: %v", debateID, err)
+	}
+	return count > 0, nil
+}
+
 // UpdateDebateStatus updates the status of a specific debate
-func (d *Database) UpdateDebateStatus(id, status string) error {
+func (d *Database) UpdateDebateStatus(id string, status types.DebateStatus) error {
+	if !status.IsValid() {
+		return fmt.Errorf("invalid debate status: %s", status)
+	}
 	query := `UPDATE debates SET status = ? WHERE id = ?`
 	result, err := d.db.Exec(query, status, id)
 	if err != nil {
@@ -503,30 +1129,93 @@ func (d *Database) UpdateDebateStatus(id, status string) error {
 	return nil
 }
 
-// UpdateDebateEnd marks a debate as finished, setting the end time and winner
-func (d *Database) UpdateDebateEnd(id, status, winner string) error {
-	query := `UPDATE debates SET status = ?, ended_at = CURRENT_TIMESTAMP, winner = ? WHERE id = ?`
-	result, err := d.db.Exec(query, status, winner, id)
+// UpdateDebateEnd marks a debate as finished, setting the end time, winner and the reason it ended.
+// A debate that has already finished keeps its result and ErrDebateAlreadyFinished is returned, so only one of
+// several callers ending a debate at once goes on to announce it.
+func (d *Database) UpdateDebateEnd(id string, status types.DebateStatus, winner, reason string) error {
+	if !status.IsValid() {
+		return fmt.Errorf("invalid debate status: %s", status)
+	}
+	query := `UPDATE debates SET status = ?, ended_at = CURRENT_TIMESTAMP, winner = ?, end_reason = ? WHERE id = ? AND status != ?`
+	result, err := d.db.Exec(query, status, winner, reason, id, types.DebateStatusFinished)
 	if err != nil {
 		return fmt.Errorf("failed to end debate %s: %v", id, err)
 	}
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
+		var exists bool
+		if err := d.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM debates WHERE id = ?)`, id).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to end debate %s: %v", id, err)
+		}
+		if exists {
+			return ErrDebateAlreadyFinished
+		}
 		return fmt.Errorf("debate %s not found for ending", id)
 	}
 	return nil
 }
 
+// SaveDebateDeadline records when a debate's loop times out, so a debate restored after a restart keeps it
+func (d *Database) SaveDebateDeadline(id string, deadline time.Time) error {
+	result, err := d.db.Exec(`UPDATE debates SET deadline = ? WHERE id = ?`, deadline.UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to save deadline for debate %s: %v", id, err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("debate %s not found for deadline update", id)
+	}
+	return nil
+}
+
+// UpdateDebateActiveTime records how long a debate was actually running, excluding pauses
+func (d *Database) UpdateDebateActiveTime(id string, activeSeconds int64) error {
+	query := `UPDATE debates SET active_seconds = ? WHERE id = ?`
+	result, err := d.db.Exec(query, activeSeconds, id)
+	if err != nil {
+		return fmt.Errorf("failed to update active time for debate %s: %v", id, err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("debate %s not found for active time update", id)
+	}
+	return nil
+}
+
+// GetAverageDebateDuration returns the average wall-clock duration in seconds of ended debates matching the filter
+func (d *Database) GetAverageDebateDuration(filter DebateFilter) (float64, error) {
+	whereClause, args := buildDebateWhereClause(filter)
+	if whereClause == "" {
+		whereClause = "WHERE ended_at IS NOT NULL"
+	} else {
+		whereClause += " AND ended_at IS NOT NULL"
+	}
+
+	query := fmt.Sprintf(
+		`SELECT COALESCE(AVG((julianday(ended_at) - julianday(created_at)) * 86400.0), 0) FROM debates %s`,
+		whereClause,
+	)
+
+	var average float64
+	if err := d.db.QueryRow(query, args...).Scan(&average); err != nil {
+		return 0, fmt.Errorf("failed to get average debate duration: %v", err)
+	}
+	return average, nil
+}
+
 // GetDebate retrieves a specific debate by its ID
 func (d *Database) GetDebate(id string) (*Debate, error) {
-	query := `SELECT id, topic, status, agent1_name, agent2_name, created_at, ended_at, winner FROM debates WHERE id = ?`
+	query := `SELECT id, topic, status, agent1_name, agent2_name, created_at, ended_at, winner, visibility, created_by, active_seconds, scheduled_at, end_reason, first_speaker, first_speaker_coin_flip, featured, featured_at, seed, parent_debate_id, forked_from_turn, config, deadline FROM debates WHERE id = ?`
 	var debate Debate
-	var endedAt sql.NullTime
-	var winner sql.NullString
+	var endedAt, scheduledAt, featuredAt, deadline sql.NullTime
+	var winner, createdBy, endReason, firstSpeaker, parentDebateID, config sql.NullString
+	var activeSeconds, seed, forkedFromTurn sql.NullInt64
 
 	err := d.db.QueryRow(query, id).Scan(
 		&debate.ID, &debate.Topic, &debate.Status, &debate.Agent1Name, &debate.Agent2Name,
-		&debate.CreatedAt, &endedAt, &winner,
+		&debate.CreatedAt, &endedAt, &winner, &debate.Visibility, &createdBy, &activeSeconds, &scheduledAt, &endReason,
+		&firstSpeaker, &debate.FirstSpeakerCoinFlip, &debate.Featured, &featuredAt, &seed, &parentDebateID, &forkedFromTurn,
+		&config, &deadline,
 	)
 
 	if err == sql.ErrNoRows {
@@ -541,45 +1230,88 @@ func (d *Database) GetDebate(id string) (*Debate, error) {
 	if winner.Valid {
 		debate.Winner = &winner.String
 	}
+	if createdBy.Valid {
+		debate.CreatedBy = &createdBy.String
+	}
+	if activeSeconds.Valid {
+		debate.ActiveSeconds = &activeSeconds.Int64
+	}
+	if scheduledAt.Valid {
+		debate.ScheduledAt = &scheduledAt.Time
+	}
+	if endReason.Valid {
+		debate.EndReason = &endReason.String
+	}
+	if firstSpeaker.Valid {
+		debate.FirstSpeaker = &firstSpeaker.String
+	}
+	if featuredAt.Valid {
+		debate.FeaturedAt = &featuredAt.Time
+	}
+	if seed.Valid {
+		debate.Seed = &seed.Int64
+	}
+	if parentDebateID.Valid && forkedFromTurn.Valid {
+		turn := int(forkedFromTurn.Int64)
+		debate.ParentDebateID = &parentDebateID.String
+		debate.ForkedFromTurn = &turn
+	}
+	debate.setRestoreState(config, deadline)
+	debate.setDuration()
 
 	return &debate, nil
 }
 
 // DebateFilter contains filter parameters for debates
 type DebateFilter struct {
-	Status  string
-	Search  string
-	SortBy  string
-	SortDir string
-	Offset  int
-	Limit   int
+	Status     string
+	Search     string
+	Visibility string // Restrict to a single visibility level; empty means all
+	Featured   bool   // Only debates featured by an admin
+	SortBy     string
+	SortDir    string
+	Offset     int
+	Limit      int
 }
 
-// ListDebates retrieves debates with pagination and filtering
-func (d *Database) ListDebates(filter DebateFilter) ([]*Debate, int, error) {
-	// Build the WHERE clause based on filters
-	whereClause := ""
+// buildDebateWhereClause builds the WHERE clause and arguments for a debate filter
+func buildDebateWhereClause(filter DebateFilter) (string, []any) {
+	conditions := []string{}
 	args := []any{}
 
 	// Add status filter if provided
 	if filter.Status != "" {
-		whereClause = "WHERE status = ?"
+		conditions = append(conditions, "status = ?")
 		args = append(args, filter.Status)
 	}
 
-	// Add search filter if provided
+	// Add search filter if provided (search in topic)
 	if filter.Search != "" {
-		if whereClause != "" {
-			whereClause += " AND "
-		} else {
-			whereClause = "WHERE "
-		}
-		// Search in topic
-		whereClause += "topic LIKE ?"
-		searchTerm := "%" + filter.Search + "%"
-		args = append(args, searchTerm)
+		conditions = append(conditions, "topic LIKE ?")
+		args = append(args, "%"+filter.Search+"%")
+	}
+
+	// Add visibility filter if provided
+	if filter.Visibility != "" {
+		conditions = append(conditions, "visibility = ?")
+		args = append(args, filter.Visibility)
 	}
 
+	if filter.Featured {
+		conditions = append(conditions, "featured = 1")
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// ListDebates retrieves debates with pagination and filtering
+func (d *Database) ListDebates(filter DebateFilter) ([]*Debate, int, error) {
+	// Build the WHERE clause based on filters
+	whereClause, args := buildDebateWhereClause(filter)
+
 	// Build the ORDER BY clause
 	orderClause := "ORDER BY "
 	if filter.SortBy != "" {
@@ -604,7 +1336,8 @@ func (d *Database) ListDebates(filter DebateFilter) ([]*Debate, int, error) {
 
 	// Build the main query with pagination
 	query := fmt.Sprintf(
-		`SELECT id, topic, status, agent1_name, agent2_name, created_at, ended_at, winner
+		`SELECT id, topic, status, agent1_name, agent2_name, created_at, ended_at, winner, visibility, created_by, active_seconds, scheduled_at, end_reason,
+			first_speaker, first_speaker_coin_flip, featured, featured_at
 		FROM debates %s %s LIMIT ? OFFSET ?`,
 		whereClause, orderClause,
 	)
@@ -622,10 +1355,13 @@ func (d *Database) ListDebates(filter DebateFilter) ([]*Debate, int, error) {
 	var debates []*Debate
 	for rows.Next() {
 		var debate Debate
-		var endedAt, winner sql.NullString
+		var endedAt, winner, createdBy, endReason, firstSpeaker sql.NullString
+		var activeSeconds sql.NullInt64
+		var scheduledAt, featuredAt sql.NullTime
 		err := rows.Scan(
 			&debate.ID, &debate.Topic, &debate.Status, &debate.Agent1Name, &debate.Agent2Name,
-			&debate.CreatedAt, &endedAt, &winner,
+			&debate.CreatedAt, &endedAt, &winner, &debate.Visibility, &createdBy, &activeSeconds, &scheduledAt, &endReason,
+			&firstSpeaker, &debate.FirstSpeakerCoinFlip, &debate.Featured, &featuredAt,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan debate row: %v", err)
@@ -641,6 +1377,25 @@ func (d *Database) ListDebates(filter DebateFilter) ([]*Debate, int, error) {
 		if winner.Valid {
 			debate.Winner = &winner.String
 		}
+		if createdBy.Valid {
+			debate.CreatedBy = &createdBy.String
+		}
+		if activeSeconds.Valid {
+			debate.ActiveSeconds = &activeSeconds.Int64
+		}
+		if scheduledAt.Valid {
+			debate.ScheduledAt = &scheduledAt.Time
+		}
+		if endReason.Valid {
+			debate.EndReason = &endReason.String
+		}
+		if firstSpeaker.Valid {
+			debate.FirstSpeaker = &firstSpeaker.String
+		}
+		if featuredAt.Valid {
+			debate.FeaturedAt = &featuredAt.Time
+		}
+		debate.setDuration()
 
 		debates = append(debates, &debate)
 	}
@@ -648,13 +1403,16 @@ func (d *Database) ListDebates(filter DebateFilter) ([]*Debate, int, error) {
 	return debates, total, nil
 }
 
-// ListActiveDebates retrieves debates that are currently 'waiting' or 'active'
+// ListActiveDebates retrieves debates that are currently 'scheduled', 'waiting', 'active' or 'paused'
 func (d *Database) ListActiveDebates() ([]*Debate, error) {
 	// Note: We could use the DebateFilter here, but for now we're using a custom query
 	// that specifically looks for both 'waiting' and 'active' statuses
 
 	// Custom query for active debates (includes 'waiting' status)
-	query := `SELECT id, topic, status, agent1_name, agent2_name, created_at FROM debates WHERE status = 'waiting' OR status = 'active' ORDER BY created_at DESC`
+	// Includes every visibility level since the debate manager reloads these on startup;
+	// public listings must filter out unlisted and private debates themselves
+	query := `SELECT id, topic, status, agent1_name, agent2_name, created_at, visibility, scheduled_at, config, deadline FROM debates
+		WHERE status IN ('scheduled', 'waiting', 'active', 'paused') ORDER BY created_at DESC`
 	rows, err := d.db.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list active debates: %v", err)
@@ -664,18 +1422,223 @@ func (d *Database) ListActiveDebates() ([]*Debate, error) {
 	var debates []*Debate
 	for rows.Next() {
 		var debate Debate
+		var scheduledAt, deadline sql.NullTime
+		var config sql.NullString
 		err := rows.Scan(
-			&debate.ID, &debate.Topic, &debate.Status, &debate.Agent1Name, &debate.Agent2Name, &debate.CreatedAt,
+			&debate.ID, &debate.Topic, &debate.Status, &debate.Agent1Name, &debate.Agent2Name, &debate.CreatedAt, &debate.Visibility, &scheduledAt,
+			&config, &deadline,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan active debate row: %v", err)
 		}
+		if scheduledAt.Valid {
+			debate.ScheduledAt = &scheduledAt.Time
+		}
+		debate.setRestoreState(config, deadline)
 		debates = append(debates, &debate)
 	}
 
 	return debates, nil
 }
 
+// GetDueScheduledDebates retrieves scheduled debates whose start time is at or before now
+func (d *Database) GetDueScheduledDebates(now time.Time) ([]*Debate, error) {
+	query := `SELECT id, topic, agent1_name, agent2_name, visibility, scheduled_at FROM debates
+		WHERE status = 'scheduled' AND scheduled_at <= ? ORDER BY scheduled_at ASC`
+	rows, err := d.db.Query(query, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due scheduled debates: %v", err)
+	}
+	defer rows.Close()
+
+	var debates []*Debate
+	for rows.Next() {
+		debate := &Debate{Status: types.DebateStatusScheduled}
+		var scheduledAt sql.NullTime
+		if err := rows.Scan(&debate.ID, &debate.Topic, &debate.Agent1Name, &debate.Agent2Name, &debate.Visibility, &scheduledAt); err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled debate row: %v", err)
+		}
+		if scheduledAt.Valid {
+			debate.ScheduledAt = &scheduledAt.Time
+		}
+		debates = append(debates, debate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scheduled debate rows: %v", err)
+	}
+
+	return debates, nil
+}
+
+// ChatMessage represents an unscored chat message sent during a debate
+type ChatMessage struct {
+	ID        int64     `json:"id"`
+	DebateID  string    `json:"debate_id"`
+	PlayerID  string    `json:"player_id"`
+	Username  string    `json:"username"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SaveChatMessage stores a chat message for a debate
+func (d *Database) SaveChatMessage(debateID, playerID, username, message string) (int64, error) {
+	query := `INSERT INTO chat_messages (debate_id, player_id, username, message) VALUES (?, ?, ?, ?)`
+	result, err := d.db.Exec(query, debateID, playerID, username, message)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save chat message for debate %s: %v", debateID, err)
+	}
+
+	id, _ := result.LastInsertId()
+	return id, nil
+}
+
+// GetChatMessages retrieves the most recent chat messages for a debate in chronological order
+func (d *Database) GetChatMessages(debateID string, limit int) ([]*ChatMessage, error) {
+	query := `
+		SELECT id, debate_id, player_id, username, message, created_at FROM (
+			SELECT id, debate_id, player_id, username, message, created_at
+			FROM chat_messages
+			WHERE debate_id = ?
+			ORDER BY created_at DESC, id DESC
+			LIMIT ?
+		) ORDER BY created_at ASC, id ASC`
+
+	rows, err := d.db.Query(query, debateID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chat messages for debate %s: %v", debateID, err)
+	}
+	defer rows.Close()
+
+	var messages []*ChatMessage
+	for rows.Next() {
+		msg := &ChatMessage{}
+		if err := rows.Scan(&msg.ID, &msg.DebateID, &msg.PlayerID, &msg.Username, &msg.Message, &msg.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan chat message row: %v", err)
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// DebateMessage is a persisted entry of a debate's transcript
+type DebateMessage struct {
+	ID          int64     `json:"id"`
+	DebateID    string    `json:"debate_id"`
+	Speaker     string    `json:"speaker"`
+	Message     string    `json:"message"`
+	IsPlayer    bool      `json:"is_player"`
+	Score       *float64  `json:"score,omitempty"`
+	AudioURL    string    `json:"audio_url,omitempty"`
+	Agent1Delta int       `json:"agent1_delta"` // HP the entry moved for Agent1, negative when it lost HP
+	Agent2Delta int       `json:"agent2_delta"` // HP the entry moved for Agent2, negative when it lost HP
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SaveDebateMessage stores an entry of a debate's transcript
+func (d *Database) SaveDebateMessage(msg *DebateMessage) (int64, error) {
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now()
+	}
+
+	query := `INSERT INTO debate_messages (debate_id, speaker, message, is_player, score, audio_url, agent1_delta, agent2_delta, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := d.db.Exec(query, msg.DebateID, msg.Speaker, msg.Message, msg.IsPlayer, msg.Score, msg.AudioURL, msg.Agent1Delta, msg.Agent2Delta, msg.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save message for debate %s: %v", msg.DebateID, err)
+	}
+
+	id, _ := result.LastInsertId()
+	msg.ID = id
+	return id, nil
+}
+
+// UpdateDebateMessage replaces the text, score, audio and HP deltas of the stored transcript entry with msg.ID,
+// e.g. after a turn is regenerated
+func (d *Database) UpdateDebateMessage(msg *DebateMessage) error {
+	query := `UPDATE debate_messages SET message = ?, score = ?, audio_url = ?, agent1_delta = ?, agent2_delta = ? WHERE id = ?`
+	result, err := d.db.Exec(query, msg.Message, msg.Score, msg.AudioURL, msg.Agent1Delta, msg.Agent2Delta, msg.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update debate message %d: %v", msg.ID, err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("debate message %d not found", msg.ID)
+	}
+	return nil
+}
+
+// GetDebateMessages retrieves a debate's full transcript in chronological order
+func (d *Database) GetDebateMessages(debateID string) ([]*DebateMessage, error) {
+	query := `
+		SELECT id, debate_id, speaker, message, is_player, score, audio_url, agent1_delta, agent2_delta, created_at
+		FROM debate_messages
+		WHERE debate_id = ?
+		ORDER BY created_at ASC, id ASC`
+
+	rows, err := d.db.Query(query, debateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query messages for debate %s: %v", debateID, err)
+	}
+	defer rows.Close()
+
+	var messages []*DebateMessage
+	for rows.Next() {
+		msg := &DebateMessage{}
+		var score sql.NullFloat64
+		if err := rows.Scan(&msg.ID, &msg.DebateID, &msg.Speaker, &msg.Message, &msg.IsPlayer, &score, &msg.AudioURL, &msg.Agent1Delta, &msg.Agent2Delta, &msg.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan debate message row: %v", err)
+		}
+		if score.Valid {
+			msg.Score = &score.Float64
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// GetDebateMessagesBefore retrieves up to limit of a debate's transcript entries older than the message with ID
+// beforeID, or the latest ones when beforeID is 0, in chronological order. It also reports whether older entries
+// remain, so callers can page back through a long transcript using the oldest returned ID as the next cursor.
+func (d *Database) GetDebateMessagesBefore(debateID string, beforeID int64, limit int) ([]*DebateMessage, bool, error) {
+	query := `
+		SELECT id, debate_id, speaker, message, is_player, score, audio_url, agent1_delta, agent2_delta, created_at
+		FROM debate_messages
+		WHERE debate_id = ? AND (? = 0 OR id < ?)
+		ORDER BY id DESC
+		LIMIT ?`
+
+	// Fetch one extra row to find out whether there is an older page
+	rows, err := d.db.Query(query, debateID, beforeID, beforeID, limit+1)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to query messages for debate %s: %v", debateID, err)
+	}
+	defer rows.Close()
+
+	var messages []*DebateMessage
+	for rows.Next() {
+		msg := &DebateMessage{}
+		var score sql.NullFloat64
+		if err := rows.Scan(&msg.ID, &msg.DebateID, &msg.Speaker, &msg.Message, &msg.IsPlayer, &score, &msg.AudioURL, &msg.Agent1Delta, &msg.Agent2Delta, &msg.CreatedAt); err != nil {
+			return nil, false, fmt.Errorf("failed to scan debate message row: %v", err)
+		}
+		if score.Valid {
+			msg.Score = &score.Float64
+		}
+		messages = append(messages, msg)
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+	// Newest first from the query, chronological for the caller
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, hasMore, nil
+}
+
 // TopicFilter contains filter parameters for topics
 type TopicFilter struct {
 	Category string
@@ -837,3 +1800,59 @@ func (d *Database) GetTopic(id int) (*Topic, error) {
 
 	return &topic, nil
 }
+
+// CreateTopics inserts topics in a single transaction, setting each inserted topic's ID. A topic whose title
+// matches an existing topic or an earlier one in the batch, ignoring case and surrounding spaces, is skipped;
+// the indexes of skipped topics are returned. On error nothing is inserted.
+func (d *Database) CreateTopics(topics []*Topic) ([]int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	skipped := make([]int, 0)
+	seen := make(map[string]bool, len(topics))
+	for i, topic := range topics {
+		title := strings.ToLower(strings.TrimSpace(topic.Title))
+		if seen[title] {
+			skipped = append(skipped, i)
+			continue
+		}
+		seen[title] = true
+
+		var exists bool
+		err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM topics WHERE LOWER(TRIM(title)) = ?)`, title).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for duplicate topic: %v", err)
+		}
+		if exists {
+			skipped = append(skipped, i)
+			continue
+		}
+
+		var description, category sql.NullString
+		if topic.Description != "" {
+			description = sql.NullString{String: topic.Description, Valid: true}
+		}
+		if topic.Category != "" {
+			category = sql.NullString{String: topic.Category, Valid: true}
+		}
+		result, err := tx.Exec(`INSERT INTO topics (title, description, agent1_name, agent1_role, agent2_name, agent2_role, category)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			topic.Title, description, topic.Agent1Name, topic.Agent1Role, topic.Agent2Name, topic.Agent2Role, category)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create topic %q: %v", topic.Title, err)
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get topic ID: %v", err)
+		}
+		topic.ID = int(id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return skipped, nil
+}
@@ -4,9 +4,13 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/neo/convinceme_backend/internal/scoring"
+	"github.com/neo/convinceme_backend/internal/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNew(t *testing.T) {
@@ -90,3 +94,278 @@ func TestDebateFilter(t *testing.T) {
 	assert.NotNil(t, debates)
 	assert.GreaterOrEqual(t, total, 0)
 }
+
+func TestScoreCritique(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	argumentID, err := db.SaveArgument("alice", "user-alice", "Cats vs dogs", "Cats are better", "agent1", "debate-1", ArgumentParent{})
+	require.NoError(t, err)
+	require.NoError(t, db.SaveScore(argumentID, "debate-1", &scoring.ArgumentScore{Strength: 7, Average: 7, Explanation: "Solid"}))
+
+	critique, err := db.GetScoreCritique(argumentID)
+	require.NoError(t, err)
+	assert.Nil(t, critique, "critiques are only generated on request")
+
+	saved := &scoring.ScoreCritique{Summary: "Good start", Aspects: map[string]string{"logic": "Clear"}, Improvements: []string{"Cite a study"}}
+	require.NoError(t, db.SaveScoreCritique(argumentID, saved))
+	critique, err = db.GetScoreCritique(argumentID)
+	require.NoError(t, err)
+	assert.Equal(t, saved, critique)
+
+	// Re-scoring the argument drops the critique of the old score
+	require.NoError(t, db.UpdateScore(argumentID, &scoring.ArgumentScore{Strength: 9, Average: 9}))
+	critique, err = db.GetScoreCritique(argumentID)
+	require.NoError(t, err)
+	assert.Nil(t, critique)
+
+	assert.Error(t, db.SaveScoreCritique(argumentID+1, saved), "unscored arguments have nothing to explain")
+}
+
+func TestGetUserVotesForDebate(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	first, err := db.SaveArgument("alice", "user-alice", "Cats vs dogs", "Cats are better", "agent1", "debate-1", ArgumentParent{})
+	require.NoError(t, err)
+	second, err := db.SaveArgument("bob", "user-bob", "Cats vs dogs", "Dogs are loyal", "agent2", "debate-1", ArgumentParent{})
+	require.NoError(t, err)
+	other, err := db.SaveArgument("bob", "user-bob", "Tea vs coffee", "Coffee wins", "agent1", "debate-2", ArgumentParent{})
+	require.NoError(t, err)
+
+	require.NoError(t, db.SubmitVote("user-carol", first, "debate-1", "upvote"))
+	require.NoError(t, db.SubmitVote("user-carol", second, "debate-1", "upvote"))
+	require.NoError(t, db.SubmitVote("user-carol", second, "debate-1", "downvote"))
+	require.NoError(t, db.SubmitVote("user-carol", other, "debate-2", "upvote"))
+	require.NoError(t, db.SubmitVote("user-dave", first, "debate-1", "downvote"))
+
+	votes, err := db.GetUserVotesForDebate("user-carol", "debate-1")
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]string{first: "upvote", second: "downvote"}, votes)
+
+	votes, err = db.GetUserVotesForDebate("user-erin", "debate-1")
+	require.NoError(t, err)
+	assert.NotNil(t, votes)
+	assert.Empty(t, votes)
+}
+
+func TestDebateSeed(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	require.NoError(t, db.CreateDebate("seeded", "Cats vs dogs", types.DebateStatusActive, "Pepito", "Tony"))
+	require.NoError(t, db.SaveDebateSettings("seeded", DebateSettings{Seed: 42}))
+	debate, err := db.GetDebate("seeded")
+	require.NoError(t, err)
+	require.NotNil(t, debate.Seed)
+	assert.Equal(t, int64(42), *debate.Seed)
+
+	// Debates created before seeds were recorded have none
+	require.NoError(t, db.CreateDebate("unseeded", "Cats vs dogs", types.DebateStatusActive, "Pepito", "Tony"))
+	debate, err = db.GetDebate("unseeded")
+	require.NoError(t, err)
+	assert.Nil(t, debate.Seed)
+}
+
+func TestDebateFork(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	require.NoError(t, db.CreateDebate("fork", "Cats vs dogs", types.DebateStatusWaiting, "Pepito", "Tony"))
+	require.NoError(t, db.SaveDebateSettings("fork", DebateSettings{ParentDebateID: "original", ForkedFromTurn: 4}))
+	debate, err := db.GetDebate("fork")
+	require.NoError(t, err)
+	require.NotNil(t, debate.ParentDebateID)
+	assert.Equal(t, "original", *debate.ParentDebateID)
+	require.NotNil(t, debate.ForkedFromTurn)
+	assert.Equal(t, 4, *debate.ForkedFromTurn)
+
+	// Debates that weren't forked have no parent
+	require.NoError(t, db.CreateDebate("original", "Cats vs dogs", types.DebateStatusFinished, "Pepito", "Tony"))
+	debate, err = db.GetDebate("original")
+	require.NoError(t, err)
+	assert.Nil(t, debate.ParentDebateID)
+	assert.Nil(t, debate.ForkedFromTurn)
+}
+
+func TestArgumentReplies(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	original, err := db.SaveArgument("alice", "", "Cats vs dogs", "Cats are better", "agent1", "debate-1", ArgumentParent{})
+	require.NoError(t, err)
+	agentTurn, err := db.SaveDebateMessage(&DebateMessage{DebateID: "debate-1", Speaker: "Tony", Message: "Dogs are loyal"})
+	require.NoError(t, err)
+	playerTurn, err := db.SaveDebateMessage(&DebateMessage{DebateID: "debate-1", Speaker: "alice", Message: "Cats are better", IsPlayer: true})
+	require.NoError(t, err)
+
+	content, err := db.GetReplyParent("debate-1", ArgumentParent{ArgumentID: original})
+	require.NoError(t, err)
+	assert.Equal(t, "Cats are better", content)
+	content, err = db.GetReplyParent("debate-1", ArgumentParent{MessageID: agentTurn})
+	require.NoError(t, err)
+	assert.Equal(t, "Dogs are loyal", content)
+	content, err = db.GetReplyParent("debate-1", ArgumentParent{})
+	require.NoError(t, err)
+	assert.Empty(t, content, "standalone arguments have no parent")
+
+	// Parents must be in the same debate, and player turns are replied to by their argument ID
+	_, err = db.GetReplyParent("debate-2", ArgumentParent{ArgumentID: original})
+	assert.ErrorIs(t, err, ErrReplyParentNotFound)
+	_, err = db.GetReplyParent("debate-2", ArgumentParent{MessageID: agentTurn})
+	assert.ErrorIs(t, err, ErrReplyParentNotFound)
+	_, err = db.GetReplyParent("debate-1", ArgumentParent{MessageID: playerTurn})
+	assert.ErrorIs(t, err, ErrReplyParentNotFound)
+	_, err = db.GetReplyParent("debate-1", ArgumentParent{ArgumentID: original, MessageID: agentTurn})
+	assert.Error(t, err)
+
+	reply, err := db.SaveArgument("bob", "", "Cats vs dogs", "Cats ignore you", "agent2", "debate-1", ArgumentParent{ArgumentID: original})
+	require.NoError(t, err)
+	rebuttal, err := db.SaveArgument("carol", "", "Cats vs dogs", "Loyalty is overrated", "agent1", "debate-1", ArgumentParent{MessageID: agentTurn})
+	require.NoError(t, err)
+
+	arguments, total, err := db.GetArgumentsByDebate("debate-1", ArgumentFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	parents := map[int64][2]*int64{}
+	for _, arg := range arguments {
+		parents[arg.ID] = [2]*int64{arg.ParentID, arg.ParentMessageID}
+	}
+	assert.Equal(t, [2]*int64{nil, nil}, parents[original])
+	assert.Equal(t, [2]*int64{&original, nil}, parents[reply])
+	assert.Equal(t, [2]*int64{nil, &agentTurn}, parents[rebuttal])
+}
+
+func TestGetScoreBreakdown(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	for _, arg := range []struct {
+		player, side, debate string
+		logic, humor         int
+	}{
+		{"alice", "agent1", "debate-1", 40, 90},
+		{"alice", "agent1", "debate-1", 60, 70},
+		{"bob", "agent2", "debate-1", 80, 20},
+		{"carol", "agent1", "debate-2", 10, 10},
+	} {
+		id, err := db.SaveArgument(arg.player, "", "Cats vs dogs", "Cats are better", arg.side, arg.debate, ArgumentParent{})
+		require.NoError(t, err)
+		require.NoError(t, db.SaveScore(id, arg.debate, &scoring.ArgumentScore{Logic: arg.logic, Humor: arg.humor, Average: 5, Explanation: "Fine"}))
+	}
+
+	breakdowns, err := db.GetScoreBreakdown("debate-1")
+	require.NoError(t, err)
+	require.Len(t, breakdowns, 4)
+	var names []string
+	for _, b := range breakdowns {
+		names = append(names, b.Kind+":"+b.Name)
+	}
+	assert.Equal(t, []string{"side:agent1", "side:agent2", "player:alice", "player:bob"}, names)
+
+	agent1 := breakdowns[0]
+	assert.Equal(t, 2, agent1.Count)
+	assert.False(t, agent1.SingleSample)
+	assert.Equal(t, 50.0, agent1.Logic)
+	assert.Equal(t, 80.0, agent1.Humor)
+	assert.True(t, breakdowns[3].SingleSample)
+
+	breakdowns, err = db.GetScoreBreakdown("debate-without-arguments")
+	require.NoError(t, err)
+	assert.Empty(t, breakdowns)
+}
+
+func TestGetArgumentsByUser(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	// The websocket saves signed-in players' arguments under their display name, with their ID as user_id
+	first, err := db.SaveArgument("Alice the Great", "user-alice", "Cats vs dogs", "Cats are better", "agent1", "debate-1", ArgumentParent{})
+	require.NoError(t, err)
+	second, err := db.SaveArgument("alice", "user-alice", "Cats vs dogs", "Cats nap more", "agent1", "debate-2", ArgumentParent{})
+	require.NoError(t, err)
+	_, err = db.SaveArgument("player_1234abcd", "", "Cats vs dogs", "Dogs are loyal", "agent2", "debate-1", ArgumentParent{})
+	require.NoError(t, err)
+
+	arguments, err := db.GetArgumentsByUser("user-alice")
+	require.NoError(t, err)
+	require.Len(t, arguments, 2)
+	assert.Equal(t, first, arguments[0].ID)
+	assert.Equal(t, second, arguments[1].ID)
+
+	arguments, err = db.GetArgumentsByUser("Alice the Great")
+	require.NoError(t, err)
+	assert.Empty(t, arguments, "display names aren't user IDs")
+}
+
+func TestUpdateDebateEnd(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	require.NoError(t, db.CreateDebate("ending", "Cats vs dogs", types.DebateStatusActive, "Pepito", "Tony"))
+	require.NoError(t, db.UpdateDebateEnd("ending", types.DebateStatusFinished, "Pepito", DebateEndReasonKnockout))
+
+	// Only the first end is recorded
+	err := db.UpdateDebateEnd("ending", types.DebateStatusFinished, "Tony", DebateEndReasonAdmin)
+	assert.ErrorIs(t, err, ErrDebateAlreadyFinished)
+	debate, err := db.GetDebate("ending")
+	require.NoError(t, err)
+	require.NotNil(t, debate.Winner)
+	assert.Equal(t, "Pepito", *debate.Winner)
+
+	err = db.UpdateDebateEnd("missing", types.DebateStatusFinished, "", DebateEndReasonAdmin)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrDebateAlreadyFinished)
+}
+
+func TestDebateRestoreState(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	require.NoError(t, db.CreateDebate("restored", "Cats vs dogs", types.DebateStatusActive, "Pepito", "Tony"))
+	require.NoError(t, db.SaveDebateSettings("restored", DebateSettings{Config: `{"Language":"fr"}`}))
+	deadline := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	require.NoError(t, db.SaveDebateDeadline("restored", deadline))
+
+	debates, err := db.ListActiveDebates()
+	require.NoError(t, err)
+	require.Len(t, debates, 1)
+	require.NotNil(t, debates[0].Config)
+	assert.JSONEq(t, `{"Language":"fr"}`, *debates[0].Config)
+	require.NotNil(t, debates[0].Deadline)
+	assert.True(t, deadline.Equal(*debates[0].Deadline))
+
+	debate, err := db.GetDebate("restored")
+	require.NoError(t, err)
+	require.NotNil(t, debate.Config)
+	require.NotNil(t, debate.Deadline)
+
+	// Debates created before the config was recorded have none
+	require.NoError(t, db.CreateDebate("legacy", "Cats vs dogs", types.DebateStatusWaiting, "Pepito", "Tony"))
+	debate, err = db.GetDebate("legacy")
+	require.NoError(t, err)
+	assert.Nil(t, debate.Config)
+	assert.Nil(t, debate.Deadline)
+	assert.Error(t, db.SaveDebateDeadline("missing", deadline))
+
+	// Debates paused for an LLM outage are reloaded too
+	require.NoError(t, db.CreateDebate("paused", "Cats vs dogs", types.DebateStatusPaused, "Pepito", "Tony"))
+	debates, err = db.ListActiveDebates()
+	require.NoError(t, err)
+	assert.Len(t, debates, 3)
+}
+
+func TestGetDueScheduledDebatesAcrossTimeZones(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	// 12:30 in UTC+2 is 10:30 UTC, so the debate is due at 11:00 UTC even though "11:00" sorts before "12:30"
+	plusTwo := time.FixedZone("UTC+2", 2*60*60)
+	scheduledAt := time.Date(2024, 5, 1, 12, 30, 0, 0, plusTwo)
+	require.NoError(t, db.CreateDebate("scheduled", "Cats vs dogs", types.DebateStatusScheduled, "Pepito", "Tony"))
+	require.NoError(t, db.SaveDebateSettings("scheduled", DebateSettings{ScheduledAt: &scheduledAt}))
+
+	due, err := db.GetDueScheduledDebates(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	due, err = db.GetDueScheduledDebates(time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.True(t, scheduledAt.Equal(*due[0].ScheduledAt))
+
+	// A local now is compared as UTC too: 12:45 in UTC+2 is past 10:30 UTC
+	due, err = db.GetDueScheduledDebates(time.Date(2024, 5, 1, 12, 45, 0, 0, plusTwo))
+	require.NoError(t, err)
+	assert.Len(t, due, 1)
+}
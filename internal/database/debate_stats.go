@@ -0,0 +1,231 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+)
+
+// GetDebateStats returns aggregate statistics about debates matching the filter
+func (d *Database) GetDebateStats(filter DebateFilter) (map[string]interface{}, error) {
+	stats := make(map[string]interface{})
+	whereClause, args := buildDebateWhereClause(filter)
+
+	// andWhere appends an extra condition to the filter's WHERE clause
+	andWhere := func(condition string) string {
+		if whereClause == "" {
+			return "WHERE " + condition
+		}
+		return whereClause + " AND " + condition
+	}
+
+	// Get total count
+	var totalCount int
+	err := d.db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM debates %s", whereClause), args...).Scan(&totalCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total count: %w", err)
+	}
+	stats["total_count"] = totalCount
+
+	// Get count by status
+	rows, err := d.db.Query(fmt.Sprintf("SELECT status, COUNT(*) FROM debates %s GROUP BY status", whereClause), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get count by status: %w", err)
+	}
+	defer rows.Close()
+
+	countByStatus := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan count by status: %w", err)
+		}
+		countByStatus[status] = count
+	}
+	stats["count_by_status"] = countByStatus
+
+	// Get average duration of ended debates
+	averageDuration, err := d.GetAverageDebateDuration(filter)
+	if err != nil {
+		return nil, err
+	}
+	stats["average_duration_seconds"] = averageDuration
+
+	// Get most active agents by participation, counting both sides
+	participationQuery := fmt.Sprintf(`
+		SELECT agent, COUNT(*) AS appearances FROM (
+			SELECT agent1_name AS agent FROM debates %s
+			UNION ALL
+			SELECT agent2_name AS agent FROM debates %s
+		)
+		GROUP BY agent
+		ORDER BY appearances DESC, agent ASC
+		LIMIT 10`, whereClause, whereClause)
+	rows, err = d.db.Query(participationQuery, append(append([]any{}, args...), args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent participation: %w", err)
+	}
+	defer rows.Close()
+
+	mostActive := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		var agent string
+		var appearances int
+		if err := rows.Scan(&agent, &appearances); err != nil {
+			return nil, fmt.Errorf("failed to scan agent participation: %w", err)
+		}
+		mostActive = append(mostActive, map[string]interface{}{"agent": agent, "appearances": appearances})
+	}
+	stats["most_active_agents"] = mostActive
+
+	// Get agents with the most wins, only counting winners that actually took part in the debate
+	winsQuery := fmt.Sprintf(`
+		SELECT winner, COUNT(*) AS wins
+		FROM debates %s
+		GROUP BY winner
+		ORDER BY wins DESC, winner ASC
+		LIMIT 10`, andWhere("winner IS NOT NULL AND (winner = agent1_name OR winner = agent2_name)"))
+	rows, err = d.db.Query(winsQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent wins: %w", err)
+	}
+	defer rows.Close()
+
+	topWinners := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		var agent string
+		var wins int
+		if err := rows.Scan(&agent, &wins); err != nil {
+			return nil, fmt.Errorf("failed to scan agent wins: %w", err)
+		}
+		topWinners = append(topWinners, map[string]interface{}{"agent": agent, "wins": wins})
+	}
+	stats["top_winning_agents"] = topWinners
+
+	// Get debates over time (last 30 days)
+	overTimeQuery := fmt.Sprintf(`
+		SELECT DATE(created_at) as date, COUNT(*) as count
+		FROM debates %s
+		GROUP BY DATE(created_at)
+		ORDER BY date`, andWhere("created_at >= date('now', '-30 days')"))
+	rows, err = d.db.Query(overTimeQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get debates over time: %w", err)
+	}
+	defer rows.Close()
+
+	debatesOverTime := make(map[string]int)
+	for rows.Next() {
+		var date string
+		var count int
+		if err := rows.Scan(&date, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan debates over time: %w", err)
+		}
+		debatesOverTime[date] = count
+	}
+	stats["debates_over_time"] = debatesOverTime
+
+	return stats, nil
+}
+
+// AgentWinRate summarizes an agent's record across finished debates
+type AgentWinRate struct {
+	Agent         string  `json:"agent"`
+	Appearances   int     `json:"appearances"`
+	Wins          int     `json:"wins"`
+	Losses        int     `json:"losses"`
+	WinPercentage float64 `json:"win_percentage"`
+}
+
+// GetAgentWinRates computes each agent's appearances, wins, losses, and win percentage across finished debates,
+// counting appearances on either side. Results are ordered by win percentage, then appearances.
+func (d *Database) GetAgentWinRates() ([]*AgentWinRate, error) {
+	query := `
+		SELECT agent, COUNT(*) AS appearances,
+			SUM(CASE WHEN winner = agent THEN 1 ELSE 0 END) AS wins,
+			SUM(CASE WHEN winner = opponent THEN 1 ELSE 0 END) AS losses
+		FROM (
+			SELECT agent1_name AS agent, agent2_name AS opponent, winner FROM debates WHERE status = 'finished'
+			UNION ALL
+			SELECT agent2_name AS agent, agent1_name AS opponent, winner FROM debates WHERE status = 'finished'
+		)
+		GROUP BY agent`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent win rates: %w", err)
+	}
+	defer rows.Close()
+
+	var winRates []*AgentWinRate
+	for rows.Next() {
+		rate := &AgentWinRate{}
+		if err := rows.Scan(&rate.Agent, &rate.Appearances, &rate.Wins, &rate.Losses); err != nil {
+			return nil, fmt.Errorf("failed to scan agent win rate: %w", err)
+		}
+		if rate.Appearances > 0 {
+			rate.WinPercentage = float64(rate.Wins) / float64(rate.Appearances) * 100
+		}
+		winRates = append(winRates, rate)
+	}
+
+	sort.SliceStable(winRates, func(i, j int) bool {
+		if winRates[i].WinPercentage != winRates[j].WinPercentage {
+			return winRates[i].WinPercentage > winRates[j].WinPercentage
+		}
+		return winRates[i].Appearances > winRates[j].Appearances
+	})
+
+	return winRates, nil
+}
+
+// Who a CriteriaBreakdown aggregates the arguments of
+const (
+	BreakdownSide   = "side"   // Arguments made for one side: agent1, agent2 or neutral
+	BreakdownPlayer = "player" // Arguments made by one player
+)
+
+// CriteriaBreakdown averages each scoring criterion over a group of scored arguments in a debate
+type CriteriaBreakdown struct {
+	Kind         string  `json:"-"`    // BreakdownSide or BreakdownPlayer
+	Name         string  `json:"name"` // The side, or the player's ID
+	Count        int     `json:"count"`
+	SingleSample bool    `json:"single_sample"` // Averages of a single argument say little
+	Strength     float64 `json:"strength"`
+	Relevance    float64 `json:"relevance"`
+	Logic        float64 `json:"logic"`
+	Truth        float64 `json:"truth"`
+	Humor        float64 `json:"humor"`
+	Average      float64 `json:"average"`
+}
+
+// GetScoreBreakdown averages the five scoring criteria of a debate's scored arguments, both per side and per
+// player, in a single aggregate query. Side breakdowns come first, each kind ordered by name.
+func (d *Database) GetScoreBreakdown(debateID string) ([]*CriteriaBreakdown, error) {
+	const criteria = `COUNT(*), AVG(s.strength), AVG(s.relevance), AVG(s.logic), AVG(s.truth), AVG(s.humor), AVG(s.average)
+		FROM scores s JOIN arguments a ON a.id = s.argument_id WHERE a.debate_id = ?`
+	query := `SELECT '` + BreakdownSide + `' AS kind, a.side AS name, ` + criteria + ` GROUP BY a.side
+		UNION ALL
+		SELECT '` + BreakdownPlayer + `' AS kind, a.player_id AS name, ` + criteria + ` GROUP BY a.player_id
+		ORDER BY kind DESC, name`
+
+	rows, err := d.db.Query(query, debateID, debateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get score breakdown: %w", err)
+	}
+	defer rows.Close()
+
+	breakdowns := make([]*CriteriaBreakdown, 0)
+	for rows.Next() {
+		b := &CriteriaBreakdown{}
+		if err := rows.Scan(&b.Kind, &b.Name, &b.Count, &b.Strength, &b.Relevance, &b.Logic, &b.Truth, &b.Humor, &b.Average); err != nil {
+			return nil, fmt.Errorf("failed to scan score breakdown: %w", err)
+		}
+		b.SingleSample = b.Count == 1
+		breakdowns = append(breakdowns, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating score breakdown: %w", err)
+	}
+	return breakdowns, nil
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/neo/convinceme_backend/internal/usage"
+)
+
+// DebateUsage is the LLM and TTS usage recorded for a debate when it ended
+type DebateUsage struct {
+	DebateID string `json:"debate_id"`
+	usage.Usage
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SaveDebateUsage stores a debate's usage, replacing any previously saved totals
+func (d *Database) SaveDebateUsage(debateID string, u usage.Usage) error {
+	query := `
+		INSERT INTO debate_usage (debate_id, llm_calls, prompt_tokens, completion_tokens, tts_characters, updated_at)
+		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(debate_id) DO UPDATE SET
+			llm_calls = excluded.llm_calls,
+			prompt_tokens = excluded.prompt_tokens,
+			completion_tokens = excluded.completion_tokens,
+			tts_characters = excluded.tts_characters,
+			updated_at = CURRENT_TIMESTAMP`
+	_, err := d.db.Exec(query, debateID, u.LLMCalls, u.PromptTokens, u.CompletionTokens, u.TTSCharacters)
+	if err != nil {
+		return fmt.Errorf("failed to save usage for debate %s: %v", debateID, err)
+	}
+	return nil
+}
+
+// GetDebateUsage retrieves the usage saved for a debate
+func (d *Database) GetDebateUsage(debateID string) (*DebateUsage, error) {
+	query := `SELECT debate_id, llm_calls, prompt_tokens, completion_tokens, tts_characters, updated_at FROM debate_usage WHERE debate_id = ?`
+	var u DebateUsage
+	err := d.db.QueryRow(query, debateID).Scan(
+		&u.DebateID, &u.LLMCalls, &u.PromptTokens, &u.CompletionTokens, &u.TTSCharacters, &u.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("no usage recorded for debate %s", debateID)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get usage for debate %s: %v", debateID, err)
+	}
+	return &u, nil
+}
+
+// GetUsageTotals sums the usage saved across all debates
+func (d *Database) GetUsageTotals() (usage.Usage, error) {
+	query := `
+		SELECT COALESCE(SUM(llm_calls), 0), COALESCE(SUM(prompt_tokens), 0),
+			COALESCE(SUM(completion_tokens), 0), COALESCE(SUM(tts_characters), 0)
+		FROM debate_usage`
+	var u usage.Usage
+	err := d.db.QueryRow(query).Scan(&u.LLMCalls, &u.PromptTokens, &u.CompletionTokens, &u.TTSCharacters)
+	if err != nil {
+		return usage.Usage{}, fmt.Errorf("failed to get usage totals: %v", err)
+	}
+	return u, nil
+}
@@ -19,6 +19,15 @@ const (
 	FeedbackTypeOther      FeedbackType = "other"
 )
 
+// IsValidFeedbackType reports whether feedbackType is a known feedback type
+func IsValidFeedbackType(feedbackType string) bool {
+	switch FeedbackType(feedbackType) {
+	case FeedbackTypeAuth, FeedbackTypeUI, FeedbackTypePerformance, FeedbackTypeFeature, FeedbackTypeBug, FeedbackTypeOther:
+		return true
+	}
+	return false
+}
+
 // Feedback represents user feedback
 type Feedback struct {
 	ID         int         `json:"id"`
@@ -140,66 +149,17 @@ func (d *Database) GetFeedback(id int) (*Feedback, error) {
 	return &feedback, nil
 }
 
-// GetFeedbackByUser gets all feedback submitted by a user
+// GetFeedbackByUser gets all feedback submitted by a user, newest first
 func (d *Database) GetFeedbackByUser(userID string) ([]*Feedback, error) {
-	query := `
-		SELECT id, user_id, type, message, rating, path, browser, device, screen_size, created_at
-		FROM feedback
-		WHERE user_id = ?
-		ORDER BY created_at DESC
-	`
-
-	rows, err := d.db.Query(query, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get feedback: %w", err)
-	}
-	defer rows.Close()
-
-	var feedbackList []*Feedback
-	for rows.Next() {
-		var feedback Feedback
-		var userIDNullable sql.NullString
-		var rating sql.NullInt64
-		var createdAt string
-
-		err := rows.Scan(
-			&feedback.ID,
-			&userIDNullable,
-			&feedback.Type,
-			&feedback.Message,
-			&rating,
-			&feedback.Path,
-			&feedback.Browser,
-			&feedback.Device,
-			&feedback.ScreenSize,
-			&createdAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan feedback: %w", err)
-		}
-
-		if userIDNullable.Valid {
-			feedback.UserID = &userIDNullable.String
-		}
-
-		if rating.Valid {
-			ratingInt := int(rating.Int64)
-			feedback.Rating = &ratingInt
-		}
-
-		feedback.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse created_at: %w", err)
-		}
-
-		feedbackList = append(feedbackList, &feedback)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating feedback rows: %w", err)
-	}
+	feedbackList, _, err := d.GetFeedbackByUserFiltered(userID, FeedbackFilter{})
+	return feedbackList, err
+}
 
-	return feedbackList, nil
+// GetFeedbackByUserFiltered gets feedback submitted by a user with filtering and pagination, like
+// GetAllFeedback. The filter's UserID is always replaced by userID.
+func (d *Database) GetFeedbackByUserFiltered(userID string, filter FeedbackFilter) ([]*Feedback, int, error) {
+	filter.UserID = userID
+	return d.GetAllFeedback(filter)
 }
 
 // GetAllFeedback gets all feedback with filtering and pagination
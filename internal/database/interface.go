@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/neo/convinceme_backend/internal/scoring"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
 )
 
 // DatabaseInterface defines the interface for database operations
@@ -15,10 +17,12 @@ type DatabaseInterface interface {
 	GetUserByID(id string) (*User, error)
 	GetUserByUsername(username string) (*User, error)
 	GetUserByEmail(email string) (*User, error)
-	UpdateUser(user *User) error
+	UpdateUser(user *User, expectedUpdatedAt time.Time) error
 	DeleteUser(id string) error
+	DeleteUserAnonymized(id string) (string, error)
 	VerifyPassword(username, password string) (*User, error)
 	UpdatePassword(userID, newPassword string) error
+	GetUserCountsByRole() (map[string]int, error)
 
 	// Authentication
 	CreateRefreshToken(userID, token string, expiresAt time.Time) error
@@ -37,6 +41,7 @@ type DatabaseInterface interface {
 	ValidateInvitationCode(code string) (*InvitationCode, error)
 	UseInvitationCode(code, usedBy string) error
 	GetInvitationsByUser(userID string) ([]*InvitationCode, error)
+	GetInvitationStats(userID string) (*InvitationStats, error)
 	DeleteInvitationCode(id int, userID string) error
 	CleanupExpiredInvitations() error
 
@@ -44,35 +49,98 @@ type DatabaseInterface interface {
 	SaveFeedback(feedback *Feedback) error
 	GetFeedback(id int) (*Feedback, error)
 	GetFeedbackByUser(userID string) ([]*Feedback, error)
+	GetFeedbackByUserFiltered(userID string, filter FeedbackFilter) ([]*Feedback, int, error)
 	GetAllFeedback(filter FeedbackFilter) ([]*Feedback, int, error)
 	GetFeedbackStats() (map[string]interface{}, error)
 	DeleteFeedback(id int) error
 
 	// Debates
-	CreateDebate(id, topic, status, agent1Name, agent2Name string) error
+	CreateDebate(id, topic string, status types.DebateStatus, agent1Name, agent2Name string) error
 	GetDebate(id string) (*Debate, error)
 	ListActiveDebates() ([]*Debate, error)
+	GetDueScheduledDebates(now time.Time) ([]*Debate, error)
 	ListDebates(filter DebateFilter) ([]*Debate, int, error)
-	UpdateDebateStatus(id, status string) error
-	UpdateDebateEnd(id, status string, winner string) error
+	UpdateDebateStatus(id string, status types.DebateStatus) error
+	UpdateDebateEnd(id string, status types.DebateStatus, winner, reason string) error
+	SaveDebateSettings(id string, settings DebateSettings) error
+	SaveDebateDeadline(id string, deadline time.Time) error
+	SetDebateFeatured(id string, featured bool) error
+	UpdateDebateActiveTime(id string, activeSeconds int64) error
+	GetAverageDebateDuration(filter DebateFilter) (float64, error)
+	GetDebateStats(filter DebateFilter) (map[string]interface{}, error)
+	GetAgentWinRates() ([]*AgentWinRate, error)
+	GetScoreBreakdown(debateID string) ([]*CriteriaBreakdown, error)
+	AddDebateParticipant(debateID, userID, invitedBy string) error
+	IsDebateParticipant(debateID, userID string) (bool, error)
+
+	// Notifications
+	CreateNotification(userID, notificationType string, payload interface{}) (int64, error)
+	GetNotifications(userID string, unreadOnly bool, limit int) ([]*Notification, int, error)
+	MarkNotificationRead(id int64, userID string) error
+	GetDebateParticipants(debateID string) ([]string, error)
+
+	// Bookmarks
+	AddBookmark(userID, debateID string) error
+	RemoveBookmark(userID, debateID string) error
+	ListBookmarks(userID string, offset, limit int) ([]*Bookmark, int, error)
+	CountBookmarks(debateID string) (int, error)
+	GetDebateBookmarkers(debateID string) ([]string, error)
+
+	// Reactions
+	AddReaction(debateID string, messageID int64, emoji string) (int, error)
+	GetReactionCounts(debateID string) (map[int64]map[string]int, error)
+
+	// Audit log
+	LogAuditEvent(actorID, action, target string, details interface{}) error
+	ListAuditEvents(filter AuditFilter) ([]*AuditEvent, int, error)
+
+	// Debate usage
+	SaveDebateUsage(debateID string, u usage.Usage) error
+	GetDebateUsage(debateID string) (*DebateUsage, error)
+	GetUsageTotals() (usage.Usage, error)
+
+	// Debate transcripts
+	SaveDebateMessage(msg *DebateMessage) (int64, error)
+	UpdateDebateMessage(msg *DebateMessage) error
+	GetDebateMessages(debateID string) ([]*DebateMessage, error)
+	GetDebateMessagesBefore(debateID string, beforeID int64, limit int) ([]*DebateMessage, bool, error)
+
+	// Chat
+	SaveChatMessage(debateID, playerID, username, message string) (int64, error)
+	GetChatMessages(debateID string, limit int) ([]*ChatMessage, error)
 
 	// Topics
 	GetTopic(id int) (*Topic, error)
 	GetTopics(filter TopicFilter) ([]*Topic, int, error)
+	CreateTopics(topics []*Topic) ([]int, error)
 
 	// Arguments and scoring
-	SaveArgument(playerID, topic, content, side, debateID string) (int64, error)
+	SaveArgument(playerID, userID, topic, content, side, debateID string, parent ArgumentParent) (int64, error)
+	GetReplyParent(debateID string, parent ArgumentParent) (string, error)
 	SaveScore(argumentID int64, debateID string, score *scoring.ArgumentScore) error
 	GetAllArguments() ([]*Argument, error)
 	GetArgumentWithScore(id int64) (*Argument, error)
 	GetLeaderboard(debateID string, limit int) ([]*Argument, error)
+	GetArgumentsByDebate(debateID string, filter ArgumentFilter) ([]*Argument, int, error)
+	GetArgumentsByUser(userID string) ([]*Argument, error)
+	UpdateArgument(id int64, userID, content string) error
+	UpdateScore(argumentID int64, score *scoring.ArgumentScore) error
+	GetScoreCritique(argumentID int64) (*scoring.ScoreCritique, error)
+	SaveScoreCritique(argumentID int64, critique *scoring.ScoreCritique) error
+	DeleteArgument(id int64, userID string) error
 
 	// Voting system
 	SubmitVote(userID string, argumentID int64, debateID string, voteType string) error
 	GetUserVoteCount(userID string, debateID string) (int, error)
 	HasUserPaidForComment(userID string, debateID string) (bool, error)
 	GetUserVoteForArgument(userID string, argumentID int64) (string, error)             // Returns vote type or empty string
+	GetUserVotesForDebate(userID, debateID string) (map[int64]string, error)            // Vote type by argument ID
 	CanUserVote(userID string, argumentID int64, debateID string) (bool, string, error) // Returns canVote, reason, error
+	GetVotesByUser(userID string) ([]*UserVote, error)
+
+	// Reputation
+	GetUserReputation(userID string) (float64, error)
+	RecomputeReputations() (int64, error)
 
 	// Migration runner
 	RunMigrations() error
@@ -19,6 +19,23 @@ type InvitationCode struct {
 	CreatedAt time.Time  `json:"created_at"`
 }
 
+// InvitationAcceptance records an account that registered with one of a user's invitation codes
+type InvitationAcceptance struct {
+	Code     string     `json:"code"`
+	UserID   string     `json:"user_id"`
+	Username string     `json:"username,omitempty"` // Empty if the account has since been deleted
+	UsedAt   *time.Time `json:"used_at,omitempty"`
+}
+
+// InvitationStats summarizes how a user's invitation codes have been used
+type InvitationStats struct {
+	Total    int                     `json:"total"`
+	Used     int                     `json:"used"`
+	Expired  int                     `json:"expired"`
+	Pending  int                     `json:"pending"`
+	Accepted []*InvitationAcceptance `json:"accepted"`
+}
+
 // CreateInvitationCode creates a new invitation code
 func (d *Database) CreateInvitationCode(createdBy string, email string, expiresIn time.Duration) (*InvitationCode, error) {
 	// Generate a unique code
@@ -133,13 +150,17 @@ func (d *Database) UseInvitationCode(code string, userID string) error {
 		return err
 	}
 	
-	// Mark the code as used
+	// Mark the code as used, recording who used it. The used check guards against two
+	// registrations racing on the same code.
 	now := time.Now()
-	query := `UPDATE invitation_codes SET used = TRUE, used_by = ?, used_at = ? WHERE id = ?`
-	_, err = d.db.Exec(query, userID, now, invitation.ID)
+	query := `UPDATE invitation_codes SET used = TRUE, used_by = ?, used_at = ? WHERE id = ? AND used = FALSE`
+	result, err := d.db.Exec(query, userID, now, invitation.ID)
 	if err != nil {
 		return fmt.Errorf("failed to mark invitation code as used: %v", err)
 	}
+	if updated, err := result.RowsAffected(); err == nil && updated == 0 {
+		return fmt.Errorf("invitation code has already been used")
+	}
 	
 	return nil
 }
@@ -209,6 +230,59 @@ func (d *Database) GetInvitationsByUser(userID string) ([]*InvitationCode, error
 	return invitations, nil
 }
 
+// GetInvitationStats summarizes the invitation codes a user created and lists the accounts that accepted them
+func (d *Database) GetInvitationStats(userID string) (*InvitationStats, error) {
+	stats := &InvitationStats{Accepted: make([]*InvitationAcceptance, 0)}
+
+	now := time.Now()
+	countQuery := `SELECT
+			COUNT(*),
+			COALESCE(SUM(CASE WHEN used THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN NOT used AND expires_at < ? THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN NOT used AND (expires_at IS NULL OR expires_at >= ?) THEN 1 ELSE 0 END), 0)
+		FROM invitation_codes WHERE created_by = ?`
+	err := d.db.QueryRow(countQuery, now, now, userID).Scan(&stats.Total, &stats.Used, &stats.Expired, &stats.Pending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count invitations: %v", err)
+	}
+
+	acceptedQuery := `SELECT i.code, i.used_by, u.username, i.used_at
+		FROM invitation_codes i
+		LEFT JOIN users u ON u.id = i.used_by
+		WHERE i.created_by = ? AND i.used = TRUE AND i.used_by IS NOT NULL
+		ORDER BY i.used_at DESC`
+	rows, err := d.db.Query(acceptedQuery, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accepted invitations: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var acceptance InvitationAcceptance
+		var username sql.NullString
+		var usedAt sql.NullTime
+
+		if err := rows.Scan(&acceptance.Code, &acceptance.UserID, &username, &usedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan accepted invitation: %v", err)
+		}
+
+		if username.Valid {
+			acceptance.Username = username.String
+		}
+		if usedAt.Valid {
+			acceptance.UsedAt = &usedAt.Time
+		}
+
+		stats.Accepted = append(stats.Accepted, &acceptance)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating accepted invitations: %v", err)
+	}
+
+	return stats, nil
+}
+
 // DeleteInvitationCode deletes an invitation code
 func (d *Database) DeleteInvitationCode(id int, userID string) error {
 	// Check if the user is the creator of the code
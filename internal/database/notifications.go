@@ -0,0 +1,126 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Notification types
+const (
+	NotificationTypeDebateEnded = "debate_ended" // A debate the user argued in or bookmarked has ended
+)
+
+// defaultNotificationLimit caps how many notifications GetNotifications returns when no limit is given
+const defaultNotificationLimit = 50
+
+// Notification is a message for a single user, e.g. for the lobby badge
+type Notification struct {
+	ID        int64           `json:"id"`
+	UserID    string          `json:"user_id"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	ReadAt    *time.Time      `json:"read_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// CreateNotification stores a notification for a user. payload is stored as JSON.
+func (d *Database) CreateNotification(userID, notificationType string, payload interface{}) (int64, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode notification payload: %v", err)
+	}
+
+	result, err := d.db.Exec(`INSERT INTO notifications (user_id, type, payload) VALUES (?, ?, ?)`,
+		userID, notificationType, string(encoded))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create notification: %v", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get notification ID: %v", err)
+	}
+	return id, nil
+}
+
+// GetNotifications returns a user's notifications, newest first, along with how many are unread.
+// limit <= 0 uses defaultNotificationLimit.
+func (d *Database) GetNotifications(userID string, unreadOnly bool, limit int) ([]*Notification, int, error) {
+	if limit <= 0 {
+		limit = defaultNotificationLimit
+	}
+
+	query := `SELECT id, user_id, type, payload, read_at, created_at FROM notifications WHERE user_id = ?`
+	if unreadOnly {
+		query += ` AND read_at IS NULL`
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ?`
+
+	rows, err := d.db.Query(query, userID, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get notifications: %v", err)
+	}
+	defer rows.Close()
+
+	notifications := make([]*Notification, 0)
+	for rows.Next() {
+		var notification Notification
+		var payload string
+		var readAt sql.NullTime
+		if err := rows.Scan(&notification.ID, &notification.UserID, &notification.Type, &payload, &readAt, &notification.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan notification: %v", err)
+		}
+		notification.Payload = json.RawMessage(payload)
+		if readAt.Valid {
+			notification.ReadAt = &readAt.Time
+		}
+		notifications = append(notifications, &notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating notifications: %v", err)
+	}
+
+	var unread int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM notifications WHERE user_id = ? AND read_at IS NULL`, userID).Scan(&unread); err != nil {
+		return nil, 0, fmt.Errorf("failed to count unread notifications: %v", err)
+	}
+
+	return notifications, unread, nil
+}
+
+// MarkNotificationRead marks one of a user's notifications as read. Marking it again is a no-op.
+func (d *Database) MarkNotificationRead(id int64, userID string) error {
+	result, err := d.db.Exec(`UPDATE notifications SET read_at = COALESCE(read_at, ?) WHERE id = ? AND user_id = ?`,
+		time.Now(), id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification as read: %v", err)
+	}
+	if updated, err := result.RowsAffected(); err == nil && updated == 0 {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
+
+// GetDebateParticipants returns the distinct authenticated users who argued in a debate.
+// Anonymous players aren't included.
+func (d *Database) GetDebateParticipants(debateID string) ([]string, error) {
+	rows, err := d.db.Query(`SELECT DISTINCT user_id FROM arguments WHERE debate_id = ? AND user_id IS NOT NULL AND user_id != '' ORDER BY user_id`, debateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get debate participants: %v", err)
+	}
+	defer rows.Close()
+
+	participants := make([]string, 0)
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan debate participant: %v", err)
+		}
+		participants = append(participants, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating debate participants: %v", err)
+	}
+	return participants, nil
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupMigratedTestDB creates a temporary database with the repository's migrations, and their seed data, applied
+func setupMigratedTestDB(t *testing.T) *Database {
+	sqlDB, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "arguments.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	require.NoError(t, NewMigrationManager(sqlDB).MigrateUp(filepath.Join("..", "..", "migrations")))
+	return &Database{db: sqlDB}
+}
+
+func TestGetDebateParticipants(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	for _, arg := range []struct{ player, user, debate string }{
+		{"alice", "user-alice", "debate-1"},
+		{"alice", "user-alice", "debate-1"},
+		{"bob", "user-bob", "debate-1"},
+		{"player_1a2b3c4d", "", "debate-1"}, // anonymous
+		{"carol", "user-carol", "debate-2"},
+	} {
+		_, err := db.SaveArgument(arg.player, arg.user, "Cats vs dogs", "Cats are better", "agent1", arg.debate, ArgumentParent{})
+		require.NoError(t, err)
+	}
+
+	participants, err := db.GetDebateParticipants("debate-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user-alice", "user-bob"}, participants)
+
+	participants, err = db.GetDebateParticipants("debate-without-arguments")
+	require.NoError(t, err)
+	assert.Empty(t, participants)
+}
+
+func TestNotifications(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	first, err := db.CreateNotification("user-alice", NotificationTypeDebateEnded, map[string]string{"debate_id": "debate-1"})
+	require.NoError(t, err)
+	second, err := db.CreateNotification("user-alice", NotificationTypeDebateEnded, map[string]string{"debate_id": "debate-2"})
+	require.NoError(t, err)
+	_, err = db.CreateNotification("user-bob", NotificationTypeDebateEnded, map[string]string{"debate_id": "debate-1"})
+	require.NoError(t, err)
+
+	notifications, unread, err := db.GetNotifications("user-alice", false, 0)
+	require.NoError(t, err)
+	require.Len(t, notifications, 2)
+	assert.Equal(t, 2, unread)
+	assert.Equal(t, second, notifications[0].ID, "newest first")
+	var payload map[string]string
+	require.NoError(t, json.Unmarshal(notifications[1].Payload, &payload))
+	assert.Equal(t, "debate-1", payload["debate_id"])
+	assert.Nil(t, notifications[1].ReadAt)
+
+	// Users can only mark their own notifications as read
+	assert.Error(t, db.MarkNotificationRead(first, "user-bob"))
+	require.NoError(t, db.MarkNotificationRead(first, "user-alice"))
+	require.NoError(t, db.MarkNotificationRead(first, "user-alice"), "marking a notification read again is a no-op")
+
+	notifications, unread, err = db.GetNotifications("user-alice", true, 0)
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, second, notifications[0].ID)
+	assert.Equal(t, 1, unread)
+
+	notifications, _, err = db.GetNotifications("user-alice", false, 1)
+	require.NoError(t, err)
+	assert.Len(t, notifications, 1)
+}
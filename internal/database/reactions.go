@@ -0,0 +1,53 @@
+package database
+
+import "fmt"
+
+// AddReaction counts an emoji reaction to a debate's transcript entry and returns the entry's new count for
+// that emoji, or ErrReactionTargetNotFound if the entry isn't in the debate
+func (d *Database) AddReaction(debateID string, messageID int64, emoji string) (int, error) {
+	result, err := d.db.Exec(`INSERT INTO message_reactions (debate_id, message_id, emoji, count)
+		SELECT debate_id, id, ?, 1 FROM debate_messages WHERE id = ? AND debate_id = ?
+		ON CONFLICT (debate_id, message_id, emoji) DO UPDATE SET count = count + 1`,
+		emoji, messageID, debateID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add reaction: %v", err)
+	}
+	if added, err := result.RowsAffected(); err == nil && added == 0 {
+		return 0, ErrReactionTargetNotFound
+	}
+
+	var count int
+	err = d.db.QueryRow(`SELECT count FROM message_reactions WHERE debate_id = ? AND message_id = ? AND emoji = ?`,
+		debateID, messageID, emoji).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get reaction count: %v", err)
+	}
+	return count, nil
+}
+
+// GetReactionCounts returns how often each emoji was used to react to a debate's transcript entries, by entry ID
+func (d *Database) GetReactionCounts(debateID string) (map[int64]map[string]int, error) {
+	rows, err := d.db.Query(`SELECT message_id, emoji, count FROM message_reactions WHERE debate_id = ?`, debateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reaction counts: %v", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]map[string]int)
+	for rows.Next() {
+		var messageID int64
+		var emoji string
+		var count int
+		if err := rows.Scan(&messageID, &emoji, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan reaction count: %v", err)
+		}
+		if counts[messageID] == nil {
+			counts[messageID] = make(map[string]int)
+		}
+		counts[messageID][emoji] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get reaction counts: %v", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReactions(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	agentTurn, err := db.SaveDebateMessage(&DebateMessage{DebateID: "debate-1", Speaker: "Tony", Message: "Dogs are loyal"})
+	require.NoError(t, err)
+	playerTurn, err := db.SaveDebateMessage(&DebateMessage{DebateID: "debate-1", Speaker: "alice", Message: "Cats are better", IsPlayer: true})
+	require.NoError(t, err)
+
+	count, err := db.AddReaction("debate-1", agentTurn, "🔥")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	count, err = db.AddReaction("debate-1", agentTurn, "🔥")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	_, err = db.AddReaction("debate-1", agentTurn, "😂")
+	require.NoError(t, err)
+	_, err = db.AddReaction("debate-1", playerTurn, "👏")
+	require.NoError(t, err)
+
+	// Reactions must target an entry of the same debate
+	_, err = db.AddReaction("debate-2", agentTurn, "🔥")
+	assert.ErrorIs(t, err, ErrReactionTargetNotFound)
+	_, err = db.AddReaction("debate-1", playerTurn+100, "🔥")
+	assert.ErrorIs(t, err, ErrReactionTargetNotFound)
+
+	counts, err := db.GetReactionCounts("debate-1")
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]map[string]int{
+		agentTurn:  {"🔥": 2, "😂": 1},
+		playerTurn: {"👏": 1},
+	}, counts)
+
+	counts, err = db.GetReactionCounts("debate-2")
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
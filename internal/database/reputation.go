@@ -0,0 +1,103 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// User reputation runs from 0 to 100 and is recomputed periodically by RecomputeReputations:
+//
+//	quality    = average LLM score (0-100) of the user's own arguments, excluding vote adjustments
+//	confidence = min(argument count, ReputationConfidenceArguments) / ReputationConfidenceArguments
+//	votes      = upvotes minus downvotes received on those arguments, clamped to ±ReputationMaxVoteBonus
+//	reputation = clamp(quality*confidence + votes, 0, 100)
+//
+// A user's reputation places them in a tier, and each of their votes moves an argument's
+// vote_score by the score blend's vote factor (VoteStep by default) times the tier weight. The
+// combined vote_score is clamped to ±MaxVoteScore by default, so votes can never outweigh the
+// LLM score they are blended with unless the blend is configured to (see score_blend.go).
+const (
+	ReputationConfidenceArguments = 10   // Arguments needed before quality counts in full
+	ReputationMaxVoteBonus        = 20.0 // Cap on the net votes contribution
+	VoteStep                      = 0.2  // vote_score change for a single unweighted vote
+	MaxVoteScore                  = 2.0  // Bound on an argument's total vote_score
+)
+
+// ReputationTier is a band of reputation whose votes carry the same weight
+type ReputationTier struct {
+	Name       string  `json:"name"`
+	MinScore   float64 `json:"min_score"` // Inclusive lower bound
+	VoteWeight float64 `json:"vote_weight"`
+}
+
+// ReputationTiers lists the tiers in ascending order of reputation
+var ReputationTiers = []ReputationTier{
+	{Name: "newcomer", MinScore: 0, VoteWeight: 1.0},
+	{Name: "established", MinScore: 20, VoteWeight: 1.25},
+	{Name: "trusted", MinScore: 50, VoteWeight: 1.5},
+	{Name: "expert", MinScore: 80, VoteWeight: 2.0},
+}
+
+// GetReputationTier returns the tier for a reputation score
+func GetReputationTier(reputation float64) ReputationTier {
+	tier := ReputationTiers[0]
+	for _, t := range ReputationTiers {
+		if reputation >= t.MinScore {
+			tier = t
+		}
+	}
+	return tier
+}
+
+// reputationWeightSQL builds a SQL CASE expression mapping a reputation column to its tier's vote weight,
+// so the SQL in SubmitVote can't drift from GetReputationTier
+func reputationWeightSQL(column string) string {
+	var b strings.Builder
+	b.WriteString("CASE")
+	for i := len(ReputationTiers) - 1; i > 0; i-- {
+		fmt.Fprintf(&b, " WHEN %s >= %g THEN %g", column, ReputationTiers[i].MinScore, ReputationTiers[i].VoteWeight)
+	}
+	fmt.Fprintf(&b, " ELSE %g END", ReputationTiers[0].VoteWeight)
+	return b.String()
+}
+
+// GetUserReputation returns a user's current reputation score
+func (d *Database) GetUserReputation(userID string) (float64, error) {
+	var reputation float64
+	err := d.db.QueryRow(`SELECT reputation FROM users WHERE id = ?`, userID).Scan(&reputation)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("user %s not found", userID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get reputation for user %s: %v", userID, err)
+	}
+	return reputation, nil
+}
+
+// RecomputeReputations recalculates every user's reputation from their arguments and the votes they received.
+// Arguments are matched to users by ID or by username, since WebSocket arguments are stored under display names.
+func (d *Database) RecomputeReputations() (int64, error) {
+	query := fmt.Sprintf(`
+		UPDATE users SET
+			reputation = (
+				SELECT MAX(0.0, MIN(100.0,
+					COALESCE(AVG((s.strength + s.relevance + s.logic + s.truth + s.humor) / 5.0), 0)
+						* MIN(COUNT(a.id), %[1]d) / %[1]d.0
+					+ MAX(-%[2]g, MIN(%[2]g, COALESCE(SUM(a.upvotes), 0) - COALESCE(SUM(a.downvotes), 0)))
+				))
+				FROM arguments a
+				LEFT JOIN scores s ON s.argument_id = a.id
+				WHERE a.player_id = users.id OR a.player_id = users.username
+			),
+			reputation_updated_at = CURRENT_TIMESTAMP`,
+		ReputationConfidenceArguments, ReputationMaxVoteBonus)
+
+	result, err := d.db.Exec(query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recompute reputations: %v", err)
+	}
+
+	updated, _ := result.RowsAffected()
+	return updated, nil
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReputationTier(t *testing.T) {
+	testCases := []struct {
+		name           string
+		reputation     float64
+		expectedTier   string
+		expectedWeight float64
+	}{
+		{name: "Zero reputation", reputation: 0, expectedTier: "newcomer", expectedWeight: 1.0},
+		{name: "Negative reputation", reputation: -5, expectedTier: "newcomer", expectedWeight: 1.0},
+		{name: "Just below established", reputation: 19.99, expectedTier: "newcomer", expectedWeight: 1.0},
+		{name: "Established lower bound", reputation: 20, expectedTier: "established", expectedWeight: 1.25},
+		{name: "Just below trusted", reputation: 49.99, expectedTier: "established", expectedWeight: 1.25},
+		{name: "Trusted lower bound", reputation: 50, expectedTier: "trusted", expectedWeight: 1.5},
+		{name: "Just below expert", reputation: 79.99, expectedTier: "trusted", expectedWeight: 1.5},
+		{name: "Expert lower bound", reputation: 80, expectedTier: "expert", expectedWeight: 2.0},
+		{name: "Maximum reputation", reputation: 100, expectedTier: "expert", expectedWeight: 2.0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tier := GetReputationTier(tc.reputation)
+			assert.Equal(t, tc.expectedTier, tier.Name)
+			assert.Equal(t, tc.expectedWeight, tier.VoteWeight)
+		})
+	}
+}
+
+func TestReputationWeightBounds(t *testing.T) {
+	// A single vote from the highest tier must stay well inside the vote_score bound
+	maxWeight := ReputationTiers[len(ReputationTiers)-1].VoteWeight
+	assert.Less(t, VoteStep*maxWeight, MaxVoteScore)
+
+	// Tiers must be ordered so GetReputationTier and the SQL CASE agree
+	for i := 1; i < len(ReputationTiers); i++ {
+		assert.Greater(t, ReputationTiers[i].MinScore, ReputationTiers[i-1].MinScore)
+		assert.GreaterOrEqual(t, ReputationTiers[i].VoteWeight, ReputationTiers[i-1].VoteWeight)
+	}
+
+	assert.Equal(t,
+		"CASE WHEN r >= 80 THEN 2 WHEN r >= 50 THEN 1.5 WHEN r >= 20 THEN 1.25 ELSE 1 END",
+		reputationWeightSQL("r"))
+}
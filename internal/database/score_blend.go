@@ -0,0 +1,131 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// An argument's ranking score (scores.average, which leaderboards sort by) blends its LLM score with the votes
+// it received:
+//
+//	vote_score = clamp(VoteFactor * sum(±voter tier weight), ±MaxVoteScore)
+//	additive:     average = LLM score + vote_score
+//	proportional: average = LLM score * (1 + vote_score/100)
+//
+// so under the additive formula vote_score is worth points, and under the proportional one a percentage of the
+// LLM score. A VoteFactor of 0 ranks by the LLM score alone; raising VoteFactor and MaxVoteScore lets votes
+// dominate. The LLM score itself is kept in scores.llm_average, so changing the blend recomputes every average.
+// The blend the averages were computed with is stored in the score_blend table.
+const (
+	ScoreBlendAdditive     = "additive"
+	ScoreBlendProportional = "proportional"
+)
+
+// ScoreBlend configures how votes blend into argument scores
+type ScoreBlend struct {
+	Formula      string  `json:"formula"`
+	VoteFactor   float64 `json:"vote_factor"`    // vote_score change for a single unweighted vote
+	MaxVoteScore float64 `json:"max_vote_score"` // Bound on an argument's total vote_score
+}
+
+// DefaultScoreBlend returns the blend used unless SetScoreBlend configures another: additive, with each vote
+// worth VoteStep up to MaxVoteScore
+func DefaultScoreBlend() ScoreBlend {
+	return ScoreBlend{Formula: ScoreBlendAdditive, VoteFactor: VoteStep, MaxVoteScore: MaxVoteScore}
+}
+
+// Validate checks that the blend has a known formula and non-negative bounds
+func (b ScoreBlend) Validate() error {
+	if b.Formula != ScoreBlendAdditive && b.Formula != ScoreBlendProportional {
+		return fmt.Errorf("unknown score blend formula %q, expected %s or %s", b.Formula, ScoreBlendAdditive, ScoreBlendProportional)
+	}
+	if b.VoteFactor < 0 {
+		return fmt.Errorf("vote factor cannot be negative")
+	}
+	if b.MaxVoteScore < 0 {
+		return fmt.Errorf("max vote score cannot be negative")
+	}
+	return nil
+}
+
+// voteScoreSQL builds the SQL expression for the vote_score of the argument whose ID is argumentID
+func (b ScoreBlend) voteScoreSQL(argumentID string) string {
+	return fmt.Sprintf(`MAX(-%[1]g, MIN(%[1]g, %[2]g * (
+				SELECT COALESCE(SUM(
+					CASE WHEN v.vote_type = 'upvote' THEN 1 ELSE -1 END * (%[3]s)
+				), 0)
+				FROM votes v
+				LEFT JOIN users u ON u.id = v.user_id
+				WHERE v.argument_id = %[4]s
+			)))`, b.MaxVoteScore, b.VoteFactor, reputationWeightSQL("COALESCE(u.reputation, 0)"), argumentID)
+}
+
+// averageSQL builds the SQL expression blending the LLM score llm with the stored vote_score of the argument
+// whose ID is argumentID. llm comes before argumentID in the expression and each appears once, so both can be
+// bound as positional parameters.
+func (b ScoreBlend) averageSQL(llm, argumentID string) string {
+	vote := fmt.Sprintf("COALESCE((SELECT vote_score FROM arguments WHERE id = %s), 0)", argumentID)
+	if b.Formula == ScoreBlendProportional {
+		return fmt.Sprintf("(%s) * (1 + %s / 100.0)", llm, vote)
+	}
+	return fmt.Sprintf("(%s) + %s", llm, vote)
+}
+
+// blend returns the configured score blend, the default for a Database that never had one set
+func (d *Database) blend() ScoreBlend {
+	if d.scoreBlend.Formula == "" {
+		return DefaultScoreBlend()
+	}
+	return d.scoreBlend
+}
+
+// SetScoreBlend changes how votes blend into argument scores. If it differs from the blend stored with the
+// scores, every argument's vote_score and average is recomputed under it, so rankings stay consistent. It's meant
+// to be called once at startup, before the database is shared.
+func (d *Database) SetScoreBlend(blend ScoreBlend) error {
+	if err := blend.Validate(); err != nil {
+		return err
+	}
+	d.scoreBlend = blend
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var stored ScoreBlend
+	err = tx.QueryRow(`SELECT formula, vote_factor, max_vote_score FROM score_blend WHERE id = 1`).
+		Scan(&stored.Formula, &stored.VoteFactor, &stored.MaxVoteScore)
+	if err == nil && stored == blend {
+		return nil
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get stored score blend: %v", err)
+	}
+
+	if _, err := tx.Exec(`UPDATE arguments SET vote_score = ` + blend.voteScoreSQL("arguments.id")); err != nil {
+		return fmt.Errorf("failed to recompute vote scores: %v", err)
+	}
+	if _, err := tx.Exec(`UPDATE scores SET average = ` + blend.averageSQL("llm_average", "scores.argument_id") +
+		` WHERE llm_average IS NOT NULL`); err != nil {
+		return fmt.Errorf("failed to recompute score averages: %v", err)
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO score_blend (id, formula, vote_factor, max_vote_score, updated_at)
+		VALUES (1, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(id) DO UPDATE SET
+			formula = excluded.formula,
+			vote_factor = excluded.vote_factor,
+			max_vote_score = excluded.max_vote_score,
+			updated_at = excluded.updated_at`,
+		blend.Formula, blend.VoteFactor, blend.MaxVoteScore); err != nil {
+		return fmt.Errorf("failed to save score blend: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
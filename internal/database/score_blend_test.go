@@ -0,0 +1,122 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/scoring"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreBlendLeaderboardOrdering(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	// The LLM prefers "strong", but the crowd prefers "popular"
+	save := func(content string, llmScore int) int64 {
+		id, err := db.SaveArgument("alice", "", "Cats vs dogs", content, "agent1", "debate-1", ArgumentParent{})
+		require.NoError(t, err)
+		require.NoError(t, db.SaveScore(id, "debate-1", &scoring.ArgumentScore{
+			Strength: llmScore, Relevance: llmScore, Logic: llmScore, Truth: llmScore, Humor: llmScore, Average: float64(llmScore),
+		}))
+		return id
+	}
+	strong := save("strong", 80)
+	popular := save("popular", 70)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, db.SubmitVote(fmt.Sprintf("voter-%d", i), popular, "debate-1", "upvote"))
+	}
+	require.NoError(t, db.SubmitVote("voter-0", strong, "debate-1", "downvote"))
+
+	ranking := func() []int64 {
+		arguments, err := db.GetLeaderboard("debate-1", 10)
+		require.NoError(t, err)
+		ids := make([]int64, len(arguments))
+		for i, argument := range arguments {
+			ids[i] = argument.ID
+		}
+		return ids
+	}
+	averages := func() (float64, float64) {
+		arguments, err := db.GetLeaderboard("debate-1", 10)
+		require.NoError(t, err)
+		byID := map[int64]float64{}
+		for _, argument := range arguments {
+			byID[argument.ID] = argument.Score.Average
+		}
+		return byID[strong], byID[popular]
+	}
+
+	// The default blend caps votes at ±2 points, so the LLM score decides
+	assert.Equal(t, []int64{strong, popular}, ranking())
+	strongAverage, popularAverage := averages()
+	assert.InDelta(t, 79.8, strongAverage, 1e-9)
+	assert.InDelta(t, 72.0, popularAverage, 1e-9)
+
+	// A factor of 0 ranks by the LLM score alone
+	require.NoError(t, db.SetScoreBlend(ScoreBlend{Formula: ScoreBlendAdditive, VoteFactor: 0, MaxVoteScore: MaxVoteScore}))
+	assert.Equal(t, []int64{strong, popular}, ranking())
+	strongAverage, popularAverage = averages()
+	assert.InDelta(t, 80.0, strongAverage, 1e-9)
+	assert.InDelta(t, 70.0, popularAverage, 1e-9)
+
+	// A high factor lets the votes dominate
+	require.NoError(t, db.SetScoreBlend(ScoreBlend{Formula: ScoreBlendAdditive, VoteFactor: 5, MaxVoteScore: 50}))
+	assert.Equal(t, []int64{popular, strong}, ranking())
+	strongAverage, popularAverage = averages()
+	assert.InDelta(t, 75.0, strongAverage, 1e-9)
+	assert.InDelta(t, 120.0, popularAverage, 1e-9)
+
+	// New votes and rescoring are blended the same way
+	require.NoError(t, db.SubmitVote("voter-0", popular, "debate-1", "downvote"))
+	_, popularAverage = averages()
+	assert.InDelta(t, 110.0, popularAverage, 1e-9)
+	require.NoError(t, db.UpdateScore(strong, &scoring.ArgumentScore{Average: 90}))
+	strongAverage, _ = averages()
+	assert.InDelta(t, 85.0, strongAverage, 1e-9)
+
+	// Proportionally, vote_score is a percentage of the LLM score
+	require.NoError(t, db.SetScoreBlend(ScoreBlend{Formula: ScoreBlendProportional, VoteFactor: 5, MaxVoteScore: 50}))
+	strongAverage, popularAverage = averages()
+	assert.InDelta(t, 90*0.95, strongAverage, 1e-9)
+	assert.InDelta(t, 70*1.4, popularAverage, 1e-9)
+	assert.Equal(t, []int64{popular, strong}, ranking())
+}
+
+func TestSetScoreBlendUnchanged(t *testing.T) {
+	db := setupMigratedTestDB(t)
+	argumentID, err := db.SaveArgument("alice", "", "Cats vs dogs", "An argument", "agent1", "debate-1", ArgumentParent{})
+	require.NoError(t, err)
+	require.NoError(t, db.SaveScore(argumentID, "debate-1", &scoring.ArgumentScore{Average: 80}))
+
+	average := func() float64 {
+		var average float64
+		require.NoError(t, db.db.QueryRow(`SELECT average FROM scores WHERE argument_id = ?`, argumentID).Scan(&average))
+		return average
+	}
+
+	require.NoError(t, db.SetScoreBlend(DefaultScoreBlend()))
+	_, err = db.db.Exec(`UPDATE scores SET average = 0 WHERE argument_id = ?`, argumentID)
+	require.NoError(t, err)
+
+	// Setting the stored blend again, as every restart does, leaves the scores alone
+	require.NoError(t, db.SetScoreBlend(DefaultScoreBlend()))
+	assert.Equal(t, 0.0, average())
+
+	// A different blend recomputes them
+	require.NoError(t, db.SetScoreBlend(ScoreBlend{Formula: ScoreBlendProportional, VoteFactor: VoteStep, MaxVoteScore: MaxVoteScore}))
+	assert.InDelta(t, 80.0, average(), 1e-9)
+	assert.Equal(t, ScoreBlendProportional, db.blend().Formula)
+}
+
+func TestScoreBlendValidate(t *testing.T) {
+	assert.NoError(t, DefaultScoreBlend().Validate())
+	assert.NoError(t, ScoreBlend{Formula: ScoreBlendProportional}.Validate())
+	assert.Error(t, ScoreBlend{Formula: "multiplicative", VoteFactor: VoteStep}.Validate())
+	assert.Error(t, ScoreBlend{Formula: ScoreBlendAdditive, VoteFactor: -1}.Validate())
+	assert.Error(t, ScoreBlend{Formula: ScoreBlendAdditive, MaxVoteScore: -1}.Validate())
+
+	db := setupMigratedTestDB(t)
+	assert.Error(t, db.SetScoreBlend(ScoreBlend{}))
+	assert.Equal(t, DefaultScoreBlend(), db.blend(), "an invalid blend isn't applied")
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTopics(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	existing, err := db.GetTopic(1)
+	require.NoError(t, err, "the seeded topics should be present")
+
+	topics := []*Topic{
+		{Title: "Cats vs dogs", Agent1Name: "Agent 1", Agent1Role: "Cat person", Agent2Name: "Agent 2", Agent2Role: "Dog person", Category: "animals"},
+		{Title: "  " + existing.Title + " ", Agent1Name: "Agent 1", Agent1Role: "Role 1", Agent2Name: "Agent 2", Agent2Role: "Role 2"},
+		{Title: "CATS VS DOGS", Agent1Name: "Agent 1", Agent1Role: "Cat person", Agent2Name: "Agent 2", Agent2Role: "Dog person"},
+	}
+	skipped, err := db.CreateTopics(topics)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, skipped, "titles matching an existing topic or an earlier one in the batch are skipped")
+	require.NotZero(t, topics[0].ID)
+
+	created, err := db.GetTopic(topics[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Cats vs dogs", created.Title)
+	assert.Equal(t, "animals", created.Category)
+	assert.Empty(t, created.Description)
+}
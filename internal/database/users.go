@@ -2,8 +2,10 @@ package database
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -143,7 +145,7 @@ func (d *Database) GetUserByID(id string) (*User, error) {
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user with ID %s not found", id)
+			return nil, fmt.Errorf("user with ID %s not found: %w", id, ErrUserNotFound)
 		}
 		return nil, fmt.Errorf("failed to get user: %v", err)
 	}
@@ -309,31 +311,47 @@ func (d *Database) GetUserByEmail(email string) (*User, error) {
 	return &user, nil
 }
 
-// UpdateUser updates a user
-func (d *Database) UpdateUser(user *User) error {
+// UpdateUser updates a user, unless it changed since it was read: expectedUpdatedAt is the UpdatedAt the caller
+// read, and ErrUserModified is returned if it no longer matches. On success user.UpdatedAt is set to the new version.
+func (d *Database) UpdateUser(user *User, expectedUpdatedAt time.Time) error {
+	// Millisecond precision, unlike CURRENT_TIMESTAMP, so updates within the same second get distinct versions.
+	// Versions are compared with julianday since older rows store updated_at in CURRENT_TIMESTAMP's format.
+	updatedAt := time.Now().UTC().Truncate(time.Millisecond)
 	query := `UPDATE users SET
 		username = ?,
 		email = ?,
 		role = ?,
 		account_locked = ?,
 		email_verified = ?,
-		updated_at = CURRENT_TIMESTAMP
-	WHERE id = ?`
+		updated_at = ?
+	WHERE id = ? AND julianday(updated_at) = julianday(?)`
 
-	_, err := d.db.Exec(
+	result, err := d.db.Exec(
 		query,
 		user.Username,
 		user.Email,
 		user.Role,
 		user.AccountLocked,
 		user.EmailVerified,
+		updatedAt,
 		user.ID,
+		expectedUpdatedAt.UTC(),
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to update user: %v", err)
 	}
 
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		// Either the user is gone or someone else updated it first
+		if _, err := d.GetUserByID(user.ID); err != nil {
+			return err
+		}
+		return ErrUserModified
+	}
+
+	user.UpdatedAt = updatedAt
 	return nil
 }
 
@@ -364,6 +382,31 @@ func (d *Database) UpdatePassword(userID string, password string) error {
 	return nil
 }
 
+// GetUserCountsByRole returns the number of users holding each role
+func (d *Database) GetUserCountsByRole() (map[string]int, error) {
+	rows, err := d.db.Query(`SELECT role, COUNT(*) FROM users GROUP BY role`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users by role: %v", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var role string
+		var count int
+		if err := rows.Scan(&role, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan user role count: %v", err)
+		}
+		counts[role] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user role counts: %v", err)
+	}
+
+	return counts, nil
+}
+
 // DeleteUser deletes a user
 func (d *Database) DeleteUser(id string) error {
 	// Start a transaction to ensure both operations succeed or fail together
@@ -380,11 +423,15 @@ func (d *Database) DeleteUser(id string) error {
 	}
 
 	// Delete the user
-	_, err = tx.Exec("DELETE FROM users WHERE id = ?", id)
+	result, err := tx.Exec("DELETE FROM users WHERE id = ?", id)
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to delete user: %v", err)
 	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		tx.Rollback()
+		return fmt.Errorf("user with ID %s not found: %w", id, ErrUserNotFound)
+	}
 
 	// Commit the transaction
 	if err := tx.Commit(); err != nil {
@@ -394,6 +441,84 @@ func (d *Database) DeleteUser(id string) error {
 	return nil
 }
 
+// AnonymizedUserPrefix starts the placeholder ID that replaces a deleted user's ID in debate history
+const AnonymizedUserPrefix = "deleted_user_"
+
+// anonymizedUserID derives the stable placeholder ID for a deleted user
+func anonymizedUserID(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return AnonymizedUserPrefix + hex.EncodeToString(sum[:])[:12]
+}
+
+// DeleteUserAnonymized deletes a user's account while keeping the debates they took part in intact.
+// The user row is kept as a tombstone under a placeholder ID with its personal fields cleared, their content is
+// reassigned to that placeholder, and their auth material is deleted. It returns the placeholder ID.
+func (d *Database) DeleteUserAnonymized(id string) (string, error) {
+	user, err := d.GetUserByID(id)
+	if err != nil {
+		return "", err
+	}
+	anonymousID := anonymizedUserID(id)
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %v", err)
+	}
+
+	// Auth material goes first so the account can't be used while it is being anonymized
+	steps := []struct {
+		description string
+		query       string
+		args        []interface{}
+	}{
+		{"delete refresh tokens", `DELETE FROM refresh_tokens WHERE user_id = ?`, []interface{}{id}},
+		// Signed-in players' arguments carry their display name as player_id and their ID as user_id
+		{"reassign arguments", `UPDATE arguments SET player_id = ?, user_id = ?, wallet_address = NULL WHERE user_id = ? OR player_id = ?`, []interface{}{anonymousID, anonymousID, id, id}},
+		{"reassign votes", `UPDATE votes SET user_id = ? WHERE user_id = ?`, []interface{}{anonymousID, id}},
+		{"reassign chat messages", `UPDATE chat_messages SET player_id = ?, username = ? WHERE player_id = ?`, []interface{}{anonymousID, anonymousID, id}},
+		{"reassign transcript entries", `UPDATE debate_messages SET speaker = ? WHERE is_player = 1 AND speaker IN (?, ?)`, []interface{}{anonymousID, id, user.Username}},
+		{"reassign debates", `UPDATE debates SET created_by = ? WHERE created_by = ?`, []interface{}{anonymousID, id}},
+		{"reassign debate participants", `UPDATE debate_participants SET user_id = ? WHERE user_id = ?`, []interface{}{anonymousID, id}},
+		{"reassign debate invitations", `UPDATE debate_participants SET invited_by = ? WHERE invited_by = ?`, []interface{}{anonymousID, id}},
+		{"reassign invitation codes", `UPDATE invitation_codes SET created_by = ? WHERE created_by = ?`, []interface{}{anonymousID, id}},
+		{"reassign used invitation codes", `UPDATE invitation_codes SET used_by = ? WHERE used_by = ?`, []interface{}{anonymousID, id}},
+		{"reassign feedback", `UPDATE feedback SET user_id = ? WHERE user_id = ?`, []interface{}{anonymousID, id}},
+		// Foreign keys aren't enforced, so rows that would cascade with the user are deleted here
+		{"delete notifications", `DELETE FROM notifications WHERE user_id = ?`, []interface{}{id}},
+		{"delete bookmarks", `DELETE FROM bookmarks WHERE user_id = ?`, []interface{}{id}},
+		{"anonymize user", `UPDATE users SET
+			id = ?,
+			username = ?,
+			email = ?,
+			password_hash = '',
+			role = ?,
+			last_login = NULL,
+			account_locked = TRUE,
+			email_verified = FALSE,
+			verification_token = NULL,
+			reset_token = NULL,
+			reset_token_expires = NULL,
+			wallet_address = NULL,
+			wallet_verified = FALSE,
+			wallet_connected_at = NULL,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`, []interface{}{anonymousID, anonymousID, anonymousID + "@deleted.invalid", RoleUser, id}},
+	}
+
+	for _, step := range steps {
+		if _, err := tx.Exec(step.query, step.args...); err != nil {
+			tx.Rollback()
+			return "", fmt.Errorf("failed to %s: %v", step.description, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return anonymousID, nil
+}
+
 // CreateRefreshToken creates a new refresh token for a user
 func (d *Database) CreateRefreshToken(userID string, token string, expiresAt time.Time) error {
 	query := `INSERT INTO refresh_tokens (user_id, token, expires_at) VALUES (?, ?, ?)`
@@ -3,6 +3,7 @@ package database
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -148,6 +149,8 @@ func TestUpdateUser(t *testing.T) {
 	// Create the user
 	err := db.CreateUser(user, "password123")
 	assert.NoError(t, err)
+	created, err := db.GetUserByID(user.ID)
+	require.NoError(t, err)
 
 	// Update the user
 	user.Username = "updateduser"
@@ -155,7 +158,7 @@ func TestUpdateUser(t *testing.T) {
 	user.Role = RoleModerator
 	user.EmailVerified = true
 
-	err = db.UpdateUser(user)
+	err = db.UpdateUser(user, created.UpdatedAt)
 	assert.NoError(t, err)
 
 	// Get the updated user
@@ -167,6 +170,41 @@ func TestUpdateUser(t *testing.T) {
 	assert.True(t, retrievedUser.EmailVerified)
 }
 
+func TestUpdateUserStaleWrite(t *testing.T) {
+	db, _, cleanup := setupUsersTestDB(t)
+	defer cleanup()
+
+	require.NoError(t, db.CreateUser(&User{ID: "test-user-id", Username: "testuser", Email: "test@example.com", Role: RoleUser}, "password123"))
+
+	// Two editors read the same version of the user
+	first, err := db.GetUserByID("test-user-id")
+	require.NoError(t, err)
+	second, err := db.GetUserByID("test-user-id")
+	require.NoError(t, err)
+
+	// The first write wins and bumps the version, even within the same second as the user was created
+	first.Username = "firstedit"
+	require.NoError(t, db.UpdateUser(first, first.UpdatedAt))
+
+	// The second write is based on a stale read and must not clobber the first
+	second.Email = "second@example.com"
+	err = db.UpdateUser(second, second.UpdatedAt)
+	assert.ErrorIs(t, err, ErrUserModified)
+
+	current, err := db.GetUserByID("test-user-id")
+	require.NoError(t, err)
+	assert.Equal(t, "firstedit", current.Username)
+	assert.Equal(t, "test@example.com", current.Email)
+
+	// After refetching, the second editor can write
+	current.Email = "second@example.com"
+	require.NoError(t, db.UpdateUser(current, current.UpdatedAt))
+
+	// Unknown users are reported as such, not as conflicts
+	err = db.UpdateUser(&User{ID: "missing-user"}, time.Now())
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
 func TestUpdatePassword(t *testing.T) {
 	// Set up test database
 	db, _, cleanup := setupUsersTestDB(t)
@@ -472,10 +510,48 @@ func TestResendVerificationEmail(t *testing.T) {
 
 	// Try to resend for a verified email
 	retrievedUser.EmailVerified = true
-	err = db.UpdateUser(retrievedUser)
+	err = db.UpdateUser(retrievedUser, retrievedUser.UpdatedAt)
 	assert.NoError(t, err)
 
 	_, err = db.ResendVerificationEmail(user.Email)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "already verified")
 }
+
+func TestAnonymizedUserID(t *testing.T) {
+	id := anonymizedUserID("test-user-id")
+
+	// The placeholder is stable for a user, distinct between users and doesn't contain the original ID
+	assert.Equal(t, id, anonymizedUserID("test-user-id"))
+	assert.NotEqual(t, id, anonymizedUserID("other-user-id"))
+	assert.True(t, strings.HasPrefix(id, AnonymizedUserPrefix))
+	assert.NotContains(t, id, "test-user-id")
+}
+
+func TestDeleteUserAnonymizedScrubsUserRows(t *testing.T) {
+	db := setupMigratedTestDB(t)
+
+	user := &User{ID: "user-alice", Username: "alice", Email: "alice@example.com", Role: RoleUser}
+	require.NoError(t, db.CreateUser(user, "password123"))
+
+	// The websocket saves a signed-in player's arguments under their display name, with their ID as user_id
+	argumentID, err := db.SaveArgument("Alice the Great", user.ID, "Cats vs dogs", "Cats are better", "agent1", "debate-1", ArgumentParent{})
+	require.NoError(t, err)
+	_, err = db.CreateNotification(user.ID, "debate_ended", map[string]string{"debate_id": "debate-1"})
+	require.NoError(t, err)
+	require.NoError(t, db.AddBookmark(user.ID, "debate-1"))
+
+	anonymousID, err := db.DeleteUserAnonymized(user.ID)
+	require.NoError(t, err)
+
+	var playerID, userID string
+	require.NoError(t, db.db.QueryRow(`SELECT player_id, user_id FROM arguments WHERE id = ?`, argumentID).Scan(&playerID, &userID))
+	assert.Equal(t, anonymousID, playerID, "the display name is scrubbed")
+	assert.Equal(t, anonymousID, userID)
+
+	var notifications, bookmarks int
+	require.NoError(t, db.db.QueryRow(`SELECT COUNT(*) FROM notifications WHERE user_id = ?`, user.ID).Scan(&notifications))
+	require.NoError(t, db.db.QueryRow(`SELECT COUNT(*) FROM bookmarks WHERE user_id = ?`, user.ID).Scan(&bookmarks))
+	assert.Zero(t, notifications)
+	assert.Zero(t, bookmarks)
+}
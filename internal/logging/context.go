@@ -0,0 +1,50 @@
+package logging
+
+import "context"
+
+// fieldsKey is the context key for fields bound with WithFields
+type fieldsKey struct{}
+
+// WithFields returns a copy of ctx carrying fields that the *Ctx logging functions add to every entry,
+// e.g. the request ID for an HTTP request or the debate ID for a debate loop. Fields already bound to ctx are kept
+// unless overridden.
+func WithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, fieldsKey{}, mergeContext(ContextFields(ctx), fields))
+}
+
+// ContextFields returns the fields bound to ctx, or nil if there are none
+func ContextFields(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).(map[string]interface{})
+	return fields
+}
+
+// withContextFields prepends the fields bound to ctx, so fields passed to the call take precedence
+func withContextFields(ctx context.Context, context []map[string]interface{}) []map[string]interface{} {
+	return append([]map[string]interface{}{ContextFields(ctx)}, context...)
+}
+
+// DebugCtx logs a debug message tagged with the fields bound to ctx
+func DebugCtx(ctx context.Context, msg string, context ...map[string]interface{}) {
+	Debug(msg, withContextFields(ctx, context)...)
+}
+
+// InfoCtx logs an info message tagged with the fields bound to ctx
+func InfoCtx(ctx context.Context, msg string, context ...map[string]interface{}) {
+	Info(msg, withContextFields(ctx, context)...)
+}
+
+// WarnCtx logs a warning message tagged with the fields bound to ctx
+func WarnCtx(ctx context.Context, msg string, context ...map[string]interface{}) {
+	Warn(msg, withContextFields(ctx, context)...)
+}
+
+// ErrorCtx logs an error message tagged with the fields bound to ctx
+func ErrorCtx(ctx context.Context, msg string, context ...map[string]interface{}) {
+	Error(msg, withContextFields(ctx, context)...)
+}
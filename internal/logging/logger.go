@@ -1,12 +1,15 @@
 package logging
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -39,6 +42,24 @@ func (l LogLevel) String() string {
 	}
 }
 
+// ParseLevel parses a level name such as "debug" or "INFO" into a LogLevel
+func ParseLevel(name string) (LogLevel, error) {
+	for _, level := range []LogLevel{DEBUG, INFO, WARN, ERROR, FATAL} {
+		if strings.EqualFold(name, level.String()) {
+			return level, nil
+		}
+	}
+	return INFO, fmt.Errorf("unknown log level %q (must be one of DEBUG, INFO, WARN, ERROR, FATAL)", name)
+}
+
+// Format selects how log entries are written
+type Format string
+
+const (
+	FormatText Format = "text" // Human-readable lines, optionally colored (default)
+	FormatJSON Format = "json" // One flat JSON object per line, for log aggregation
+)
+
 // Color codes for terminal output
 const (
 	ColorReset  = "\033[0m"
@@ -53,9 +74,11 @@ const (
 
 // Logger provides structured logging capabilities
 type Logger struct {
+	levelMu    sync.RWMutex // Guards level, which can change at runtime
 	level      LogLevel
 	prefix     string
 	colored    bool
+	format     Format
 	fileLogger *log.Logger
 	file       *os.File
 }
@@ -70,6 +93,7 @@ type Config struct {
 	Colored     bool
 	LogToFile   bool
 	LogFilePath string
+	Format      Format // FormatText (default) or FormatJSON
 }
 
 // NewLogger creates a new logger instance
@@ -78,6 +102,10 @@ func NewLogger(config Config) (*Logger, error) {
 		level:   config.Level,
 		prefix:  config.Prefix,
 		colored: config.Colored,
+		format:  config.Format,
+	}
+	if logger.format != FormatJSON {
+		logger.format = FormatText
 	}
 
 	// Set up file logging if enabled
@@ -112,6 +140,22 @@ func InitDefaultLogger(config Config) error {
 	return err
 }
 
+// Level returns the logger's current minimum level
+func (l *Logger) Level() LogLevel {
+	l.levelMu.RLock()
+	defer l.levelMu.RUnlock()
+	return l.level
+}
+
+// SetLevel changes the logger's minimum level at runtime and returns the previous level
+func (l *Logger) SetLevel(level LogLevel) LogLevel {
+	l.levelMu.Lock()
+	defer l.levelMu.Unlock()
+	previous := l.level
+	l.level = level
+	return previous
+}
+
 // Close closes the logger and any open files
 func (l *Logger) Close() error {
 	if l.file != nil {
@@ -120,16 +164,30 @@ func (l *Logger) Close() error {
 	return nil
 }
 
+// callerLocation returns the file:line of the first caller outside this package,
+// so entries point at the code that logged rather than at the helpers in between
+func callerLocation() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePath+".") {
+			return fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// packagePath is this package's import path, used to skip its own frames when finding the caller
+var packagePath = reflect.TypeOf(Logger{}).PkgPath()
+
 // formatMessage formats a log message with timestamp, level, caller info, and message
 func (l *Logger) formatMessage(level LogLevel, msg string, context map[string]interface{}) string {
 	// Get caller information
-	_, file, line, ok := runtime.Caller(3)
-	var caller string
-	if ok {
-		caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
-	} else {
-		caller = "unknown"
-	}
+	caller := callerLocation()
 
 	// Format timestamp
 	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
@@ -182,9 +240,70 @@ func (l *Logger) formatMessage(level LogLevel, msg string, context map[string]in
 	return baseMsg
 }
 
+// reservedJSONKeys are the top-level keys of a JSON log entry; context fields with these names get a "field_" prefix
+var reservedJSONKeys = map[string]bool{"time": true, "level": true, "caller": true, "msg": true, "logger": true}
+
+// formatJSON formats a log entry as a single-line JSON object, with the context fields flattened into it
+func (l *Logger) formatJSON(level LogLevel, msg string, context map[string]interface{}) string {
+	// Get caller information
+	caller := callerLocation()
+
+	entry := make(map[string]interface{}, len(context)+5)
+	for k, v := range context {
+		if reservedJSONKeys[k] {
+			k = "field_" + k
+		}
+		entry[k] = jsonValue(v)
+	}
+	entry["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	entry["level"] = level.String()
+	entry["caller"] = caller
+	entry["msg"] = msg
+	if l.prefix != "" {
+		entry["logger"] = l.prefix
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Sprintf(`{"level":%q,"msg":%q,"log_error":%q}`, level.String(), msg, err.Error())
+	}
+	return string(data)
+}
+
+// jsonValue converts a context value into something that serializes meaningfully;
+// errors become their message and values JSON can't encode fall back to their %v form
+func jsonValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case error:
+		return value.Error()
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return value.String()
+	}
+	if _, err := json.Marshal(v); err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return v
+}
+
 // log is the internal logging method
 func (l *Logger) log(level LogLevel, msg string, context map[string]interface{}) {
-	if level < l.level {
+	if level < l.Level() {
+		return
+	}
+
+	if l.format == FormatJSON {
+		entry := l.formatJSON(level, msg, context)
+		fmt.Println(entry)
+		if l.fileLogger != nil {
+			l.fileLogger.Println(entry)
+		}
+		if level == FATAL {
+			os.Exit(1)
+		}
 		return
 	}
 
@@ -278,6 +397,14 @@ func Fatal(msg string, context ...map[string]interface{}) {
 	}
 }
 
+// SetLevel changes the global logger's level at runtime and returns the previous level
+func SetLevel(level LogLevel) (LogLevel, error) {
+	if defaultLogger == nil {
+		return level, fmt.Errorf("logger is not initialized")
+	}
+	return defaultLogger.SetLevel(level), nil
+}
+
 // mergeContext merges multiple context maps into one
 func mergeContext(contexts ...map[string]interface{}) map[string]interface{} {
 	result := make(map[string]interface{})
@@ -0,0 +1,69 @@
+package scoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
+)
+
+// critiquePromptPrefix starts ExplainScore's prompt
+const critiquePromptPrefix = "Critique this scored argument"
+
+// ScoreCritique is a detailed, rubric-style explanation of an argument's score, for players learning to argue
+type ScoreCritique struct {
+	Summary      string            `json:"summary"`
+	Aspects      map[string]string `json:"aspects"`      // Why the argument got its score, keyed by strength, relevance, logic, truth and humor
+	Strengths    []string          `json:"strengths"`    // What worked
+	Weaknesses   []string          `json:"weaknesses"`   // What cost it points
+	Improvements []string          `json:"improvements"` // Concrete ways to make the argument stronger
+}
+
+// ExplainScore asks the judge to expand on the score it gave an argument: why each aspect scored as it did,
+// what was strong, what weakened it and how to improve it
+func (s *Scorer) ExplainScore(ctx context.Context, argument, topic string, score *ArgumentScore, language types.Language) (*ScoreCritique, error) {
+	prompt := fmt.Sprintf(`%s about "%s" for a player who wants to learn to argue better:
+
+"%s"
+
+It was scored from 0-10 on each aspect: strength %d, relevance %d, logic %d, truth %d, humor %d.
+The judge's brief explanation was: %s
+
+Explain the scores like a debate coach grading against a rubric. Be specific to this argument.
+
+Your response MUST ONLY be a valid JSON object with the following structure. Dont write the word json, just output a correct json-formatted object, starting with a { symbol
+    "summary": "<two or three sentences on the argument overall>",
+    "aspects": {
+        "strength": "<why it scored as it did>",
+        "relevance": "<why it scored as it did>",
+        "logic": "<why it scored as it did>",
+        "truth": "<why it scored as it did>",
+        "humor": "<why it scored as it did>"
+    },
+    "strengths": ["<what worked>"],
+    "weaknesses": ["<what weakened it>"],
+    "improvements": ["<a concrete way to improve it>"]
+}`, critiquePromptPrefix, topic, argument, score.Strength, score.Relevance, score.Logic, score.Truth, score.Humor, score.Explanation)
+
+	if language != "" && language != types.LanguageEnglish {
+		prompt += fmt.Sprintf(`
+
+The debate is held in %[1]s. Write the critique in %[1]s. Keep the JSON keys in English.`, language.Name())
+	}
+
+	completion, err := s.llm.Call(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("critique failed: %v", err)
+	}
+	usage.Record(ctx, usage.LLMCall(prompt, completion))
+
+	completion = strings.Trim(strings.TrimSpace(completion), "`")
+	var critique ScoreCritique
+	if err := json.Unmarshal([]byte(completion), &critique); err != nil {
+		return nil, fmt.Errorf("failed to parse critique: %v\nraw response: %s", err, completion)
+	}
+	return &critique, nil
+}
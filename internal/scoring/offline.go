@@ -0,0 +1,84 @@
+package scoring
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// offlineLLM stands in for the scoring model in offline mode. Its scores are pseudo-random but seeded by the
+// prompt, so the same argument about the same topic always gets the same score.
+type offlineLLM struct{}
+
+var _ llms.LLM = offlineLLM{}
+
+// Call returns a score for the argument in the prompt, as JSON in the format ScoreArgumentInLanguage asks for,
+// or a placeholder critique for ExplainScore's prompts
+func (offlineLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	if strings.HasPrefix(prompt, critiquePromptPrefix) {
+		return offlineCritique()
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(prompt))
+	random := rand.New(rand.NewSource(int64(hash.Sum64())))
+
+	// Keep scores in 3-10 so arguments move HP noticeably in local testing
+	score := func() int { return 3 + random.Intn(8) }
+	response, err := json.Marshal(ArgumentScore{
+		Strength:    score(),
+		Relevance:   score(),
+		Logic:       score(),
+		Truth:       score(),
+		Humor:       score(),
+		Explanation: "Offline mode: this score is a placeholder, not a real evaluation.",
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(response), nil
+}
+
+// offlineCritique returns a placeholder critique, as JSON in the format ExplainScore asks for
+func offlineCritique() (string, error) {
+	const placeholder = "Offline mode: this critique is a placeholder, not a real evaluation."
+	response, err := json.Marshal(ScoreCritique{
+		Summary: placeholder,
+		Aspects: map[string]string{
+			"strength":  placeholder,
+			"relevance": placeholder,
+			"logic":     placeholder,
+			"truth":     placeholder,
+			"humor":     placeholder,
+		},
+		Strengths:    []string{placeholder},
+		Weaknesses:   []string{placeholder},
+		Improvements: []string{placeholder},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(response), nil
+}
+
+// Generate calls Call for each prompt
+func (l offlineLLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) {
+	generations := make([]*llms.Generation, 0, len(prompts))
+	for _, prompt := range prompts {
+		text, err := l.Call(ctx, prompt, options...)
+		if err != nil {
+			return nil, err
+		}
+		generations = append(generations, &llms.Generation{Text: text})
+	}
+	return generations, nil
+}
+
+// NewOfflineScorer creates a scorer for local development that needs no API key and gives deterministic placeholder scores
+func NewOfflineScorer() *Scorer {
+	return &Scorer{llm: offlineLLM{}}
+}
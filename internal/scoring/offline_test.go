@@ -0,0 +1,61 @@
+package scoring
+
+import (
+	"context"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineScorer(t *testing.T) {
+	scorer := NewOfflineScorer()
+	ctx := context.Background()
+
+	score, err := scorer.ScoreArgument(ctx, "Messi made everyone around him better", "Who's the GOAT?")
+	require.NoError(t, err)
+	for _, value := range []int{score.Strength, score.Relevance, score.Logic, score.Truth, score.Humor} {
+		assert.GreaterOrEqual(t, value, 3)
+		assert.LessOrEqual(t, value, 10)
+	}
+	assert.Equal(t, float64(score.Strength+score.Relevance+score.Logic+score.Truth+score.Humor)/5.0, score.Average)
+
+	// The same argument always gets the same score
+	again, err := scorer.ScoreArgument(ctx, "Messi made everyone around him better", "Who's the GOAT?")
+	require.NoError(t, err)
+	assert.Equal(t, score, again)
+}
+
+func TestScoreArgumentInStyle(t *testing.T) {
+	scorer := NewOfflineScorer()
+	ctx := context.Background()
+	const argument, topic = "Ronaldo once scored with his ear", "Who's the GOAT?"
+
+	debate, err := scorer.ScoreArgumentInStyle(ctx, argument, topic, types.LanguageEnglish, types.ResponseStyleDebate)
+	require.NoError(t, err)
+	assert.InDelta(t, float64(debate.Strength+debate.Relevance+debate.Logic+debate.Truth+debate.Humor)/5, debate.Average, 1e-9,
+		"the debate style weighs every aspect equally")
+
+	humorous, err := scorer.ScoreArgumentInStyle(ctx, argument, topic, types.LanguageEnglish, types.ResponseStyleHumorous)
+	require.NoError(t, err)
+	others := float64(humorous.Strength + humorous.Relevance + humorous.Logic + humorous.Truth)
+	assert.InDelta(t, (others+2*float64(humorous.Humor))/6, humorous.Average, 1e-9)
+
+	technical, err := scorer.ScoreArgumentInStyle(ctx, argument, topic, types.LanguageEnglish, types.ResponseStyleTechnical)
+	require.NoError(t, err)
+	assert.InDelta(t, (others+0.5*float64(technical.Humor))/4.5, technical.Average, 1e-9)
+}
+
+func TestExplainScore(t *testing.T) {
+	scorer := NewOfflineScorer()
+	ctx := context.Background()
+
+	score, err := scorer.ScoreArgument(ctx, "Messi made everyone around him better", "Who's the GOAT?")
+	require.NoError(t, err)
+	critique, err := scorer.ExplainScore(ctx, "Messi made everyone around him better", "Who's the GOAT?", score, types.LanguageEnglish)
+	require.NoError(t, err)
+	assert.NotEmpty(t, critique.Summary)
+	assert.Len(t, critique.Aspects, 5)
+	assert.NotEmpty(t, critique.Improvements)
+}
@@ -7,6 +7,8 @@ import (
 	"log"
 	"strings"
 
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/openai"
 )
@@ -17,7 +19,7 @@ type ArgumentScore struct {
 	Logic       int     `json:"logic"`       // Logical structure (0-100)
 	Truth       int     `json:"truth"`       // Factual accuracy (0-100)
 	Humor       int     `json:"humor"`       // Entertainment value (0-100)
-	Average     float64 `json:"average"`     // Average of all scores
+	Average     float64 `json:"average"`     // Average of all scores, with Humor weighted by the debate's style
 	Explanation string  `json:"explanation"` // Brief explanation
 }
 
@@ -25,11 +27,17 @@ type Scorer struct {
 	llm llms.LLM
 }
 
-func NewScorer(apiKey string) (*Scorer, error) {
-	llm, err := openai.New(
+// NewScorer creates a scorer whose LLM requests go to the OpenAI endpoint in endpoints
+func NewScorer(apiKey string, endpoints types.APIEndpoints) (*Scorer, error) {
+	opts := []openai.Option{
 		openai.WithToken(apiKey),
 		openai.WithModel("gpt-4o-mini"),
-	)
+		openai.WithBaseURL(endpoints.OpenAI()),
+	}
+	if endpoints.OpenAIOrganization != "" {
+		opts = append(opts, openai.WithOrganization(endpoints.OpenAIOrganization))
+	}
+	llm, err := openai.New(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create scorer LLM: %v", err)
 	}
@@ -37,7 +45,49 @@ func NewScorer(apiKey string) (*Scorer, error) {
 	return &Scorer{llm: llm}, nil
 }
 
+// Ping makes the smallest possible LLM call, to check whether the provider is serving requests again
+func (s *Scorer) Ping(ctx context.Context) error {
+	prompt := "Reply with OK."
+	completion, err := s.llm.Call(ctx, prompt, llms.WithMaxTokens(1))
+	if err != nil {
+		return fmt.Errorf("ping failed: %v", err)
+	}
+	usage.Record(ctx, usage.LLMCall(prompt, completion))
+	return nil
+}
+
 func (s *Scorer) ScoreArgument(ctx context.Context, argument, topic string) (*ArgumentScore, error) {
+	return s.ScoreArgumentInLanguage(ctx, argument, topic, types.LanguageEnglish)
+}
+
+// ScoreArgumentInLanguage scores an argument made in the given language, so relevance and logic are judged in-language
+func (s *Scorer) ScoreArgumentInLanguage(ctx context.Context, argument, topic string, language types.Language) (*ArgumentScore, error) {
+	return s.ScoreArgumentInStyle(ctx, argument, topic, language, "")
+}
+
+// humorWeight is how much Humor counts towards the average, relative to each other aspect, in a debate with the
+// given response style. Humorous debates reward wit; formal and technical ones care less about it.
+func humorWeight(style types.ResponseStyle) float64 {
+	switch style {
+	case types.ResponseStyleHumorous:
+		return 2
+	case types.ResponseStyleFormal, types.ResponseStyleTechnical:
+		return 0.5
+	default:
+		return 1
+	}
+}
+
+// ScoreArgumentInStyle scores an argument made in the given language in a debate with the given response style.
+// The style tells the judge what tone the debate asked for and sets how much Humor counts towards the average;
+// an empty style weighs every aspect equally.
+func (s *Scorer) ScoreArgumentInStyle(ctx context.Context, argument, topic string, language types.Language, style types.ResponseStyle) (*ArgumentScore, error) {
+	return s.ScoreReplyInStyle(ctx, argument, "", topic, language, style)
+}
+
+// ScoreReplyInStyle scores an argument like ScoreArgumentInStyle, given the earlier argument or agent turn it
+// replies to, so its relevance is judged against the point it answers. An empty parent scores it as standalone.
+func (s *Scorer) ScoreReplyInStyle(ctx context.Context, argument, parent, topic string, language types.Language, style types.ResponseStyle) (*ArgumentScore, error) {
 	prompt := fmt.Sprintf(`Evaluate this argument about "%s":
 
 "%s"
@@ -60,10 +110,29 @@ Your response MUST ONLY be a valid JSON object with the following structure. Don
     "Explanation": "<brief explanation of scores>"
 }`, topic, argument)
 
+	if parent != "" {
+		prompt += fmt.Sprintf(`
+
+The argument replies to this earlier point in the debate; judge Relevance by how well it answers it:
+
+"%s"`, parent)
+	}
+	if language != "" && language != types.LanguageEnglish {
+		prompt += fmt.Sprintf(`
+
+The debate is held in %[1]s. Judge the argument as a native %[1]s speaker would: do not penalize it for not being in English, and write the explanation in %[1]s. Keep the JSON keys in English.`, language.Name())
+	}
+	if style.IsValid() {
+		prompt += fmt.Sprintf(`
+
+The debate asked for a %s tone. Score each aspect on its own merits; the tone only matters for Humor.`, style)
+	}
+
 	completion, err := s.llm.Call(ctx, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("scoring failed: %v", err)
 	}
+	usage.Record(ctx, usage.LLMCall(prompt, completion))
 
 	completion = strings.TrimSpace(completion)
 	completion = strings.Trim(completion, "`")
@@ -77,7 +146,8 @@ Your response MUST ONLY be a valid JSON object with the following structure. Don
 	}
 
 	// Calculate average
-	score.Average = float64(score.Strength+score.Relevance+score.Logic+score.Truth+score.Humor) / 5.0
+	weight := humorWeight(style)
+	score.Average = (float64(score.Strength+score.Relevance+score.Logic+score.Truth) + weight*float64(score.Humor)) / (4 + weight)
 
 	return &score, nil
 }
@@ -0,0 +1,376 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/logging"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
+)
+
+// Version is the server version reported by the admin dashboard, overridable at build time with -ldflags
+var Version = "1.0.0"
+
+// dashboardCacheTTL is how long each admin dashboard section is cached
+const dashboardCacheTTL = 30 * time.Second
+
+// setupAdminRoutes sets up the admin-only routes
+func (s *Server) setupAdminRoutes() {
+	// Group all admin routes under /api/admin
+	adminGroup := s.router.Group("/api/admin")
+	adminGroup.Use(s.auth.AuthMiddleware())
+	adminGroup.Use(s.auth.RequireRole(string(database.RoleAdmin)))
+	{
+		// Get aggregate debate statistics
+		adminGroup.GET("/stats/debates", s.getDebateStatsHandler)
+
+		// Get everything the admin dashboard renders in one call
+		adminGroup.GET("/dashboard", s.requireAdminDashboard(), s.getAdminDashboardHandler)
+
+		// Change the log level without a restart, e.g. to capture debug logs during an incident
+		adminGroup.PUT("/loglevel", s.setLogLevelHandler)
+
+		// See what a debate cost in LLM and TTS usage
+		adminGroup.GET("/debates/:debateID/usage", s.getDebateUsageHandler)
+
+		// Feature a debate on the homepage, or stop featuring it
+		adminGroup.PUT("/debates/:debateID/featured", s.setDebateFeaturedHandler(true))
+		adminGroup.DELETE("/debates/:debateID/featured", s.setDebateFeaturedHandler(false))
+
+		// Add topics to the catalog in bulk, from JSON or CSV
+		adminGroup.POST("/topics/import", s.importTopicsHandler)
+
+		// Who did what: admin and moderator actions, newest first
+		adminGroup.GET("/audit", s.listAuditEventsHandler)
+
+		// Inspect and flush the generated audio cache
+		adminGroup.GET("/audio/cache/stats", s.getAudioCacheStatsHandler)
+		adminGroup.DELETE("/audio/cache", s.flushAudioCacheHandler)
+	}
+}
+
+// requireAdminDashboard hides the dashboard routes with a 404 when the feature flag is off
+func (s *Server) requireAdminDashboard() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.featureFlags.GetFlags().EnableAdminDashboard {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Not found"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// getAdminDashboardHandler returns the combined admin dashboard payload.
+// Each section is cached separately so one slow aggregation doesn't invalidate the rest.
+func (s *Server) getAdminDashboardHandler(c *gin.Context) {
+	debateStats, err := s.dashboardCache.GetOrCompute("debates", func() (interface{}, error) {
+		return s.db.GetDebateStats(database.DebateFilter{})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get debate statistics", "details": err.Error()})
+		return
+	}
+
+	feedbackStats, err := s.dashboardCache.GetOrCompute("feedback", func() (interface{}, error) {
+		return s.db.GetFeedbackStats()
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get feedback statistics", "details": err.Error()})
+		return
+	}
+
+	usersByRole, err := s.dashboardCache.GetOrCompute("users", func() (interface{}, error) {
+		return s.db.GetUserCountsByRole()
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user counts", "details": err.Error()})
+		return
+	}
+
+	usageTotals, err := s.dashboardCache.GetOrCompute("usage", func() (interface{}, error) {
+		return s.db.GetUsageTotals()
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get usage totals", "details": err.Error()})
+		return
+	}
+	totals, _ := usageTotals.(usage.Usage)
+
+	// There is no moderation queue yet, so bug reports submitted as feedback stand in for open reports
+	openReports := 0
+	if stats, ok := feedbackStats.(map[string]interface{}); ok {
+		if byType, ok := stats["count_by_type"].(map[string]int); ok {
+			openReports = byType[string(database.FeedbackTypeBug)]
+		}
+	}
+
+	totalDebates := 0
+	if stats, ok := debateStats.(map[string]interface{}); ok {
+		totalDebates, _ = stats["total_count"].(int)
+	}
+
+	// Live debates are counted from memory on every call, it's cheap and should never be stale
+	activeDebates := 0
+	if s.debateManager != nil {
+		activeDebates = s.debateManager.ActiveDebateCount()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"debates": gin.H{
+			"active": activeDebates,
+			"total":  totalDebates,
+		},
+		"feedback":      feedbackStats,
+		"users_by_role": usersByRole,
+		"open_reports":  openReports,
+		"usage": gin.H{
+			"totals":         totals,
+			"estimated_cost": s.config.GetUsageRates().Cost(totals),
+		},
+		"server": gin.H{
+			"version":        Version,
+			"api_version":    CurrentAPIVersion,
+			"uptime_seconds": int64(time.Since(s.startedAt).Seconds()),
+			"started_at":     s.startedAt,
+		},
+	})
+}
+
+// getDebateUsageHandler returns a debate's LLM and TTS usage with its estimated cost.
+// Running debates report their live usage; ended debates report what was saved when they finished.
+func (s *Server) getDebateUsageHandler(c *gin.Context) {
+	debateID := c.Param("debateID")
+
+	var debateUsage usage.Usage
+	source := "live"
+	if session, exists := s.debateManager.GetDebate(debateID); exists && session.GetStatus() != types.DebateStatusFinished {
+		debateUsage = session.GetUsage()
+	} else {
+		saved, err := s.db.GetDebateUsage(debateID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No usage recorded for this debate", "details": err.Error()})
+			return
+		}
+		debateUsage = saved.Usage
+		source = "saved"
+	}
+
+	rates := s.config.GetUsageRates()
+	c.JSON(http.StatusOK, gin.H{
+		"debate_id":        debateID,
+		"usage":            debateUsage,
+		"source":           source,
+		"tokens_estimated": true, // The LLM client doesn't report token counts, so they're estimated from text length
+		"rates":            rates,
+		"estimated_cost":   rates.Cost(debateUsage),
+	})
+}
+
+// restartDebateLoopHandler restarts the loop of an active debate that has none running, without waiting for
+// the periodic reconciliation
+func (s *Server) restartDebateLoopHandler(c *gin.Context) {
+	debateID := c.Param("debateID")
+
+	session, exists := s.debateManager.GetDebate(debateID)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Debate not found"})
+		return
+	}
+	if status := session.GetStatus(); status != types.DebateStatusActive {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only active debates have a loop to restart", "status": status})
+		return
+	}
+	if !s.debateManager.StartDebateLoop(session) {
+		c.JSON(http.StatusConflict, gin.H{"error": "The debate loop is already running"})
+		return
+	}
+
+	userID, _ := auth.GetUserID(c)
+	logging.LogDebateEvent("debate_loop_restarted", debateID, map[string]interface{}{
+		"triggered_by": userID,
+	})
+	s.recordAudit(c, auditActionDebateRestartLoop, debateID, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"debate_id": debateID,
+		"restarted": true,
+	})
+}
+
+// endDebateWinnerDraw is the winner a moderator passes to end a debate as a draw
+const endDebateWinnerDraw = "draw"
+
+// endDebateHandler lets a moderator end a debate immediately, stopping its loop and any turn in flight.
+// The optional winner is one of the debate's agents or "draw"; without one the agent with more HP wins.
+// Ending a debate that already finished changes nothing, so the request is safe to retry.
+func (s *Server) endDebateHandler(c *gin.Context) {
+	debateID := c.Param("debateID")
+
+	var req struct {
+		Winner string `json:"winner"` // Optional: an agent's name or "draw"
+		Reason string `json:"reason"` // Optional: shown to players with the game_over message
+	}
+	// An empty body is fine, everything is optional
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	session, exists := s.debateManager.GetDebate(debateID)
+	if !exists {
+		s.endStoredDebate(c, debateID, req.Winner)
+		return
+	}
+
+	var winner string
+	switch req.Winner {
+	case "":
+		if leader := hpLeader(session); leader != nil {
+			winner = leader.GetName()
+		}
+	case endDebateWinnerDraw:
+	case session.Agent1.GetName(), session.Agent2.GetName():
+		winner = req.Winner
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("winner must be %s, %s or %s", session.Agent1.GetName(), session.Agent2.GetName(), endDebateWinnerDraw)})
+		return
+	}
+
+	userID, _ := auth.GetUserID(c)
+	extra := gin.H{"ended_by": userID}
+	if req.Reason != "" {
+		extra["reason"] = req.Reason
+	}
+	if !handleGameOver(s, session, debateID, winner, database.DebateEndReasonAdmin, extra) {
+		c.JSON(http.StatusOK, gin.H{"debate_id": debateID, "ended": false, "already_finished": true})
+		return
+	}
+
+	logging.LogDebateEvent("debate_ended_by_moderator", debateID, map[string]interface{}{
+		"triggered_by": userID,
+		"winner":       winner,
+		"reason":       req.Reason,
+	})
+	s.recordAudit(c, auditActionDebateEnd, debateID, gin.H{"winner": winner, "reason": req.Reason})
+
+	c.JSON(http.StatusOK, gin.H{
+		"debate_id":  debateID,
+		"ended":      true,
+		"winner":     winner,
+		"draw":       winner == "",
+		"end_reason": database.DebateEndReasonAdmin,
+	})
+}
+
+// endStoredDebate ends a debate that has no running session, e.g. one left active by a crash, in the database only
+func (s *Server) endStoredDebate(c *gin.Context, debateID, winner string) {
+	debate, err := s.db.GetDebate(debateID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Debate not found", "details": err.Error()})
+		return
+	}
+	if debate.Status == types.DebateStatusFinished {
+		c.JSON(http.StatusOK, gin.H{"debate_id": debateID, "ended": false, "already_finished": true})
+		return
+	}
+
+	// Without a session there is no HP to pick a leader from, so only an explicit winner is recorded
+	switch winner {
+	case "", endDebateWinnerDraw:
+		winner = ""
+	case debate.Agent1Name, debate.Agent2Name:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("winner must be %s, %s or %s", debate.Agent1Name, debate.Agent2Name, endDebateWinnerDraw)})
+		return
+	}
+
+	err = s.db.UpdateDebateEnd(debateID, types.DebateStatusFinished, winner, database.DebateEndReasonAdmin)
+	if errors.Is(err, database.ErrDebateAlreadyFinished) {
+		// Another request ended it first and announced it
+		c.JSON(http.StatusOK, gin.H{"debate_id": debateID, "ended": false, "already_finished": true})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to end debate", "details": err.Error()})
+		return
+	}
+	s.notifyDebateEnded(debateID, debate.Topic, winner, database.DebateEndReasonAdmin)
+
+	userID, _ := auth.GetUserID(c)
+	logging.LogDebateEvent("debate_ended_by_moderator", debateID, map[string]interface{}{
+		"triggered_by": userID,
+		"winner":       winner,
+	})
+	s.recordAudit(c, auditActionDebateEnd, debateID, gin.H{"winner": winner, "stored_only": true})
+
+	c.JSON(http.StatusOK, gin.H{
+		"debate_id":  debateID,
+		"ended":      true,
+		"winner":     winner,
+		"draw":       winner == "",
+		"end_reason": database.DebateEndReasonAdmin,
+	})
+}
+
+// getDebateStatsHandler returns aggregate statistics about debates
+func (s *Server) getDebateStatsHandler(c *gin.Context) {
+	filter := database.DebateFilter{
+		Status:     c.Query("status"),
+		Visibility: c.Query("visibility"),
+	}
+
+	// The stats scan the whole debates table, so results are cached briefly per filter
+	cacheKey := fmt.Sprintf("%s|%s", filter.Status, filter.Visibility)
+	stats, err := s.debateStatsCache.GetOrCompute(cacheKey, func() (interface{}, error) {
+		return s.db.GetDebateStats(filter)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get debate statistics", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// setLogLevelHandler changes the global log level at runtime
+func (s *Server) setLogLevelHandler(c *gin.Context) {
+	var req struct {
+		Level string `json:"level" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	level, err := logging.ParseLevel(req.Level)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid log level", "details": err.Error()})
+		return
+	}
+
+	previous, err := logging.SetLevel(level)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to set log level", "details": err.Error()})
+		return
+	}
+
+	userID, _ := auth.GetUserID(c)
+	logging.WarnCtx(c.Request.Context(), "Log level changed", map[string]interface{}{
+		"previous_level": previous.String(),
+		"level":          level.String(),
+		"changed_by":     userID,
+	})
+	s.recordAudit(c, auditActionLogLevel, "", gin.H{"previous_level": previous.String(), "level": level.String()})
+
+	c.JSON(http.StatusOK, gin.H{
+		"previous_level": previous.String(),
+		"level":          level.String(),
+	})
+}
@@ -0,0 +1,354 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/logging"
+	"github.com/neo/convinceme_backend/internal/usage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDebateStatsHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.setupAdminRoutes()
+
+	adminToken, err := server.auth.GenerateToken(auth.User{ID: "admin-id", Username: "admin", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+	userToken, err := server.auth.GenerateToken(auth.User{ID: "test-user-id", Username: "testuser", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		token          string
+		expectedStatus int
+	}{
+		{
+			name:           "Admin user",
+			token:          adminToken,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Regular user",
+			token:          userToken,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Unauthenticated",
+			token:          "",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/api/admin/stats/debates", nil)
+			require.NoError(t, err)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+
+				assert.Contains(t, response, "total_count")
+				assert.Contains(t, response, "count_by_status")
+				assert.Contains(t, response, "average_duration_seconds")
+				assert.Contains(t, response, "most_active_agents")
+				assert.Contains(t, response, "top_winning_agents")
+				assert.Contains(t, response, "debates_over_time")
+			}
+		})
+	}
+}
+
+func TestGetAdminDashboardHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.debateManager = &DebateManager{debates: make(map[string]*conversation.DebateSession)}
+	server.setupAdminRoutes()
+
+	adminToken, err := server.auth.GenerateToken(auth.User{ID: "admin-id", Username: "admin", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+	userToken, err := server.auth.GenerateToken(auth.User{ID: "test-user-id", Username: "testuser", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name             string
+		token            string
+		dashboardEnabled bool
+		expectedStatus   int
+	}{
+		{
+			name:             "Admin user",
+			token:            adminToken,
+			dashboardEnabled: true,
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "Dashboard disabled",
+			token:            adminToken,
+			dashboardEnabled: false,
+			expectedStatus:   http.StatusNotFound,
+		},
+		{
+			name:             "Regular user",
+			token:            userToken,
+			dashboardEnabled: true,
+			expectedStatus:   http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server.featureFlags.flags.EnableAdminDashboard = tc.dashboardEnabled
+
+			req, err := http.NewRequest("GET", "/api/admin/dashboard", nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+
+				debates := response["debates"].(map[string]interface{})
+				assert.Equal(t, float64(0), debates["active"])
+				assert.Equal(t, float64(3), debates["total"])
+				assert.Equal(t, float64(5), response["open_reports"])
+				assert.Equal(t, float64(1), response["users_by_role"].(map[string]interface{})["admin"])
+				assert.Equal(t, Version, response["server"].(map[string]interface{})["version"])
+				usageSection := response["usage"].(map[string]interface{})
+				assert.Equal(t, float64(10), usageSection["totals"].(map[string]interface{})["llm_calls"])
+				assert.InDelta(t, 1.2, usageSection["estimated_cost"].(map[string]interface{})["total"], 1e-9)
+			}
+		})
+	}
+}
+
+func TestGetDebateUsageHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+
+	liveSession := &conversation.DebateSession{DebateID: "live-debate", Status: "active"}
+	liveSession.AddUsage(usage.Usage{LLMCalls: 2, PromptTokens: 1000000})
+	server.debateManager = &DebateManager{debates: map[string]*conversation.DebateSession{"live-debate": liveSession}}
+	server.setupAdminRoutes()
+
+	adminToken, err := server.auth.GenerateToken(auth.User{ID: "admin-id", Username: "admin", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+	userToken, err := server.auth.GenerateToken(auth.User{ID: "test-user-id", Username: "testuser", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		debateID       string
+		token          string
+		expectedStatus int
+		expectedSource string
+		expectedCost   float64
+	}{
+		{
+			name:           "Running debate reports live usage",
+			debateID:       "live-debate",
+			token:          adminToken,
+			expectedStatus: http.StatusOK,
+			expectedSource: "live",
+			expectedCost:   0.15,
+		},
+		{
+			name:           "Ended debate reports saved usage",
+			debateID:       "finished-debate",
+			token:          adminToken,
+			expectedStatus: http.StatusOK,
+			expectedSource: "saved",
+			expectedCost:   1.2,
+		},
+		{
+			name:           "No usage recorded",
+			debateID:       "unknown-debate",
+			token:          adminToken,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Regular user",
+			debateID:       "finished-debate",
+			token:          userToken,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/api/admin/debates/"+tc.debateID+"/usage", nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedSource, response["source"])
+				assert.InDelta(t, tc.expectedCost, response["estimated_cost"].(map[string]interface{})["total"], 1e-9)
+			}
+		})
+	}
+}
+
+func TestAudioCacheAdminEndpoints(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/audio/:id", server.handleAudioStream)
+	server.setupAdminRoutes()
+
+	server.audioCache = map[string]audioCache{}
+	server.audioCache["recent"] = audioCache{data: make([]byte, 100), timestamp: time.Now()}
+	server.audioCache["older"] = audioCache{data: make([]byte, 50), timestamp: time.Now().Add(-10 * time.Minute)}
+
+	adminToken, err := server.auth.GenerateToken(auth.User{ID: "admin-id", Username: "admin", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+	userToken, err := server.auth.GenerateToken(auth.User{ID: "test-user-id", Username: "testuser", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	request := func(method, path, token string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, path, nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w
+	}
+
+	// One hit and one miss
+	assert.Equal(t, http.StatusOK, request("GET", "/api/audio/recent", "").Code)
+	assert.Equal(t, http.StatusNotFound, request("GET", "/api/audio/missing", "").Code)
+
+	assert.Equal(t, http.StatusForbidden, request("GET", "/api/admin/audio/cache/stats", userToken).Code)
+	assert.Equal(t, http.StatusForbidden, request("DELETE", "/api/admin/audio/cache", userToken).Code)
+
+	w := request("GET", "/api/admin/audio/cache/stats", adminToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	var stats audioCacheStats
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, int64(150), stats.TotalBytes)
+	assert.GreaterOrEqual(t, stats.OldestAgeSeconds, float64(600))
+	assert.Equal(t, DefaultAudioCacheTTL.Seconds(), stats.TTLSeconds)
+	assert.Equal(t, int64(1), stats.Hits)
+	assert.Equal(t, int64(1), stats.Misses)
+
+	w = request("DELETE", "/api/admin/audio/cache", adminToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	var flushResponse map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &flushResponse))
+	assert.Equal(t, float64(2), flushResponse["flushed"])
+	assert.Equal(t, http.StatusNotFound, request("GET", "/api/audio/recent", "").Code)
+}
+
+func TestSetLogLevelHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.setupAdminRoutes()
+
+	require.NoError(t, logging.InitDefaultLogger(logging.Config{Level: logging.INFO}))
+	defer logging.SetLevel(logging.INFO)
+
+	adminToken, err := server.auth.GenerateToken(auth.User{ID: "admin-id", Username: "admin", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+	userToken, err := server.auth.GenerateToken(auth.User{ID: "test-user-id", Username: "testuser", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name             string
+		body             string
+		token            string
+		expectedStatus   int
+		expectedPrevious string
+		expectedLevel    string
+	}{
+		{
+			name:             "Admin raises verbosity",
+			body:             `{"level": "debug"}`,
+			token:            adminToken,
+			expectedStatus:   http.StatusOK,
+			expectedPrevious: "INFO",
+			expectedLevel:    "DEBUG",
+		},
+		{
+			name:             "Admin restores the level",
+			body:             `{"level": "INFO"}`,
+			token:            adminToken,
+			expectedStatus:   http.StatusOK,
+			expectedPrevious: "DEBUG",
+			expectedLevel:    "INFO",
+		},
+		{
+			name:           "Unknown level",
+			body:           `{"level": "verbose"}`,
+			token:          adminToken,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Regular user",
+			body:           `{"level": "debug"}`,
+			token:          userToken,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("PUT", "/api/admin/loglevel", bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.expectedStatus == http.StatusOK {
+				var response map[string]string
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedPrevious, response["previous_level"])
+				assert.Equal(t, tc.expectedLevel, response["level"])
+				assert.Equal(t, tc.expectedLevel, logging.GetDefaultLogger().Level().String())
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/types"
+)
+
+// AgentBusyError is returned when a debate can't be created because one of its agents is already in a debate
+// that hasn't finished and Config.OneDebatePerAgent is set
+type AgentBusyError struct {
+	Agent    string
+	DebateID string // Empty if the other debate is still being created
+}
+
+func (e *AgentBusyError) Error() string {
+	if e.DebateID == "" {
+		return fmt.Sprintf("agent %s is already in a debate being created", e.Agent)
+	}
+	return fmt.Sprintf("agent %s is already in debate %s, which hasn't finished", e.Agent, e.DebateID)
+}
+
+// AgentAvailability is an agent's involvement in debates that haven't finished
+type AgentAvailability struct {
+	LiveDebates int      `json:"live_debates"`
+	DebateIDs   []string `json:"debate_ids,omitempty"` // Only shown to moderators, as they include unlisted and private debates
+	Available   bool     `json:"available"`            // Whether a new debate with the agent could be created
+}
+
+// liveDebatesByAgentLocked returns the IDs of the debates each agent is in that haven't finished;
+// caller must hold debatesMutex
+func (m *DebateManager) liveDebatesByAgentLocked() map[string][]string {
+	byAgent := make(map[string][]string)
+	for debateID, session := range m.debates {
+		if session.GetStatus() == types.DebateStatusFinished {
+			continue
+		}
+		for _, name := range []string{session.Agent1.GetName(), session.Agent2.GetName()} {
+			byAgent[name] = append(byAgent[name], debateID)
+		}
+	}
+	for _, debateIDs := range byAgent {
+		sort.Strings(debateIDs)
+	}
+	return byAgent
+}
+
+// AgentAvailability reports, for each of the named agents and any other agent in a debate that hasn't finished,
+// which of those debates it's in
+func (m *DebateManager) AgentAvailability(names []string) map[string]AgentAvailability {
+	m.debatesMutex.RLock()
+	defer m.debatesMutex.RUnlock()
+
+	oneDebatePerAgent := m.oneDebatePerAgent()
+	byAgent := m.liveDebatesByAgentLocked()
+	availability := make(map[string]AgentAvailability, len(names))
+	for _, name := range names {
+		availability[name] = AgentAvailability{DebateIDs: []string{}, Available: m.pendingAgents[name] == 0 || !oneDebatePerAgent}
+	}
+	for name, debateIDs := range byAgent {
+		availability[name] = AgentAvailability{
+			LiveDebates: len(debateIDs),
+			DebateIDs:   debateIDs,
+			Available:   !oneDebatePerAgent,
+		}
+	}
+	return availability
+}
+
+// oneDebatePerAgent reports whether an agent may only be in one debate that hasn't finished at a time
+func (m *DebateManager) oneDebatePerAgent() bool {
+	return m.server != nil && m.server.config != nil && m.server.config.OneDebatePerAgent
+}
+
+// reserveAgents claims the named agents for a debate being created when Config.OneDebatePerAgent is set,
+// failing with an AgentBusyError if one is already in a debate that hasn't finished. The claim ends once the
+// debate is stored, or is given back with releaseAgents if creating it fails.
+func (m *DebateManager) reserveAgents(names ...string) error {
+	if !m.oneDebatePerAgent() {
+		return nil
+	}
+
+	m.debatesMutex.Lock()
+	defer m.debatesMutex.Unlock()
+
+	byAgent := m.liveDebatesByAgentLocked()
+	for _, name := range names {
+		if debateIDs := byAgent[name]; len(debateIDs) > 0 {
+			return &AgentBusyError{Agent: name, DebateID: debateIDs[0]}
+		}
+		if m.pendingAgents[name] > 0 {
+			return &AgentBusyError{Agent: name}
+		}
+	}
+	if m.pendingAgents == nil {
+		m.pendingAgents = make(map[string]int)
+	}
+	for _, name := range names {
+		m.pendingAgents[name]++
+	}
+	return nil
+}
+
+// releaseAgentsLocked ends a claim made by reserveAgents; caller must hold debatesMutex
+func (m *DebateManager) releaseAgentsLocked(names ...string) {
+	for _, name := range names {
+		if m.pendingAgents[name] <= 1 {
+			delete(m.pendingAgents, name)
+		} else {
+			m.pendingAgents[name]--
+		}
+	}
+}
+
+// getAgentAvailabilityHandler reports which agents are in debates that haven't finished, so organizers can
+// schedule around busy agents. Agents are shared between concurrent debates unless OneDebatePerAgent is set.
+// Everyone sees the counts; only moderators and admins see the debate IDs, since unlisted and private debates
+// must stay unreachable to anyone who wasn't given their ID.
+func (s *Server) getAgentAvailabilityHandler(c *gin.Context) {
+	agents := s.agents.List()
+	names := make([]string, 0, len(agents))
+	for name := range agents {
+		names = append(names, name)
+	}
+
+	availability := s.debateManager.AgentAvailability(names)
+	if role, _ := auth.GetUserRole(c); role != string(database.RoleModerator) && role != string(database.RoleAdmin) {
+		for name, agentAvailability := range availability {
+			agentAvailability.DebateIDs = nil
+			availability[name] = agentAvailability
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"agents":               availability,
+		"one_debate_per_agent": s.debateManager.oneDebatePerAgent(),
+	})
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentAvailability(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/agents/availability", server.auth.OptionalAuthMiddleware(), server.getAgentAvailabilityHandler)
+	moderatorToken, err := server.auth.GenerateToken(auth.User{ID: "mod-1", Username: "mod", Role: string(database.RoleModerator)})
+	require.NoError(t, err)
+
+	agents := map[string]*agent.Agent{}
+	for _, name := range []string{"Agent 1", "Agent 2", "Agent 3", "Agent 4"} {
+		agents[name] = agent.NewOfflineAgent(agent.AgentConfig{Name: name})
+	}
+	server.agents = NewAgentRegistry(agents)
+	server.debateManager = &DebateManager{
+		db:     server.db,
+		agents: server.agents,
+		debates: map[string]*conversation.DebateSession{
+			"debate-b": {Status: "active", Agent1: agents["Agent 1"], Agent2: agents["Agent 2"]},
+			"debate-a": {Status: "waiting", Agent1: agents["Agent 1"], Agent2: agents["Agent 3"]},
+			"finished": {Status: "finished", Agent1: agents["Agent 2"], Agent2: agents["Agent 4"]},
+		},
+		server: server,
+	}
+
+	availability := func(token string) map[string]interface{} {
+		req := httptest.NewRequest(http.MethodGet, "/api/agents/availability", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	response := availability(moderatorToken)
+	assert.Equal(t, false, response["one_debate_per_agent"])
+	byAgent := response["agents"].(map[string]interface{})
+	agent1 := byAgent["Agent 1"].(map[string]interface{})
+	assert.Equal(t, float64(2), agent1["live_debates"])
+	assert.Equal(t, []interface{}{"debate-a", "debate-b"}, agent1["debate_ids"])
+	assert.Equal(t, true, agent1["available"], "agents are shared between debates by default")
+	agent4 := byAgent["Agent 4"].(map[string]interface{})
+	assert.Equal(t, float64(0), agent4["live_debates"], "finished debates don't count")
+	assert.NotContains(t, agent4, "debate_ids")
+
+	// Anyone else only sees the counts, so unlisted and private debates stay hidden
+	agent1 = availability("")["agents"].(map[string]interface{})["Agent 1"].(map[string]interface{})
+	assert.Equal(t, float64(2), agent1["live_debates"])
+	assert.NotContains(t, agent1, "debate_ids")
+
+	// Under the one-debate-per-agent policy, busy agents can't be put in another debate
+	server.config.OneDebatePerAgent = true
+	assert.Equal(t, false, availability("")["agents"].(map[string]interface{})["Agent 1"].(map[string]interface{})["available"])
+
+	_, err = server.debateManager.CreateDebateWithConfig(conversation.DefaultConfig(), agents["Agent 4"], agents["Agent 3"], "", database.DebateSettings{})
+	var busy *AgentBusyError
+	require.ErrorAs(t, err, &busy)
+	assert.Equal(t, "Agent 3", busy.Agent)
+	assert.Equal(t, "debate-a", busy.DebateID)
+
+	_, err = server.debateManager.CreateDebateWithConfig(conversation.DefaultConfig(), agents["Agent 4"], agents["Agent 2"], "", database.DebateSettings{})
+	require.ErrorAs(t, err, &busy, "Agent 2 is busy in debate-b")
+	assert.Empty(t, server.debateManager.pendingAgents, "a rejected debate gives its claims back")
+
+	session, _ := server.debateManager.GetDebate("debate-b")
+	session.UpdateStatus("finished")
+	_, err = server.debateManager.CreateDebateWithConfig(conversation.DefaultConfig(), agents["Agent 4"], agents["Agent 2"], "", database.DebateSettings{})
+	require.NoError(t, err)
+	assert.Empty(t, server.debateManager.pendingAgents)
+	assert.Equal(t, false, availability("")["agents"].(map[string]interface{})["Agent 4"].(map[string]interface{})["available"])
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+)
+
+// AgentRegistry holds the loaded agents by name. It is safe for concurrent use, so the agents can be
+// replaced at runtime while handlers and debate loops read them.
+type AgentRegistry struct {
+	mu     sync.RWMutex
+	agents map[string]*agent.Agent
+}
+
+// NewAgentRegistry creates a registry holding a copy of agents
+func NewAgentRegistry(agents map[string]*agent.Agent) *AgentRegistry {
+	return &AgentRegistry{agents: copyAgents(agents)}
+}
+
+// Get returns the agent with the given name. A nil registry has no agents.
+func (r *AgentRegistry) Get(name string) (*agent.Agent, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	a, exists := r.agents[name]
+	return a, exists
+}
+
+// List returns a snapshot of the agents by name, which later changes to the registry don't affect
+func (r *AgentRegistry) List() map[string]*agent.Agent {
+	if r == nil {
+		return map[string]*agent.Agent{}
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return copyAgents(r.agents)
+}
+
+// Swap replaces all agents with a copy of agents and returns the previous ones.
+// Debates already running keep the agents they were created with.
+func (r *AgentRegistry) Swap(agents map[string]*agent.Agent) map[string]*agent.Agent {
+	replacement := copyAgents(agents)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	previous := r.agents
+	r.agents = replacement
+	return previous
+}
+
+func copyAgents(agents map[string]*agent.Agent) map[string]*agent.Agent {
+	copied := make(map[string]*agent.Agent, len(agents))
+	for name, a := range agents {
+		copied[name] = a
+	}
+	return copied
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentRegistry(t *testing.T) {
+	original := map[string]*agent.Agent{"Agent 1": {}, "Agent 2": {}}
+	registry := NewAgentRegistry(original)
+
+	// The registry keeps its own copy, so the caller's map can't change it
+	delete(original, "Agent 2")
+	_, exists := registry.Get("Agent 2")
+	assert.True(t, exists)
+
+	snapshot := registry.List()
+	previous := registry.Swap(map[string]*agent.Agent{"Agent 3": {}})
+	assert.Len(t, previous, 2)
+	assert.Len(t, snapshot, 2, "a listed snapshot is unaffected by a swap")
+	_, exists = registry.Get("Agent 1")
+	assert.False(t, exists)
+	_, exists = registry.Get("Agent 3")
+	assert.True(t, exists)
+
+	var nilRegistry *AgentRegistry
+	_, exists = nilRegistry.Get("Agent 1")
+	assert.False(t, exists)
+	assert.Empty(t, nilRegistry.List())
+}
+
+func TestAgentProfileHandlers(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/agents", server.listAgents)
+	server.router.GET("/api/agents/:name", server.getAgentHandler)
+
+	pepito := agent.NewOfflineAgent(agent.AgentConfig{
+		Name:         "Pepito",
+		Role:         "Messi Devotee",
+		Description:  "Has never missed a Messi match.",
+		AvatarURL:    "https://cdn.example.com/pepito.png",
+		SystemPrompt: "SECRET: always argue for Messi",
+	})
+	server.agents = NewAgentRegistry(map[string]*agent.Agent{"Pepito": pepito})
+
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/agents", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "SECRET")
+	var list struct {
+		Agents []map[string]interface{} `json:"agents"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Agents, 1)
+	assert.Equal(t, "Pepito", list.Agents[0]["name"])
+	assert.Equal(t, "Messi Devotee", list.Agents[0]["role"])
+	assert.Equal(t, "https://cdn.example.com/pepito.png", list.Agents[0]["avatar_url"])
+	assert.NotContains(t, list.Agents[0], "stats")
+
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/agents/Pepito", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var single struct {
+		Agent agent.AgentProfile `json:"agent"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &single))
+	assert.Equal(t, pepito.GetProfile(), single.Agent)
+
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/agents/Nobody", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// Reloading agents must not race with handlers reading them; run with make test-race
+func TestAgentRegistryConcurrentReload(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/agents", server.listAgents)
+	server.router.POST("/api/debates", server.auth.OptionalAuthMiddleware(), server.createDebateHandler)
+	server.config.MaxConcurrentDebates = 1000
+
+	pairing := map[string]*agent.Agent{"Agent 1": {}, "Agent 2": {}}
+	server.agents = NewAgentRegistry(pairing)
+	server.debateManager = &DebateManager{db: server.db, agents: server.agents, debates: map[string]*conversation.DebateSession{}, server: server}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				server.agents.Swap(pairing)
+			}
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/agents", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/debates", strings.NewReader(`{"topic":"Cats vs dogs","agent1":"Agent 1","agent2":"Agent 2"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w = httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	}
+	close(stop)
+	wg.Wait()
+}
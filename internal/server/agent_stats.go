@@ -0,0 +1,60 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/database"
+)
+
+// defaultMinGamesForRanking is the number of finished debates an agent needs before its win rate is ranked
+const defaultMinGamesForRanking = 3
+
+// agentWinRateEntry is an agent's win rate annotated for display
+type agentWinRateEntry struct {
+	*database.AgentWinRate
+	Qualified bool `json:"qualified"` // Has played enough games to be ranked
+	Available bool `json:"available"` // Agent is currently loaded on this server
+}
+
+// rankAgentWinRates orders win rates so agents meeting the minimum-games threshold come first
+func (s *Server) rankAgentWinRates(rates []*database.AgentWinRate, minGames int) []agentWinRateEntry {
+	qualified := make([]agentWinRateEntry, 0, len(rates))
+	unqualified := make([]agentWinRateEntry, 0)
+
+	for _, rate := range rates {
+		_, available := s.agents.Get(rate.Agent)
+		entry := agentWinRateEntry{
+			AgentWinRate: rate,
+			Qualified:    rate.Appearances >= minGames,
+			Available:    available,
+		}
+		if entry.Qualified {
+			qualified = append(qualified, entry)
+		} else {
+			unqualified = append(unqualified, entry)
+		}
+	}
+
+	return append(qualified, unqualified...)
+}
+
+// getAgentWinRatesHandler returns the agent win-rate leaderboard
+func (s *Server) getAgentWinRatesHandler(c *gin.Context) {
+	minGames, err := strconv.Atoi(c.DefaultQuery("min_games", strconv.Itoa(defaultMinGamesForRanking)))
+	if err != nil || minGames < 0 {
+		minGames = defaultMinGamesForRanking
+	}
+
+	rates, err := s.db.GetAgentWinRates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get agent win rates", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"winrates":  s.rankAgentWinRates(rates, minGames),
+		"min_games": minGames,
+	})
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAgentWinRatesHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.agents = NewAgentRegistry(map[string]*agent.Agent{"Agent 1": nil, "Agent 2": nil})
+	server.router.GET("/api/agents/winrates", server.getAgentWinRatesHandler)
+
+	req, err := http.NewRequest("GET", "/api/agents/winrates", nil)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		MinGames int `json:"min_games"`
+		Winrates []struct {
+			Agent     string `json:"agent"`
+			Qualified bool   `json:"qualified"`
+			Available bool   `json:"available"`
+		} `json:"winrates"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	// The retired agent has a perfect record but too few games, so it's ranked last
+	assert.Equal(t, defaultMinGamesForRanking, response.MinGames)
+	require.Len(t, response.Winrates, 3)
+	assert.Equal(t, "Agent 1", response.Winrates[0].Agent)
+	assert.True(t, response.Winrates[0].Qualified)
+	assert.True(t, response.Winrates[0].Available)
+	assert.Equal(t, "Retired Agent", response.Winrates[2].Agent)
+	assert.False(t, response.Winrates[2].Qualified)
+	assert.False(t, response.Winrates[2].Available)
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API versions served by this backend
+const (
+	APIVersion1 = "v1" // Original /api/* response shapes
+	APIVersion2 = "v2" // /api/v2/* with data/meta envelopes and structured errors
+)
+
+// CurrentAPIVersion is the newest API version, reported to WebSocket clients in the welcome message
+const CurrentAPIVersion = APIVersion2
+
+// responder serializes handler results for a specific API version.
+// Handlers shared between versions contain the business logic and only call the responder to write output.
+type responder interface {
+	// Item writes a single resource along with optional related data
+	Item(c *gin.Context, key string, item any, meta gin.H)
+	// List writes a collection, paginated when pagination is non-nil
+	List(c *gin.Context, key string, items any, meta gin.H, pagination *PaginationParams)
+	// Error writes an error response
+	Error(c *gin.Context, status int, message string, err error)
+	// InlineError writes an error response for handlers whose v1 body carried the cause in the message
+	InlineError(c *gin.Context, status int, message string, err error)
+}
+
+// v1Responder keeps the original /api/* response shapes
+type v1Responder struct{}
+
+// Item writes the resource under its key with related data merged at the top level
+func (v1Responder) Item(c *gin.Context, key string, item any, meta gin.H) {
+	response := gin.H{key: item}
+	for k, v := range meta {
+		response[k] = v
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// List writes the collection as a paginated response or under its key
+func (v1Responder) List(c *gin.Context, key string, items any, meta gin.H, pagination *PaginationParams) {
+	var response gin.H
+	if pagination != nil {
+		response = BuildPaginationResponse(c, *pagination, items)
+	} else {
+		response = gin.H{key: items}
+	}
+	for k, v := range meta {
+		response[k] = v
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// Error writes a flat error message with optional details
+func (v1Responder) Error(c *gin.Context, status int, message string, err error) {
+	response := gin.H{"error": message}
+	if err != nil {
+		response["details"] = err.Error()
+	}
+	c.JSON(status, response)
+}
+
+// InlineError writes the cause into the message, {"error": "message: cause"}, as these handlers did before
+// responses were versioned
+func (v1Responder) InlineError(c *gin.Context, status int, message string, err error) {
+	if err != nil {
+		message = fmt.Sprintf("%s: %v", message, err)
+	}
+	c.JSON(status, gin.H{"error": message})
+}
+
+// v2Responder wraps results in data/meta envelopes and returns structured errors
+type v2Responder struct{}
+
+// Item writes the resource under "data" and related data under "meta"
+func (v2Responder) Item(c *gin.Context, key string, item any, meta gin.H) {
+	response := gin.H{"data": item}
+	if len(meta) > 0 {
+		response["meta"] = meta
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// List writes the collection under "data" with counts and pagination under "meta"
+func (v2Responder) List(c *gin.Context, key string, items any, meta gin.H, pagination *PaginationParams) {
+	responseMeta := gin.H{}
+	for k, v := range meta {
+		responseMeta[k] = v
+	}
+	if pagination != nil {
+		responseMeta["pagination"] = BuildPaginationResponse(c, *pagination, nil)["pagination"]
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data": items,
+		"meta": responseMeta,
+	})
+}
+
+// Error writes a structured ErrorResponse with a machine-readable error code
+func (v2Responder) Error(c *gin.Context, status int, message string, err error) {
+	errorResponse := ErrorResponse{
+		Status:    status,
+		Message:   message,
+		Path:      c.Request.URL.Path,
+		Timestamp: time.Now(),
+		RequestID: c.GetString("RequestID"),
+		ErrorCode: errorCodeForStatus(status),
+	}
+	if err != nil {
+		errorResponse.Details = err.Error()
+	}
+	c.JSON(status, gin.H{"error": errorResponse})
+}
+
+// InlineError writes the same structured error as Error; only v1 has a legacy shape to keep
+func (r v2Responder) InlineError(c *gin.Context, status int, message string, err error) {
+	r.Error(c, status, message, err)
+}
+
+// errorCodeForStatus converts an HTTP status into a snake_case error code, e.g. 404 -> "not_found"
+func errorCodeForStatus(status int) string {
+	return strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
+
+// withResponder adapts a version-agnostic handler into a gin handler for one API version
+func withResponder(r responder, handler func(*gin.Context, responder)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handler(c, r)
+	}
+}
+
+// setupV2Routes mounts the /api/v2 handlers, which share business logic with /api/*
+func (s *Server) setupV2Routes() {
+	v2 := s.router.Group("/api/v2")
+	{
+		v2.GET("/agents", withResponder(v2Responder{}, s.listAgentsWith))
+		v2.GET("/agents/:name", withResponder(v2Responder{}, s.getAgentWith))
+		v2.GET("/debates", withResponder(v2Responder{}, s.listDebatesWith))
+		v2.GET("/debates/:debateID", s.auth.OptionalAuthMiddleware(), withResponder(v2Responder{}, s.getDebateWith))
+		v2.GET("/topics", withResponder(v2Responder{}, s.listTopicsWith))
+		v2.GET("/topics/:id", withResponder(v2Responder{}, s.getTopicWith))
+	}
+}
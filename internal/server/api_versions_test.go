@@ -0,0 +1,135 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIVersionResponseShapes(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/topics", server.listTopicsHandler)
+	server.router.GET("/api/topics/:id", server.getTopicHandler)
+	server.setupV2Routes()
+
+	testCases := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		checkResponse  func(t *testing.T, response map[string]interface{})
+	}{
+		{
+			name:           "v1 topic list keeps items and pagination",
+			path:           "/api/topics",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, response map[string]interface{}) {
+				assert.Contains(t, response, "items")
+				assert.Contains(t, response, "pagination")
+			},
+		},
+		{
+			name:           "v2 topic list uses data and meta",
+			path:           "/api/v2/topics",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, response map[string]interface{}) {
+				assert.Len(t, response["data"], 1)
+				meta, ok := response["meta"].(map[string]interface{})
+				require.True(t, ok)
+				assert.Contains(t, meta, "pagination")
+			},
+		},
+		{
+			name:           "v1 topic error is a flat message",
+			path:           "/api/topics/abc",
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, response map[string]interface{}) {
+				assert.Equal(t, "Invalid topic ID", response["error"])
+			},
+		},
+		{
+			name:           "v2 topic error is structured",
+			path:           "/api/v2/topics/abc",
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, response map[string]interface{}) {
+				errorResponse, ok := response["error"].(map[string]interface{})
+				require.True(t, ok)
+				assert.Equal(t, "Invalid topic ID", errorResponse["message"])
+				assert.Equal(t, "bad_request", errorResponse["error_code"])
+				assert.Equal(t, "/api/v2/topics/abc", errorResponse["path"])
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tc.path, nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err = json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			tc.checkResponse(t, response)
+		})
+	}
+}
+
+// failingTopicsDB fails every topic and debate listing
+type failingTopicsDB struct {
+	*TestMockDB
+}
+
+func (m *failingTopicsDB) GetTopic(id int) (*database.Topic, error) {
+	return nil, errors.New("database is locked")
+}
+
+func (m *failingTopicsDB) GetTopics(filter database.TopicFilter) ([]*database.Topic, int, error) {
+	return nil, 0, errors.New("database is locked")
+}
+
+func (m *failingTopicsDB) ListDebates(filter database.DebateFilter) ([]*database.Debate, int, error) {
+	return nil, 0, errors.New("database is locked")
+}
+
+// TestAPIV1ErrorBodies tests that /api/* errors keep the exact bodies they had before responses were versioned
+func TestAPIV1ErrorBodies(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.db = &failingTopicsDB{TestMockDB: &TestMockDB{}}
+	server.router.GET("/api/debates", server.listDebatesHandler)
+	server.router.GET("/api/topics", server.listTopicsHandler)
+	server.router.GET("/api/topics/:id", server.getTopicHandler)
+	server.setupV2Routes()
+
+	for path, body := range map[string]string{
+		"/api/debates":  `{"error":"Failed to list debates: database is locked"}`,
+		"/api/topics":   `{"error":"Failed to get topics: database is locked"}`,
+		"/api/topics/1": `{"error":"Failed to get topic: database is locked"}`,
+	} {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code, path)
+		assert.JSONEq(t, body, w.Body.String(), path)
+	}
+
+	// v2 keeps the cause out of the message
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/topics/1", nil))
+	var response struct {
+		Error ErrorResponse `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Failed to get topic", response.Error.Message)
+	assert.Equal(t, "database is locked", response.Error.Details)
+}
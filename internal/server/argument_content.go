@@ -0,0 +1,76 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/neo/convinceme_backend/internal/conversation"
+)
+
+// maxArgumentLengthLimit bounds the argument length limits accepted by ValidateDebateConfig
+const maxArgumentLengthLimit = 5000
+
+// What counts as obvious spam in a player argument
+const (
+	maxRepeatedChars      = 8   // Longest allowed run of one character, so "!!!!!!!!!" or "aaaaaaaaa" is spam
+	minShoutingLetters    = 20  // Letters an argument needs before it can be an all-caps flood
+	maxUppercaseLetterPct = 0.9 // Share of uppercase letters at which a long argument is an all-caps flood
+)
+
+// checkArgumentContent checks a player argument against the debate's length limits and for obvious spam, so
+// garbage is rejected before it's scored. It returns the nack reason and a message for the player, or empty
+// strings if the argument is acceptable. Surrounding whitespace doesn't count toward the length.
+func checkArgumentContent(content string, config conversation.DebateConfig) (reason, message string) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nackReasonEmpty, "Arguments cannot be empty"
+	}
+
+	minLength, maxLength := config.ArgumentLengthLimits()
+	length := utf8.RuneCountInString(content)
+	if length < minLength {
+		return nackReasonTooShort, fmt.Sprintf("Arguments must be at least %d characters", minLength)
+	}
+	if length > maxLength {
+		return nackReasonTooLong, fmt.Sprintf("Arguments are limited to %d characters", maxLength)
+	}
+
+	if longestRun(content) > maxRepeatedChars {
+		return nackReasonSpam, "Arguments cannot repeat the same character over and over"
+	}
+
+	letters, uppercase := 0, 0
+	for _, r := range content {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				uppercase++
+			}
+		}
+	}
+	if letters >= minShoutingLetters && float64(uppercase) >= maxUppercaseLetterPct*float64(letters) {
+		return nackReasonSpam, "Arguments cannot be written in all caps"
+	}
+
+	return "", ""
+}
+
+// longestRun returns the length of the longest run of one repeated non-space character
+func longestRun(s string) int {
+	longest, run := 0, 0
+	var previous rune
+	for _, r := range s {
+		if r == previous && !unicode.IsSpace(r) {
+			run++
+		} else {
+			run = 1
+		}
+		previous = r
+		if run > longest {
+			longest = run
+		}
+	}
+	return longest
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckArgumentContent(t *testing.T) {
+	config := conversation.DefaultConfig()
+	config.MinArgumentLength = 20
+	config.MaxArgumentLength = 100
+
+	tests := []struct {
+		name    string
+		content string
+		reason  string
+	}{
+		{"Empty", "", nackReasonEmpty},
+		{"Whitespace only", " \t\n ", nackReasonEmpty},
+		{"Exactly min length", strings.Repeat("ab ", 6) + "ab", ""},
+		{"One under min length", strings.Repeat("ab ", 6) + "a", nackReasonTooShort},
+		{"Surrounding whitespace doesn't count", "   " + strings.Repeat("ab ", 6) + "a   ", nackReasonTooShort},
+		{"Exactly max length", strings.Repeat("abcd ", 19) + "abcde", ""},
+		{"One over max length", strings.Repeat("abcd ", 20) + "a", nackReasonTooLong},
+		{"Length is in characters, not bytes", strings.Repeat("éè ", 33) + "é", ""},
+		{"Repeated characters", "Messi is the best!!!!!!!!!!!!", nackReasonSpam},
+		{"Allowed run of characters", "Messi is the best!!!!!!!!", ""},
+		{"All-caps flood", "MESSI IS THE GREATEST OF ALL TIME", nackReasonSpam},
+		{"Mostly lowercase with an acronym", "The NBA and FIFA both agree with me here", ""},
+		{"Short all-caps", "GOAT MESSI, NO DOUBT", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, message := checkArgumentContent(tt.content, config)
+			assert.Equal(t, tt.reason, reason)
+			assert.Equal(t, tt.reason == "", message == "")
+		})
+	}
+
+	// A config without limits falls back to the defaults
+	reason, _ := checkArgumentContent("Too short", conversation.DebateConfig{})
+	assert.Equal(t, nackReasonTooShort, reason)
+	reason, _ = checkArgumentContent(strings.Repeat("word ", conversation.DefaultMaxArgumentLength/5+1), conversation.DebateConfig{})
+	assert.Equal(t, nackReasonTooLong, reason)
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/logging"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
+)
+
+// explainRateLimit caps requests for new score critiques per user per minute, since each one is a paid LLM call
+const explainRateLimit = 5
+
+// States of an argument's score critique
+const (
+	critiqueReady   = "ready"   // The critique is included in the response
+	critiquePending = "pending" // The critique is being generated; poll GET /api/arguments/:id/explain
+	critiqueMissing = "none"    // No critique has been requested
+)
+
+// critiqueJobs tracks the critiques being generated, so an argument is only ever being explained once at a
+// time. The zero value is ready to use.
+type critiqueJobs struct {
+	mu      sync.Mutex
+	running map[int64]bool
+}
+
+// start claims an argument's critique for generation, reporting false if it is already being generated
+func (j *critiqueJobs) start(argumentID int64) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.running[argumentID] {
+		return false
+	}
+	if j.running == nil {
+		j.running = make(map[int64]bool)
+	}
+	j.running[argumentID] = true
+	return true
+}
+
+// finish releases an argument's critique claimed with start
+func (j *critiqueJobs) finish(argumentID int64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	delete(j.running, argumentID)
+}
+
+// isRunning reports whether an argument's critique is being generated
+func (j *critiqueJobs) isRunning(argumentID int64) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.running[argumentID]
+}
+
+// explainArgumentHandler asks the judge for a detailed critique of an argument's score. A cached critique is
+// returned straight away; otherwise generation starts in the background and the response carries the score's
+// brief explanation, with status "pending" until the critique can be fetched from getArgumentExplanationHandler.
+func (s *Server) explainArgumentHandler(c *gin.Context) {
+	argument, ok := s.scoredArgument(c)
+	if !ok {
+		return
+	}
+
+	critique, err := s.db.GetScoreCritique(argument.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get score critique", "details": err.Error()})
+		return
+	}
+	if critique != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"argument_id": argument.ID,
+			"status":      critiqueReady,
+			"explanation": argument.Score.Explanation,
+			"critique":    critique,
+		})
+		return
+	}
+
+	if s.scorer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Scoring is currently unavailable"})
+		return
+	}
+
+	if s.critiques.start(argument.ID) {
+		userID, _ := auth.GetUserID(c)
+		go s.generateScoreCritique(argument, userID)
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"argument_id": argument.ID,
+		"status":      critiquePending,
+		"explanation": argument.Score.Explanation,
+	})
+}
+
+// getArgumentExplanationHandler returns an argument's score critique if it has been generated, without
+// generating one, so clients can poll after explainArgumentHandler
+func (s *Server) getArgumentExplanationHandler(c *gin.Context) {
+	argument, ok := s.scoredArgument(c)
+	if !ok {
+		return
+	}
+
+	critique, err := s.db.GetScoreCritique(argument.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get score critique", "details": err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"argument_id": argument.ID,
+		"status":      critiqueMissing,
+		"explanation": argument.Score.Explanation,
+	}
+	switch {
+	case critique != nil:
+		response["status"] = critiqueReady
+		response["critique"] = critique
+	case s.critiques.isRunning(argument.ID):
+		response["status"] = critiquePending
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// scoredArgument loads the argument named by the :argumentID parameter, answering the request itself if it
+// doesn't exist or hasn't been scored
+func (s *Server) scoredArgument(c *gin.Context) (*database.Argument, bool) {
+	argumentID, err := strconv.ParseInt(c.Param("argumentID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid argument ID"})
+		return nil, false
+	}
+
+	argument, err := s.db.GetArgumentWithScore(argumentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if argument.Score == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Argument has not been scored"})
+		return nil, false
+	}
+	return argument, true
+}
+
+// generateScoreCritique generates and caches a critique of an argument's score. It runs in the background, so
+// failures are logged; the argument can be explained again once this returns.
+func (s *Server) generateScoreCritique(argument *database.Argument, requestedBy string) {
+	defer s.critiques.finish(argument.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.GetLLMTimeout())
+	defer cancel()
+
+	// The critique is billed to the argument's debate while it is still in memory
+	language := types.LanguageEnglish
+	if argument.DebateID != nil && s.debateManager != nil {
+		if session, exists := s.debateManager.GetDebate(*argument.DebateID); exists {
+			ctx = usage.WithRecorder(ctx, session)
+			language = session.Config.Language
+		}
+	}
+
+	critique, err := s.scorer.ExplainScore(ctx, argument.Content, argument.Topic, argument.Score, language)
+	if err != nil {
+		logging.Error("Failed to generate score critique", map[string]interface{}{
+			"argument_id": argument.ID,
+			"error":       err.Error(),
+		})
+		return
+	}
+	if err := s.db.SaveScoreCritique(argument.ID, critique); err != nil {
+		logging.Error("Failed to save score critique", map[string]interface{}{
+			"argument_id": argument.ID,
+			"error":       err.Error(),
+		})
+		return
+	}
+
+	logging.Info("Generated score critique", map[string]interface{}{
+		"argument_id":  argument.ID,
+		"requested_by": requestedBy,
+	})
+}
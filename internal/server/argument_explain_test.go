@@ -0,0 +1,91 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neo/convinceme_backend/internal/scoring"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// critiqueDB caches score critiques in memory
+type critiqueDB struct {
+	*TestMockDB
+	mu        sync.Mutex
+	critiques map[int64]*scoring.ScoreCritique
+}
+
+func (m *critiqueDB) GetScoreCritique(argumentID int64) (*scoring.ScoreCritique, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.critiques[argumentID], nil
+}
+
+func (m *critiqueDB) SaveScoreCritique(argumentID int64, critique *scoring.ScoreCritique) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.critiques[argumentID] = critique
+	return nil
+}
+
+func TestExplainArgument(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.db = &critiqueDB{TestMockDB: &TestMockDB{}, critiques: map[int64]*scoring.ScoreCritique{}}
+	server.router.POST("/api/arguments/:argumentID/explain", server.explainArgumentHandler)
+	server.router.GET("/api/arguments/:argumentID/explain", server.getArgumentExplanationHandler)
+
+	explain := func(method string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(method, "/api/arguments/7/explain", nil))
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	code, response := explain(http.MethodGet)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, critiqueMissing, response["status"])
+
+	code, _ = explain(http.MethodPost)
+	assert.Equal(t, http.StatusServiceUnavailable, code, "the test server has no scorer")
+
+	server.scorer = scoring.NewOfflineScorer()
+	code, response = explain(http.MethodPost)
+	assert.Equal(t, http.StatusAccepted, code)
+	assert.Equal(t, critiquePending, response["status"])
+	assert.NotContains(t, response, "critique")
+
+	require.Eventually(t, func() bool {
+		_, response := explain(http.MethodGet)
+		return response["status"] == critiqueReady
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.False(t, server.critiques.isRunning(7))
+
+	// Once generated, the critique is served from the cache
+	code, response = explain(http.MethodPost)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, critiqueReady, response["status"])
+	critique := response["critique"].(map[string]interface{})
+	assert.NotEmpty(t, critique["summary"])
+	assert.Len(t, critique["aspects"], 5)
+
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/arguments/nope/explain", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCritiqueJobs(t *testing.T) {
+	var jobs critiqueJobs
+	assert.True(t, jobs.start(1))
+	assert.False(t, jobs.start(1), "an argument is only explained once at a time")
+	assert.True(t, jobs.start(2))
+	jobs.finish(1)
+	assert.False(t, jobs.isRunning(1))
+	assert.True(t, jobs.start(1))
+}
@@ -0,0 +1,269 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/logging"
+	"github.com/neo/convinceme_backend/internal/usage"
+)
+
+// argumentEditWindow is how long after submission a player may still edit an argument
+const argumentEditWindow = 2 * time.Minute
+
+// scorePreviewRateLimit caps score previews per user per minute, since each one is a paid LLM call
+const scorePreviewRateLimit = 3
+
+// setupArgumentRoutes sets up the protected argument routes
+func (s *Server) setupArgumentRoutes() {
+	// Protected argument endpoints - require authentication
+	argumentGroup := s.router.Group("/api/arguments")
+	argumentGroup.Use(s.auth.AuthMiddleware())
+	{
+		argumentGroup.POST("/:argumentID/vote", s.submitVoteHandler)                                            // Submit votes on arguments
+		argumentGroup.PUT("/:argumentID", TimeoutMiddleware(s.config.GetLLMTimeout()), s.updateArgumentHandler) // Edit own argument within the edit window
+		argumentGroup.DELETE("/:argumentID", s.deleteArgumentHandler)                                           // Delete own argument
+		argumentGroup.POST("/:argumentID/explain", UserRateLimitMiddleware(explainRateLimit, time.Minute),
+			s.explainArgumentHandler) // Detailed critique of an argument's score, generated in the background
+		argumentGroup.GET("/:argumentID/explain", s.getArgumentExplanationHandler) // Poll for the critique
+	}
+}
+
+// parseArgumentTime parses the created_at value returned for an argument
+func parseArgumentTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02 15:04:05", value)
+}
+
+// updateArgumentHandler lets a player edit and re-score their own argument shortly after submitting it
+func (s *Server) updateArgumentHandler(c *gin.Context) {
+	argumentID, err := strconv.ParseInt(c.Param("argumentID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid argument ID"})
+		return
+	}
+
+	var req struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+	req.Content = strings.TrimSpace(req.Content)
+	if req.Content == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Content cannot be empty"})
+		return
+	}
+
+	// Get user ID from authentication context
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	argument, err := s.db.GetArgumentWithScore(argumentID)
+	if errors.Is(err, database.ErrArgumentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Argument not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get argument", "details": err.Error()})
+		return
+	}
+
+	if argument.PlayerID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own arguments"})
+		return
+	}
+
+	// The edit must meet the limits of the argument's debate, read from its stored config once it's no longer
+	// in memory
+	config := conversation.DefaultConfig()
+	if argument.DebateID != nil && s.debateManager != nil {
+		if session, exists := s.debateManager.GetDebate(*argument.DebateID); exists {
+			config = session.Config
+		} else if debate, err := s.db.GetDebate(*argument.DebateID); err == nil {
+			config = s.debateManager.restoredConfig(debate)
+		}
+	}
+	if reason, message := checkArgumentContent(req.Content, config); reason != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message, "reason": reason})
+		return
+	}
+
+	// Arguments are locked once the edit window has passed
+	createdAt, err := parseArgumentTime(argument.CreatedAt)
+	if err != nil || time.Since(createdAt) > argumentEditWindow {
+		c.JSON(http.StatusConflict, gin.H{"error": "Argument can no longer be edited"})
+		return
+	}
+
+	if s.scorer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Scoring is currently unavailable"})
+		return
+	}
+
+	// Re-score the edited argument against the same topic
+	score, err := s.scorer.ScoreArgument(c.Request.Context(), req.Content, argument.Topic)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to score argument", "details": err.Error()})
+		return
+	}
+
+	if err := s.db.UpdateArgument(argumentID, userID, req.Content); err != nil {
+		if errors.Is(err, database.ErrNotArgumentOwner) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own arguments"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update argument", "details": err.Error()})
+		return
+	}
+
+	if err := s.db.UpdateScore(argumentID, score); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update argument score", "details": err.Error()})
+		return
+	}
+	if argument.DebateID != nil {
+		s.invalidateLeaderboard(*argument.DebateID)
+	}
+
+	logging.InfoCtx(c.Request.Context(), "Argument edited", map[string]interface{}{
+		"argument_id": argumentID,
+		"user_id":     userID,
+		"score":       score.Average,
+	})
+
+	updated, err := s.db.GetArgumentWithScore(argumentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load updated argument", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// deleteArgumentHandler lets a player delete their own argument along with its votes
+func (s *Server) deleteArgumentHandler(c *gin.Context) {
+	argumentID, err := strconv.ParseInt(c.Param("argumentID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid argument ID"})
+		return
+	}
+
+	// Get user ID from authentication context
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	argument, err := s.db.GetArgumentWithScore(argumentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if argument.PlayerID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own arguments"})
+		return
+	}
+
+	if err := s.db.DeleteArgument(argumentID, userID); err != nil {
+		if errors.Is(err, database.ErrNotArgumentOwner) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own arguments"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete argument", "details": err.Error()})
+		return
+	}
+	if argument.DebateID != nil {
+		s.invalidateLeaderboard(*argument.DebateID)
+	}
+
+	logging.InfoCtx(c.Request.Context(), "Argument deleted", map[string]interface{}{
+		"argument_id": argumentID,
+		"user_id":     userID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Argument deleted successfully",
+	})
+}
+
+// scorePreviewHandler scores an argument against a debate's topic without saving it, changing HP or broadcasting,
+// so players can try an argument out before spending a real submission
+func (s *Server) scorePreviewHandler(c *gin.Context) {
+	debateID := c.Param("debateID")
+
+	var req struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+	req.Content = strings.TrimSpace(req.Content)
+	if req.Content == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Content cannot be empty"})
+		return
+	}
+
+	// Get user ID from authentication context
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	session, exists := s.debateManager.GetDebate(debateID)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Debate not found or not active"})
+		return
+	}
+
+	// Only players connected to the debate may preview, which keeps this from being used as a free scoring API
+	if !session.HasParticipant(userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You must join the debate before previewing arguments"})
+		return
+	}
+
+	if reason, message := checkArgumentContent(req.Content, session.Config); reason != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message, "reason": reason})
+		return
+	}
+
+	if s.scorer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Scoring is currently unavailable"})
+		return
+	}
+
+	// Previews are billed to the debate like any other scoring call, and scored the way the argument itself would be
+	score, err := s.scorer.ScoreArgumentInStyle(usage.WithRecorder(c.Request.Context(), session), req.Content, session.Config.Topic, session.Config.Language, session.Config.ResponseStyle)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to score argument", "details": err.Error()})
+		return
+	}
+
+	logging.InfoCtx(c.Request.Context(), "Argument score previewed", map[string]interface{}{
+		"debate_id": debateID,
+		"user_id":   userID,
+		"score":     score.Average,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"score":              score,
+		"preview":            true,
+		"counts_toward_game": false,
+		"message":            "This is a preview only: the argument was not submitted and does not affect the debate",
+	})
+}
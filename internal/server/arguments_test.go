@@ -0,0 +1,351 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateArgumentHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.setupArgumentRoutes()
+
+	// The mock argument belongs to player-1 and was created long ago
+	ownerToken, err := server.auth.GenerateToken(auth.User{ID: "player-1", Username: "player1", Role: "user"})
+	require.NoError(t, err)
+	otherToken, err := server.auth.GenerateToken(auth.User{ID: "player-2", Username: "player2", Role: "user"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		argumentID     string
+		body           map[string]interface{}
+		token          string
+		expectedStatus int
+	}{
+		{
+			name:           "Owner editing after the window",
+			argumentID:     "1",
+			body:           map[string]interface{}{"content": "A better argument"},
+			token:          ownerToken,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Non-owner editing",
+			argumentID:     "1",
+			body:           map[string]interface{}{"content": "Hijacked argument"},
+			token:          otherToken,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Empty content",
+			argumentID:     "1",
+			body:           map[string]interface{}{"content": "   "},
+			token:          ownerToken,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid argument ID",
+			argumentID:     "abc",
+			body:           map[string]interface{}{"content": "A better argument"},
+			token:          ownerToken,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unauthenticated",
+			argumentID:     "1",
+			body:           map[string]interface{}{"content": "A better argument"},
+			token:          "",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			req, err := http.NewRequest("PUT", "/api/arguments/"+tc.argumentID, bytes.NewBuffer(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
+
+// argumentLookupDB is a TestMockDB whose argument lookups fail with err
+type argumentLookupDB struct {
+	*TestMockDB
+	err error
+}
+
+func (m *argumentLookupDB) GetArgumentWithScore(id int64) (*database.Argument, error) {
+	return nil, m.err
+}
+
+// TestUpdateArgumentHandlerLookup tests that only a missing argument is a 404, and that an argument whose debate
+// is no longer in memory is held to the debate's stored limits
+func TestUpdateArgumentHandlerLookup(t *testing.T) {
+	update := func(db database.DatabaseInterface) *httptest.ResponseRecorder {
+		server, tempDir := setupTestServer(t)
+		defer teardownTestServer(tempDir)
+		server.db = db
+		server.debateManager = &DebateManager{db: db, debates: map[string]*conversation.DebateSession{}}
+		server.setupArgumentRoutes()
+		ownerToken, err := server.auth.GenerateToken(auth.User{ID: "player-1", Username: "player1", Role: "user"})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPut, "/api/arguments/1", strings.NewReader(`{"content":"A much better argument than before"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+ownerToken)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := update(&argumentLookupDB{TestMockDB: &TestMockDB{}, err: fmt.Errorf("argument 1 not found: %w", database.ErrArgumentNotFound)})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = update(&argumentLookupDB{TestMockDB: &TestMockDB{}, err: errors.New("failed to get argument: database is locked")})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = update(&forkSourceDB{TestMockDB: &TestMockDB{}, config: `{"MaxArgumentLength":20}`})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), nackReasonTooLong)
+}
+
+func TestDeleteArgumentHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.setupArgumentRoutes()
+
+	ownerToken, err := server.auth.GenerateToken(auth.User{ID: "player-1", Username: "player1", Role: "user"})
+	require.NoError(t, err)
+	otherToken, err := server.auth.GenerateToken(auth.User{ID: "player-2", Username: "player2", Role: "user"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		token          string
+		expectedStatus int
+	}{
+		{
+			name:           "Owner deleting argument",
+			token:          ownerToken,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Non-owner deleting argument",
+			token:          otherToken,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Unauthenticated",
+			token:          "",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("DELETE", "/api/arguments/1", nil)
+			require.NoError(t, err)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, "Argument deleted successfully", response["message"])
+			}
+		})
+	}
+}
+
+func TestGetDebateArgumentsHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/debates/:debateID/arguments", server.auth.OptionalAuthMiddleware(), server.getDebateArgumentsHandler)
+
+	// The mock records an upvote from voter-1 on argument 1
+	voterToken, err := server.auth.GenerateToken(auth.User{ID: "voter-1", Username: "voter1", Role: "user"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name             string
+		query            string
+		token            string
+		expectedStatus   int
+		expectedUserVote string
+	}{
+		{
+			name:             "Anonymous caller",
+			query:            "",
+			expectedStatus:   http.StatusOK,
+			expectedUserVote: "",
+		},
+		{
+			name:             "Authenticated caller sees own vote",
+			query:            "?sort_by=score&sort_dir=desc",
+			token:            voterToken,
+			expectedStatus:   http.StatusOK,
+			expectedUserVote: "upvote",
+		},
+		{
+			name:           "Unsupported sort",
+			query:          "?sort_by=content",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/api/debates/test-debate/arguments"+tc.query, nil)
+			require.NoError(t, err)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.expectedStatus == http.StatusOK {
+				var response struct {
+					Items      []map[string]interface{} `json:"items"`
+					Pagination map[string]interface{}   `json:"pagination"`
+				}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.Len(t, response.Items, 2)
+				assert.Equal(t, float64(2), response.Pagination["total_items"])
+
+				userVote, _ := response.Items[0]["user_vote"].(string)
+				assert.Equal(t, tc.expectedUserVote, userVote)
+			}
+		})
+	}
+}
+
+func TestScorePreviewHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.POST("/api/debates/:debateID/score-preview", server.auth.AuthMiddleware(), server.scorePreviewHandler)
+
+	// player-1 is connected to the live debate as a participant; the test server has no scorer
+	session := &conversation.DebateSession{
+		DebateID:    "test-debate",
+		Config:      conversation.DebateConfig{Topic: "Test Topic"},
+		Clients:     make(map[*websocket.Conn]string),
+		ClientRoles: make(map[*websocket.Conn]string),
+	}
+	require.NoError(t, session.AddClientWithRole(&websocket.Conn{}, "player-1", conversation.ClientRoleParticipant))
+	require.NoError(t, session.AddClientWithRole(&websocket.Conn{}, "spectator-1", conversation.ClientRoleSpectator))
+	server.debateManager = &DebateManager{debates: map[string]*conversation.DebateSession{"test-debate": session}}
+
+	playerToken, err := server.auth.GenerateToken(auth.User{ID: "player-1", Username: "player1", Role: "user"})
+	require.NoError(t, err)
+	spectatorToken, err := server.auth.GenerateToken(auth.User{ID: "spectator-1", Username: "spectator1", Role: "user"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		debateID       string
+		body           map[string]interface{}
+		token          string
+		expectedStatus int
+	}{
+		{
+			name:           "Joined participant reaches the scorer",
+			debateID:       "test-debate",
+			body:           map[string]interface{}{"content": "A test argument"},
+			token:          playerToken,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "Spectator cannot preview",
+			debateID:       "test-debate",
+			body:           map[string]interface{}{"content": "A test argument"},
+			token:          spectatorToken,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Debate not active",
+			debateID:       "missing-debate",
+			body:           map[string]interface{}{"content": "A test argument"},
+			token:          playerToken,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Empty content",
+			debateID:       "test-debate",
+			body:           map[string]interface{}{"content": "  "},
+			token:          playerToken,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Content under the debate's minimum length",
+			debateID:       "test-debate",
+			body:           map[string]interface{}{"content": "Nope"},
+			token:          playerToken,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unauthenticated",
+			debateID:       "test-debate",
+			body:           map[string]interface{}{"content": "A test argument"},
+			token:          "",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			req, err := http.NewRequest("POST", "/api/debates/"+tc.debateID+"/score-preview", bytes.NewBuffer(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
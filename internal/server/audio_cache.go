@@ -0,0 +1,87 @@
+package server
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// audioCacheCounters counts audio cache activity since the server started
+type audioCacheCounters struct {
+	hits   atomic.Int64 // Fetches served from the cache
+	misses atomic.Int64 // Fetches for audio that was never cached or already evicted
+	stores atomic.Int64 // Clips added to the cache
+
+	lastSweep atomic.Int64 // UnixNano of the last eviction sweep, used to throttle sweeps
+}
+
+// audioCacheStats describes the in-memory audio cache
+type audioCacheStats struct {
+	Entries          int     `json:"entries"`
+	TotalBytes       int64   `json:"total_bytes"`
+	OldestAgeSeconds float64 `json:"oldest_age_seconds"` // 0 when the cache is empty
+	TTLSeconds       float64 `json:"ttl_seconds"`
+	Hits             int64   `json:"hits"`
+	Misses           int64   `json:"misses"`
+	Stores           int64   `json:"stores"`
+}
+
+// getAudioCacheStats summarizes the audio cache's contents and counters
+func (s *Server) getAudioCacheStats() audioCacheStats {
+	stats := audioCacheStats{
+		TTLSeconds: s.config.GetAudioCacheTTL().Seconds(),
+		Hits:       s.cacheStats.hits.Load(),
+		Misses:     s.cacheStats.misses.Load(),
+		Stores:     s.cacheStats.stores.Load(),
+	}
+
+	s.cacheMutex.RLock()
+	defer s.cacheMutex.RUnlock()
+
+	var oldest time.Time
+	for _, cache := range s.audioCache {
+		stats.Entries++
+		stats.TotalBytes += int64(len(cache.data))
+		for _, variant := range cache.variants {
+			stats.TotalBytes += int64(len(variant))
+		}
+		if oldest.IsZero() || cache.timestamp.Before(oldest) {
+			oldest = cache.timestamp
+		}
+	}
+	if !oldest.IsZero() {
+		stats.OldestAgeSeconds = time.Since(oldest).Seconds()
+	}
+	return stats
+}
+
+// flushAudioCache removes all cached audio and HLS segments, returning how many cached clips were removed
+func (s *Server) flushAudioCache() int {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	flushed := len(s.audioCache)
+	s.audioCache = make(map[string]audioCache)
+
+	if s.hlsDir != "" {
+		cleanupHLSSegments(s.hlsDir, time.Now())
+	}
+	return flushed
+}
+
+// getAudioCacheStatsHandler reports the audio cache's size and hit rate
+func (s *Server) getAudioCacheStatsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, s.getAudioCacheStats())
+}
+
+// flushAudioCacheHandler empties the audio cache. Audio URLs already handed out will 404 afterwards.
+func (s *Server) flushAudioCacheHandler(c *gin.Context) {
+	flushed := s.flushAudioCache()
+	s.recordAudit(c, auditActionAudioCacheFlush, "", gin.H{"flushed": flushed})
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Audio cache flushed",
+		"flushed": flushed,
+	})
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudioCacheDelayedFetchSurvivesTTL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Now()
+	s := &Server{
+		config: &Config{AudioCacheTTL: time.Hour},
+		audioCache: map[string]audioCache{
+			"fetched":   {data: []byte("a"), timestamp: now.Add(-2 * time.Hour)},
+			"unfetched": {data: []byte("b"), timestamp: now.Add(-2 * time.Hour)},
+		},
+	}
+
+	// A slow client fetches the audio after its TTL has passed but before a sweep ran
+	router := gin.New()
+	router.GET("/api/audio/:id", s.handleAudioStream)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/audio/fetched", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// handleAudioStream kicks off a sweep in the background; wait for it to finish
+	require.Eventually(t, func() bool {
+		s.cacheMutex.RLock()
+		defer s.cacheMutex.RUnlock()
+		_, exists := s.audioCache["unfetched"]
+		return !exists
+	}, time.Second, 10*time.Millisecond, "audio past its TTL and grace should be evicted")
+	s.cacheMutex.RLock()
+	defer s.cacheMutex.RUnlock()
+
+	assert.Contains(t, s.audioCache, "fetched", "audio fetched just now should survive the sweep")
+	assert.False(t, s.audioCache["fetched"].lastAccess.IsZero())
+}
+
+func TestAudioCacheUnfetchedGrace(t *testing.T) {
+	now := time.Now()
+	s := &Server{
+		config: &Config{AudioCacheTTL: time.Minute},
+		audioCache: map[string]audioCache{
+			"within-grace": {data: []byte("a"), timestamp: now.Add(-5 * time.Minute)},
+			"past-grace":   {data: []byte("b"), timestamp: now.Add(-audioFetchGrace - time.Minute)},
+			"stale-fetch":  {data: []byte("c"), timestamp: now.Add(-5 * time.Minute), lastAccess: now.Add(-2 * time.Minute)},
+		},
+	}
+
+	s.cleanupCache()
+
+	assert.Contains(t, s.audioCache, "within-grace", "unfetched audio should be kept for the fetch grace even past its TTL")
+	assert.NotContains(t, s.audioCache, "past-grace")
+	assert.NotContains(t, s.audioCache, "stale-fetch", "fetched audio should expire once its TTL passes since the last fetch")
+
+	// A second sweep within the interval is skipped
+	s.audioCache["expired"] = audioCache{data: []byte("d"), timestamp: now.Add(-time.Hour)}
+	s.cleanupCache()
+	assert.Contains(t, s.audioCache, "expired")
+}
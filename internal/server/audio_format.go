@@ -0,0 +1,204 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultAllowedAudioTypes are the content types audio is served as when Config.AllowedAudioTypes is empty
+var DefaultAllowedAudioTypes = []string{"audio/mpeg", "audio/aac", "audio/ogg", "audio/wav", "audio/webm", "audio/mp4", "audio/flac"}
+
+// audioTranscodeTimeout bounds a single ffmpeg transcoding run
+const audioTranscodeTimeout = 30 * time.Second
+
+// audioTranscodes tracks the transcodes in flight, so concurrent requests for the same audio and format share
+// one ffmpeg run
+type audioTranscodes struct {
+	mu      sync.Mutex
+	running map[string]*audioTranscode
+}
+
+// audioTranscode is a transcode in flight; done is closed once data and err are set
+type audioTranscode struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// do runs transcode for a key, or waits for the run already in flight for it and returns its result
+func (t *audioTranscodes) do(key string, transcode func() ([]byte, error)) ([]byte, error) {
+	t.mu.Lock()
+	if call, exists := t.running[key]; exists {
+		t.mu.Unlock()
+		<-call.done
+		return call.data, call.err
+	}
+	if t.running == nil {
+		t.running = make(map[string]*audioTranscode)
+	}
+	call := &audioTranscode{done: make(chan struct{})}
+	t.running[key] = call
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		delete(t.running, key)
+		t.mu.Unlock()
+		close(call.done)
+	}()
+	call.data, call.err = transcode()
+	return call.data, call.err
+}
+
+// audioFormat is a format clients can request audio in with ?format=
+type audioFormat struct {
+	contentType string
+	ffmpegArgs  []string // Codec and container arguments for ffmpeg
+}
+
+// audioFormats are the formats handleAudioStream can transcode to, by their ?format= name
+var audioFormats = map[string]audioFormat{
+	"mp3":  {contentType: "audio/mpeg", ffmpegArgs: []string{"-c:a", "libmp3lame", "-b:a", "128k", "-f", "mp3"}},
+	"opus": {contentType: "audio/ogg", ffmpegArgs: []string{"-c:a", "libopus", "-b:a", "64k", "-f", "ogg"}},
+	"aac":  {contentType: "audio/aac", ffmpegArgs: []string{"-c:a", "aac", "-b:a", "128k", "-f", "adts"}},
+}
+
+// hlsContentTypes are the content types of HLS files by extension, which not every system's MIME table knows
+var hlsContentTypes = map[string]string{
+	".m3u8": "application/vnd.apple.mpegurl",
+	".aac":  "audio/aac",
+	".ts":   "video/mp2t",
+}
+
+// detectAudioContentType returns the content type of audio from its leading bytes. Unrecognized data is
+// assumed to be MP3, which is what the TTS providers return.
+func detectAudioContentType(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("ID3")):
+		return "audio/mpeg"
+	case len(data) >= 2 && data[0] == 0xFF && data[1]&0xF6 == 0xF0:
+		// ADTS frame sync with layer 0; MP3 frames share the sync bits but have a non-zero layer
+		return "audio/aac"
+	case len(data) >= 2 && data[0] == 0xFF && data[1]&0xE0 == 0xE0:
+		return "audio/mpeg"
+	case bytes.HasPrefix(data, []byte("OggS")):
+		return "audio/ogg"
+	case len(data) >= 12 && bytes.HasPrefix(data, []byte("RIFF")) && string(data[8:12]) == "WAVE":
+		return "audio/wav"
+	case bytes.HasPrefix(data, []byte("fLaC")):
+		return "audio/flac"
+	case bytes.HasPrefix(data, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		return "audio/webm"
+	case len(data) >= 8 && string(data[4:8]) == "ftyp":
+		return "audio/mp4"
+	}
+	return "audio/mpeg"
+}
+
+// audioTypeAllowed reports whether audio of a content type may be served, ignoring any parameters
+func audioTypeAllowed(allowed []string, contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	for _, allowedType := range allowed {
+		if strings.EqualFold(strings.TrimSpace(allowedType), strings.TrimSpace(mediaType)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ffmpegTranscode converts audio to a format with ffmpeg, piping it through stdin and stdout
+func ffmpegTranscode(data []byte, format audioFormat) ([]byte, error) {
+	ffmpegPath, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg not available: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), audioTranscodeTimeout)
+	defer cancel()
+
+	args := append([]string{"-hide_banner", "-loglevel", "error", "-i", "pipe:0", "-vn"}, format.ffmpegArgs...)
+	cmd := exec.CommandContext(ctx, ffmpegPath, append(args, "pipe:1")...)
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg transcoding failed: %v: %s", err, stderr.Bytes())
+	}
+	return stdout.Bytes(), nil
+}
+
+// transcodeCachedAudio converts cached audio to a requested format and caches the result with the original, so
+// later requests for the format reuse it and it's evicted along with the original. Concurrent requests for the
+// same audio and format share one transcode.
+func (s *Server) transcodeCachedAudio(audioID string, cache audioCache, formatName string, format audioFormat) ([]byte, error) {
+	return s.transcodes.do(audioID+"/"+formatName, func() ([]byte, error) {
+		// A transcode that finished since the caller looked up the cache has already stored the variant
+		s.cacheMutex.RLock()
+		variant, exists := s.audioCache[audioID].variants[formatName]
+		s.cacheMutex.RUnlock()
+		if exists {
+			return variant, nil
+		}
+		return s.runTranscode(audioID, cache, formatName, format)
+	})
+}
+
+// runTranscode transcodes cached audio once an ffmpeg slot is free and caches the result
+func (s *Server) runTranscode(audioID string, cache audioCache, formatName string, format audioFormat) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), audioTranscodeTimeout)
+	defer cancel()
+	release, err := acquireFFmpeg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	transcode := s.transcodeAudio
+	if transcode == nil {
+		transcode = ffmpegTranscode
+	}
+	data, err := transcode(cache.data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheMutex.Lock()
+	if current, exists := s.audioCache[audioID]; exists {
+		if current.variants == nil {
+			current.variants = make(map[string][]byte)
+		}
+		current.variants[formatName] = data
+		s.audioCache[audioID] = current
+	}
+	s.cacheMutex.Unlock()
+	return data, nil
+}
+
+// hlsContentTypeMiddleware sets the content type of HLS playlists and segments before they are served
+func hlsContentTypeMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if dot := strings.LastIndex(path, "."); dot >= 0 {
+			if contentType, exists := hlsContentTypes[strings.ToLower(path[dot:])]; exists {
+				c.Header("Content-Type", contentType)
+			}
+		}
+		c.Next()
+	}
+}
+
+// serveAudio writes audio with the headers handleAudioStream uses for every format
+func serveAudio(c *gin.Context, contentType string, data []byte) {
+	c.Header("Content-Length", fmt.Sprintf("%d", len(data)))
+	c.Header("Cache-Control", "public, max-age=31536000")
+	c.Data(http.StatusOK, contentType, data)
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectAudioContentType(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{name: "MP3 with ID3 tag", data: []byte("ID3\x04\x00"), expected: "audio/mpeg"},
+		{name: "MP3 frame", data: []byte{0xFF, 0xFB, 0x90, 0x64}, expected: "audio/mpeg"},
+		{name: "AAC ADTS frame", data: []byte{0xFF, 0xF1, 0x50, 0x80}, expected: "audio/aac"},
+		{name: "Ogg", data: []byte("OggS\x00\x02"), expected: "audio/ogg"},
+		{name: "WAV", data: []byte("RIFF\x24\x00\x00\x00WAVEfmt "), expected: "audio/wav"},
+		{name: "FLAC", data: []byte("fLaC\x00"), expected: "audio/flac"},
+		{name: "WebM", data: []byte{0x1A, 0x45, 0xDF, 0xA3, 0x01}, expected: "audio/webm"},
+		{name: "MP4", data: []byte("\x00\x00\x00\x20ftypM4A "), expected: "audio/mp4"},
+		{name: "Unrecognized", data: []byte("data"), expected: "audio/mpeg"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, detectAudioContentType(tc.data))
+		})
+	}
+}
+
+func TestHandleAudioStreamFormats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	wav := []byte("RIFF\x24\x00\x00\x00WAVEfmt ")
+	transcodes := 0
+	s := &Server{
+		config: &Config{},
+		audioCache: map[string]audioCache{
+			"mp3": {data: []byte("ID3\x04\x00"), timestamp: time.Now(), contentType: "audio/mpeg"},
+			"wav": {data: wav, timestamp: time.Now()},
+		},
+		transcodeAudio: func(data []byte, format audioFormat) ([]byte, error) {
+			transcodes++
+			return []byte(format.contentType), nil
+		},
+	}
+	router := gin.New()
+	router.GET("/api/audio/:id", s.handleAudioStream)
+
+	fetch := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	// Audio is served in its original format, labeled with its actual type
+	w := fetch("/api/audio/wav")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "audio/wav", w.Header().Get("Content-Type"))
+	assert.Equal(t, wav, w.Body.Bytes())
+
+	// Requesting the format the audio is already in doesn't transcode it
+	w = fetch("/api/audio/mp3?format=mp3")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "audio/mpeg", w.Header().Get("Content-Type"))
+	assert.Equal(t, 0, transcodes)
+
+	// Transcoded variants are cached per format
+	w = fetch("/api/audio/wav?format=opus")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "audio/ogg", w.Header().Get("Content-Type"))
+	w = fetch("/api/audio/wav?format=opus")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "audio/ogg", w.Body.String())
+	assert.Equal(t, 1, transcodes)
+	w = fetch("/api/audio/wav?format=aac")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "audio/aac", w.Header().Get("Content-Type"))
+	assert.Equal(t, 2, transcodes)
+
+	assert.Equal(t, http.StatusBadRequest, fetch("/api/audio/wav?format=flac").Code)
+
+	// Types outside the allowlist are only served transcoded to an allowed one
+	s.config.AllowedAudioTypes = []string{"audio/mpeg"}
+	assert.Equal(t, http.StatusUnsupportedMediaType, fetch("/api/audio/wav").Code)
+	assert.Equal(t, http.StatusUnsupportedMediaType, fetch("/api/audio/wav?format=aac").Code)
+	w = fetch("/api/audio/wav?format=mp3")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "audio/mpeg", w.Header().Get("Content-Type"))
+}
+
+func TestTranscodeCachedAudioDeduplicates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	var transcodes atomic.Int32
+	s := &Server{
+		config: &Config{},
+		audioCache: map[string]audioCache{
+			"wav": {data: []byte("RIFF\x24\x00\x00\x00WAVEfmt "), timestamp: time.Now()},
+		},
+		transcodeAudio: func(data []byte, format audioFormat) ([]byte, error) {
+			if transcodes.Add(1) == 1 {
+				close(started)
+			}
+			<-unblock
+			return []byte(format.contentType), nil
+		},
+	}
+	router := gin.New()
+	router.GET("/api/audio/:id", s.handleAudioStream)
+
+	// Requests arriving while the first transcode runs wait for it instead of starting their own
+	const requests = 5
+	codes := make([]int, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/audio/wav?format=opus", nil))
+			codes[i] = w.Code
+		}(i)
+	}
+	<-started
+	time.Sleep(20 * time.Millisecond)
+	close(unblock)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), transcodes.Load())
+	for _, code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+}
+
+func TestHLSContentTypes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "segment_000.aac"), []byte{0xFF, 0xF1}, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, hlsPlaylistName), []byte("#EXTM3U\n"), 0644))
+
+	router := gin.New()
+	router.Group("/hls", hlsContentTypeMiddleware()).Static("/", dir)
+
+	for file, contentType := range map[string]string{
+		"segment_000.aac": "audio/aac",
+		hlsPlaylistName:   "application/vnd.apple.mpegurl",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/hls/"+file, nil))
+		require.Equal(t, http.StatusOK, w.Code, file)
+		assert.Equal(t, contentType, w.Header().Get("Content-Type"), file)
+	}
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/logging"
+)
+
+// Admin and moderator actions recorded in the audit log
+const (
+	auditActionDebateEnd         = "debate.end"
+	auditActionDebateRestartLoop = "debate.restart_loop"
+	auditActionDebateRegenerate  = "debate.regenerate_last"
+	auditActionDebateFork        = "debate.fork"
+	auditActionDebateFeature     = "debate.feature"
+	auditActionDebateUnfeature   = "debate.unfeature"
+	auditActionUserDelete        = "user.delete"
+	auditActionFeedbackDelete    = "feedback.delete"
+	auditActionTopicsImport      = "topics.import"
+	auditActionFeatureFlags      = "feature_flags.update"
+	auditActionLogLevel          = "log_level.set"
+	auditActionAudioCacheFlush   = "audio_cache.flush"
+)
+
+// recordAudit records an admin or moderator action taken by the request's user, with the request ID added to
+// the details. It's written in the background and failures are only logged, so auditing can never hold up or
+// fail the action itself.
+func (s *Server) recordAudit(c *gin.Context, action, target string, details gin.H) {
+	actorID, _ := auth.GetUserID(c)
+	entry := gin.H{"request_id": c.GetString("RequestID")}
+	for key, value := range details {
+		entry[key] = value
+	}
+
+	// The database is read now, as the goroutine may outlive the request
+	db := s.db
+	go func() {
+		if err := db.LogAuditEvent(actorID, action, target, entry); err != nil {
+			logging.Error("Failed to record audit event", map[string]interface{}{
+				"actor_id": actorID,
+				"action":   action,
+				"target":   target,
+				"error":    err.Error(),
+			})
+		}
+	}()
+}
+
+// listAuditEventsHandler lists the audit log, newest first, with pagination. It can be filtered by actor_id,
+// action and target, and by an RFC 3339 since/until time range.
+func (s *Server) listAuditEventsHandler(c *gin.Context) {
+	paginationParams := GetPaginationParams(c)
+
+	filter := database.AuditFilter{
+		ActorID: c.Query("actor_id"),
+		Action:  c.Query("action"),
+		Target:  c.Query("target"),
+		Offset:  paginationParams.CalculateOffset(),
+		Limit:   paginationParams.PageSize,
+	}
+	bounds := []struct {
+		param string
+		value *time.Time
+	}{{"since", &filter.Since}, {"until", &filter.Until}}
+	for _, bound := range bounds {
+		if value := c.Query(bound.param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + bound.param + " time, expected RFC 3339", "details": err.Error()})
+				return
+			}
+			*bound.value = parsed
+		}
+	}
+
+	events, total, err := s.db.ListAuditEvents(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list audit events", "details": err.Error()})
+		return
+	}
+
+	paginationParams.Total = total
+	SendPaginatedResponse(c, paginationParams, events)
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditDB records audit events in memory, or fails to when broken
+type auditDB struct {
+	*TestMockDB
+	mu     sync.Mutex
+	events []*database.AuditEvent
+	filter database.AuditFilter
+	broken bool
+}
+
+func (m *auditDB) LogAuditEvent(actorID, action, target string, details interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.broken {
+		return errors.New("disk full")
+	}
+	encoded, _ := json.Marshal(details)
+	m.events = append(m.events, &database.AuditEvent{ActorID: actorID, Action: action, Target: target, Details: encoded})
+	return nil
+}
+
+func (m *auditDB) ListAuditEvents(filter database.AuditFilter) ([]*database.AuditEvent, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.filter = filter
+	return m.events, len(m.events), nil
+}
+
+func (m *auditDB) recorded() []*database.AuditEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*database.AuditEvent(nil), m.events...)
+}
+
+func TestAuditLog(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	db := &auditDB{TestMockDB: &TestMockDB{}}
+	server.db = db
+	server.router.Use(RequestIDMiddleware())
+	admin := server.router.Group("/api/admin", server.auth.AuthMiddleware(), server.auth.RequireRole(string(database.RoleAdmin)))
+	admin.DELETE("/audio/cache", server.flushAudioCacheHandler)
+	admin.GET("/audit", server.listAuditEventsHandler)
+
+	adminToken, err := server.auth.GenerateToken(auth.User{ID: "admin-1", Username: "admin", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+	request := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		server.router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request(http.MethodDelete, "/api/admin/audio/cache")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Eventually(t, func() bool { return len(db.recorded()) == 1 }, time.Second, 5*time.Millisecond)
+	event := db.recorded()[0]
+	assert.Equal(t, "admin-1", event.ActorID)
+	assert.Equal(t, auditActionAudioCacheFlush, event.Action)
+	var details map[string]interface{}
+	require.NoError(t, json.Unmarshal(event.Details, &details))
+	assert.Equal(t, w.Header().Get("X-Request-ID"), details["request_id"])
+	assert.Equal(t, float64(0), details["flushed"])
+
+	// The action still succeeds when the audit log can't be written
+	db.mu.Lock()
+	db.broken = true
+	db.mu.Unlock()
+	assert.Equal(t, http.StatusOK, request(http.MethodDelete, "/api/admin/audio/cache").Code)
+
+	// Listing passes the filters and pagination through
+	w = request(http.MethodGet, "/api/admin/audit?actor_id=admin-1&action=audio_cache.flush&since=2024-05-01T00:00:00Z&page=2&page_size=5")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response["items"], 1)
+	assert.Equal(t, float64(1), response["pagination"].(map[string]interface{})["total_items"])
+	assert.Equal(t, "admin-1", db.filter.ActorID)
+	assert.Equal(t, auditActionAudioCacheFlush, db.filter.Action)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), db.filter.Since)
+	assert.True(t, db.filter.Until.IsZero())
+	assert.Equal(t, 5, db.filter.Offset)
+	assert.Equal(t, 5, db.filter.Limit)
+
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodGet, "/api/admin/audit?until=yesterday").Code)
+}
@@ -1,9 +1,11 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -255,13 +257,121 @@ func (s *Server) meHandler(c *gin.Context) {
 		return
 	}
 
-	// Return user
+	// Return user; updated_at lets clients detect conflicting edits when updating it
 	c.JSON(http.StatusOK, gin.H{
 		"user": gin.H{
-			"id":       user.ID,
-			"username": user.Username,
-			"email":    user.Email,
+			"id":         user.ID,
+			"username":   user.Username,
+			"email":      user.Email,
+			"updated_at": user.UpdatedAt,
+		},
+	})
+}
+
+// profileHandler returns the current user's full profile, permissions and the feature flags that apply to them.
+// Users authenticated by an external provider may have no local account yet; their profile comes from the token.
+func (s *Server) profileHandler(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	user, source, err := s.currentUser(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get user: %v", err)})
+		return
+	}
+
+	role := string(user.Role)
+	c.JSON(http.StatusOK, gin.H{
+		"user":   user,
+		"source": source,
+		"permissions": gin.H{
+			"is_admin":     user.Role == database.RoleAdmin,
+			"can_moderate": user.Role == database.RoleAdmin || user.Role == database.RoleModerator,
 		},
+		"feature_flags": s.featureFlags.GetFlags().ForRole(role),
+	})
+}
+
+// currentUser loads the authenticated user's account, falling back to the token claims when there is no local
+// account. The returned source is "account" or "token" accordingly.
+func (s *Server) currentUser(c *gin.Context, userID string) (*database.User, string, error) {
+	user, err := s.db.GetUserByID(userID)
+	if errors.Is(err, database.ErrUserNotFound) {
+		username, _ := auth.GetUsername(c)
+		email, _ := auth.GetUserEmail(c)
+		role, _ := auth.GetUserRole(c)
+		return &database.User{
+			ID:       userID,
+			Username: username,
+			Email:    email,
+			Role:     database.UserRole(role),
+		}, "token", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return user, "account", nil
+}
+
+// exportUserDataHandler returns everything stored about the current user as a downloadable JSON bundle,
+// so users can exercise their right of access under GDPR
+func (s *Server) exportUserDataHandler(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	// The User type never serializes password hashes or reset/verification tokens
+	user, source, err := s.currentUser(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get user: %v", err)})
+		return
+	}
+
+	arguments, err := s.db.GetArgumentsByUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export arguments", "details": err.Error()})
+		return
+	}
+
+	votes, err := s.db.GetVotesByUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export votes", "details": err.Error()})
+		return
+	}
+
+	feedback, err := s.db.GetFeedbackByUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export feedback", "details": err.Error()})
+		return
+	}
+
+	invitations, err := s.db.GetInvitationsByUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export invitations", "details": err.Error()})
+		return
+	}
+	// Leave out who redeemed each invitation, since that identifies another user
+	exportedInvitations := make([]database.InvitationCode, 0, len(invitations))
+	for _, invitation := range invitations {
+		exported := *invitation
+		exported.UsedBy = ""
+		exportedInvitations = append(exportedInvitations, exported)
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="convinceme-export-%s.json"`, userID))
+	c.JSON(http.StatusOK, gin.H{
+		"exported_at": time.Now().UTC(),
+		"user":        user,
+		"source":      source,
+		"arguments":   arguments,
+		"votes":       votes,
+		"feedback":    feedback,
+		"invitations": exportedInvitations,
 	})
 }
 
@@ -278,6 +388,8 @@ func (s *Server) updateUserHandler(c *gin.Context) {
 	var req struct {
 		Username string `json:"username" binding:"omitempty,min=3,max=30"`
 		Email    string `json:"email" binding:"omitempty,email"`
+		// Optional: the updated_at the client last read, so an edit based on a stale profile gets a 409
+		UpdatedAt *time.Time `json:"updated_at"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -313,8 +425,16 @@ func (s *Server) updateUserHandler(c *gin.Context) {
 		user.Email = req.Email
 	}
 
-	// Update user in database
-	err = s.db.UpdateUser(user)
+	// Update user in database, unless someone else changed it since it was read
+	expectedUpdatedAt := user.UpdatedAt
+	if req.UpdatedAt != nil {
+		expectedUpdatedAt = *req.UpdatedAt
+	}
+	err = s.db.UpdateUser(user, expectedUpdatedAt)
+	if errors.Is(err, database.ErrUserModified) {
+		c.JSON(http.StatusConflict, gin.H{"error": "User was modified by another request, refetch and try again"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update user: %v", err)})
 		return
@@ -324,9 +444,10 @@ func (s *Server) updateUserHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message": "User updated successfully",
 		"user": gin.H{
-			"id":       user.ID,
-			"username": user.Username,
-			"email":    user.Email,
+			"id":         user.ID,
+			"username":   user.Username,
+			"email":      user.Email,
+			"updated_at": user.UpdatedAt,
 		},
 	})
 }
@@ -386,9 +507,12 @@ func (s *Server) deleteUserHandler(c *gin.Context) {
 		return
 	}
 
-	// Delete user
-	err := s.db.DeleteUser(userID)
-	if err != nil {
+	// Anonymize rather than hard-delete, so the user's arguments stay in debate transcripts and leaderboards
+	if _, err := s.db.DeleteUserAnonymized(userID); err != nil {
+		if errors.Is(err, database.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete user: %v", err)})
 		return
 	}
@@ -397,3 +521,35 @@ func (s *Server) deleteUserHandler(c *gin.Context) {
 		"message": "User deleted successfully",
 	})
 }
+
+// adminDeleteUserHandler lets an admin delete any user. Accounts are anonymized by default; ?hard=true removes
+// the user row outright and leaves their content under the old ID.
+func (s *Server) adminDeleteUserHandler(c *gin.Context) {
+	userID := c.Param("userID")
+
+	if c.Query("hard") == "true" {
+		if err := s.db.DeleteUser(userID); err != nil {
+			if errors.Is(err, database.ErrUserNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete user: %v", err)})
+			return
+		}
+		s.recordAudit(c, auditActionUserDelete, userID, gin.H{"mode": "hard"})
+		c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully", "mode": "hard"})
+		return
+	}
+
+	anonymousID, err := s.db.DeleteUserAnonymized(userID)
+	if err != nil {
+		if errors.Is(err, database.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete user: %v", err)})
+		return
+	}
+	s.recordAudit(c, auditActionUserDelete, userID, gin.H{"mode": "anonymized", "anonymized_id": anonymousID})
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully", "mode": "anonymized", "anonymized_id": anonymousID})
+}
@@ -219,6 +219,8 @@ func (s *Server) setupAuthRoutes() {
 		// Protected routes
 		authGroup.Use(s.auth.AuthMiddleware())
 		authGroup.GET("/me", s.meHandler)
+		authGroup.GET("/profile", s.profileHandler)
+		authGroup.GET("/export", s.exportUserDataHandler)
 		authGroup.PUT("/me", s.updateUserHandler)
 		authGroup.POST("/change-password", s.changePasswordHandler)
 		authGroup.DELETE("/me", s.deleteUserHandler)
@@ -226,6 +228,6 @@ func (s *Server) setupAuthRoutes() {
 		// Admin routes
 		adminGroup := authGroup.Group("/admin")
 		adminGroup.Use(s.auth.RequireRole(string(database.RoleAdmin)))
-		// TODO: Add admin routes
+		adminGroup.DELETE("/users/:userID", s.adminDeleteUserHandler)
 	}
 }
@@ -862,3 +862,223 @@ func TestProtectedRoutes(t *testing.T) {
 		})
 	}
 }
+
+func TestProfileHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+
+	// test-user-id has a local account in the mock; external-user-id only exists in its token
+	accountToken, err := server.auth.GenerateToken(auth.User{ID: "test-user-id", Username: "testuser", Email: "test@example.com", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+	externalToken, err := server.auth.GenerateToken(auth.User{ID: "external-user-id", Username: "external", Email: "external@example.com", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name             string
+		token            string
+		expectedStatus   int
+		expectedSource   string
+		expectedUsername string
+		expectedAdmin    bool
+	}{
+		{
+			name:             "Local account",
+			token:            accountToken,
+			expectedStatus:   http.StatusOK,
+			expectedSource:   "account",
+			expectedUsername: "testuser",
+			expectedAdmin:    false,
+		},
+		{
+			name:             "Externally authenticated user",
+			token:            externalToken,
+			expectedStatus:   http.StatusOK,
+			expectedSource:   "token",
+			expectedUsername: "external",
+			expectedAdmin:    true,
+		},
+		{
+			name:           "Unauthenticated",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/api/auth/profile", nil)
+			require.NoError(t, err)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.expectedStatus == http.StatusOK {
+				var response struct {
+					User         database.User   `json:"user"`
+					Source       string          `json:"source"`
+					Permissions  map[string]bool `json:"permissions"`
+					FeatureFlags FeatureFlags    `json:"feature_flags"`
+				}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+
+				assert.Equal(t, tc.expectedSource, response.Source)
+				assert.Equal(t, tc.expectedUsername, response.User.Username)
+				assert.Equal(t, tc.expectedAdmin, response.Permissions["is_admin"])
+				// The admin dashboard flag only applies to admins
+				assert.Equal(t, tc.expectedAdmin, response.FeatureFlags.EnableAdminDashboard)
+				assert.NotContains(t, w.Body.String(), "password_hash")
+			}
+		})
+	}
+}
+
+func TestExportUserDataHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+
+	// player-1 has an argument and a vote in the mock
+	token, err := server.auth.GenerateToken(auth.User{ID: "player-1", Username: "player1", Email: "player1@example.com", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	t.Run("Exports the user's data as an attachment", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/api/auth/export", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+
+		var response struct {
+			User        database.User             `json:"user"`
+			Arguments   []database.Argument       `json:"arguments"`
+			Votes       []database.UserVote       `json:"votes"`
+			Feedback    []database.Feedback       `json:"feedback"`
+			Invitations []database.InvitationCode `json:"invitations"`
+		}
+		err = json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+
+		assert.Equal(t, "player-1", response.User.ID)
+		require.Len(t, response.Arguments, 1)
+		assert.Equal(t, "player-1", response.Arguments[0].PlayerID)
+		require.Len(t, response.Votes, 1)
+		assert.Equal(t, "upvote", response.Votes[0].VoteType)
+		assert.Len(t, response.Feedback, 2)
+		assert.Empty(t, response.Invitations)
+		assert.NotContains(t, w.Body.String(), "password_hash")
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/api/auth/export", nil)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestDeleteUserHandlers(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+
+	userToken, err := server.auth.GenerateToken(auth.User{ID: "test-user-id", Username: "testuser", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+	adminToken, err := server.auth.GenerateToken(auth.User{ID: "admin-id", Username: "admin", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		path           string
+		token          string
+		expectedStatus int
+		expectedMode   string
+	}{
+		{
+			name:           "User deletes own account",
+			path:           "/api/auth/me",
+			token:          userToken,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Admin anonymizes a user",
+			path:           "/api/auth/admin/users/test-user-id",
+			token:          adminToken,
+			expectedStatus: http.StatusOK,
+			expectedMode:   "anonymized",
+		},
+		{
+			name:           "Admin hard-deletes a user",
+			path:           "/api/auth/admin/users/test-user-id?hard=true",
+			token:          adminToken,
+			expectedStatus: http.StatusOK,
+			expectedMode:   "hard",
+		},
+		{
+			name:           "Admin deletes unknown user",
+			path:           "/api/auth/admin/users/missing-id",
+			token:          adminToken,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Non-admin uses admin delete",
+			path:           "/api/auth/admin/users/test-user-id?hard=true",
+			token:          userToken,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("DELETE", tc.path, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.expectedMode != "" {
+				var response map[string]interface{}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedMode, response["mode"])
+			}
+		})
+	}
+}
+
+// TestUpdateUserConflict tests that a profile edit based on a stale read gets a 409
+func TestUpdateUserConflict(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+
+	token, err := server.auth.GenerateToken(auth.User{ID: "test-user-id", Username: "testuser", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	update := func(body string) int {
+		req := httptest.NewRequest(http.MethodPut, "/api/auth/me", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// The mock user was never updated, so a client that saw a later version is out of date
+	assert.Equal(t, http.StatusConflict, update(`{"username": "renamed", "updated_at": "2024-01-01T00:00:00Z"}`))
+	assert.Equal(t, http.StatusOK, update(`{"username": "renamed"}`))
+}
@@ -67,8 +67,8 @@ func (m *MockDatabase) VerifyPassword(username, password string) (*database.User
 }
 
 // UpdateUser mocks the UpdateUser method
-func (m *MockDatabase) UpdateUser(user *database.User) error {
-	args := m.Called(user)
+func (m *MockDatabase) UpdateUser(user *database.User, expectedUpdatedAt time.Time) error {
+	args := m.Called(user, expectedUpdatedAt)
 	return args.Error(0)
 }
 
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/logging"
+)
+
+// bookmarkedDebate is a bookmarked debate with its live state and when it was bookmarked
+type bookmarkedDebate struct {
+	liveDebate
+	BookmarkedAt time.Time `json:"bookmarked_at"`
+}
+
+// addBookmarkHandler bookmarks a debate the current user can see, so they can follow it and are notified
+// when it ends
+func (s *Server) addBookmarkHandler(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	debateID := c.Param("debateID")
+	if status, err := s.checkDebateAccess(c, debateID); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.db.AddBookmark(userID, debateID); err != nil {
+		if errors.Is(err, database.ErrBookmarkExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Debate is already bookmarked"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to bookmark debate", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"debate_id":  debateID,
+		"bookmarked": true,
+	})
+}
+
+// removeBookmarkHandler removes the current user's bookmark of a debate
+func (s *Server) removeBookmarkHandler(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	debateID := c.Param("debateID")
+	if err := s.db.RemoveBookmark(userID, debateID); err != nil {
+		if errors.Is(err, database.ErrBookmarkNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Bookmark not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove bookmark", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"debate_id":  debateID,
+		"bookmarked": false,
+	})
+}
+
+// listBookmarksHandler lists the current user's bookmarked debates, most recently bookmarked first, with
+// pagination and each debate's live state. Bookmarks of debates that no longer exist are left out of the page.
+func (s *Server) listBookmarksHandler(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	paginationParams := GetPaginationParams(c)
+	bookmarks, total, err := s.db.ListBookmarks(userID, paginationParams.CalculateOffset(), paginationParams.PageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list bookmarks", "details": err.Error()})
+		return
+	}
+
+	debates := make([]*database.Debate, 0, len(bookmarks))
+	bookmarkedAt := make([]time.Time, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		debate, err := s.db.GetDebate(bookmark.DebateID)
+		if err != nil {
+			continue
+		}
+		debates = append(debates, debate)
+		bookmarkedAt = append(bookmarkedAt, bookmark.CreatedAt)
+	}
+
+	liveDebates, _ := s.withLiveState(debates)
+	items := make([]bookmarkedDebate, len(liveDebates))
+	for i, live := range liveDebates {
+		items[i] = bookmarkedDebate{liveDebate: live, BookmarkedAt: bookmarkedAt[i]}
+	}
+
+	paginationParams.Total = total
+	SendPaginatedResponse(c, paginationParams, items)
+}
+
+// debateBookmarkCount returns how many users have bookmarked a debate. Failures are logged and count as 0,
+// so they never keep the debate itself from loading.
+func (s *Server) debateBookmarkCount(debateID string) int {
+	count, err := s.db.CountBookmarks(debateID)
+	if err != nil {
+		logging.Error("Failed to count debate bookmarks", map[string]interface{}{
+			"debate_id": debateID,
+			"error":     err.Error(),
+		})
+		return 0
+	}
+	return count
+}
+
+// setupBookmarkRoutes sets up the routes for bookmarking debates and listing the current user's bookmarks
+func (s *Server) setupBookmarkRoutes() {
+	bookmarkGroup := s.router.Group("/api")
+	bookmarkGroup.Use(s.auth.AuthMiddleware())
+	{
+		bookmarkGroup.POST("/debates/:debateID/bookmark", s.addBookmarkHandler)
+		bookmarkGroup.DELETE("/debates/:debateID/bookmark", s.removeBookmarkHandler)
+		bookmarkGroup.GET("/users/me/bookmarks", s.listBookmarksHandler)
+	}
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bookmarkingDB keeps bookmarks in memory, in the order they were added
+type bookmarkingDB struct {
+	*TestMockDB
+	bookmarks []*database.Bookmark
+}
+
+func (m *bookmarkingDB) AddBookmark(userID, debateID string) error {
+	for _, bookmark := range m.bookmarks {
+		if bookmark.UserID == userID && bookmark.DebateID == debateID {
+			return database.ErrBookmarkExists
+		}
+	}
+	m.bookmarks = append(m.bookmarks, &database.Bookmark{UserID: userID, DebateID: debateID, CreatedAt: time.Now()})
+	return nil
+}
+
+func (m *bookmarkingDB) RemoveBookmark(userID, debateID string) error {
+	for i, bookmark := range m.bookmarks {
+		if bookmark.UserID == userID && bookmark.DebateID == debateID {
+			m.bookmarks = append(m.bookmarks[:i], m.bookmarks[i+1:]...)
+			return nil
+		}
+	}
+	return database.ErrBookmarkNotFound
+}
+
+func (m *bookmarkingDB) ListBookmarks(userID string, offset, limit int) ([]*database.Bookmark, int, error) {
+	var bookmarks []*database.Bookmark
+	for i := len(m.bookmarks) - 1; i >= 0; i-- {
+		if m.bookmarks[i].UserID == userID {
+			bookmarks = append(bookmarks, m.bookmarks[i])
+		}
+	}
+	total := len(bookmarks)
+	bookmarks = bookmarks[min(offset, total):]
+	return bookmarks[:min(limit, len(bookmarks))], total, nil
+}
+
+func (m *bookmarkingDB) CountBookmarks(debateID string) (int, error) {
+	count := 0
+	for _, bookmark := range m.bookmarks {
+		if bookmark.DebateID == debateID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func TestBookmarkHandlers(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	db := &bookmarkingDB{TestMockDB: &TestMockDB{}}
+	server.db = db
+	server.debateManager = &DebateManager{db: db, debates: map[string]*conversation.DebateSession{}, server: server}
+	server.router.GET("/api/debates/:debateID", server.auth.OptionalAuthMiddleware(), server.getDebateHandler)
+	server.setupBookmarkRoutes()
+
+	token, err := server.auth.GenerateToken(auth.User{ID: "user-1", Username: "user", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+	otherToken, err := server.auth.GenerateToken(auth.User{ID: "user-2", Username: "other", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	request := func(method, path, token string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	code, _ := request(http.MethodPost, "/api/debates/debate-1/bookmark", "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = request(http.MethodPost, "/api/debates/missing-debate/bookmark", token)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _ = request(http.MethodPost, "/api/debates/debate-1/bookmark", token)
+	assert.Equal(t, http.StatusCreated, code)
+	code, _ = request(http.MethodPost, "/api/debates/debate-1/bookmark", token)
+	assert.Equal(t, http.StatusConflict, code)
+	code, _ = request(http.MethodPost, "/api/debates/debate-2/bookmark", token)
+	assert.Equal(t, http.StatusCreated, code)
+	code, _ = request(http.MethodPost, "/api/debates/debate-1/bookmark", otherToken)
+	assert.Equal(t, http.StatusCreated, code)
+
+	// Popular debates show how many users follow them
+	code, response := request(http.MethodGet, "/api/debates/debate-1", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(2), response["bookmark_count"])
+
+	// The user's bookmarks are listed newest first, a page at a time
+	code, response = request(http.MethodGet, "/api/users/me/bookmarks?page_size=1", token)
+	require.Equal(t, http.StatusOK, code)
+	items := response["items"].([]interface{})
+	require.Len(t, items, 1)
+	assert.Equal(t, "debate-2", items[0].(map[string]interface{})["id"])
+	assert.Contains(t, items[0], "bookmarked_at")
+	assert.Contains(t, items[0], "client_count")
+	pagination := response["pagination"].(map[string]interface{})
+	assert.Equal(t, float64(2), pagination["total_items"])
+	assert.Equal(t, true, pagination["has_next"])
+
+	code, _ = request(http.MethodDelete, "/api/debates/debate-1/bookmark", token)
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = request(http.MethodDelete, "/api/debates/debate-1/bookmark", token)
+	assert.Equal(t, http.StatusNotFound, code)
+	code, response = request(http.MethodGet, "/api/debates/debate-1", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(1), response["bookmark_count"])
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+// ttlCacheEntry holds a cached value and when it expires
+type ttlCacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// ttlCache is a small keyed cache whose entries expire after a fixed duration.
+// A nil *ttlCache is valid and simply computes every value.
+type ttlCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]ttlCacheEntry
+}
+
+// newTTLCache creates a cache whose entries live for ttl
+func newTTLCache(ttl time.Duration) *ttlCache {
+	return &ttlCache{
+		ttl:     ttl,
+		entries: make(map[string]ttlCacheEntry),
+	}
+}
+
+// GetOrCompute returns the cached value for key, calling compute to refresh it when missing or expired
+func (c *ttlCache) GetOrCompute(key string, compute func() (interface{}, error)) (interface{}, error) {
+	if c == nil {
+		return compute()
+	}
+
+	c.mu.Lock()
+	entry, exists := c.entries[key]
+	c.mu.Unlock()
+	if exists && time.Now().Before(entry.expiresAt) {
+		return entry.value, nil
+	}
+
+	value, err := compute()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = ttlCacheEntry{value: value, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return value, nil
+}
+
+// Delete removes the cached entry for key, if any
+func (c *ttlCache) Delete(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+// Invalidate removes every cached entry
+func (c *ttlCache) Invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.entries = make(map[string]ttlCacheEntry)
+	c.mu.Unlock()
+}
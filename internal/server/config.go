@@ -1,14 +1,296 @@
 package server
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
+)
+
+// Defaults for request limits, used when the corresponding Config field is zero
+const (
+	DefaultMaxBodyBytes              int64 = 1 << 20  // 1 MiB for JSON API requests
+	DefaultMaxUploadBytes            int64 = 25 << 20 // 25 MiB for audio uploads, the Whisper API limit
+	DefaultWebSocketMaxMessageBytes  int64 = 16 << 10 // 16 KiB per WebSocket message
+	DefaultLLMTimeout                      = 60 * time.Second
+	DefaultAudioCacheTTL                   = time.Hour       // How long cached audio and HLS segments are kept
+	DefaultMaxConcurrentDebates            = 50              // Debates that may be waiting, active or paused at once
+	DefaultDuplicateSubmissionWindow       = 3 * time.Second // How long an identical argument from the same player is ignored
+)
+
+// Defaults for the TTS circuit breaker, used when the corresponding Config field is zero
+const (
+	DefaultTTSFailureThreshold = 3                // Consecutive TTS failures that open the breaker
+	DefaultTTSFailureWindow    = time.Minute      // Time within which those failures must happen
+	DefaultTTSBreakerCooldown  = 30 * time.Second // How long audio is skipped before the provider is probed
+)
+
+// Defaults for pausing debates while the LLM provider rejects the account, used when the Config field is zero
+const (
+	DefaultLLMOutageThreshold = 3           // Consecutive quota or auth errors that pause all debates
+	DefaultLLMProbeInterval   = time.Minute // How often the provider is checked for recovery while debates are paused
+)
+
 // Config holds server configuration
 type Config struct {
 	Port                     string
 	OpenAIKey                string
 	ElevenLabsKey            string
+	OpenAIBaseURL            string // OpenAI-compatible API base for a gateway or proxy, empty for types.DefaultOpenAIBaseURL
+	OpenAIOrganization       string // Sent as the OpenAI-Organization header when set
+	ElevenLabsBaseURL        string // ElevenLabs API base for a gateway or proxy, empty for types.DefaultElevenLabsBaseURL
 	ResponseDelay            int
-	JWTSecret                string // Secret key for JWT authentication
-	RequireEmailVerification bool   // Whether to require email verification
-	RequireInvitation        bool   // Whether to require invitation codes for registration
+	JWTSecret                string        // Secret key for JWT authentication
+	RequireEmailVerification bool          // Whether to require email verification
+	RequireInvitation        bool          // Whether to require invitation codes for registration
+	MaxBodyBytes             int64         // Maximum request body size, 0 for DefaultMaxBodyBytes
+	MaxUploadBytes           int64         // Maximum body size for audio uploads, 0 for DefaultMaxUploadBytes
+	WebSocketMaxMessageBytes int64         // Maximum size of an incoming WebSocket message, 0 for DefaultWebSocketMaxMessageBytes
+	LLMTimeout               time.Duration // Deadline for handlers that call an LLM, 0 for DefaultLLMTimeout
+	DefaultAgent1            string        // Agent name used for quick debates, empty to fall back to GetOrderedAgentNames
+	DefaultAgent2            string        // Opponent used for quick debates, set together with DefaultAgent1
+	UsageRates               usage.Rates   // Prices for estimating debate costs, zero fields use UsageModel's list prices
+	UsageModel               string        // LLM model whose list prices usage is estimated at, empty for agent.DefaultModel
+	AudioCacheTTL            time.Duration // How long generated audio stays fetchable, 0 for DefaultAudioCacheTTL
+	MaxConcurrentDebates     int           // Debates that may be waiting, active or paused at once, 0 for DefaultMaxConcurrentDebates
+	MaxHistoryInMemory       int           // History entries each debate keeps in memory, 0 for conversation.DefaultMaxHistoryInMemory
+	OneDebatePerAgent        bool          // Reject creating a debate with an agent already in one that hasn't finished
+	// How long an identical argument from the same player is ignored, 0 for DefaultDuplicateSubmissionWindow
+	DuplicateSubmissionWindow time.Duration
+	// Use stub agents and a deterministic scorer instead of the OpenAI and ElevenLabs APIs, for local development
+	OfflineMode bool
+	// Stretches the pause between agent turns off-peak and when nobody is watching, nil for a constant pace
+	TurnPacing *PacingSchedule
+	// When to stop generating audio while the TTS provider is failing, zero fields use the Default values
+	TTSFailureThreshold int
+	TTSFailureWindow    time.Duration
+	TTSBreakerCooldown  time.Duration
+	// When to pause debates because the LLM provider rejects the account, zero fields use the Default values
+	LLMOutageThreshold int
+	LLMProbeInterval   time.Duration
+	// Content types cached audio is served as, original or transcoded, empty for DefaultAllowedAudioTypes
+	AllowedAudioTypes []string
+	// IPs or CIDRs of the reverse proxies whose X-Forwarded-For header is believed for the client IP, empty to use
+	// the connection's address, so clients can't pick their own IP to get around per-IP rate limits
+	TrustedProxies []string
+}
+
+// ValidateUsageRates checks that the usage of the loaded agents can be priced: either the LLM prices are
+// configured, or the agents use the usage model and it has list prices. Otherwise costs, and the debate budgets
+// enforced with them, would be estimated at another model's prices.
+func (c *Config) ValidateUsageRates(agents map[string]*agent.Agent) error {
+	if c != nil && c.UsageRates.PromptPerMillionTokens > 0 && c.UsageRates.CompletionPerMillionTokens > 0 {
+		return nil
+	}
+	model := c.usageModel()
+	if _, exists := usage.DefaultRates(model); !exists {
+		return fmt.Errorf("there are no list prices for usage model %q, set the prompt and completion prices", model)
+	}
+
+	names := make([]string, 0, len(agents))
+	for name := range agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if agentModel := agents[name].GetModel(); agentModel != model {
+			return fmt.Errorf("agent %q uses %s but usage is priced as %s, set the usage model or the prompt and completion prices", name, agentModel, model)
+		}
+	}
+	return nil
+}
+
+// ValidateDefaultAgents checks that the configured quick-debate pairing names two different loaded agents
+func (c *Config) ValidateDefaultAgents(agents map[string]*agent.Agent) error {
+	if c == nil || (c.DefaultAgent1 == "" && c.DefaultAgent2 == "") {
+		return nil
+	}
+	if c.DefaultAgent1 == "" || c.DefaultAgent2 == "" {
+		return fmt.Errorf("DefaultAgent1 and DefaultAgent2 must be set together (got %q and %q)", c.DefaultAgent1, c.DefaultAgent2)
+	}
+	if c.DefaultAgent1 == c.DefaultAgent2 {
+		return fmt.Errorf("DefaultAgent1 and DefaultAgent2 must be different agents (both are %q)", c.DefaultAgent1)
+	}
+
+	for _, name := range []string{c.DefaultAgent1, c.DefaultAgent2} {
+		if _, exists := agents[name]; !exists {
+			available := make([]string, 0, len(agents))
+			for agentName := range agents {
+				available = append(available, agentName)
+			}
+			sort.Strings(available)
+			return fmt.Errorf("default agent %q is not loaded (available agents: %s)", name, strings.Join(available, ", "))
+		}
+	}
+	return nil
+}
+
+// GetMaxBodyBytes returns the configured request body limit or its default
+func (c *Config) GetMaxBodyBytes() int64 {
+	if c == nil || c.MaxBodyBytes <= 0 {
+		return DefaultMaxBodyBytes
+	}
+	return c.MaxBodyBytes
+}
+
+// GetMaxUploadBytes returns the configured upload body limit or its default
+func (c *Config) GetMaxUploadBytes() int64 {
+	if c == nil || c.MaxUploadBytes <= 0 {
+		return DefaultMaxUploadBytes
+	}
+	return c.MaxUploadBytes
+}
+
+// GetWebSocketMaxMessageBytes returns the configured WebSocket message limit or its default
+func (c *Config) GetWebSocketMaxMessageBytes() int64 {
+	if c == nil || c.WebSocketMaxMessageBytes <= 0 {
+		return DefaultWebSocketMaxMessageBytes
+	}
+	return c.WebSocketMaxMessageBytes
+}
+
+// GetLLMTimeout returns the configured LLM handler deadline or its default
+func (c *Config) GetLLMTimeout() time.Duration {
+	if c == nil || c.LLMTimeout <= 0 {
+		return DefaultLLMTimeout
+	}
+	return c.LLMTimeout
+}
+
+// GetAudioCacheTTL returns the configured audio cache TTL or its default
+func (c *Config) GetAudioCacheTTL() time.Duration {
+	if c == nil || c.AudioCacheTTL <= 0 {
+		return DefaultAudioCacheTTL
+	}
+	return c.AudioCacheTTL
+}
+
+// GetMaxConcurrentDebates returns the configured cap on concurrent debates or its default
+func (c *Config) GetMaxConcurrentDebates() int {
+	if c == nil || c.MaxConcurrentDebates <= 0 {
+		return DefaultMaxConcurrentDebates
+	}
+	return c.MaxConcurrentDebates
+}
+
+// GetMaxHistoryInMemory returns how many history entries each debate keeps in memory, or its default
+func (c *Config) GetMaxHistoryInMemory() int {
+	if c == nil || c.MaxHistoryInMemory <= 0 {
+		return conversation.DefaultMaxHistoryInMemory
+	}
+	return c.MaxHistoryInMemory
+}
+
+// GetDuplicateSubmissionWindow returns the configured window for ignoring repeated arguments or its default
+func (c *Config) GetDuplicateSubmissionWindow() time.Duration {
+	if c == nil || c.DuplicateSubmissionWindow <= 0 {
+		return DefaultDuplicateSubmissionWindow
+	}
+	return c.DuplicateSubmissionWindow
+}
+
+// GetTTSFailureThreshold returns how many consecutive TTS failures open the breaker, or its default
+func (c *Config) GetTTSFailureThreshold() int {
+	if c == nil || c.TTSFailureThreshold <= 0 {
+		return DefaultTTSFailureThreshold
+	}
+	return c.TTSFailureThreshold
+}
+
+// GetTTSFailureWindow returns the window the TTS failures must fall within, or its default
+func (c *Config) GetTTSFailureWindow() time.Duration {
+	if c == nil || c.TTSFailureWindow <= 0 {
+		return DefaultTTSFailureWindow
+	}
+	return c.TTSFailureWindow
+}
+
+// GetTTSBreakerCooldown returns how long audio is skipped once the TTS breaker opens, or its default
+func (c *Config) GetTTSBreakerCooldown() time.Duration {
+	if c == nil || c.TTSBreakerCooldown <= 0 {
+		return DefaultTTSBreakerCooldown
+	}
+	return c.TTSBreakerCooldown
+}
+
+// GetAllowedAudioTypes returns the content types audio may be served as, or their defaults
+func (c *Config) GetAllowedAudioTypes() []string {
+	if c == nil || len(c.AllowedAudioTypes) == 0 {
+		return DefaultAllowedAudioTypes
+	}
+	return c.AllowedAudioTypes
+}
+
+// GetLLMOutageThreshold returns how many consecutive quota or auth errors pause all debates, or its default
+func (c *Config) GetLLMOutageThreshold() int {
+	if c == nil || c.LLMOutageThreshold <= 0 {
+		return DefaultLLMOutageThreshold
+	}
+	return c.LLMOutageThreshold
+}
+
+// GetLLMProbeInterval returns how often the LLM provider is checked for recovery during an outage, or its default
+func (c *Config) GetLLMProbeInterval() time.Duration {
+	if c == nil || c.LLMProbeInterval <= 0 {
+		return DefaultLLMProbeInterval
+	}
+	return c.LLMProbeInterval
+}
+
+// GetTurnPacing returns the pacing schedule for debate turns, nil for a constant pace
+func (c *Config) GetTurnPacing() *PacingSchedule {
+	if c == nil {
+		return nil
+	}
+	return c.TurnPacing
+}
+
+// IsOffline reports whether the server runs with stub agents and scorer instead of the real APIs
+func (c *Config) IsOffline() bool {
+	return c != nil && c.OfflineMode
+}
+
+// GetAPIEndpoints returns where the OpenAI and ElevenLabs clients should send their requests
+func (c *Config) GetAPIEndpoints() types.APIEndpoints {
+	if c == nil {
+		return types.APIEndpoints{}
+	}
+	return types.APIEndpoints{
+		OpenAIBaseURL:      c.OpenAIBaseURL,
+		OpenAIOrganization: c.OpenAIOrganization,
+		ElevenLabsBaseURL:  c.ElevenLabsBaseURL,
+	}
+}
+
+// usageModel returns the LLM model whose list prices usage is estimated at
+func (c *Config) usageModel() string {
+	if c == nil || c.UsageModel == "" {
+		return agent.DefaultModel
+	}
+	return c.UsageModel
+}
+
+// GetUsageRates returns the configured usage prices, with unset prices taken from the usage model's list prices
+func (c *Config) GetUsageRates() usage.Rates {
+	rates, _ := usage.DefaultRates(c.usageModel())
+	if c == nil {
+		return rates
+	}
+	if c.UsageRates.PromptPerMillionTokens > 0 {
+		rates.PromptPerMillionTokens = c.UsageRates.PromptPerMillionTokens
+	}
+	if c.UsageRates.CompletionPerMillionTokens > 0 {
+		rates.CompletionPerMillionTokens = c.UsageRates.CompletionPerMillionTokens
+	}
+	if c.UsageRates.TTSPerThousandCharacters > 0 {
+		rates.TTSPerThousandCharacters = c.UsageRates.TTSPerThousandCharacters
+	}
+	return rates
 }
 
 type AgentConfig struct {
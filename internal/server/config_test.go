@@ -0,0 +1,78 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/usage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateUsageRates(t *testing.T) {
+	mini := agent.NewOfflineAgent(agent.AgentConfig{Name: "Mini"})
+	gpt4o := agent.NewOfflineAgent(agent.AgentConfig{Name: "4o", Model: "gpt-4o"})
+	custom := agent.NewOfflineAgent(agent.AgentConfig{Name: "Custom", Model: "my-finetune"})
+	prices := usage.Rates{PromptPerMillionTokens: 1, CompletionPerMillionTokens: 2}
+
+	testCases := []struct {
+		name        string
+		config      *Config
+		agents      []*agent.Agent
+		expectError bool
+	}{
+		{name: "Default model", config: &Config{}, agents: []*agent.Agent{mini}},
+		{name: "Agent on another model", config: &Config{}, agents: []*agent.Agent{mini, gpt4o}, expectError: true},
+		{name: "Usage model matches the agents", config: &Config{UsageModel: "gpt-4o"}, agents: []*agent.Agent{gpt4o}},
+		{name: "Usage model without list prices", config: &Config{UsageModel: "my-finetune"}, agents: []*agent.Agent{custom}, expectError: true},
+		{name: "Configured prices cover any model", config: &Config{UsageRates: prices}, agents: []*agent.Agent{mini, custom}},
+		{name: "Only the prompt price configured", config: &Config{UsageRates: usage.Rates{PromptPerMillionTokens: 1}}, agents: []*agent.Agent{custom}, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			agents := map[string]*agent.Agent{}
+			for _, a := range tc.agents {
+				agents[a.GetName()] = a
+			}
+			err := tc.config.ValidateUsageRates(agents)
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	// Unset prices come from the usage model's list prices
+	rates := (&Config{UsageModel: "gpt-4o", UsageRates: usage.Rates{TTSPerThousandCharacters: 0.2}}).GetUsageRates()
+	assert.Equal(t, usage.Rates{PromptPerMillionTokens: 2.50, CompletionPerMillionTokens: 10.00, TTSPerThousandCharacters: 0.2}, rates)
+}
+
+func TestValidateDefaultAgents(t *testing.T) {
+	agents := map[string]*agent.Agent{"Agent 1": {}, "Agent 2": {}}
+
+	testCases := []struct {
+		name        string
+		agent1      string
+		agent2      string
+		expectError bool
+	}{
+		{name: "Unset falls back to ordered agents", expectError: false},
+		{name: "Valid pairing", agent1: "Agent 1", agent2: "Agent 2", expectError: false},
+		{name: "Only one agent set", agent1: "Agent 1", expectError: true},
+		{name: "Same agent twice", agent1: "Agent 1", agent2: "Agent 1", expectError: true},
+		{name: "Unknown agent", agent1: "Agent 1", agent2: "Agent 3", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &Config{DefaultAgent1: tc.agent1, DefaultAgent2: tc.agent2}
+			err := config.ValidateDefaultAgents(agents)
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
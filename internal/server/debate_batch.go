@@ -0,0 +1,64 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBatchDebateIDs caps how many debates a single batch request can fetch
+const maxBatchDebateIDs = 50
+
+// getDebatesBatchHandler returns several debates at once, keyed by ID, each with the same real-time state as
+// getDebateHandler. IDs of debates that don't exist, or are private debates the caller may not see, are listed
+// under not_found instead of failing the request.
+func (s *Server) getDebatesBatchHandler(c *gin.Context) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range strings.Split(c.Query("ids"), ",") {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ids is required, as a comma-separated list of debate IDs"})
+		return
+	}
+	if len(ids) > maxBatchDebateIDs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d debate IDs can be fetched at once", maxBatchDebateIDs)})
+		return
+	}
+
+	debates := make(map[string]gin.H, len(ids))
+	notFound := []string{}
+	for _, id := range ids {
+		// Private debates are reported like missing ones, so the batch doesn't reveal which IDs exist
+		if _, err := s.checkDebateAccess(c, id); err != nil {
+			notFound = append(notFound, id)
+			continue
+		}
+		debate, err := s.db.GetDebate(id)
+		if err != nil {
+			notFound = append(notFound, id)
+			continue
+		}
+
+		entry := gin.H{"debate": debate}
+		if realTime := s.debateRealTime(c, debate); realTime != nil {
+			entry["real_time"] = realTime
+		}
+		debates[id] = entry
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"debates":   debates,
+		"not_found": notFound,
+		"count":     len(debates),
+	})
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDebatesBatch(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/debates/batch", server.auth.OptionalAuthMiddleware(), server.getDebatesBatchHandler)
+	server.debateManager = &DebateManager{debates: map[string]*conversation.DebateSession{
+		"debate-1": {
+			DebateID:  "debate-1",
+			GameScore: conversation.GameScore{Agent1Score: 90, Agent2Score: 70},
+		},
+	}}
+
+	get := func(ids string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/debates/batch?ids="+ids, nil))
+		return w
+	}
+
+	w := get("debate-1,debate-2,missing-debate,debate-1")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Debates map[string]struct {
+			Debate struct {
+				ID string `json:"id"`
+			} `json:"debate"`
+			RealTime map[string]interface{} `json:"real_time"`
+		} `json:"debates"`
+		NotFound []string `json:"not_found"`
+		Count    int      `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Count)
+	assert.Equal(t, []string{"missing-debate"}, response.NotFound)
+
+	// Loaded debates carry the same live state as GET /api/debates/:id
+	require.Contains(t, response.Debates, "debate-1")
+	assert.Equal(t, map[string]interface{}{"Agent 1": float64(90), "Agent 2": float64(70)}, response.Debates["debate-1"].RealTime["game_score"])
+	require.Contains(t, response.Debates, "debate-2")
+	assert.Nil(t, response.Debates["debate-2"].RealTime)
+
+	assert.Equal(t, http.StatusBadRequest, get("").Code)
+	tooMany := make([]string, maxBatchDebateIDs+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("debate-%d", i)
+	}
+	assert.Equal(t, http.StatusBadRequest, get(strings.Join(tooMany, ",")).Code)
+}
+
+func TestGetDebatesBatchPrivateDebates(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.db = &privateDebateDB{TestMockDB: &TestMockDB{}}
+	server.router.GET("/api/debates/batch", server.auth.OptionalAuthMiddleware(), server.getDebatesBatchHandler)
+	server.debateManager = &DebateManager{debates: map[string]*conversation.DebateSession{}}
+
+	invitedToken, err := server.auth.GenerateToken(auth.User{ID: "test-user-id", Username: "testuser", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+	strangerToken, err := server.auth.GenerateToken(auth.User{ID: "stranger-id", Username: "stranger", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	notFound := func(token string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/api/debates/batch?ids=debate-1,missing-debate", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			NotFound []string `json:"not_found"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.NotFound
+	}
+
+	assert.Equal(t, []string{"debate-1", "missing-debate"}, notFound(""))
+	assert.Equal(t, []string{"debate-1", "missing-debate"}, notFound(strangerToken))
+	assert.Equal(t, []string{"missing-debate"}, notFound(invitedToken))
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// debateCapacity describes how many debates are running against the server's cap
+func (s *Server) debateCapacity() gin.H {
+	current, max := s.debateManager.DebateCapacity()
+	available := max - current
+	if available < 0 {
+		available = 0
+	}
+	return gin.H{
+		"current":     current,
+		"max":         max,
+		"available":   available,
+		"at_capacity": available == 0,
+	}
+}
+
+// getDebateCapacityHandler reports how many debates are waiting, active or paused against the server's maximum,
+// so the UI can disable creating debates when it's full
+func (s *Server) getDebateCapacityHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, s.debateCapacity())
+}
+
+// respondDebateCreateFailed answers a failed debate creation, with 503 and the capacity when the server is full,
+// 503 while debates are paused for an LLM outage, or 409 when one of the agents is busy in another debate
+func (s *Server) respondDebateCreateFailed(c *gin.Context, err error) {
+	if errors.Is(err, ErrDebateCapacityReached) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error(), "capacity": s.debateCapacity()})
+		return
+	}
+	if errors.Is(err, ErrLLMUnavailable) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error(), "degraded": true})
+		return
+	}
+	var busy *AgentBusyError
+	if errors.As(err, &busy) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "agent": busy.Agent, "debate_id": busy.DebateID})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create debate: %v", err)})
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebateCapacity(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/debates/capacity", server.getDebateCapacityHandler)
+	server.router.POST("/api/debates/quick", server.auth.OptionalAuthMiddleware(), server.quickDebateHandler)
+
+	server.config.MaxConcurrentDebates = 3
+	server.config.DefaultAgent1 = "Agent 1"
+	server.config.DefaultAgent2 = "Agent 2"
+	server.agents = NewAgentRegistry(map[string]*agent.Agent{"Agent 1": {}, "Agent 2": {}})
+	server.debateManager = &DebateManager{
+		db:     server.db,
+		agents: server.agents,
+		debates: map[string]*conversation.DebateSession{
+			"active":    {Status: "active"},
+			"paused":    {Status: "paused"},
+			"finished":  {Status: "finished"},
+			"scheduled": {Status: "scheduled"},
+		},
+		server: server,
+	}
+
+	capacity := func() map[string]interface{} {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/debates/capacity", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	quickDebate := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/debates/quick", strings.NewReader(`{"topic": "Is a hot dog a sandwich?"}`))
+		req.Header.Set("Content-Type", "application/json")
+		server.router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Finished and scheduled debates don't count
+	response := capacity()
+	assert.Equal(t, float64(2), response["current"])
+	assert.Equal(t, float64(3), response["max"])
+	assert.Equal(t, false, response["at_capacity"])
+
+	require.Equal(t, http.StatusCreated, quickDebate().Code)
+	assert.Equal(t, true, capacity()["at_capacity"])
+
+	w := quickDebate()
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), `"capacity"`)
+
+	// Scheduled debates can still be created, since they only count once they open
+	startsAt := time.Now().Add(time.Hour)
+	agent1, _ := server.agents.Get("Agent 1")
+	agent2, _ := server.agents.Get("Agent 2")
+	_, err := server.debateManager.CreateDebateWithConfig(conversation.DefaultConfig(), agent1, agent2, "",
+		database.DebateSettings{ScheduledAt: &startsAt})
+	require.NoError(t, err)
+
+	// A finished debate frees its slot
+	session, _ := server.debateManager.GetDebate("active")
+	session.UpdateStatus("finished")
+	assert.Equal(t, http.StatusCreated, quickDebate().Code)
+}
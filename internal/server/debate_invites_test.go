@@ -0,0 +1,90 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInviteToDebateHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.POST("/api/debates/:debateID/invite", server.auth.AuthMiddleware(), server.inviteToDebateHandler)
+
+	// The mock debate is created by creator-id
+	creatorToken, err := server.auth.GenerateToken(auth.User{ID: "creator-id", Username: "creator", Role: "user"})
+	require.NoError(t, err)
+	otherToken, err := server.auth.GenerateToken(auth.User{ID: "other-id", Username: "other", Role: "user"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		body           map[string]interface{}
+		token          string
+		expectedStatus int
+	}{
+		{
+			name:           "Creator invites existing user",
+			body:           map[string]interface{}{"user_id": "test-user-id"},
+			token:          creatorToken,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Non-creator invites user",
+			body:           map[string]interface{}{"user_id": "test-user-id"},
+			token:          otherToken,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Unknown invitee",
+			body:           map[string]interface{}{"username": "nobody"},
+			token:          creatorToken,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Missing invitee",
+			body:           map[string]interface{}{},
+			token:          creatorToken,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unauthenticated",
+			body:           map[string]interface{}{"user_id": "test-user-id"},
+			token:          "",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			req, err := http.NewRequest("POST", "/api/debates/debate-1/invite", bytes.NewBuffer(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, "test-user-id", response["user_id"])
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -10,26 +12,53 @@ import (
 	"github.com/gin-gonic/gin" // Add missing gin import
 	"github.com/google/uuid"
 	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/audio"
 	"github.com/neo/convinceme_backend/internal/conversation"
 	"github.com/neo/convinceme_backend/internal/database"
 	"github.com/neo/convinceme_backend/internal/logging"
 	"github.com/neo/convinceme_backend/internal/scoring"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
 )
 
 // DebateManager handles the creation, tracking, and cleanup of debate sessions
 type DebateManager struct {
 	db           database.DatabaseInterface
-	agents       map[string]*agent.Agent
+	agents       *AgentRegistry
 	debates      map[string]*conversation.DebateSession
 	debatesMutex sync.RWMutex
 	apiKey       string
 	scorer       *scoring.Scorer
-	server       *Server // Reference to the server for audio caching
+	server       *Server     // Reference to the server for audio caching
+	ttsBreaker   *ttsBreaker // Skips audio generation while the TTS provider keeps failing
+	llmOutage    *llmOutage  // Pauses debates while the LLM provider rejects the account
+	// Checks whether the LLM provider answers again during an outage, nil without a scorer
+	probeLLM func(ctx context.Context) error
+	// Debates being created, counted toward the concurrency cap until they are stored; guarded by debatesMutex
+	pendingCreates int
+	// Agents of debates being created, claimed under Config.OneDebatePerAgent; guarded by debatesMutex
+	pendingAgents map[string]int
+}
+
+// ErrDebateCapacityReached is returned when a debate can't be created because MaxConcurrentDebates are running
+var ErrDebateCapacityReached = errors.New("the maximum number of concurrent debates are running, try again later")
+
+// countsTowardCapacity reports whether a debate with the given status counts toward MaxConcurrentDebates
+func countsTowardCapacity(status types.DebateStatus) bool {
+	switch status {
+	case types.DebateStatusWaiting, types.DebateStatusActive, types.DebateStatusPaused:
+		return true
+	}
+	return false
 }
 
 // NewDebateManager creates a new debate manager
-func NewDebateManager(db database.DatabaseInterface, agents map[string]*agent.Agent, apiKey string, server *Server) *DebateManager {
-	scorer, err := scoring.NewScorer(apiKey)
+func NewDebateManager(db database.DatabaseInterface, agents *AgentRegistry, apiKey string, server *Server) *DebateManager {
+	var config *Config
+	if server != nil {
+		config = server.config
+	}
+	scorer, err := newScorer(apiKey, config)
 	if err != nil {
 		log.Printf("Warning: Failed to initialize scorer in DebateManager: %v", err)
 	}
@@ -41,6 +70,12 @@ func NewDebateManager(db database.DatabaseInterface, agents map[string]*agent.Ag
 		apiKey:  apiKey,
 		scorer:  scorer,
 		server:  server,
+		ttsBreaker: newTTSBreaker(config.GetTTSFailureThreshold(), config.GetTTSFailureWindow(),
+			config.GetTTSBreakerCooldown()),
+		llmOutage: newLLMOutage(config.GetLLMOutageThreshold()),
+	}
+	if scorer != nil {
+		manager.probeLLM = scorer.Ping
 	}
 
 	// Load active debates from database into memory
@@ -54,6 +89,49 @@ func NewDebateManager(db database.DatabaseInterface, agents map[string]*agent.Ag
 
 // CreateDebate creates a new debate with the given topic and agents
 func (m *DebateManager) CreateDebate(topic string, agent1, agent2 *agent.Agent, createdBy string) (string, error) {
+	// Create debate config
+	config := conversation.DefaultConfig()
+	config.Topic = topic
+
+	return m.CreateDebateWithConfig(config, agent1, agent2, createdBy, database.DebateSettings{})
+}
+
+// CreateDebateWithConfig creates a new debate session from a custom config and stores its settings
+func (m *DebateManager) CreateDebateWithConfig(config conversation.DebateConfig, agent1, agent2 *agent.Agent, createdBy string, settings database.DebateSettings) (string, error) {
+	topic := config.Topic
+
+	// Debates can't be played while the LLM provider rejects the account
+	if m.llmOutage.isDegraded() {
+		return "", ErrLLMUnavailable
+	}
+
+	// Scheduled debates only count toward the cap once they open
+	reserved := false
+	if settings.ScheduledAt == nil {
+		if err := m.reserveDebateSlot(); err != nil {
+			return "", err
+		}
+		reserved = true
+		defer func() {
+			if reserved {
+				m.releaseDebateSlot()
+			}
+		}()
+	}
+
+	// Under the one-debate-per-agent policy, claim the agents until the debate is stored
+	if err := m.reserveAgents(agent1.GetName(), agent2.GetName()); err != nil {
+		return "", err
+	}
+	claimedAgents := m.oneDebatePerAgent()
+	defer func() {
+		if claimedAgents {
+			m.debatesMutex.Lock()
+			m.releaseAgentsLocked(agent1.GetName(), agent2.GetName())
+			m.debatesMutex.Unlock()
+		}
+	}()
+
 	// Generate a unique ID for the debate
 	debateID := uuid.New().String()
 
@@ -62,13 +140,13 @@ func (m *DebateManager) CreateDebate(topic string, agent1, agent2 *agent.Agent,
 		"agent1":     agent1.GetName(),
 		"agent2":     agent2.GetName(),
 		"created_by": createdBy,
+		"visibility": settings.Visibility,
 	})
 
-	// Create debate config
-	config := conversation.DefaultConfig()
-	config.Topic = topic
-
 	// Create a new debate session
+	if config.MaxHistoryInMemory <= 0 {
+		config.MaxHistoryInMemory = m.maxHistoryInMemory()
+	}
 	session, err := conversation.NewDebateSession(debateID, agent1, agent2, config, m.apiKey)
 	if err != nil {
 		logging.LogDebateEvent("debate_session_creation_failed", debateID, map[string]interface{}{
@@ -78,8 +156,31 @@ func (m *DebateManager) CreateDebate(topic string, agent1, agent2 *agent.Agent,
 		return "", fmt.Errorf("failed to create debate session: %v", err)
 	}
 
+	// Record who opens when it was chosen, including the result of a coin flip
+	if config.FirstSpeaker != "" {
+		settings.FirstSpeaker = session.FirstSpeaker()
+		settings.FirstSpeakerCoinFlip = config.FirstSpeaker == conversation.FirstSpeakerRandom
+	}
+	// Record the seed, picked by the session if the config didn't set one, so the debate can be reproduced
+	settings.Seed = session.Config.Seed
+	// Record the whole config, so the debate is restored with its settings after a restart
+	if data, err := json.Marshal(session.Config); err != nil {
+		logging.LogDebateEvent("debate_config_encode_failed", debateID, map[string]interface{}{
+			"error": err,
+		})
+	} else {
+		settings.Config = string(data)
+	}
+
+	// Scheduled debates can't be joined until the scheduler opens them
+	status := types.DebateStatusWaiting
+	if settings.ScheduledAt != nil {
+		status = types.DebateStatusScheduled
+		session.UpdateStatus(status)
+	}
+
 	// Store debate in database
-	err = m.db.CreateDebate(debateID, topic, "waiting", agent1.GetName(), agent2.GetName())
+	err = m.db.CreateDebate(debateID, topic, status, agent1.GetName(), agent2.GetName())
 	if err != nil {
 		logging.LogDebateEvent("debate_db_creation_failed", debateID, map[string]interface{}{
 			"error": err,
@@ -88,16 +189,34 @@ func (m *DebateManager) CreateDebate(topic string, agent1, agent2 *agent.Agent,
 		return "", fmt.Errorf("failed to store debate in database: %v", err)
 	}
 
-	// Store session in memory
+	// Store optional settings (visibility, creator, seed) when they differ from the defaults
+	if settings != (database.DebateSettings{}) {
+		if err := m.db.SaveDebateSettings(debateID, settings); err != nil {
+			logging.LogDebateEvent("debate_settings_save_failed", debateID, map[string]interface{}{
+				"error": err,
+			})
+			return "", fmt.Errorf("failed to store debate settings: %v", err)
+		}
+	}
+
+	// Store session in memory, handing its reserved slot over to it
 	m.debatesMutex.Lock()
 	m.debates[debateID] = session
+	if reserved {
+		m.pendingCreates--
+		reserved = false
+	}
+	if claimedAgents {
+		m.releaseAgentsLocked(agent1.GetName(), agent2.GetName())
+		claimedAgents = false
+	}
 	m.debatesMutex.Unlock()
 
 	logging.LogDebateEvent("debate_created_successfully", debateID, map[string]interface{}{
 		"topic":  topic,
 		"agent1": agent1.GetName(),
 		"agent2": agent2.GetName(),
-		"status": "waiting",
+		"status": status,
 	})
 
 	return debateID, nil
@@ -112,67 +231,136 @@ func (m *DebateManager) GetDebate(debateID string) (*conversation.DebateSession,
 	return session, exists
 }
 
-// StartDebateLoop starts the debate loop for a session
-func (m *DebateManager) StartDebateLoop(session *conversation.DebateSession) {
+// maxDebateLoopCrashes is how many times a debate's loop may panic before the debate is ended instead of resumed
+const maxDebateLoopCrashes = 3
+
+// StartDebateLoop starts the debate loop for a session. It returns false without starting another loop if one
+// is already running. A loop that panics leaves the debate active so ReconcileDebateLoops resumes it, until it
+// has crashed maxDebateLoopCrashes times and the debate is ended.
+func (m *DebateManager) StartDebateLoop(session *conversation.DebateSession) bool {
+	if !session.TryStartLoop() {
+		log.Printf("Debate loop for %s is already running", session.DebateID)
+		return false
+	}
+
 	// Start the debate loop in a goroutine
 	go func() {
+		defer session.LoopStopped()
+
 		// Add panic recovery to prevent the debate loop from crashing silently
 		defer func() {
 			if r := recover(); r != nil {
+				crashes := session.RecordLoopCrash()
 				logging.Error("Panic in debate loop", map[string]interface{}{
 					"debate_id": session.DebateID,
 					"panic":     r,
+					"crashes":   crashes,
 				})
-				// Set debate status to finished if it panicked
-				session.UpdateStatus("finished")
+				if crashes >= maxDebateLoopCrashes {
+					if recordDebateEnd(m.server, session, session.DebateID, "", database.DebateEndReasonError) {
+						session.Broadcast(gin.H{
+							"type":       "error",
+							"end_reason": database.DebateEndReasonError,
+							"message":    "Internal error occurred in debate. Debate has ended.",
+						})
+					}
+					return
+				}
 				session.Broadcast(gin.H{
 					"type":    "error",
-					"message": "Internal error occurred in debate. Debate has ended.",
+					"message": "Internal error occurred in debate. It will resume shortly.",
 				})
 			}
 		}()
 
-		ctx := context.Background()
+		// Bind the debate ID once so every entry logged by this loop carries it
 		debateID := session.DebateID
+		ctx := logging.WithFields(context.Background(), map[string]interface{}{"debate_id": debateID})
+		// LLM and TTS calls made by the loop are attributed to this debate, and the agents, which other
+		// debates share, remember its turns apart from theirs
+		ctx = usage.WithRecorder(ctx, session)
+		ctx = agent.WithMemoryScope(ctx, debateID)
+
+		// Ending the debate from outside the loop, e.g. by a moderator, cancels the turn in flight
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			select {
+			case <-session.GetStopChannel():
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
 
-		logging.Info("Starting debate loop", map[string]interface{}{
-			"debate_id": debateID,
-		})
+		// A restarted loop keeps the deadline set when the debate first started
+		resumed := !session.GetDeadline().IsZero()
 
-		// Generate initial message
-		initialMessage := fmt.Sprintf("Welcome to the debate on: %s", session.Config.Topic)
-		session.Broadcast(gin.H{
-			"type":    "system",
-			"message": initialMessage,
+		logging.InfoCtx(ctx, "Starting debate loop", map[string]interface{}{
+			"max_turns": session.Config.MaxTurns,
+			"resumed":   resumed,
 		})
 
-		// Add a slight delay before first agent speaks
-		time.Sleep(2 * time.Second)
+		if resumed {
+			session.Broadcast(gin.H{
+				"type":    "system",
+				"message": "The debate has resumed.",
+			})
+		} else {
+			// Generate initial message, announcing the opening agent when a coin flip picked it
+			coinFlip := session.Config.FirstSpeaker == conversation.FirstSpeakerRandom
+			firstSpeaker := session.FirstSpeakerAgent().GetName()
+			session.Broadcast(gin.H{
+				"type":          "system",
+				"message":       introMessage(session.Config, firstSpeaker, coinFlip),
+				"first_speaker": firstSpeaker,
+				"coin_flip":     coinFlip,
+			})
+		}
 
-		// Set debate timeout to 15 minutes
-		debateTimeout := time.NewTimer(15 * time.Minute)
+		// Let the intro land before the first agent speaks
+		time.Sleep(session.Config.IntroDelay)
+		if !resumed {
+			m.deliverOpeningStatements(ctx, session)
+		}
+
+		// Set the overall debate timeout from the debate's config
+		maxDuration := session.Config.MaxDuration
+		deadline := session.GetDeadline()
+		if deadline.IsZero() {
+			deadline = time.Now().Add(maxDuration)
+			session.SetDeadline(deadline)
+			// A debate restored after a restart gets only the time it had left
+			if err := m.db.SaveDebateDeadline(debateID, deadline); err != nil {
+				logging.ErrorCtx(ctx, "Failed to save debate deadline", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+		}
+		debateTimeout := time.NewTimer(time.Until(deadline))
 		defer debateTimeout.Stop()
 
 		// Add heartbeat to monitor debate progress
 		lastActivityTime := time.Now()
-		maxInactivityDuration := 5 * time.Minute // If no progress for 5 minutes, something is wrong
+		maxInactivityDuration := session.Config.MaxInactivity // If no progress for this long, something is wrong
 
 		// Main debate loop - continue until winner or timeout
 		agentTurnCount := 0 // Add counter to track agent turns
+		budgetWarned := false
 
 		for {
 			// Check for timeout
 			select {
 			case <-debateTimeout.C:
-				logging.Info("Debate timed out", map[string]interface{}{
-					"debate_id":        debateID,
-					"timeout_duration": "15m",
-				})
-				session.UpdateStatus("finished")
-				session.Broadcast(gin.H{
-					"type":    "timeout",
-					"message": "Debate timed out after 15 minutes. No winner determined.",
+				logging.InfoCtx(ctx, "Debate timed out", map[string]interface{}{
+					"timeout_duration": maxDuration.String(),
 				})
+				if recordDebateEnd(m.server, session, debateID, "", database.DebateEndReasonTimeout) {
+					session.Broadcast(gin.H{
+						"type":       "timeout",
+						"end_reason": database.DebateEndReasonTimeout,
+						"message":    fmt.Sprintf("Debate timed out after %s. No winner determined.", maxDuration),
+					})
+				}
 				return
 			default:
 				// Continue with debate logic
@@ -180,80 +368,71 @@ func (m *DebateManager) StartDebateLoop(session *conversation.DebateSession) {
 
 			// Check for inactivity (debate stuck)
 			if time.Since(lastActivityTime) > maxInactivityDuration {
-				logging.Error("Debate appears stuck - no progress for too long", map[string]interface{}{
-					"debate_id":           debateID,
+				logging.ErrorCtx(ctx, "Debate appears stuck - no progress for too long", map[string]interface{}{
 					"last_activity":       lastActivityTime,
 					"inactivity_duration": time.Since(lastActivityTime),
 					"max_allowed":         maxInactivityDuration,
 				})
-				session.UpdateStatus("finished")
-				session.Broadcast(gin.H{
-					"type":    "error",
-					"message": "Debate ended due to inactivity. No progress detected for 5 minutes.",
-				})
+				if recordDebateEnd(m.server, session, debateID, "", database.DebateEndReasonInactivity) {
+					session.Broadcast(gin.H{
+						"type":       "error",
+						"end_reason": database.DebateEndReasonInactivity,
+						"message":    fmt.Sprintf("Debate ended due to inactivity. No progress detected for %s.", maxInactivityDuration),
+					})
+				}
 				return
 			}
 
 			// Check if debate should continue
 			status := session.GetStatus()
-			if status != "active" {
-				logging.Info("Debate no longer active, ending loop", map[string]interface{}{
-					"debate_id": debateID,
-					"status":    status,
+			if status != types.DebateStatusActive {
+				logging.InfoCtx(ctx, "Debate no longer active, ending loop", map[string]interface{}{
+					"status": status,
 				})
 				break
 			}
 
 			// Increment agent turn counter
-			agentTurnCount++
-			logging.Info("Starting agent turn", map[string]interface{}{
-				"debate_id": debateID,
-				"turn":      agentTurnCount,
+			agentTurnCount = session.IncrementTurnCount()
+			logging.InfoCtx(ctx, "Starting agent turn", map[string]interface{}{
+				"turn": agentTurnCount,
 			})
 
 			// Add a small delay to allow for player interruptions
 			time.Sleep(1 * time.Second)
 
-			// Get next agent to speak
-			agent := session.GetNextAgent()
+			// Get next agent to speak, by the debate's turn order
+			agent := conversation.NextSpeaker(session)
 			agentName := agent.GetName()
-			logging.Info("Agent will speak", map[string]interface{}{
-				"debate_id":  debateID,
+			logging.InfoCtx(ctx, "Agent will speak", map[string]interface{}{
 				"agent_name": agentName,
 				"turn":       agentTurnCount,
 			})
 
-			// Get context from recent history
-			recentHistory := session.GetRecentHistory(5)
-			var contextStr string
-			for _, entry := range recentHistory {
-				contextStr += fmt.Sprintf("%s: %s\n", entry.Speaker, entry.Message)
-			}
-			logging.Debug("Context for agent", map[string]interface{}{
-				"debate_id": debateID,
-				"turn":      agentTurnCount,
-				"context":   contextStr,
+			// Generate response, with context from recent history and a summary of what came before it
+			refreshHistorySummary(ctx, session, agent, agentTurnCount)
+			prompt := agentTurnPrompt(session, agentName, session.ContextHistory(session.Config.HistoryWindow()))
+			logging.DebugCtx(ctx, "Prompt for agent", map[string]interface{}{
+				"turn":   agentTurnCount,
+				"prompt": prompt,
 			})
-
-			// Generate response
-			prompt := getPrompt(contextStr, "", agentName, "Debate Participant", session.Config.Topic)
-			logging.Info("Calling agent.GenerateResponse", map[string]interface{}{
-				"debate_id":  debateID,
+			logging.InfoCtx(ctx, "Calling agent.GenerateResponse", map[string]interface{}{
 				"agent_name": agentName,
 				"turn":       agentTurnCount,
 			})
-			response, err := agent.GenerateResponse(ctx, session.Config.Topic, prompt)
+			turnStart := time.Now()
+			response, err := agent.GenerateResponse(ctx, session.Config.Topic, prompt, generationFallback(session.Config))
+			textDuration := time.Since(turnStart)
+			m.recordLLMResult(err)
 			if err != nil {
-				logging.Error("Error generating response", map[string]interface{}{
-					"debate_id":  debateID,
+				logging.ErrorCtx(ctx, "Error generating response", map[string]interface{}{
 					"agent_name": agentName,
 					"turn":       agentTurnCount,
 					"error":      err.Error(),
 				})
 				continue
 			}
-			logging.Info("Successfully generated response", map[string]interface{}{
-				"debate_id":  debateID,
+			logging.InfoCtx(ctx, "Successfully generated response", map[string]interface{}{
 				"agent_name": agentName,
 				"turn":       agentTurnCount,
 				"response":   response,
@@ -264,48 +443,29 @@ func (m *DebateManager) StartDebateLoop(session *conversation.DebateSession) {
 
 			// Add to history - scoring will be done later
 			session.AddHistoryEntry(agentName, response, false)
-			logging.Info("Added response to history", map[string]interface{}{
-				"debate_id":  debateID,
+			logging.InfoCtx(ctx, "Added response to history", map[string]interface{}{
 				"agent_name": agentName,
 				"turn":       agentTurnCount,
 			})
 
-			// Generate audio for the response
-			logging.Info("Generating audio", map[string]interface{}{
-				"debate_id":  debateID,
+			// Generate audio for the response in the background while the argument is scored
+			logging.InfoCtx(ctx, "Generating audio", map[string]interface{}{
 				"agent_name": agentName,
 				"turn":       agentTurnCount,
 			})
-			audioData, err := agent.GenerateAndStreamAudio(ctx, response)
-			var audioURL string
-			if err != nil {
-				logging.Error("Error generating audio", map[string]interface{}{
-					"debate_id":  debateID,
-					"agent_name": agentName,
-					"turn":       agentTurnCount,
-					"error":      err.Error(),
-				})
-			} else {
-				// Store audio in cache and get URL
-				audioURL = m.server.CacheAudio(audioData)
-				logging.Info("Generated audio", map[string]interface{}{
-					"debate_id":  debateID,
-					"agent_name": agentName,
-					"turn":       agentTurnCount,
-					"audio_url":  audioURL,
-				})
-			}
+			voiceSettings := voiceSettingsForHP(agent.VoiceSettings(), agentHP(session, agentName)).Resolve()
+			audioResult := m.generateTurnAudio(ctx, session, agent, response, voiceSettings, agentTurnCount)
 
 			// Score the argument
-			logging.Info("Scoring argument", map[string]interface{}{
-				"debate_id":  debateID,
+			scoringStart := time.Now()
+			logging.InfoCtx(ctx, "Scoring argument", map[string]interface{}{
 				"agent_name": agentName,
 				"turn":       agentTurnCount,
 			})
-			score, err := m.scorer.ScoreArgument(ctx, response, session.Config.Topic)
+			score, err := m.scorer.ScoreArgumentInStyle(ctx, response, session.Config.Topic, session.Config.Language, session.Config.ResponseStyle)
+			m.recordLLMResult(err)
 			if err != nil {
-				logging.Error("Error scoring response", map[string]interface{}{
-					"debate_id":  debateID,
+				logging.ErrorCtx(ctx, "Error scoring response", map[string]interface{}{
 					"agent_name": agentName,
 					"turn":       agentTurnCount,
 					"error":      err.Error(),
@@ -321,71 +481,80 @@ func (m *DebateManager) StartDebateLoop(session *conversation.DebateSession) {
 					Explanation: "Failed to calculate score",
 				}
 			} else {
-				logging.Info("Successfully scored argument", map[string]interface{}{
-					"debate_id":  debateID,
+				logging.InfoCtx(ctx, "Successfully scored argument", map[string]interface{}{
 					"agent_name": agentName,
 					"turn":       agentTurnCount,
 					"score":      score.Average,
 				})
 			}
 
+			scoringDuration := time.Since(scoringStart)
+
+			// A debate ended while the turn was in flight doesn't apply it
+			if ctx.Err() != nil {
+				logging.InfoCtx(ctx, "Debate ended during turn, discarding it", map[string]interface{}{
+					"agent_name": agentName,
+					"turn":       agentTurnCount,
+				})
+				break
+			}
+
 			// Update the history entry with the score
 			session.UpdateLastHistoryEntryScore(score.Average)
 
 			// Update game score based on direct scoring
 			// Each agent's score adds to their side and subtracts from opponent
 			currentAgentScore := score.Average
-			scorePoints := int(currentAgentScore) // Convert 0-10 score to integer points
+			opponentScore := session.GetLastAgentScore(opponentName(session, agentName))
+			scorePoints, noDamageReason := session.Config.HPTuning.AgentPoints(currentAgentScore, opponentScore) // Convert 0-100 score to integer points
+			if noDamageReason == conversation.NoDamageBelowMinScore || noDamageReason == conversation.NoDamageDeadband {
+				logging.InfoCtx(ctx, "Agent turn dealt no damage", map[string]interface{}{
+					"current_agent":  agentName,
+					"score":          currentAgentScore,
+					"opponent_score": opponentScore,
+					"reason":         noDamageReason,
+				})
+			}
 
-			logging.Info("Applying direct scoring based on agent performance", map[string]interface{}{
-				"debate_id":     debateID,
+			logging.InfoCtx(ctx, "Applying direct scoring based on agent performance", map[string]interface{}{
 				"current_agent": agentName,
 				"score":         currentAgentScore,
 				"score_points":  scorePoints,
 			})
 
 			// Apply score: agent gets +points, opponent gets -points
-			var agent1Delta, agent2Delta int
-			if agentName == session.Agent1.GetName() {
-				agent1Delta = scorePoints  // Agent1 gets positive points
-				agent2Delta = -scorePoints // Agent2 loses same amount of points
-			} else {
-				agent1Delta = -scorePoints // Agent1 loses points
-				agent2Delta = scorePoints  // Agent2 gets positive points
-			}
+			agent1Delta, agent2Delta := agentTurnDeltas(session, agentName, scorePoints)
 
 			gameScore := session.UpdateGameScore(agent1Delta, agent2Delta)
-			logging.Info("Updated game score with direct scoring", map[string]interface{}{
-				"debate_id":     debateID,
+			logging.InfoCtx(ctx, "Updated game score with direct scoring", map[string]interface{}{
 				"turn":          agentTurnCount,
 				"current_agent": agentName,
 				"agent_score":   currentAgentScore,
-				"score_points":  int(currentAgentScore),
+				"score_points":  scorePoints,
 				"agent1_score":  gameScore.Agent1Score,
 				"agent2_score":  gameScore.Agent2Score,
 				"agent1_delta":  agent1Delta,
 				"agent2_delta":  agent2Delta,
 			})
 
-			// Check for game over condition
+			// Check for game over condition. Judged debates keep HP for display only.
 			var gameOver bool
 			var winner string
+			judged := session.Config.IsJudged()
 
-			if gameScore.Agent1Score <= 0 {
+			if !judged && gameScore.Agent1Score <= 0 {
 				gameOver = true
 				winner = session.Agent2.GetName()
-				logging.Info("Game over - Agent1 health depleted", map[string]interface{}{
-					"debate_id":    debateID,
+				logging.InfoCtx(ctx, "Game over - Agent1 health depleted", map[string]interface{}{
 					"winner":       winner,
 					"agent1_score": gameScore.Agent1Score,
 					"agent2_score": gameScore.Agent2Score,
 					"turn":         agentTurnCount,
 				})
-			} else if gameScore.Agent2Score <= 0 {
+			} else if !judged && gameScore.Agent2Score <= 0 {
 				gameOver = true
 				winner = session.Agent1.GetName()
-				logging.Info("Game over - Agent2 health depleted", map[string]interface{}{
-					"debate_id":    debateID,
+				logging.InfoCtx(ctx, "Game over - Agent2 health depleted", map[string]interface{}{
 					"winner":       winner,
 					"agent1_score": gameScore.Agent1Score,
 					"agent2_score": gameScore.Agent2Score,
@@ -393,6 +562,32 @@ func (m *DebateManager) StartDebateLoop(session *conversation.DebateSession) {
 				})
 			}
 
+			// Wait for the audio; a failure leaves audioURL empty and the turn is broadcast as text only
+			audioWaitStart := time.Now()
+			generatedAudio := <-audioResult
+			audioURL := generatedAudio.url
+			logging.InfoCtx(ctx, "Turn latency breakdown", map[string]interface{}{
+				"agent_name":    agentName,
+				"turn":          agentTurnCount,
+				"text_ms":       textDuration.Milliseconds(),
+				"audio_ms":      generatedAudio.duration.Milliseconds(),
+				"scoring_ms":    scoringDuration.Milliseconds(),
+				"audio_wait_ms": time.Since(audioWaitStart).Milliseconds(),
+				"total_ms":      time.Since(turnStart).Milliseconds(),
+			})
+
+			// Persist the turn for replays
+			turnScore := score.Average
+			messageID := m.server.recordDebateMessage(&database.DebateMessage{
+				DebateID:    debateID,
+				Speaker:     agentName,
+				Message:     response,
+				Score:       &turnScore,
+				AudioURL:    audioURL,
+				Agent1Delta: agent1Delta,
+				Agent2Delta: agent2Delta,
+			})
+
 			// Broadcast response with score
 			message := gin.H{
 				"type":    "message",
@@ -407,8 +602,14 @@ func (m *DebateManager) StartDebateLoop(session *conversation.DebateSession) {
 			if audioURL != "" {
 				message["audioUrl"] = audioURL
 			}
+			// Players reply to the turn by its transcript message ID
+			if messageID != 0 {
+				message["message_id"] = messageID
+			}
 
-			session.Broadcast(message)
+			seq := session.Broadcast(message)
+			// Keep what the turn did, so an admin can regenerate it
+			session.RecordTurn(agentName, false, agent1Delta, agent2Delta, messageID, seq)
 
 			// Also broadcast separate audio message for frontend audio player
 			if audioURL != "" {
@@ -416,56 +617,249 @@ func (m *DebateManager) StartDebateLoop(session *conversation.DebateSession) {
 					"type":     "audio",
 					"audioUrl": audioURL,
 					"agent":    agentName,
+					"voice":    voiceSettings,
 				})
 			}
 
 			// Broadcast updated game score
-			session.Broadcast(gin.H{
-				"type": "game_score",
-				"gameScore": gin.H{
-					session.Agent1.GetName(): m.NormalizeScore(gameScore.Agent1Score),
-					session.Agent2.GetName(): m.NormalizeScore(gameScore.Agent2Score),
-				},
-				"internalScore": gin.H{
-					session.Agent1.GetName(): gameScore.Agent1Score,
-					session.Agent2.GetName(): gameScore.Agent2Score,
-				},
-			})
+			session.Broadcast(m.gameScoreMessage(session, gameScore))
 
 			// If game over, end debate
 			if gameOver {
-				log.Printf("Game over in debate %s. Winner: %s", debateID, winner)
-
-				// Update status in memory
-				session.UpdateStatus("finished")
-
-				// Update database
-				err := m.db.UpdateDebateEnd(debateID, "finished", winner)
-				if err != nil {
-					log.Printf("Error updating debate end in database: %v", err)
-				}
+				// Update status, persist the result and broadcast game over
+				handleGameOver(m.server, session, debateID, winner, database.DebateEndReasonKnockout, nil)
+				break
+			}
 
-				// Broadcast game over message
-				session.Broadcast(gin.H{
-					"type":    "game_over",
-					"winner":  winner,
-					"message": fmt.Sprintf("Game over! %s has won the debate!", winner),
+			// After MaxTurns agent turns, judged debates end with the judge's verdict
+			// and HP debates end in favour of the agent with more HP
+			if session.Config.MaxTurns > 0 && agentTurnCount >= session.Config.MaxTurns {
+				logging.InfoCtx(ctx, "Debate reached max turns", map[string]interface{}{
+					"turn":      agentTurnCount,
+					"max_turns": session.Config.MaxTurns,
 				})
-
+				if judged {
+					handleJudgedGameOver(ctx, m.server, session, debateID)
+				} else {
+					handleHPLeaderGameOver(m.server, session, debateID, database.DebateEndReasonMaxTurns)
+				}
 				break
 			}
 
-			// Pause between turns
-			time.Sleep(session.Config.TurnDelay)
+			// Debates with a budget end on HP once their estimated cost reaches it, judged or not
+			if budget := session.Config.MaxCostUSD; budget > 0 {
+				cost := m.server.debateCost(session)
+				if cost >= budget {
+					logging.WarnCtx(ctx, "Debate exceeded its budget", map[string]interface{}{
+						"turn":         agentTurnCount,
+						"cost_usd":     cost,
+						"max_cost_usd": budget,
+					})
+					handleHPLeaderGameOver(m.server, session, debateID, database.DebateEndReasonBudget)
+					break
+				}
+				if !budgetWarned && cost >= budget*budgetWarningFraction {
+					budgetWarned = true
+					session.Broadcast(gin.H{
+						"type":         "budget_warning",
+						"cost_usd":     cost,
+						"max_cost_usd": budget,
+						"message":      "This debate is close to its budget and will end soon",
+					})
+				}
+			}
+
+			// Pause between turns, stretched off-peak and when nobody is watching
+			delay := m.paceNextTurn(session)
+			waitForNextTurn(session, delay)
+			// Time spent slowed down by the schedule isn't a stuck debate
+			if extra := delay - session.Config.TurnDelay; extra > 0 {
+				lastActivityTime = lastActivityTime.Add(extra)
+			}
 		}
 
 		// Debate ended due to status change (winner determined, timeout, or error)
-		logging.Info("Debate loop ended", map[string]interface{}{
-			"debate_id":    debateID,
+		logging.InfoCtx(ctx, "Debate loop ended", map[string]interface{}{
 			"final_status": session.GetStatus(),
 			"total_turns":  agentTurnCount,
 		})
 	}()
+
+	return true
+}
+
+// budgetWarningFraction is the share of a debate's budget at which players are warned it will end soon
+const budgetWarningFraction = 0.8
+
+// debateCost estimates what a debate has spent so far on LLM and TTS calls, in USD
+func (s *Server) debateCost(session *conversation.DebateSession) float64 {
+	var config *Config
+	if s != nil {
+		config = s.config
+	}
+	return config.GetUsageRates().Cost(session.GetUsage()).Total
+}
+
+// agentTurnPrompt builds the prompt for an agent's next turn, with the given recent history as context,
+// preceded by the session's running summary of older history if there is one, and the tone its HP gap calls for
+func agentTurnPrompt(session *conversation.DebateSession, agentName string, recentHistory []conversation.DebateEntry) string {
+	contextStr := formatHistory(recentHistory)
+	if summary := session.HistorySummary(); summary != "" {
+		contextStr = fmt.Sprintf("Summary of the earlier debate: %s\n%s", summary, contextStr)
+	}
+	prompt := getPrompt(contextStr, "", agentName, "Debate Participant", session.Config.Topic, session.PromptStyle())
+	prompt = applyPromptOverride(prompt, session.Config.AgentPromptOverrides[agentName])
+	prompt = applyHPTone(prompt, session.AgentTone(agentName))
+	return localizePrompt(prompt, session.Config.Language)
+}
+
+// agentTurnDeltas splits an agent turn's points into HP changes: the agent gains them and its opponent loses them
+func agentTurnDeltas(session *conversation.DebateSession, agentName string, points int) (agent1Delta, agent2Delta int) {
+	if agentName == session.Agent1.GetName() {
+		return points, -points
+	}
+	return -points, points
+}
+
+// generationFallback returns the debate-level model settings used where an agent's config doesn't set its own
+func generationFallback(config conversation.DebateConfig) agent.GenerationSettings {
+	return agent.GenerationSettings{MaxTokens: config.MaxCompletionTokens}
+}
+
+// turnAudio is the outcome of generating and caching audio for an agent turn
+type turnAudio struct {
+	url      string        // Empty if audio generation failed
+	duration time.Duration // Time spent generating and caching the audio
+}
+
+// generateTurnAudio generates and caches audio for an agent's response in a goroutine.
+// The returned channel always receives exactly one result, even if generation fails or panics.
+func (m *DebateManager) generateTurnAudio(ctx context.Context, session *conversation.DebateSession, speaker *agent.Agent, response string, voiceSettings audio.VoiceSettings, turn int) <-chan turnAudio {
+	result := make(chan turnAudio, 1)
+
+	go func() {
+		start := time.Now()
+		var audioURL string
+		var probe, reported bool // Whether this request probes the TTS breaker, and whether it recorded an outcome
+		defer func() {
+			if r := recover(); r != nil {
+				logging.ErrorCtx(ctx, "Panic generating audio", map[string]interface{}{
+					"agent_name": speaker.GetName(),
+					"turn":       turn,
+					"panic":      r,
+				})
+				audioURL = ""
+			}
+			// A probe that ends without an outcome must not leave the breaker half-open, and audio off, for good
+			if probe && !reported {
+				m.ttsBreaker.abandonProbe()
+			}
+			result <- turnAudio{url: audioURL, duration: time.Since(start)}
+		}()
+
+		// While the TTS provider is down, turns go text-only instead of waiting on requests bound to fail
+		allowed, probing := m.ttsBreaker.acquire()
+		probe = probing
+		if !allowed {
+			return
+		}
+
+		audioData, err := speaker.GenerateAudioWithSettings(ctx, response, session.Config.Language, voiceSettings)
+		if errors.Is(err, agent.ErrTTSUnavailable) {
+			return // Offline agents have no voice, so the turn is text-only
+		}
+		if err != nil {
+			logging.ErrorCtx(ctx, "Error generating audio", map[string]interface{}{
+				"agent_name": speaker.GetName(),
+				"turn":       turn,
+				"error":      err.Error(),
+			})
+			// A turn cancelled because its debate ended or paused says nothing about the provider
+			if ctx.Err() != nil {
+				return
+			}
+			reported = true
+			if m.ttsBreaker.recordFailure() {
+				m.announceAudioUnavailable()
+			}
+			return
+		}
+		reported = true
+		m.ttsBreaker.recordSuccess()
+
+		// Store audio in cache and get URL; CacheAudio guards the cache with its own mutex
+		audioURL = m.server.CacheAudio(audioData)
+		logging.InfoCtx(ctx, "Generated audio", map[string]interface{}{
+			"agent_name": speaker.GetName(),
+			"turn":       turn,
+			"audio_url":  audioURL,
+		})
+	}()
+
+	return result
+}
+
+// lowHPThreshold is the HP at or below which an agent's delivery becomes agitated
+const lowHPThreshold = 30
+
+// agentHP returns the current HP of the named agent
+func agentHP(session *conversation.DebateSession, agentName string) int {
+	gameScore := session.GetGameScore()
+	if agentName == session.Agent1.GetName() {
+		return gameScore.Agent1Score
+	}
+	return gameScore.Agent2Score
+}
+
+// opponentName returns the name of the agent debating against agentName
+func opponentName(session *conversation.DebateSession, agentName string) string {
+	if agentName == session.Agent1.GetName() {
+		return session.Agent2.GetName()
+	}
+	return session.Agent1.GetName()
+}
+
+// voiceSettingsForHP overrides an agent's default delivery as its HP drops:
+// agents under pressure speak faster and more agitated
+func voiceSettingsForHP(base audio.VoiceSettings, hp int) audio.VoiceSettings {
+	if hp > lowHPThreshold {
+		return base
+	}
+
+	speed := base.Speed
+	if speed <= 0 {
+		speed = 1.0
+	}
+	return base.Override(audio.VoiceSettings{
+		Speed:   speed * 1.1,
+		Emotion: audio.EmotionAgitated,
+	})
+}
+
+// gameScoreMessage builds the game_score broadcast, including the derived momentum and close-match state
+func (m *DebateManager) gameScoreMessage(session *conversation.DebateSession, gameScore conversation.GameScore) gin.H {
+	momentum := session.GetMomentum()
+	support := session.GetCrowdSupport()
+	return gin.H{
+		"type": "game_score",
+		"gameScore": gin.H{
+			session.Agent1.GetName(): m.NormalizeScore(gameScore.Agent1Score),
+			session.Agent2.GetName(): m.NormalizeScore(gameScore.Agent2Score),
+		},
+		"internalScore": gin.H{
+			session.Agent1.GetName(): gameScore.Agent1Score,
+			session.Agent2.GetName(): gameScore.Agent2Score,
+		},
+		"momentum": gin.H{
+			session.Agent1.GetName(): momentum,
+			session.Agent2.GetName(): -momentum,
+		},
+		"close_match": session.IsCloseMatch(),
+		"supporters": gin.H{
+			session.Agent1.GetName(): support.Agent1,
+			session.Agent2.GetName(): support.Agent2,
+		},
+	}
 }
 
 // NormalizeScore normalizes a score to a 0-100 scale for display
@@ -481,15 +875,99 @@ func (m *DebateManager) NormalizeScore(score int) float64 {
 	return float64(score)
 }
 
+// maxConcurrentDebates returns the configured cap on concurrent debates
+func (m *DebateManager) maxConcurrentDebates() int {
+	var config *Config
+	if m.server != nil {
+		config = m.server.config
+	}
+	return config.GetMaxConcurrentDebates()
+}
+
+// maxHistoryInMemory returns how many history entries each debate keeps in memory
+func (m *DebateManager) maxHistoryInMemory() int {
+	var config *Config
+	if m.server != nil {
+		config = m.server.config
+	}
+	return config.GetMaxHistoryInMemory()
+}
+
+// liveDebateCountLocked counts the waiting, active and paused debates, including those being created;
+// caller must hold debatesMutex
+func (m *DebateManager) liveDebateCountLocked() int {
+	count := m.pendingCreates
+	for _, session := range m.debates {
+		if countsTowardCapacity(session.GetStatus()) {
+			count++
+		}
+	}
+	return count
+}
+
+// DebateCapacity returns how many debates count toward the concurrency cap, and the cap
+func (m *DebateManager) DebateCapacity() (current, max int) {
+	m.debatesMutex.RLock()
+	defer m.debatesMutex.RUnlock()
+	return m.liveDebateCountLocked(), m.maxConcurrentDebates()
+}
+
+// reserveDebateSlot claims a place under the concurrency cap for a debate being created, so concurrent
+// creations can't overshoot it. The slot passes to the debate once it is stored, or is given back with
+// releaseDebateSlot if creating it fails.
+func (m *DebateManager) reserveDebateSlot() error {
+	m.debatesMutex.Lock()
+	defer m.debatesMutex.Unlock()
+
+	if m.liveDebateCountLocked() >= m.maxConcurrentDebates() {
+		return ErrDebateCapacityReached
+	}
+	m.pendingCreates++
+	return nil
+}
+
+// releaseDebateSlot gives back a slot claimed by reserveDebateSlot
+func (m *DebateManager) releaseDebateSlot() {
+	m.debatesMutex.Lock()
+	defer m.debatesMutex.Unlock()
+	m.pendingCreates--
+}
+
+// ActiveDebateCount returns the number of debates currently held in memory and not yet finished
+func (m *DebateManager) ActiveDebateCount() int {
+	m.debatesMutex.RLock()
+	defer m.debatesMutex.RUnlock()
+
+	count := 0
+	for _, session := range m.debates {
+		if session.GetStatus() != types.DebateStatusFinished {
+			count++
+		}
+	}
+	return count
+}
+
 // RemoveDebate removes a debate from the manager
 func (m *DebateManager) RemoveDebate(debateID string) {
 	m.debatesMutex.Lock()
 	defer m.debatesMutex.Unlock()
 
+	if session, exists := m.debates[debateID]; exists {
+		forgetDebateMemory(session)
+	}
 	delete(m.debates, debateID)
 	log.Printf("Removed debate %s from manager", debateID)
 }
 
+// forgetDebateMemory drops what the debate's agents remember from it
+func forgetDebateMemory(session *conversation.DebateSession) {
+	for _, a := range []*agent.Agent{session.Agent1, session.Agent2} {
+		if a != nil {
+			a.ForgetMemory(session.DebateID)
+		}
+	}
+}
+
 // Add a periodic cleanup method to run in the server
 func (m *DebateManager) StartPeriodicCleanup(interval time.Duration) {
 	ticker := time.NewTicker(interval)
@@ -500,6 +978,105 @@ func (m *DebateManager) StartPeriodicCleanup(interval time.Duration) {
 	}()
 }
 
+// StartScheduledDebatePromoter periodically opens scheduled debates whose start time has arrived
+func (m *DebateManager) StartScheduledDebatePromoter(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			m.PromoteDueDebates()
+		}
+	}()
+}
+
+// StartDebateLoopReconciler restarts stalled debate loops right away, e.g. those of debates loaded at startup,
+// and then periodically
+func (m *DebateManager) StartDebateLoopReconciler(interval time.Duration) {
+	m.ReconcileDebateLoops()
+
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			m.ReconcileDebateLoops()
+		}
+	}()
+}
+
+// ReconcileDebateLoops restarts the loop of every active debate that has none running: debates loaded from the
+// database after a restart, or whose loop crashed. It returns the IDs of the debates it restarted.
+func (m *DebateManager) ReconcileDebateLoops() []string {
+	m.debatesMutex.RLock()
+	var stalled []*conversation.DebateSession
+	for _, session := range m.debates {
+		if session.GetStatus() == types.DebateStatusActive && !session.IsLoopRunning() {
+			stalled = append(stalled, session)
+		}
+	}
+	m.debatesMutex.RUnlock()
+
+	var restarted []string
+	for _, session := range stalled {
+		if m.StartDebateLoop(session) {
+			logging.LogDebateEvent("debate_loop_restarted", session.DebateID, map[string]interface{}{
+				"triggered_by": "reconciler",
+			})
+			restarted = append(restarted, session.DebateID)
+		}
+	}
+	return restarted
+}
+
+// PromoteDueDebates moves every due scheduled debate to 'waiting' so players can join, and announces it in the lobby.
+// Opened debates count toward MaxConcurrentDebates like created ones; while the cap is reached, due debates stay
+// scheduled and are opened by a later run.
+func (m *DebateManager) PromoteDueDebates() {
+	debates, err := m.db.GetDueScheduledDebates(time.Now())
+	if err != nil {
+		log.Printf("Failed to check for due scheduled debates: %v", err)
+		return
+	}
+
+	for i, debate := range debates {
+		if err := m.reserveDebateSlot(); err != nil {
+			log.Printf("Leaving %d due scheduled debates for later: %v", len(debates)-i, err)
+			return
+		}
+		opened := m.openScheduledDebate(debate.ID)
+		m.releaseDebateSlot()
+		if !opened {
+			continue
+		}
+
+		logging.LogDebateEvent("scheduled_debate_opened", debate.ID, map[string]interface{}{
+			"topic":        debate.Topic,
+			"scheduled_at": debate.ScheduledAt,
+		})
+
+		// Unlisted and private debates are opened quietly
+		if m.server != nil && (debate.Visibility == "" || debate.Visibility == database.DebateVisibilityPublic) {
+			m.server.lobby.Publish(gin.H{
+				"type":      "debate_opened",
+				"debate_id": debate.ID,
+				"topic":     debate.Topic,
+				"agent1":    debate.Agent1Name,
+				"agent2":    debate.Agent2Name,
+			})
+		}
+	}
+}
+
+// openScheduledDebate moves a scheduled debate to 'waiting'. The caller holds a slot under the concurrency cap,
+// which the debate's session takes over once it's waiting.
+func (m *DebateManager) openScheduledDebate(debateID string) bool {
+	if err := m.db.UpdateDebateStatus(debateID, types.DebateStatusWaiting); err != nil {
+		log.Printf("Failed to open scheduled debate %s: %v", debateID, err)
+		return false
+	}
+	if session, exists := m.GetDebate(debateID); exists {
+		session.UpdateStatus(types.DebateStatusWaiting)
+	}
+	return true
+}
+
 // CleanupInactiveDebates removes finished debates that have been inactive for a certain period
 func (m *DebateManager) CleanupInactiveDebates() {
 	m.debatesMutex.Lock()
@@ -510,7 +1087,7 @@ func (m *DebateManager) CleanupInactiveDebates() {
 	var toRemove []string
 
 	for id, session := range m.debates {
-		if session.GetStatus() == "finished" {
+		if session.GetStatus() == types.DebateStatusFinished {
 			// TODO: Currently removing all finished debates without checking inactivity period
 			// This could be enhanced by adding a "finished_at" timestamp to DebateSession
 			// and comparing: if finishedAt.Before(cutoff) { ... }
@@ -527,12 +1104,17 @@ func (m *DebateManager) CleanupInactiveDebates() {
 	}
 
 	for _, id := range toRemove {
+		forgetDebateMemory(m.debates[id])
 		delete(m.debates, id)
 		log.Printf("Cleaned up inactive debate %s", id)
 	}
 }
 
-// LoadActiveDebates loads active debates from the database into memory
+// LoadActiveDebates loads active debates from the database into memory, with the config each was created with
+// and their transcript, which restores their HP, turn count and next speaker. Debates loaded as 'active' have no
+// loop running yet; ReconcileDebateLoops resumes them where they left off, without replaying the intro and with
+// the time they had left. Debates loaded as 'paused' were paused for an LLM outage; the server stays degraded until
+// the provider answers a probe, which resumes them.
 func (m *DebateManager) LoadActiveDebates() error {
 	m.debatesMutex.Lock()
 	defer m.debatesMutex.Unlock()
@@ -545,10 +1127,11 @@ func (m *DebateManager) LoadActiveDebates() error {
 
 	log.Printf("Loading %d active debates into memory", len(debates))
 
+	var paused []string
 	for _, debate := range debates {
 		// Get agents for this debate
-		agent1, exists1 := m.agents[debate.Agent1Name]
-		agent2, exists2 := m.agents[debate.Agent2Name]
+		agent1, exists1 := m.agents.Get(debate.Agent1Name)
+		agent2, exists2 := m.agents.Get(debate.Agent2Name)
 
 		if !exists1 || !exists2 {
 			log.Printf("Warning: Skipping debate %s - missing agents (Agent1: %s exists: %v, Agent2: %s exists: %v)",
@@ -556,9 +1139,7 @@ func (m *DebateManager) LoadActiveDebates() error {
 			continue
 		}
 
-		// Create debate config
-		config := conversation.DefaultConfig()
-		config.Topic = debate.Topic
+		config := m.restoredConfig(debate)
 
 		// Create new debate session
 		session, err := conversation.NewDebateSession(debate.ID, agent1, agent2, config, m.apiKey)
@@ -568,19 +1149,67 @@ func (m *DebateManager) LoadActiveDebates() error {
 		}
 
 		// Set the correct status from database
-		session.UpdateStatus(debate.Status)
+		session.RestoreStatus(debate.Status)
+
+		// Replay the stored transcript, so the debate keeps the HP it moved, the turns it took and whose turn is next
+		messages, err := m.db.GetDebateMessages(debate.ID)
+		if err != nil {
+			log.Printf("Warning: Restoring debate %s without its transcript, it can't be read: %v", debate.ID, err)
+		} else if len(messages) > 0 {
+			entries := make([]conversation.DebateEntry, 0, len(messages))
+			for _, msg := range messages {
+				entries = append(entries, transcriptEntry(msg))
+			}
+			session.RestoreHistory(entries)
+		}
+
+		// A debate that was running or paused resumes with its deadline; one stored before deadlines were recorded
+		// gets a fresh MaxDuration
+		if debate.Status == types.DebateStatusActive || debate.Status == types.DebateStatusPaused {
+			deadline := time.Now().Add(config.MaxDuration)
+			if debate.Deadline != nil {
+				deadline = *debate.Deadline
+			}
+			session.SetDeadline(deadline)
+		}
 
 		// Store in memory
 		m.debates[debate.ID] = session
 
 		log.Printf("Loaded debate %s (%s) into memory with status: %s", debate.ID, debate.Topic, debate.Status)
+		if debate.Status == types.DebateStatusActive {
+			log.Printf("Debate %s was running before the restart and will be resumed", debate.ID)
+		}
+		if debate.Status == types.DebateStatusPaused {
+			log.Printf("Debate %s was paused for an LLM outage and will be resumed when the provider recovers", debate.ID)
+			paused = append(paused, debate.ID)
+		}
 	}
+	m.llmOutage.restorePaused(paused)
 
 	log.Printf("Successfully loaded %d debates into memory", len(m.debates))
 	return nil
 }
 
-// GetDebateInfo returns comprehensive information about a debate for reconnecting clients
+// restoredConfig rebuilds the config a stored debate was created with. Debates stored before their config was
+// recorded, or whose config can't be read, get the default settings for their topic.
+func (m *DebateManager) restoredConfig(debate *database.Debate) conversation.DebateConfig {
+	config := conversation.DefaultConfig()
+	if debate.Config != nil {
+		if err := json.Unmarshal([]byte(*debate.Config), &config); err != nil {
+			log.Printf("Warning: Restoring debate %s with the default settings, its config can't be read: %v", debate.ID, err)
+			config = conversation.DefaultConfig()
+		}
+	}
+	config.Topic = debate.Topic
+	if config.MaxHistoryInMemory <= 0 {
+		config.MaxHistoryInMemory = m.maxHistoryInMemory()
+	}
+	return config
+}
+
+// GetDebateInfo returns comprehensive information about a debate for reconnecting clients. It's served both as
+// the WebSocket get_state reply and by GET /api/debates/:debateID/state.
 func (m *DebateManager) GetDebateInfo(debateID string) (map[string]interface{}, error) {
 	m.debatesMutex.RLock()
 	defer m.debatesMutex.RUnlock()
@@ -596,6 +1225,14 @@ func (m *DebateManager) GetDebateInfo(debateID string) (map[string]interface{},
 	// Get recent history for catch-up
 	recentHistory := session.GetRecentHistory(10)
 
+	// Get connected clients broken down by role
+	presence := session.GetPresence()
+
+	winCondition := session.Config.WinCondition
+	if winCondition == "" {
+		winCondition = conversation.WinConditionHP
+	}
+
 	// Convert history to a format suitable for frontend
 	historyData := make([]map[string]interface{}, 0, len(recentHistory))
 	for _, entry := range recentHistory {
@@ -622,8 +1259,17 @@ func (m *DebateManager) GetDebateInfo(debateID string) (map[string]interface{},
 			session.Agent2.GetName(): gameScore.Agent2Score,
 		},
 		"history":      historyData,
-		"client_count": len(session.Clients),
-		"is_active":    session.GetStatus() == "active",
+		"client_count": presence.Total,
+		"presence":     presence,
+		"is_active":    session.GetStatus() == types.DebateStatusActive,
+	}
+
+	// How the debate ends: its win condition, timeouts and turn limit
+	debateInfo["win_condition"] = winCondition
+	debateInfo["timeouts"] = debateTimeouts(session)
+	debateInfo["turns"] = map[string]interface{}{
+		"current": session.GetTurnCount(),
+		"max":     session.Config.MaxTurns, // 0 or less means unlimited
 	}
 
 	return debateInfo, nil
@@ -2,16 +2,22 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"github.com/neo/convinceme_backend/internal/agent"
 	"github.com/neo/convinceme_backend/internal/conversation"
 	"github.com/neo/convinceme_backend/internal/database"
 	"github.com/neo/convinceme_backend/internal/scoring"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockDatabase for testing
@@ -22,18 +28,26 @@ type MockDatabaseForDebate struct {
 // Ensure MockDatabaseForDebate implements database.DatabaseInterface
 var _ database.DatabaseInterface = (*MockDatabaseForDebate)(nil)
 
-func (m *MockDatabaseForDebate) CreateDebate(id, topic, status, agent1Name, agent2Name string) error {
+func (m *MockDatabaseForDebate) CreateDebate(id, topic string, status types.DebateStatus, agent1Name, agent2Name string) error {
 	args := m.Called(id, topic, status, agent1Name, agent2Name)
 	return args.Error(0)
 }
 
-func (m *MockDatabaseForDebate) UpdateDebateStatus(id, status string) error {
+func (m *MockDatabaseForDebate) GetDueScheduledDebates(now time.Time) ([]*database.Debate, error) {
+	args := m.Called(now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*database.Debate), args.Error(1)
+}
+
+func (m *MockDatabaseForDebate) UpdateDebateStatus(id string, status types.DebateStatus) error {
 	args := m.Called(id, status)
 	return args.Error(0)
 }
 
-func (m *MockDatabaseForDebate) UpdateDebateEnd(id, status, winner string) error {
-	args := m.Called(id, status, winner)
+func (m *MockDatabaseForDebate) UpdateDebateEnd(id string, status types.DebateStatus, winner, reason string) error {
+	args := m.Called(id, status, winner, reason)
 	return args.Error(0)
 }
 
@@ -55,6 +69,154 @@ func (m *MockDatabaseForDebate) ListDebates(filter database.DebateFilter) ([]*da
 	return args.Get(0).([]*database.Debate), args.Int(1), args.Error(2)
 }
 
+func (m *MockDatabaseForDebate) SaveDebateSettings(id string, settings database.DebateSettings) error {
+	return nil
+}
+
+func (m *MockDatabaseForDebate) SaveDebateDeadline(id string, deadline time.Time) error {
+	return nil
+}
+
+func (m *MockDatabaseForDebate) SetDebateFeatured(id string, featured bool) error {
+	return nil
+}
+
+func (m *MockDatabaseForDebate) AddDebateParticipant(debateID, userID, invitedBy string) error {
+	return nil
+}
+
+func (m *MockDatabaseForDebate) IsDebateParticipant(debateID, userID string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockDatabaseForDebate) SaveChatMessage(debateID, playerID, username, message string) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockDatabaseForDebate) GetChatMessages(debateID string, limit int) ([]*database.ChatMessage, error) {
+	return nil, nil
+}
+
+func (m *MockDatabaseForDebate) UpdateDebateActiveTime(id string, activeSeconds int64) error {
+	return nil
+}
+
+func (m *MockDatabaseForDebate) GetAverageDebateDuration(filter database.DebateFilter) (float64, error) {
+	return 0, nil
+}
+
+func (m *MockDatabaseForDebate) GetDebateStats(filter database.DebateFilter) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (m *MockDatabaseForDebate) GetAgentWinRates() ([]*database.AgentWinRate, error) {
+	return nil, nil
+}
+
+func (m *MockDatabaseForDebate) GetScoreBreakdown(debateID string) ([]*database.CriteriaBreakdown, error) {
+	return nil, nil
+}
+
+func (m *MockDatabaseForDebate) SaveDebateMessage(msg *database.DebateMessage) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockDatabaseForDebate) UpdateDebateMessage(msg *database.DebateMessage) error {
+	return nil
+}
+
+func (m *MockDatabaseForDebate) GetDebateMessagesBefore(debateID string, beforeID int64, limit int) ([]*database.DebateMessage, bool, error) {
+	return nil, false, nil
+}
+
+func (m *MockDatabaseForDebate) GetDebateMessages(debateID string) ([]*database.DebateMessage, error) {
+	return nil, nil
+}
+
+func (m *MockDatabaseForDebate) SaveDebateUsage(debateID string, u usage.Usage) error {
+	return nil
+}
+
+func (m *MockDatabaseForDebate) CreateNotification(userID, notificationType string, payload interface{}) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockDatabaseForDebate) GetNotifications(userID string, unreadOnly bool, limit int) ([]*database.Notification, int, error) {
+	return nil, 0, nil
+}
+
+func (m *MockDatabaseForDebate) MarkNotificationRead(id int64, userID string) error {
+	return nil
+}
+
+func (m *MockDatabaseForDebate) GetDebateParticipants(debateID string) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockDatabaseForDebate) AddBookmark(userID, debateID string) error {
+	return nil
+}
+
+func (m *MockDatabaseForDebate) RemoveBookmark(userID, debateID string) error {
+	return nil
+}
+
+func (m *MockDatabaseForDebate) ListBookmarks(userID string, offset, limit int) ([]*database.Bookmark, int, error) {
+	return nil, 0, nil
+}
+
+func (m *MockDatabaseForDebate) CountBookmarks(debateID string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockDatabaseForDebate) GetDebateBookmarkers(debateID string) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockDatabaseForDebate) AddReaction(debateID string, messageID int64, emoji string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockDatabaseForDebate) GetReactionCounts(debateID string) (map[int64]map[string]int, error) {
+	return nil, nil
+}
+
+func (m *MockDatabaseForDebate) LogAuditEvent(actorID, action, target string, details interface{}) error {
+	return nil
+}
+
+func (m *MockDatabaseForDebate) ListAuditEvents(filter database.AuditFilter) ([]*database.AuditEvent, int, error) {
+	return nil, 0, nil
+}
+
+func (m *MockDatabaseForDebate) GetDebateUsage(debateID string) (*database.DebateUsage, error) {
+	return nil, nil
+}
+
+func (m *MockDatabaseForDebate) GetUsageTotals() (usage.Usage, error) {
+	return usage.Usage{}, nil
+}
+
+func (m *MockDatabaseForDebate) GetUserReputation(userID string) (float64, error) {
+	return 0, nil
+}
+
+func (m *MockDatabaseForDebate) RecomputeReputations() (int64, error) {
+	return 0, nil
+}
+
+func (m *MockDatabaseForDebate) GetArgumentsByDebate(debateID string, filter database.ArgumentFilter) ([]*database.Argument, int, error) {
+	return nil, 0, nil
+}
+
+func (m *MockDatabaseForDebate) GetUserCountsByRole() (map[string]int, error) {
+	return nil, nil
+}
+
+func (m *MockDatabaseForDebate) GetInvitationStats(userID string) (*database.InvitationStats, error) {
+	return nil, nil
+}
+
 func (m *MockDatabaseForDebate) GetTopic(id int) (*database.Topic, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -68,11 +230,21 @@ func (m *MockDatabaseForDebate) GetTopics(filter database.TopicFilter) ([]*datab
 	return args.Get(0).([]*database.Topic), args.Int(1), args.Error(2)
 }
 
-func (m *MockDatabaseForDebate) SaveArgument(playerID, topic, content, side, debateID string) (int64, error) {
-	args := m.Called(playerID, topic, content, side, debateID)
+func (m *MockDatabaseForDebate) CreateTopics(topics []*database.Topic) ([]int, error) {
+	args := m.Called(topics)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockDatabaseForDebate) SaveArgument(playerID, userID, topic, content, side, debateID string, parent database.ArgumentParent) (int64, error) {
+	args := m.Called(playerID, userID, topic, content, side, debateID, parent)
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockDatabaseForDebate) GetReplyParent(debateID string, parent database.ArgumentParent) (string, error) {
+	args := m.Called(debateID, parent)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockDatabaseForDebate) SaveScore(argumentID int64, debateID string, score *scoring.ArgumentScore) error {
 	args := m.Called(argumentID, debateID, score)
 	return args.Error(0)
@@ -91,6 +263,37 @@ func (m *MockDatabaseForDebate) GetArgumentWithScore(id int64) (*database.Argume
 	return args.Get(0).(*database.Argument), args.Error(1)
 }
 
+func (m *MockDatabaseForDebate) GetLeaderboard(debateID string, limit int) ([]*database.Argument, error) {
+	args := m.Called(debateID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*database.Argument), args.Error(1)
+}
+
+func (m *MockDatabaseForDebate) UpdateArgument(id int64, userID, content string) error {
+	args := m.Called(id, userID, content)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseForDebate) UpdateScore(argumentID int64, score *scoring.ArgumentScore) error {
+	args := m.Called(argumentID, score)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseForDebate) GetScoreCritique(argumentID int64) (*scoring.ScoreCritique, error) {
+	return nil, nil
+}
+
+func (m *MockDatabaseForDebate) SaveScoreCritique(argumentID int64, critique *scoring.ScoreCritique) error {
+	return nil
+}
+
+func (m *MockDatabaseForDebate) DeleteArgument(id int64, userID string) error {
+	args := m.Called(id, userID)
+	return args.Error(0)
+}
+
 // Stub implementations for other interface methods
 func (m *MockDatabaseForDebate) Close() error {
 	return nil
@@ -112,7 +315,7 @@ func (m *MockDatabaseForDebate) GetUserByEmail(email string) (*database.User, er
 	return nil, nil
 }
 
-func (m *MockDatabaseForDebate) UpdateUser(user *database.User) error {
+func (m *MockDatabaseForDebate) UpdateUser(user *database.User, expectedUpdatedAt time.Time) error {
 	return nil
 }
 
@@ -120,6 +323,10 @@ func (m *MockDatabaseForDebate) DeleteUser(id string) error {
 	return nil
 }
 
+func (m *MockDatabaseForDebate) DeleteUserAnonymized(id string) (string, error) {
+	return "", nil
+}
+
 func (m *MockDatabaseForDebate) VerifyPassword(username, password string) (*database.User, error) {
 	return nil, nil
 }
@@ -204,6 +411,10 @@ func (m *MockDatabaseForDebate) GetFeedbackByUser(userID string) ([]*database.Fe
 	return nil, nil
 }
 
+func (m *MockDatabaseForDebate) GetFeedbackByUserFiltered(userID string, filter database.FeedbackFilter) ([]*database.Feedback, int, error) {
+	return nil, 0, nil
+}
+
 func (m *MockDatabaseForDebate) GetAllFeedback(filter database.FeedbackFilter) ([]*database.Feedback, int, error) {
 	return nil, 0, nil
 }
@@ -216,6 +427,42 @@ func (m *MockDatabaseForDebate) DeleteFeedback(id int) error {
 	return nil
 }
 
+func (m *MockDatabaseForDebate) SubmitVote(userID string, argumentID int64, debateID string, voteType string) error {
+	return nil
+}
+
+func (m *MockDatabaseForDebate) GetUserVoteCount(userID string, debateID string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockDatabaseForDebate) HasUserPaidForComment(userID string, debateID string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockDatabaseForDebate) GetUserVoteForArgument(userID string, argumentID int64) (string, error) {
+	return "", nil
+}
+
+func (m *MockDatabaseForDebate) GetUserVotesForDebate(userID, debateID string) (map[int64]string, error) {
+	return map[int64]string{}, nil
+}
+
+func (m *MockDatabaseForDebate) GetVotesByUser(userID string) ([]*database.UserVote, error) {
+	return nil, nil
+}
+
+func (m *MockDatabaseForDebate) GetArgumentsByUser(userID string) ([]*database.Argument, error) {
+	return nil, nil
+}
+
+func (m *MockDatabaseForDebate) CanUserVote(userID string, argumentID int64, debateID string) (bool, string, error) {
+	return false, "", nil
+}
+
+func (m *MockDatabaseForDebate) RunMigrations() error {
+	return nil
+}
+
 // MockAgent for testing
 type MockAgent struct {
 	mock.Mock
@@ -250,13 +497,13 @@ func TestCreateDebate(t *testing.T) {
 	// Setup debate manager
 	debateManager := &DebateManager{
 		db:      mockDB,
-		agents:  agents,
+		agents:  NewAgentRegistry(agents),
 		apiKey:  "test-api-key",
 		debates: make(map[string]*conversation.DebateSession),
 	}
 
 	// Setup expectations
-	mockDB.On("CreateDebate", mock.AnythingOfType("string"), "Test Topic", "waiting", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+	mockDB.On("CreateDebate", mock.AnythingOfType("string"), "Test Topic", types.DebateStatusWaiting, mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
 
 	// Call the function
 	debateID, err := debateManager.CreateDebate("Test Topic", agent1, agent2, "test_user")
@@ -279,7 +526,7 @@ func TestGetDebate(t *testing.T) {
 	// Setup debate manager
 	debateManager := &DebateManager{
 		db:      mockDB,
-		agents:  make(map[string]*agent.Agent),
+		agents:  NewAgentRegistry(nil),
 		apiKey:  "test-api-key",
 		debates: make(map[string]*conversation.DebateSession),
 	}
@@ -310,7 +557,7 @@ func TestRemoveDebate(t *testing.T) {
 	// Setup debate manager
 	debateManager := &DebateManager{
 		db:      mockDB,
-		agents:  make(map[string]*agent.Agent),
+		agents:  NewAgentRegistry(nil),
 		apiKey:  "test-api-key",
 		debates: make(map[string]*conversation.DebateSession),
 	}
@@ -342,7 +589,7 @@ func TestCleanupInactiveDebates(t *testing.T) {
 	// Setup debate manager
 	debateManager := &DebateManager{
 		db:      mockDB,
-		agents:  make(map[string]*agent.Agent),
+		agents:  NewAgentRegistry(nil),
 		apiKey:  "test-api-key",
 		debates: make(map[string]*conversation.DebateSession),
 	}
@@ -384,7 +631,7 @@ func TestNormalizeScore(t *testing.T) {
 	// Setup debate manager
 	debateManager := &DebateManager{
 		db:      nil,
-		agents:  make(map[string]*agent.Agent),
+		agents:  NewAgentRegistry(nil),
 		apiKey:  "test-api-key",
 		debates: make(map[string]*conversation.DebateSession),
 	}
@@ -409,3 +656,568 @@ func TestNormalizeScore(t *testing.T) {
 		})
 	}
 }
+
+// TestCreateScheduledDebate tests that debates with a start time are created as scheduled
+func TestCreateScheduledDebate(t *testing.T) {
+	mockDB := new(MockDatabaseForDebate)
+
+	agent1 := &agent.Agent{}
+	agent2 := &agent.Agent{}
+
+	debateManager := &DebateManager{
+		db:      mockDB,
+		agents:  NewAgentRegistry(map[string]*agent.Agent{"Agent1": agent1, "Agent2": agent2}),
+		apiKey:  "test-api-key",
+		debates: make(map[string]*conversation.DebateSession),
+	}
+
+	mockDB.On("CreateDebate", mock.AnythingOfType("string"), "Test Topic", types.DebateStatusScheduled, mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+
+	config := conversation.DefaultConfig()
+	config.Topic = "Test Topic"
+	startsAt := time.Now().Add(time.Hour)
+	debateID, err := debateManager.CreateDebateWithConfig(config, agent1, agent2, "test_user", database.DebateSettings{ScheduledAt: &startsAt})
+
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+
+	session, exists := debateManager.debates[debateID]
+	require.True(t, exists)
+	assert.Equal(t, types.DebateStatusScheduled, session.GetStatus())
+}
+
+// TestCreateDebateFirstSpeaker tests that the chosen or coin-flipped first speaker is resolved on the session
+func TestCreateDebateFirstSpeaker(t *testing.T) {
+	mockDB := new(MockDatabaseForDebate)
+
+	agent1 := &agent.Agent{}
+	agent2 := &agent.Agent{}
+
+	debateManager := &DebateManager{
+		db:      mockDB,
+		agents:  NewAgentRegistry(map[string]*agent.Agent{"Agent1": agent1, "Agent2": agent2}),
+		apiKey:  "test-api-key",
+		debates: make(map[string]*conversation.DebateSession),
+		server:  &Server{config: &Config{MaxConcurrentDebates: 100}},
+	}
+
+	mockDB.On("CreateDebate", mock.AnythingOfType("string"), "Test Topic", types.DebateStatusWaiting, mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+
+	create := func(firstSpeaker string) *conversation.DebateSession {
+		config := conversation.DefaultConfig()
+		config.Topic = "Test Topic"
+		config.FirstSpeaker = firstSpeaker
+		debateID, err := debateManager.CreateDebateWithConfig(config, agent1, agent2, "test_user", database.DebateSettings{})
+		require.NoError(t, err)
+		return debateManager.debates[debateID]
+	}
+
+	assert.Equal(t, conversation.FirstSpeakerAgent1, create("").FirstSpeaker())
+	session := create(conversation.FirstSpeakerAgent2)
+	assert.Equal(t, conversation.FirstSpeakerAgent2, session.FirstSpeaker())
+	assert.Same(t, agent2, session.FirstSpeakerAgent())
+
+	// A coin flip resolves to one of the agents; over enough flips both come up
+	seen := map[string]bool{}
+	for i := 0; i < 64; i++ {
+		seen[create(conversation.FirstSpeakerRandom).FirstSpeaker()] = true
+	}
+	assert.Equal(t, map[string]bool{conversation.FirstSpeakerAgent1: true, conversation.FirstSpeakerAgent2: true}, seen)
+}
+
+// TestPromoteDueDebates tests that due scheduled debates are opened for joining
+func TestPromoteDueDebates(t *testing.T) {
+	mockDB := new(MockDatabaseForDebate)
+
+	debateManager := &DebateManager{
+		db:      mockDB,
+		agents:  NewAgentRegistry(nil),
+		apiKey:  "test-api-key",
+		debates: make(map[string]*conversation.DebateSession),
+	}
+
+	dueDebateID := "due-debate-id"
+	dueSession := &conversation.DebateSession{DebateID: dueDebateID, Status: "scheduled"}
+	debateManager.debates[dueDebateID] = dueSession
+
+	laterSession := &conversation.DebateSession{DebateID: "later-debate-id", Status: "scheduled"}
+	debateManager.debates["later-debate-id"] = laterSession
+
+	mockDB.On("GetDueScheduledDebates", mock.AnythingOfType("time.Time")).Return([]*database.Debate{
+		{ID: dueDebateID, Topic: "Test Topic", Status: "scheduled", Visibility: database.DebateVisibilityPublic},
+	}, nil)
+	mockDB.On("UpdateDebateStatus", dueDebateID, types.DebateStatusWaiting).Return(nil)
+
+	debateManager.PromoteDueDebates()
+
+	mockDB.AssertExpectations(t)
+	assert.Equal(t, types.DebateStatusWaiting, dueSession.GetStatus())
+	assert.Equal(t, types.DebateStatusScheduled, laterSession.GetStatus())
+}
+
+// TestPromoteDueDebatesCapacity tests that scheduled debates are only opened while under MaxConcurrentDebates
+func TestPromoteDueDebatesCapacity(t *testing.T) {
+	mockDB := new(MockDatabaseForDebate)
+	debateManager := &DebateManager{
+		db:      mockDB,
+		agents:  NewAgentRegistry(nil),
+		debates: make(map[string]*conversation.DebateSession),
+		server:  &Server{config: &Config{MaxConcurrentDebates: 2}},
+	}
+	debateManager.debates["running"] = &conversation.DebateSession{DebateID: "running", Status: "active"}
+	first := &conversation.DebateSession{DebateID: "first", Status: "scheduled"}
+	second := &conversation.DebateSession{DebateID: "second", Status: "scheduled"}
+	debateManager.debates["first"] = first
+	debateManager.debates["second"] = second
+
+	mockDB.On("GetDueScheduledDebates", mock.AnythingOfType("time.Time")).Return([]*database.Debate{
+		{ID: "first", Topic: "Test Topic", Status: "scheduled", Visibility: database.DebateVisibilityPrivate},
+		{ID: "second", Topic: "Test Topic", Status: "scheduled", Visibility: database.DebateVisibilityPrivate},
+	}, nil)
+	mockDB.On("UpdateDebateStatus", "first", types.DebateStatusWaiting).Return(nil)
+
+	// One slot is free, so only the first due debate opens
+	debateManager.PromoteDueDebates()
+	assert.Equal(t, types.DebateStatusWaiting, first.GetStatus())
+	assert.Equal(t, types.DebateStatusScheduled, second.GetStatus(), "the second stays scheduled while the cap is reached")
+	mockDB.AssertNotCalled(t, "UpdateDebateStatus", "second", types.DebateStatusWaiting)
+	current, max := debateManager.DebateCapacity()
+	assert.Equal(t, 2, current)
+	assert.Equal(t, 2, max)
+}
+
+// TestHPLeader tests that debates cut short are won by the agent with more HP, or drawn
+func TestHPLeader(t *testing.T) {
+	agent1 := &agent.Agent{}
+	agent2 := &agent.Agent{}
+
+	testCases := []struct {
+		name     string
+		score    conversation.GameScore
+		expected *agent.Agent
+	}{
+		{"agent 1 ahead", conversation.GameScore{Agent1Score: 60, Agent2Score: 40}, agent1},
+		{"agent 2 ahead", conversation.GameScore{Agent1Score: 30, Agent2Score: 45}, agent2},
+		{"draw", conversation.GameScore{Agent1Score: 50, Agent2Score: 50}, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			session := &conversation.DebateSession{Agent1: agent1, Agent2: agent2, GameScore: tc.score}
+			assert.Same(t, tc.expected, hpLeader(session))
+		})
+	}
+}
+
+// TestHandleGameOverRecordsEndReason tests that the end reason is persisted and broadcast with the result
+func TestHandleGameOverRecordsEndReason(t *testing.T) {
+	mockDB := new(MockDatabaseForDebate)
+	mockDB.On("UpdateDebateEnd", "test-debate", types.DebateStatusFinished, "Agent 1", database.DebateEndReasonKnockout).Return(nil)
+	server := &Server{db: mockDB}
+
+	session := &conversation.DebateSession{DebateID: "test-debate", Status: "active"}
+
+	handleGameOver(server, session, "test-debate", "Agent 1", database.DebateEndReasonKnockout, nil)
+
+	mockDB.AssertExpectations(t)
+	assert.Equal(t, types.DebateStatusFinished, session.GetStatus())
+}
+
+// TestJudgedGameOverFallsBackToHP tests that a judged debate the judge can't decide goes to the HP leader,
+// and is a draw at equal HP
+func TestJudgedGameOverFallsBackToHP(t *testing.T) {
+	testCases := []struct {
+		name     string
+		score    conversation.GameScore
+		expected string
+	}{
+		{"agent 1 ahead", conversation.GameScore{Agent1Score: 60, Agent2Score: 40}, "Agent 1"},
+		{"agent 2 ahead", conversation.GameScore{Agent1Score: 30, Agent2Score: 45}, "Agent 2"},
+		{"draw", conversation.GameScore{Agent1Score: 50, Agent2Score: 50}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := new(MockDatabaseForDebate)
+			mockDB.On("UpdateDebateEnd", "test-debate", types.DebateStatusFinished, tc.expected, database.DebateEndReasonMaxTurns).Return(nil)
+			server := &Server{db: mockDB}
+
+			// Without a judge, the verdict fails
+			session := &conversation.DebateSession{
+				DebateID:  "test-debate",
+				Status:    "active",
+				Agent1:    agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 1"}),
+				Agent2:    agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 2"}),
+				GameScore: tc.score,
+			}
+			handleJudgedGameOver(context.Background(), server, session, "test-debate")
+
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+// TestReconcileDebateLoops tests that only active debates without a running loop are restarted
+func TestReconcileDebateLoops(t *testing.T) {
+	mockDB := new(MockDatabaseForDebate)
+	mockDB.On("UpdateDebateEnd", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	debateManager := &DebateManager{
+		db:      mockDB,
+		debates: make(map[string]*conversation.DebateSession),
+		server:  &Server{db: mockDB},
+	}
+
+	newSession := func(id string, status types.DebateStatus) *conversation.DebateSession {
+		session := &conversation.DebateSession{DebateID: id, Status: status, Agent1: &agent.Agent{}, Agent2: &agent.Agent{}}
+		debateManager.debates[id] = session
+		return session
+	}
+	stalled := newSession("stalled", "active")
+	running := newSession("running", "active")
+	require.True(t, running.TryStartLoop())
+	newSession("waiting", "waiting")
+	newSession("finished", "finished")
+
+	assert.Equal(t, []string{"stalled"}, debateManager.ReconcileDebateLoops())
+	assert.True(t, stalled.IsLoopRunning())
+
+	// A loop that's already running isn't started twice
+	assert.Empty(t, debateManager.ReconcileDebateLoops())
+	assert.False(t, debateManager.StartDebateLoop(running))
+}
+
+// TestDebateLoopCrashRecovery tests that a crashed loop leaves the debate to be resumed until it crashes too often
+func TestDebateLoopCrashRecovery(t *testing.T) {
+	mockDB := new(MockDatabaseForDebate)
+	mockDB.On("UpdateDebateEnd", "crashing", types.DebateStatusFinished, "", database.DebateEndReasonError).Return(nil)
+	debateManager := &DebateManager{
+		db:      mockDB,
+		debates: make(map[string]*conversation.DebateSession),
+		server:  &Server{db: mockDB},
+	}
+
+	// Without agents the loop panics as soon as it starts
+	session := &conversation.DebateSession{DebateID: "crashing", Status: "active"}
+	debateManager.debates["crashing"] = session
+	loopStopped := func() bool { return !session.IsLoopRunning() }
+
+	require.True(t, debateManager.StartDebateLoop(session))
+	require.Eventually(t, loopStopped, time.Second, 5*time.Millisecond)
+	assert.Equal(t, types.DebateStatusActive, session.GetStatus(), "a crashed debate should stay active to be resumed")
+
+	for crashes := 2; crashes <= maxDebateLoopCrashes; crashes++ {
+		require.Equal(t, []string{"crashing"}, debateManager.ReconcileDebateLoops())
+		require.Eventually(t, loopStopped, time.Second, 5*time.Millisecond)
+	}
+
+	assert.Equal(t, types.DebateStatusFinished, session.GetStatus())
+	mockDB.AssertExpectations(t)
+	assert.Empty(t, debateManager.ReconcileDebateLoops())
+}
+
+// TestLoadedActiveDebatesResume tests that debates loaded as active get their loop started, and only once
+func TestLoadedActiveDebatesResume(t *testing.T) {
+	mockDB := new(MockDatabaseForDebate)
+	mockDB.On("ListActiveDebates").Return([]*database.Debate{
+		{ID: "was-active", Topic: "Cats vs dogs", Status: "active", Agent1Name: "Agent1", Agent2Name: "Agent2"},
+		{ID: "was-waiting", Topic: "Tea vs coffee", Status: "waiting", Agent1Name: "Agent1", Agent2Name: "Agent2"},
+	}, nil)
+	debateManager := &DebateManager{
+		db:      mockDB,
+		agents:  NewAgentRegistry(map[string]*agent.Agent{"Agent1": {}, "Agent2": {}}),
+		apiKey:  "test-api-key",
+		debates: make(map[string]*conversation.DebateSession),
+		server:  &Server{db: mockDB},
+	}
+	require.NoError(t, debateManager.LoadActiveDebates())
+
+	assert.Equal(t, []string{"was-active"}, debateManager.ReconcileDebateLoops())
+	active, _ := debateManager.GetDebate("was-active")
+	waiting, _ := debateManager.GetDebate("was-waiting")
+	assert.True(t, active.IsLoopRunning())
+	assert.False(t, waiting.IsLoopRunning())
+
+	// A client connecting afterwards doesn't start a second loop
+	assert.False(t, debateManager.StartDebateLoop(active))
+
+	// Let the resumed loop exit once its start-up delay is over
+	active.UpdateStatus("finished")
+}
+
+// TestLoadedDebatesKeepTheirConfig tests that debates restored after a restart keep the config they were
+// created with and the time they had left, while debates stored without one get the defaults
+func TestLoadedDebatesKeepTheirConfig(t *testing.T) {
+	config := conversation.DefaultConfig()
+	config.Topic = "Stale topic"
+	config.ResponseStyle = types.ResponseStyleCasual
+	config.Language = "fr"
+	config.TurnOrder = conversation.TurnOrderChallenged
+	config.FirstSpeaker = conversation.FirstSpeakerAgent2
+	config.MaxArgumentLength = 300
+	config.MaxDuration = 10 * time.Minute
+	config.Seed = 42
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+	stored := string(data)
+	deadline := time.Now().Add(3 * time.Minute)
+
+	mockDB := new(MockDatabaseForDebate)
+	mockDB.On("ListActiveDebates").Return([]*database.Debate{
+		{ID: "configured", Topic: "Cats vs dogs", Status: "active", Agent1Name: "Agent1", Agent2Name: "Agent2", Config: &stored, Deadline: &deadline},
+		{ID: "legacy", Topic: "Tea vs coffee", Status: "waiting", Agent1Name: "Agent1", Agent2Name: "Agent2"},
+	}, nil)
+	debateManager := &DebateManager{
+		db:      mockDB,
+		agents:  NewAgentRegistry(map[string]*agent.Agent{"Agent1": {}, "Agent2": {}}),
+		apiKey:  "test-api-key",
+		debates: make(map[string]*conversation.DebateSession),
+		server:  &Server{db: mockDB},
+	}
+	require.NoError(t, debateManager.LoadActiveDebates())
+
+	configured, _ := debateManager.GetDebate("configured")
+	assert.Equal(t, "Cats vs dogs", configured.Config.Topic)
+	assert.Equal(t, types.ResponseStyleCasual, configured.Config.ResponseStyle)
+	assert.Equal(t, types.Language("fr"), configured.Config.Language)
+	assert.Equal(t, conversation.TurnOrderChallenged, configured.Config.TurnOrder)
+	assert.Equal(t, conversation.FirstSpeakerAgent2, configured.FirstSpeaker())
+	assert.Equal(t, 300, configured.Config.MaxArgumentLength)
+	assert.Equal(t, int64(42), configured.Config.Seed)
+	assert.True(t, deadline.Equal(configured.GetDeadline()), "a running debate keeps the time it had left")
+
+	legacy, _ := debateManager.GetDebate("legacy")
+	defaults := conversation.DefaultConfig()
+	assert.Equal(t, "Tea vs coffee", legacy.Config.Topic)
+	assert.Equal(t, defaults.ResponseStyle, legacy.Config.ResponseStyle)
+	assert.Equal(t, defaults.MaxArgumentLength, legacy.Config.MaxArgumentLength)
+	assert.True(t, legacy.GetDeadline().IsZero(), "a debate that hasn't started has no deadline yet")
+}
+
+// transcriptDB is a MockDatabaseForDebate with stored transcripts
+type transcriptDB struct {
+	*MockDatabaseForDebate
+	transcripts map[string][]*database.DebateMessage
+}
+
+func (db *transcriptDB) GetDebateMessages(debateID string) ([]*database.DebateMessage, error) {
+	return db.transcripts[debateID], nil
+}
+
+// TestLoadedDebatesResumeWhereTheyLeftOff tests that a debate restarted halfway keeps its HP, turns and next speaker
+func TestLoadedDebatesResumeWhereTheyLeftOff(t *testing.T) {
+	score := 70.0
+	mockDB := &transcriptDB{
+		MockDatabaseForDebate: new(MockDatabaseForDebate),
+		transcripts: map[string][]*database.DebateMessage{
+			"half-played": {
+				{ID: 1, Speaker: "Agent1", Message: "Opening", Score: &score, Agent2Delta: -6},
+				{ID: 2, Speaker: "player-1", Message: "Agent 2 is right", IsPlayer: true, Score: &score, Agent1Delta: -3, Agent2Delta: 3},
+				{ID: 3, Speaker: "Agent2", Message: "Rebuttal", Score: &score, Agent1Delta: -4},
+			},
+		},
+	}
+	mockDB.On("ListActiveDebates").Return([]*database.Debate{
+		{ID: "half-played", Topic: "Cats vs dogs", Status: "active", Agent1Name: "Agent1", Agent2Name: "Agent2"},
+	}, nil)
+	agent1 := agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent1"})
+	agent2 := agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent2"})
+	debateManager := &DebateManager{
+		db:      mockDB,
+		agents:  NewAgentRegistry(map[string]*agent.Agent{"Agent1": agent1, "Agent2": agent2}),
+		debates: make(map[string]*conversation.DebateSession),
+	}
+	require.NoError(t, debateManager.LoadActiveDebates())
+
+	session, exists := debateManager.GetDebate("half-played")
+	require.True(t, exists)
+	assert.Equal(t, conversation.GameScore{Agent1Score: 93, Agent2Score: 97}, session.GetGameScore())
+	assert.Equal(t, 2, session.GetTurnCount())
+	assert.Same(t, agent1, conversation.NextSpeaker(session))
+	history := session.GetRecentHistory(10)
+	require.Len(t, history, 3)
+	assert.Equal(t, int64(3), history[2].MessageID)
+}
+
+// TestAgentTurnPromptResponseStyle tests that an agent's turn prompt sets the tone of the debate's response style
+func TestAgentTurnPromptResponseStyle(t *testing.T) {
+	session := &conversation.DebateSession{
+		Agent1: agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 1"}),
+		Agent2: agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 2"}),
+		Config: conversation.DebateConfig{Topic: "Cats vs dogs", ResponseStyle: types.ResponseStyleTechnical},
+	}
+
+	technical := agentTurnPrompt(session, "Agent 1", nil)
+	assert.Contains(t, technical, "Use technical language and precise terminology.")
+
+	session.Config.ResponseStyle = types.ResponseStyleHumorous
+	humorous := agentTurnPrompt(session, "Agent 1", nil)
+	assert.Contains(t, humorous, "Keep the tone light and humorous.")
+	assert.NotEqual(t, technical, humorous)
+}
+
+// TestAgentTurnPromptHPTone tests that an agent's turn prompt takes the tone its HP gap to its opponent calls for
+func TestAgentTurnPromptHPTone(t *testing.T) {
+	session := &conversation.DebateSession{
+		Agent1:    agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 1"}),
+		Agent2:    agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 2"}),
+		Config:    conversation.DebateConfig{Topic: "Cats vs dogs"},
+		GameScore: conversation.GameScore{Agent1Score: 40, Agent2Score: 100},
+	}
+
+	// No tones by default
+	neutral := agentTurnPrompt(session, "Agent 1", nil)
+	assert.NotContains(t, neutral, "TONE FOR THIS TURN")
+
+	session.Config.HPTone = conversation.HPTone{
+		Behind: []conversation.ToneThreshold{{Gap: 50, Tone: "You're losing badly: take risks."}, {Gap: 20, Tone: "Press harder."}},
+		Ahead:  []conversation.ToneThreshold{{Gap: 20, Tone: "Stay calm and confident."}},
+	}
+	behind := agentTurnPrompt(session, "Agent 1", nil)
+	assert.Contains(t, behind, "You're losing badly: take risks.")
+	assert.NotContains(t, behind, "Press harder.")
+	assert.Equal(t, neutral, behind[:len(neutral)], "the tone is only added to the prompt")
+	assert.Contains(t, agentTurnPrompt(session, "Agent 2", nil), "Stay calm and confident.")
+
+	// Below the smallest gap, and at a tie, the prompt is unchanged
+	session.GameScore = conversation.GameScore{Agent1Score: 90, Agent2Score: 100}
+	assert.Equal(t, neutral, agentTurnPrompt(session, "Agent 1", nil))
+	session.GameScore = conversation.GameScore{Agent1Score: 100, Agent2Score: 100}
+	assert.Equal(t, neutral, agentTurnPrompt(session, "Agent 1", nil))
+}
+
+// TestHistorySummary tests that history older than the context window is summarized every SummaryInterval turns
+// and the summary is carried in the agents' prompts
+func TestHistorySummary(t *testing.T) {
+	session := &conversation.DebateSession{
+		Agent1: agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 1"}),
+		Agent2: agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 2"}),
+		Config: conversation.DebateConfig{Topic: "Cats vs dogs", ContextWindow: 2, SummaryInterval: 2},
+	}
+	for i, speaker := range []string{"Agent 1", "Agent 2", "Agent 1", "Agent 2"} {
+		session.AddHistoryEntry(speaker, fmt.Sprintf("Argument %d", i+1), false)
+	}
+
+	// Only every second turn refreshes the summary
+	refreshHistorySummary(context.Background(), session, session.Agent1, 1)
+	assert.Empty(t, session.HistorySummary())
+
+	refreshHistorySummary(context.Background(), session, session.Agent1, 2)
+	summary := session.HistorySummary()
+	assert.Equal(t, "Earlier in the debate on Cats vs dogs, Agent 1, Agent 2 made their cases.", summary)
+	_, pending, upTo := session.HistoryToSummarize(2)
+	assert.Empty(t, pending)
+	assert.Equal(t, 2, upTo)
+
+	prompt := agentTurnPrompt(session, "Agent 1", session.GetRecentHistory(session.Config.HistoryWindow()))
+	assert.Contains(t, prompt, "Summary of the earlier debate: "+summary)
+	assert.Contains(t, prompt, "Agent 2: Argument 4")
+	assert.NotContains(t, prompt, "Argument 2")
+
+	// Nothing new has left the window, so the next refresh leaves the summary alone
+	refreshHistorySummary(context.Background(), session, session.Agent2, 4)
+	assert.Equal(t, summary, session.HistorySummary())
+
+	// Entries that left the window since the last refresh stay in the prompt until they're summarized
+	session.AddHistoryEntry("Agent 1", "Argument 5", false)
+	recent := session.ContextHistory(session.Config.HistoryWindow())
+	require.Len(t, recent, 3)
+	assert.Equal(t, "Argument 3", recent[0].Message)
+	prompt = agentTurnPrompt(session, "Agent 1", recent)
+	assert.Contains(t, prompt, "Agent 1: Argument 3")
+	assert.NotContains(t, prompt, "Argument 2")
+
+	// Without a running summary the window alone is the context
+	session.Config.SummaryInterval = 0
+	assert.Len(t, session.ContextHistory(session.Config.HistoryWindow()), 2)
+}
+
+// TestHistoryTrimming tests that a long debate keeps only the newest history entries in memory, while score
+// history, momentum and the running summary still count from the start of the debate
+func TestHistoryTrimming(t *testing.T) {
+	assert.Equal(t, conversation.DefaultMaxHistoryInMemory, conversation.DebateConfig{}.HistoryLimit())
+	assert.Equal(t, conversation.MinHistoryInMemory, conversation.DebateConfig{MaxHistoryInMemory: 10}.HistoryLimit())
+
+	session := &conversation.DebateSession{
+		Agent1: agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 1"}),
+		Agent2: agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 2"}),
+		Config: conversation.DebateConfig{Topic: "Cats vs dogs", ContextWindow: 2, MaxHistoryInMemory: 100},
+	}
+	speakers := []string{"Agent 1", "Agent 2"}
+	for i := 0; i < 5000; i++ {
+		session.AddHistoryEntry(speakers[i%2], fmt.Sprintf("Argument %d", i+1), false)
+		session.UpdateLastHistoryEntryScore(float64(i % 10))
+	}
+
+	history := session.GetRecentHistory(5000)
+	require.Len(t, history, 100)
+	assert.Equal(t, "Argument 4901", history[0].Message)
+	assert.Equal(t, "Argument 5000", history[99].Message)
+	assert.Less(t, cap(session.History), 200, "trimming shouldn't leave a growing backing array")
+
+	scores := session.GetAgentScoreHistory("Agent 1")
+	require.Len(t, scores, 50)
+	assert.Equal(t, 2451, scores[0].Turn)
+	assert.Equal(t, 2500, scores[49].Turn)
+	// Agent 1's last two arguments, 4997 and 4999, scored 6 and 8
+	assert.Equal(t, 7.0, session.GetRecentAgentScore("Agent 1", 2))
+
+	// Everything but the context window is still to be summarized, counted from the start of the debate
+	_, pending, upTo := session.HistoryToSummarize(2)
+	assert.Len(t, pending, 98)
+	assert.Equal(t, 4998, upTo)
+	session.SetHistorySummary("Earlier arguments", upTo)
+	for i := 0; i < 10; i++ {
+		session.AddHistoryEntry(speakers[i%2], "More", false)
+	}
+	_, pending, upTo = session.HistoryToSummarize(2)
+	assert.Len(t, pending, 10)
+	assert.Equal(t, 5008, upTo)
+}
+
+// TestHPTuningThresholdAlias tests that hp_tuning still accepts threshold, the old name of min_score_for_damage
+func TestHPTuningThresholdAlias(t *testing.T) {
+	var tuning conversation.HPTuning
+	require.NoError(t, json.Unmarshal([]byte(`{"threshold":40,"agent_multiplier":2}`), &tuning))
+	assert.Equal(t, 40.0, tuning.MinScoreForDamage)
+	assert.Equal(t, 2.0, tuning.AgentMultiplier)
+
+	tuning = conversation.DefaultHPTuning()
+	require.NoError(t, json.Unmarshal([]byte(`{"threshold":40,"min_score_for_damage":55}`), &tuning))
+	assert.Equal(t, 55.0, tuning.MinScoreForDamage, "the new name wins")
+	assert.Equal(t, 1.0, tuning.PlayerMultiplier, "fields left out keep their values")
+
+	// Encoding uses the new name only
+	data, err := json.Marshal(tuning)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"min_score_for_damage":55`)
+	assert.NotContains(t, string(data), "threshold")
+}
+
+// TestCrowdSupport tests that supporter tallies scale player arguments' HP swings only when the debate opts in,
+// and are reported in the game score
+func TestCrowdSupport(t *testing.T) {
+	session := &conversation.DebateSession{
+		Agent1: agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 1"}),
+		Agent2: agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 2"}),
+		Config: conversation.DebateConfig{HPTuning: conversation.HPTuning{MaxLoss: 12}},
+	}
+	alice, bob, carol := &websocket.Conn{}, &websocket.Conn{}, &websocket.Conn{}
+	session.SetClientSide(alice, "Agent 1")
+	session.SetClientSide(bob, "Agent 1")
+	session.SetClientSide(carol, "Agent 2")
+	assert.Equal(t, conversation.CrowdSupport{Agent1: 2, Agent2: 1}, session.GetCrowdSupport())
+
+	// Off by default
+	assert.Equal(t, 8, session.CrowdAdjustedPoints(8, "Agent 1"))
+
+	session.Config.CrowdFactor = 0.75
+	assert.Equal(t, 10, session.CrowdAdjustedPoints(8, "Agent 1"), "a third more supporters adds a quarter")
+	assert.Equal(t, 6, session.CrowdAdjustedPoints(8, "Agent 2"))
+
+	// Switching to neutral stops counting a player, and the swing stays within MaxLoss
+	session.SetClientSide(carol, "")
+	assert.Equal(t, 12, session.CrowdAdjustedPoints(8, "Agent 1"))
+
+	message := (&DebateManager{}).gameScoreMessage(session, session.GetGameScore())
+	assert.Equal(t, gin.H{"Agent 1": 2, "Agent 2": 0}, message["supporters"])
+}
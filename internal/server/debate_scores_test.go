@@ -0,0 +1,110 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAgentScoreHistoryHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.debateManager = &DebateManager{debates: make(map[string]*conversation.DebateSession)}
+	server.router.GET("/api/debates/:debateID/scores/:agent", server.auth.OptionalAuthMiddleware(), server.getAgentScoreHistoryHandler)
+
+	scores := func(path, token string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	// Score history is only kept for debates running on this server
+	code, _ := scores("/api/debates/not-running/scores/Agent%201", "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	session, err := conversation.NewDebateSession("live", agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 1"}),
+		agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 2"}), conversation.DefaultConfig(), "")
+	require.NoError(t, err)
+	session.AddHistoryEntry("Agent 1", "First argument", false)
+	session.UpdateLastHistoryEntryScore(6)
+	session.AddHistoryEntry("Agent 2", "Rebuttal", false)
+	session.UpdateLastHistoryEntryScore(4)
+	session.AddHistoryEntry("Agent 1", "Second argument", false)
+	session.UpdateLastHistoryEntryScore(8)
+	server.debateManager.debates["live"] = session
+
+	code, _ = scores("/api/debates/live/scores/Nobody", "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, response := scores("/api/debates/live/scores/Agent%201", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "live", response["debate_id"])
+	assert.Equal(t, "Agent 1", response["agent"])
+	points := response["scores"].([]interface{})
+	require.Len(t, points, 2, "only Agent 1's arguments are included")
+	assert.Equal(t, 1.0, points[0].(map[string]interface{})["turn"])
+	assert.Equal(t, 6.0, points[0].(map[string]interface{})["score"])
+	assert.Equal(t, 2.0, points[1].(map[string]interface{})["turn"])
+	assert.Equal(t, 8.0, points[1].(map[string]interface{})["score"])
+
+	// Private debates' scores are only shown to the people who may watch them
+	server.db = &privateDebateDB{TestMockDB: &TestMockDB{}}
+	code, _ = scores("/api/debates/live/scores/Agent%201", "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	strangerToken, err := server.auth.GenerateToken(auth.User{ID: "stranger-id", Username: "stranger", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+	code, _ = scores("/api/debates/live/scores/Agent%201", strangerToken)
+	assert.Equal(t, http.StatusForbidden, code)
+	invitedToken, err := server.auth.GenerateToken(auth.User{ID: "test-user-id", Username: "testuser", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+	code, _ = scores("/api/debates/live/scores/Agent%201", invitedToken)
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestGetScoreBreakdownHandler(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/debates/:debateID/score-breakdown", server.auth.OptionalAuthMiddleware(), server.getScoreBreakdownHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/debates/missing-debate/score-breakdown", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/debates/finished-debate/score-breakdown", nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Agents  map[string]database.CriteriaBreakdown `json:"agents"`
+		Neutral *database.CriteriaBreakdown           `json:"neutral"`
+		Players []database.CriteriaBreakdown          `json:"players"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Contains(t, response.Agents, "Agent 1", "sides are reported by agent name")
+	assert.NotContains(t, response.Agents, "Agent 2", "no arguments were made for Agent 2")
+	assert.Equal(t, 2, response.Agents["Agent 1"].Count)
+	assert.Greater(t, response.Agents["Agent 1"].Humor, response.Agents["Agent 1"].Logic)
+	require.NotNil(t, response.Neutral)
+	assert.True(t, response.Neutral.SingleSample)
+	require.Len(t, response.Players, 2)
+	assert.Equal(t, "alice", response.Players[0].Name)
+	assert.False(t, response.Players[0].SingleSample)
+	assert.True(t, response.Players[1].SingleSample)
+}
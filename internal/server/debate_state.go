@@ -0,0 +1,27 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getDebateStateHandler returns the full state snapshot of a running debate, the same one WebSocket clients get
+// in reply to get_state, so page reloads and clients without a WebSocket can catch up in one call. Debates that
+// aren't running have no live state; their record is at GET /api/debates/:debateID.
+func (s *Server) getDebateStateHandler(c *gin.Context) {
+	debateID := c.Param("debateID")
+
+	if status, err := s.checkDebateAccess(c, debateID); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	state, err := s.debateManager.GetDebateInfo(debateID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Debate is not running", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, state)
+}
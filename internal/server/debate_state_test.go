@@ -0,0 +1,65 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDebateState(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/debates/:debateID/state", server.auth.OptionalAuthMiddleware(), server.getDebateStateHandler)
+
+	live, err := conversation.NewDebateSession("live", agent.NewOfflineAgent(agent.AgentConfig{Name: "Pepito"}),
+		agent.NewOfflineAgent(agent.AgentConfig{Name: "Tony"}), conversation.DebateConfig{
+			Topic:         "Messi vs Ronaldo",
+			MaxTurns:      12,
+			MaxDuration:   30 * time.Minute,
+			MaxInactivity: 2 * time.Minute,
+		}, "")
+	require.NoError(t, err)
+	live.UpdateStatus("active")
+	live.UpdateGameScore(-20, 20)
+	live.IncrementTurnCount()
+	live.AddHistoryEntry("Pepito", "Messi has more Ballon d'Ors", false)
+	server.debateManager = &DebateManager{
+		db:      server.db,
+		server:  server,
+		debates: map[string]*conversation.DebateSession{"live": live},
+	}
+
+	state := func(debateID string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/debates/"+debateID+"/state", nil))
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	code, response := state("live")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "Messi vs Ronaldo", response["topic"])
+	assert.Equal(t, "active", response["status"])
+	assert.Equal(t, true, response["is_active"])
+	assert.Equal(t, "Pepito", response["agent1"])
+	assert.Equal(t, map[string]interface{}{"Pepito": float64(80), "Tony": float64(120)}, response["internal_score"])
+	assert.Len(t, response["history"], 1)
+	assert.Equal(t, conversation.WinConditionHP, response["win_condition"])
+	assert.Equal(t, map[string]interface{}{"current": float64(1), "max": float64(12)}, response["turns"])
+	timeouts := response["timeouts"].(map[string]interface{})
+	assert.Equal(t, float64(1800), timeouts["max_duration_seconds"])
+	assert.Equal(t, float64(120), timeouts["max_inactivity_seconds"])
+
+	code, _ = state("stopped")
+	assert.Equal(t, http.StatusNotFound, code, "only running debates have live state")
+	code, _ = state("missing-debate")
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -0,0 +1,390 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/types"
+)
+
+// DebateConfigRequest is the debate configuration accepted by createDebateHandler and validateDebateConfigHandler
+type DebateConfigRequest struct {
+	Topic        string                 `json:"topic"`
+	Agent1       string                 `json:"agent1"`
+	Agent2       string                 `json:"agent2"`
+	CreatedBy    string                 `json:"created_by"`
+	TopicID      int                    `json:"topic_id"`      // Optional: Use a pre-generated topic
+	Visibility   string                 `json:"visibility"`    // Optional: public (default), unlisted or private
+	WinCondition string                 `json:"win_condition"` // Optional: hp (default) or judge
+	HPTuning     *conversation.HPTuning `json:"hp_tuning"`     // Optional: override how scores translate into HP
+	HPTone       *conversation.HPTone   `json:"hp_tone"`       // Optional: tones agents take as their HP gap grows
+	Language     string                 `json:"language"`      // Optional: ISO 639-1 code, defaults to en
+	FirstSpeaker string                 `json:"first_speaker"` // Optional: agent1 (default), agent2 or random
+	TurnOrder    string                 `json:"turn_order"`    // Optional: alternate (default), challenged, random or comeback
+	Seed         int64                  `json:"seed"`          // Optional: seed of the debate's random choices, to reproduce a debate
+
+	ResponseStyle string `json:"response_style"` // Optional: formal, casual, technical, debate (default) or humorous
+
+	ContextWindow   int `json:"context_window"`   // Optional: history entries the agents see each turn, defaults to 5
+	SummaryInterval int `json:"summary_interval"` // Optional: agent turns between summaries of older history, 0 (default) to not summarize
+
+	CrowdFactor float64 `json:"crowd_factor"` // Optional: 0 (default) to 1, how much crowd support scales player arguments' HP swings
+
+	MinArgumentLength int `json:"min_argument_length"` // Optional: fewest characters in a player argument, defaults to 10
+	MaxArgumentLength int `json:"max_argument_length"` // Optional: most characters in a player argument, defaults to 1000
+
+	MaxDurationSeconds   int     `json:"max_duration_seconds"`   // Optional: overall debate timeout
+	MaxInactivitySeconds int     `json:"max_inactivity_seconds"` // Optional: how long the debate may stall before ending
+	MaxCostUSD           float64 `json:"max_cost_usd"`           // Optional, admin only: estimated spend at which the debate ends, 0 for unlimited
+
+	ScheduledAt *time.Time `json:"scheduled_at"` // Optional: RFC 3339 start time; the debate can't be joined before then
+
+	AgentPromptOverrides map[string]string `json:"agent_prompt_overrides"` // Optional, admin only: extra persona instructions per agent name
+
+	IntroMessage      string            `json:"intro_message"`       // Optional: welcome broadcast when the debate starts
+	IntroDelaySeconds *int              `json:"intro_delay_seconds"` // Optional: pause after the welcome before anyone speaks, defaults to 2
+	OpeningStatements map[string]string `json:"opening_statements"`  // Optional: scripted opening statement per agent name
+}
+
+// DebateConfigError is a problem with one field of a DebateConfigRequest
+type DebateConfigError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	status  int    // Status createDebateHandler responds with when this is the first error
+}
+
+// ValidatedDebateConfig is a DebateConfigRequest that passed ValidateDebateConfig, resolved into what
+// CreateDebateWithConfig takes
+type ValidatedDebateConfig struct {
+	Config    conversation.DebateConfig
+	Agent1    *agent.Agent
+	Agent2    *agent.Agent
+	CreatedBy string
+	Settings  database.DebateSettings
+
+	agent1Name, agent2Name string // The agents' names in Server.agents
+}
+
+// ValidateDebateConfig checks a debate configuration for the caller of c without creating anything: the agents
+// exist and differ, the options are known values, HP tuning and timeouts are in bounds, and admin-only options
+// are only set by admins. It returns every problem found, in the order createDebateHandler reports them.
+func (s *Server) ValidateDebateConfig(c *gin.Context, req DebateConfigRequest) (*ValidatedDebateConfig, []DebateConfigError) {
+	var errs []DebateConfigError
+	fail := func(status int, field, format string, args ...interface{}) {
+		errs = append(errs, DebateConfigError{Field: field, Message: fmt.Sprintf(format, args...), status: status})
+	}
+
+	// Validate visibility
+	if req.Visibility == "" {
+		req.Visibility = database.DebateVisibilityPublic
+	}
+	if !database.IsValidDebateVisibility(req.Visibility) {
+		fail(http.StatusBadRequest, "visibility", "Invalid visibility. Must be 'public', 'unlisted' or 'private'")
+	}
+
+	// Validate win condition
+	if req.WinCondition == "" {
+		req.WinCondition = conversation.WinConditionHP
+	}
+	if !conversation.IsValidWinCondition(req.WinCondition) {
+		fail(http.StatusBadRequest, "win_condition", "Invalid win_condition. Must be 'hp' or 'judge'")
+	}
+
+	// Validate the first speaker
+	if req.FirstSpeaker != "" && !conversation.IsValidFirstSpeaker(req.FirstSpeaker) {
+		fail(http.StatusBadRequest, "first_speaker", "Invalid first_speaker. Must be 'agent1', 'agent2' or 'random'")
+	}
+
+	// Validate the turn order
+	if req.TurnOrder == "" {
+		req.TurnOrder = conversation.TurnOrderAlternate
+	}
+	if !conversation.IsValidTurnOrder(req.TurnOrder) {
+		fail(http.StatusBadRequest, "turn_order", "Invalid turn_order. Must be 'alternate', 'challenged', 'random' or 'comeback'")
+	}
+
+	// Validate the seed; without one the debate picks its own
+	if req.Seed < 0 || req.Seed > conversation.MaxSeed {
+		fail(http.StatusBadRequest, "seed", "seed must be between 1 and %d, or 0 for a random seed", int64(conversation.MaxSeed))
+	}
+
+	// Validate language
+	language := types.LanguageEnglish
+	if req.Language != "" {
+		language = types.Language(req.Language)
+		if !language.IsValid() {
+			fail(http.StatusBadRequest, "language", "Unsupported language '%s'", req.Language)
+		}
+	}
+
+	// Validate the response style
+	responseStyle := types.ResponseStyleDebate
+	if req.ResponseStyle != "" {
+		responseStyle = types.ResponseStyle(req.ResponseStyle)
+		if !responseStyle.IsValid() {
+			fail(http.StatusBadRequest, "response_style", "Invalid response_style '%s'. Must be 'formal', 'casual', 'technical', 'debate' or 'humorous'", req.ResponseStyle)
+		}
+	}
+
+	// Validate HP tuning overrides
+	if req.HPTuning != nil {
+		if err := req.HPTuning.Validate(); err != nil {
+			fail(http.StatusBadRequest, "hp_tuning", "Invalid hp_tuning: %v", err)
+		}
+	}
+	if req.HPTone != nil {
+		if err := req.HPTone.Validate(); err != nil {
+			fail(http.StatusBadRequest, "hp_tone", "Invalid hp_tone: %v", err)
+		}
+	}
+
+	// Validate timeouts
+	if req.MaxDurationSeconds != 0 && (req.MaxDurationSeconds < minDebateDurationSeconds || req.MaxDurationSeconds > maxDebateDurationSeconds) {
+		fail(http.StatusBadRequest, "max_duration_seconds", "max_duration_seconds must be between %d and %d", minDebateDurationSeconds, maxDebateDurationSeconds)
+	}
+	if req.MaxInactivitySeconds != 0 && (req.MaxInactivitySeconds < minDebateInactivitySeconds || req.MaxInactivitySeconds > maxDebateInactivitySeconds) {
+		fail(http.StatusBadRequest, "max_inactivity_seconds", "max_inactivity_seconds must be between %d and %d", minDebateInactivitySeconds, maxDebateInactivitySeconds)
+	}
+
+	// Validate the agents' context
+	if req.ContextWindow < 0 || req.ContextWindow > maxContextWindow {
+		fail(http.StatusBadRequest, "context_window", "context_window must be between 1 and %d, or 0 for the default", maxContextWindow)
+	}
+	if req.SummaryInterval < 0 || req.SummaryInterval > maxSummaryInterval {
+		fail(http.StatusBadRequest, "summary_interval", "summary_interval must be between 0 and %d", maxSummaryInterval)
+	}
+
+	// Validate crowd support
+	if req.CrowdFactor < 0 || req.CrowdFactor > maxCrowdFactor {
+		fail(http.StatusBadRequest, "crowd_factor", "crowd_factor must be between 0 and %g", maxCrowdFactor)
+	}
+
+	// Validate the argument length limits
+	argumentLimits := conversation.DebateConfig{MinArgumentLength: req.MinArgumentLength, MaxArgumentLength: req.MaxArgumentLength}
+	minArgumentLength, maxArgumentLength := argumentLimits.ArgumentLengthLimits()
+	if req.MinArgumentLength < 0 || req.MinArgumentLength > maxArgumentLengthLimit {
+		fail(http.StatusBadRequest, "min_argument_length", "min_argument_length must be between 1 and %d, or 0 for the default", maxArgumentLengthLimit)
+	} else if req.MaxArgumentLength < 0 || req.MaxArgumentLength > maxArgumentLengthLimit {
+		fail(http.StatusBadRequest, "max_argument_length", "max_argument_length must be between 1 and %d, or 0 for the default", maxArgumentLengthLimit)
+	} else if minArgumentLength > maxArgumentLength {
+		fail(http.StatusBadRequest, "max_argument_length", "max_argument_length (%d) cannot be less than min_argument_length (%d)", maxArgumentLength, minArgumentLength)
+	}
+
+	// Validate the intro
+	if len(strings.TrimSpace(req.IntroMessage)) > maxIntroMessageLength {
+		fail(http.StatusBadRequest, "intro_message", "intro_message exceeds %d characters", maxIntroMessageLength)
+	}
+	if req.IntroDelaySeconds != nil && (*req.IntroDelaySeconds < 0 || *req.IntroDelaySeconds > maxIntroDelaySeconds) {
+		fail(http.StatusBadRequest, "intro_delay_seconds", "intro_delay_seconds must be between 0 and %d", maxIntroDelaySeconds)
+	}
+
+	// Validate the schedule
+	if req.ScheduledAt != nil && !req.ScheduledAt.After(time.Now()) {
+		fail(http.StatusBadRequest, "scheduled_at", "scheduled_at must be in the future")
+	}
+
+	// Private debates need an authenticated creator to manage the invite list
+	userID, authenticated := auth.GetUserID(c)
+	if req.Visibility == database.DebateVisibilityPrivate && !authenticated {
+		fail(http.StatusUnauthorized, "visibility", "Authentication required to create a private debate")
+	}
+
+	// If a topic ID is provided, use the pre-generated topic
+	if req.TopicID > 0 {
+		topic, err := s.db.GetTopic(req.TopicID)
+		if err != nil {
+			fail(http.StatusBadRequest, "topic_id", "Topic with ID %d not found: %v", req.TopicID, err)
+		} else {
+			// Override the request with the pre-generated topic details
+			req.Topic = topic.Title
+			req.Agent1 = topic.Agent1Name
+			req.Agent2 = topic.Agent2Name
+		}
+	}
+
+	// Validate agents exist
+	agent1, agent1Exists := s.agents.Get(req.Agent1)
+	if !agent1Exists {
+		fail(http.StatusBadRequest, "agent1", "Agent '%s' not found", req.Agent1)
+	}
+	agent2, agent2Exists := s.agents.Get(req.Agent2)
+	if !agent2Exists {
+		fail(http.StatusBadRequest, "agent2", "Agent '%s' not found", req.Agent2)
+	}
+	if agent1Exists && req.Agent1 == req.Agent2 {
+		fail(http.StatusBadRequest, "agent2", "Cannot create debate with the same agent on both sides")
+	}
+
+	// Costs are only visible to admins, so only they may set a budget
+	role, _ := auth.GetUserRole(c)
+	isAdmin := role == string(database.RoleAdmin)
+	if req.MaxCostUSD < 0 {
+		fail(http.StatusBadRequest, "max_cost_usd", "max_cost_usd cannot be negative")
+	} else if req.MaxCostUSD > 0 && !isAdmin {
+		fail(http.StatusForbidden, "max_cost_usd", "Only admins can set max_cost_usd")
+	}
+
+	// Prompt overrides change how agents behave, so only admins may set them
+	if len(req.AgentPromptOverrides) > 0 {
+		if !isAdmin {
+			fail(http.StatusForbidden, "agent_prompt_overrides", "Only admins can set agent_prompt_overrides")
+		} else {
+			for name, override := range req.AgentPromptOverrides {
+				if name != req.Agent1 && name != req.Agent2 {
+					fail(http.StatusBadRequest, "agent_prompt_overrides", "agent_prompt_overrides: agent '%s' is not in this debate", name)
+				} else if len(override) > maxPromptOverrideLength {
+					fail(http.StatusBadRequest, "agent_prompt_overrides", "agent_prompt_overrides: override for '%s' exceeds %d characters", name, maxPromptOverrideLength)
+				}
+			}
+		}
+	}
+
+	// Opening statements are checked once the agents are known, since a topic ID can change them
+	for name, statement := range req.OpeningStatements {
+		if name != req.Agent1 && name != req.Agent2 {
+			fail(http.StatusBadRequest, "opening_statements", "opening_statements: agent '%s' is not in this debate", name)
+		} else if len(strings.TrimSpace(statement)) > maxOpeningStatementLength {
+			fail(http.StatusBadRequest, "opening_statements", "opening_statements: statement for '%s' exceeds %d characters", name, maxOpeningStatementLength)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	config := conversation.DefaultConfig()
+	config.Topic = req.Topic
+	config.WinCondition = req.WinCondition
+	config.Language = language
+	config.ResponseStyle = responseStyle
+	if req.ContextWindow != 0 {
+		config.ContextWindow = req.ContextWindow
+	}
+	config.SummaryInterval = req.SummaryInterval
+	config.CrowdFactor = req.CrowdFactor
+	config.MinArgumentLength, config.MaxArgumentLength = minArgumentLength, maxArgumentLength
+	if req.HPTuning != nil {
+		config.HPTuning = *req.HPTuning
+	}
+	if req.HPTone != nil {
+		config.HPTone = *req.HPTone
+	}
+	if req.MaxDurationSeconds != 0 {
+		config.MaxDuration = time.Duration(req.MaxDurationSeconds) * time.Second
+	}
+	if req.MaxInactivitySeconds != 0 {
+		config.MaxInactivity = time.Duration(req.MaxInactivitySeconds) * time.Second
+	}
+	if config.MaxInactivity > config.MaxDuration {
+		config.MaxInactivity = config.MaxDuration
+	}
+	config.AgentPromptOverrides = req.AgentPromptOverrides
+	config.MaxCostUSD = req.MaxCostUSD
+	config.FirstSpeaker = req.FirstSpeaker
+	config.TurnOrder = req.TurnOrder
+	config.Seed = req.Seed
+	config.IntroMessage = strings.TrimSpace(req.IntroMessage)
+	if req.IntroDelaySeconds != nil {
+		config.IntroDelay = time.Duration(*req.IntroDelaySeconds) * time.Second
+	}
+	for name, statement := range req.OpeningStatements {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			if config.OpeningStatements == nil {
+				config.OpeningStatements = make(map[string]string)
+			}
+			config.OpeningStatements[name] = statement
+		}
+	}
+
+	return &ValidatedDebateConfig{
+		Config:    config,
+		Agent1:    agent1,
+		Agent2:    agent2,
+		CreatedBy: req.CreatedBy,
+		Settings: database.DebateSettings{
+			Visibility:  req.Visibility,
+			CreatedBy:   userID,
+			ScheduledAt: req.ScheduledAt,
+		},
+		agent1Name: req.Agent1,
+		agent2Name: req.Agent2,
+	}, nil
+}
+
+// normalized returns the configuration the debate would be created with, defaults filled in, in request field names
+func (v *ValidatedDebateConfig) normalized() gin.H {
+	firstSpeaker := v.Config.FirstSpeaker
+	if firstSpeaker == "" {
+		firstSpeaker = conversation.FirstSpeakerAgent1
+	}
+	normalized := gin.H{
+		"topic":                  v.Config.Topic,
+		"agent1":                 v.agent1Name,
+		"agent2":                 v.agent2Name,
+		"visibility":             v.Settings.Visibility,
+		"win_condition":          v.Config.WinCondition,
+		"hp_tuning":              v.Config.HPTuning,
+		"language":               v.Config.Language,
+		"first_speaker":          firstSpeaker,
+		"turn_order":             v.Config.TurnOrder,
+		"response_style":         v.Config.ResponseStyle,
+		"context_window":         v.Config.HistoryWindow(),
+		"summary_interval":       v.Config.SummaryInterval,
+		"crowd_factor":           v.Config.CrowdFactor,
+		"min_argument_length":    v.Config.MinArgumentLength,
+		"max_argument_length":    v.Config.MaxArgumentLength,
+		"max_duration_seconds":   int(v.Config.MaxDuration / time.Second),
+		"max_inactivity_seconds": int(v.Config.MaxInactivity / time.Second),
+		"max_cost_usd":           v.Config.MaxCostUSD,
+		"scheduled_at":           v.Settings.ScheduledAt,
+		"intro_delay_seconds":    int(v.Config.IntroDelay / time.Second),
+	}
+	if len(v.Config.AgentPromptOverrides) > 0 {
+		normalized["agent_prompt_overrides"] = v.Config.AgentPromptOverrides
+	}
+	if !v.Config.HPTone.IsZero() {
+		normalized["hp_tone"] = v.Config.HPTone
+	}
+	if v.Config.IntroMessage != "" {
+		normalized["intro_message"] = v.Config.IntroMessage
+	}
+	if v.Config.Seed != 0 {
+		normalized["seed"] = v.Config.Seed
+	}
+	if len(v.Config.OpeningStatements) > 0 {
+		normalized["opening_statements"] = v.Config.OpeningStatements
+	}
+	return normalized
+}
+
+// validateDebateConfigHandler checks a debate configuration the way createDebateHandler would, without creating the
+// debate. It returns the normalized configuration, or every field-level problem at once.
+func (s *Server) validateDebateConfigHandler(c *gin.Context) {
+	var req DebateConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	validated, errs := s.ValidateDebateConfig(c, req)
+	if len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"valid":  false,
+			"error":  errs[0].Message,
+			"errors": errs,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"valid":  true,
+		"config": validated.normalized(),
+	})
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDebateConfigHandler(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.POST("/api/debates", server.auth.OptionalAuthMiddleware(), server.createDebateHandler)
+	server.router.POST("/api/debates/validate", server.auth.OptionalAuthMiddleware(), server.validateDebateConfigHandler)
+	server.agents = NewAgentRegistry(map[string]*agent.Agent{"Agent 1": {}, "Agent 2": {}})
+	server.debateManager = &DebateManager{db: server.db, agents: server.agents, debates: map[string]*conversation.DebateSession{}, server: server}
+
+	post := func(path, body, token string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+	fields := func(response map[string]interface{}) []string {
+		var fields []string
+		for _, err := range response["errors"].([]interface{}) {
+			fields = append(fields, err.(map[string]interface{})["field"].(string))
+		}
+		return fields
+	}
+
+	// A valid config comes back with its defaults filled in
+	code, response := post("/api/debates/validate", `{"topic":"Cats vs dogs","agent1":"Agent 1","agent2":"Agent 2","max_inactivity_seconds":1800,"max_duration_seconds":600}`, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, response["valid"])
+	config := response["config"].(map[string]interface{})
+	assert.Equal(t, "Cats vs dogs", config["topic"])
+	assert.Equal(t, database.DebateVisibilityPublic, config["visibility"])
+	assert.Equal(t, conversation.WinConditionHP, config["win_condition"])
+	assert.Equal(t, "en", config["language"])
+	assert.Equal(t, conversation.FirstSpeakerAgent1, config["first_speaker"])
+	assert.Equal(t, "debate", config["response_style"])
+	assert.Equal(t, float64(conversation.DefaultContextWindow), config["context_window"])
+	assert.Equal(t, float64(0), config["summary_interval"])
+	assert.Equal(t, float64(0), config["crowd_factor"])
+	assert.Equal(t, conversation.TurnOrderAlternate, config["turn_order"])
+	assert.Equal(t, float64(conversation.DefaultMinArgumentLength), config["min_argument_length"])
+	assert.Equal(t, float64(conversation.DefaultMaxArgumentLength), config["max_argument_length"])
+	assert.Equal(t, float64(600), config["max_inactivity_seconds"], "inactivity is capped at the duration")
+	assert.Contains(t, config, "hp_tuning")
+	assert.Equal(t, float64(2), config["intro_delay_seconds"])
+	assert.NotContains(t, config, "opening_statements")
+
+	// The intro can be customized, with a scripted opening per agent
+	code, response = post("/api/debates/validate", `{"agent1":"Agent 1","agent2":"Agent 2","intro_message":" Tonight's grudge match! ","intro_delay_seconds":0,"opening_statements":{"Agent 2":"I never lose.","Agent 1":" "}}`, "")
+	require.Equal(t, http.StatusOK, code)
+	config = response["config"].(map[string]interface{})
+	assert.Equal(t, "Tonight's grudge match!", config["intro_message"])
+	assert.Equal(t, float64(0), config["intro_delay_seconds"])
+	assert.Equal(t, map[string]interface{}{"Agent 2": "I never lose."}, config["opening_statements"])
+	code, response = post("/api/debates/validate", `{"agent1":"Agent 1","agent2":"Agent 2","intro_delay_seconds":600,"opening_statements":{"Agent 3":"Hi"}}`, "")
+	require.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, []string{"intro_delay_seconds", "opening_statements"}, fields(response))
+
+	// Argument length limits can be tightened per debate, as long as they leave room for an argument
+	code, response = post("/api/debates/validate", `{"agent1":"Agent 1","agent2":"Agent 2","min_argument_length":30,"max_argument_length":280}`, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(30), response["config"].(map[string]interface{})["min_argument_length"])
+	assert.Equal(t, float64(280), response["config"].(map[string]interface{})["max_argument_length"])
+	code, response = post("/api/debates/validate", `{"agent1":"Agent 1","agent2":"Agent 2","min_argument_length":2000}`, "")
+	require.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, []string{"max_argument_length"}, fields(response), "the minimum exceeds the default maximum")
+
+	// Agents can change tone as their HP gap grows
+	code, response = post("/api/debates/validate", `{"agent1":"Agent 1","agent2":"Agent 2","hp_tone":{"behind":[{"gap":30,"tone":"Get aggressive."}]}}`, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Contains(t, response["config"], "hp_tone")
+	code, response = post("/api/debates/validate", `{"agent1":"Agent 1","agent2":"Agent 2","hp_tone":{"ahead":[{"gap":0,"tone":"Relax."}]}}`, "")
+	require.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, []string{"hp_tone"}, fields(response))
+
+	// Organizers pick the turn order
+	code, response = post("/api/debates/validate", `{"agent1":"Agent 1","agent2":"Agent 2","turn_order":"comeback"}`, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "comeback", response["config"].(map[string]interface{})["turn_order"])
+	code, response = post("/api/debates/validate", `{"agent1":"Agent 1","agent2":"Agent 2","turn_order":"whoever"}`, "")
+	require.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, []string{"turn_order"}, fields(response))
+
+	// A seed reproduces a debate's random choices
+	code, response = post("/api/debates/validate", `{"agent1":"Agent 1","agent2":"Agent 2","seed":42}`, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(42), response["config"].(map[string]interface{})["seed"])
+	code, response = post("/api/debates/validate", `{"agent1":"Agent 1","agent2":"Agent 2","seed":-1}`, "")
+	require.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, []string{"seed"}, fields(response))
+
+	// Organizers pick the tone of the debate
+	code, response = post("/api/debates/validate", `{"agent1":"Agent 1","agent2":"Agent 2","response_style":"humorous"}`, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "humorous", response["config"].(map[string]interface{})["response_style"])
+
+	// Every problem is reported at once, by field
+	body := `{"agent1":"Agent 1","agent2":"Agent 1","visibility":"secret","language":"xx","response_style":"roast","hp_tuning":{"max_loss":-1},"max_duration_seconds":5,"max_cost_usd":1,"context_window":100}`
+	code, response = post("/api/debates/validate", body, "")
+	require.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, false, response["valid"])
+	assert.Equal(t, []string{"visibility", "language", "response_style", "hp_tuning", "max_duration_seconds", "context_window", "agent2", "max_cost_usd"}, fields(response))
+
+	// Admin-only options are valid for admins
+	adminToken, err := server.auth.GenerateToken(auth.User{ID: "admin-1", Username: "admin", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+	code, _ = post("/api/debates/validate", `{"agent1":"Agent 1","agent2":"Agent 2","max_cost_usd":1}`, adminToken)
+	assert.Equal(t, http.StatusOK, code)
+
+	// Creating with the same config fails the same way, with the first error's status and message, and creates nothing
+	code, response = post("/api/debates", body, "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, map[string]interface{}{"error": "Invalid visibility. Must be 'public', 'unlisted' or 'private'"}, response)
+	code, _ = post("/api/debates", `{"agent1":"Agent 1","agent2":"Agent 2","max_cost_usd":1}`, "")
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Empty(t, server.debateManager.debates)
+}
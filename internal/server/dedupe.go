@@ -0,0 +1,49 @@
+package server
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// dedupePruneSize is the number of tracked players above which stale submissions are swept on the next check
+const dedupePruneSize = 1000
+
+// lastSubmission is the most recent argument a player submitted in a debate
+type lastSubmission struct {
+	message string
+	at      time.Time
+}
+
+// submissionDeduper spots a player submitting the same argument twice in quick succession, e.g. after a
+// double-click or a flaky connection resending it. The zero value is ready to use.
+type submissionDeduper struct {
+	mu   sync.Mutex
+	last map[string]lastSubmission // Keyed by debate and player ID
+}
+
+// isDuplicate reports whether the player already submitted the same message to the debate within window.
+// Otherwise it records the message as the player's latest submission.
+func (d *submissionDeduper) isDuplicate(debateID, playerID, message string, window time.Duration, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.last == nil {
+		d.last = make(map[string]lastSubmission)
+	}
+	if len(d.last) > dedupePruneSize {
+		for key, submission := range d.last {
+			if now.Sub(submission.at) >= window {
+				delete(d.last, key)
+			}
+		}
+	}
+
+	key := debateID + "/" + playerID
+	message = strings.TrimSpace(message)
+	if previous, exists := d.last[key]; exists && previous.message == message && now.Sub(previous.at) < window {
+		return true
+	}
+	d.last[key] = lastSubmission{message: message, at: now}
+	return false
+}
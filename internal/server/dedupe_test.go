@@ -0,0 +1,44 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubmissionDeduperRapidDuplicate(t *testing.T) {
+	var d submissionDeduper
+	window := 3 * time.Second
+	now := time.Now()
+
+	assert.False(t, d.isDuplicate("debate-1", "player-1", "Cats are better", window, now))
+
+	// The same argument resent a moment later, give or take whitespace, is a duplicate
+	assert.True(t, d.isDuplicate("debate-1", "player-1", "Cats are better", window, now.Add(time.Second)))
+	assert.True(t, d.isDuplicate("debate-1", "player-1", "  Cats are better\n", window, now.Add(2*time.Second)))
+
+	// Other players and debates are tracked separately
+	assert.False(t, d.isDuplicate("debate-1", "player-2", "Cats are better", window, now.Add(time.Second)))
+	assert.False(t, d.isDuplicate("debate-2", "player-1", "Cats are better", window, now.Add(time.Second)))
+
+	// A different argument goes through and becomes the one compared against
+	assert.False(t, d.isDuplicate("debate-1", "player-1", "Dogs are loyal", window, now.Add(2*time.Second)))
+	assert.False(t, d.isDuplicate("debate-1", "player-1", "Cats are better", window, now.Add(2*time.Second)))
+
+	// Repeating an argument once the window has passed is allowed
+	assert.False(t, d.isDuplicate("debate-1", "player-1", "Cats are better", window, now.Add(2*time.Second+window)))
+}
+
+func TestSubmissionDeduperPrunesStaleEntries(t *testing.T) {
+	var d submissionDeduper
+	now := time.Now()
+	for i := 0; i <= dedupePruneSize; i++ {
+		d.isDuplicate("debate-1", fmt.Sprintf("player-%d", i), "argument", time.Second, now)
+	}
+
+	d.isDuplicate("debate-1", "late-player", "argument", time.Second, now.Add(time.Minute))
+	assert.Len(t, d.last, 1)
+	assert.Equal(t, DefaultDuplicateSubmissionWindow, (*Config)(nil).GetDuplicateSubmissionWindow())
+}
@@ -70,6 +70,11 @@ func RequestIDMiddleware() gin.HandlerFunc {
 		requestID := fmt.Sprintf("%d", time.Now().UnixNano())
 		c.Set("RequestID", requestID)
 		c.Header("X-Request-ID", requestID)
+
+		// Tag every log entry written with the request context with this ID
+		c.Request = c.Request.WithContext(logging.WithFields(c.Request.Context(), map[string]interface{}{
+			"request_id": requestID,
+		}))
 		c.Next()
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/logging"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -220,3 +221,26 @@ func TestLoggingMiddleware(t *testing.T) {
 	// Check if the response has the request ID header
 	assert.NotEmpty(t, w.Header().Get("X-Request-ID"))
 }
+
+func TestRequestIDMiddlewareTagsLogContext(t *testing.T) {
+	// Set Gin to test mode
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestIDMiddleware())
+
+	var fields map[string]interface{}
+	router.GET("/test", func(c *gin.Context) {
+		fields = logging.ContextFields(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req, err := http.NewRequest("GET", "/test", nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Logs written with the request context carry the same ID the client sees
+	require.NotNil(t, fields)
+	assert.Equal(t, w.Header().Get("X-Request-ID"), fields["request_id"])
+}
@@ -32,12 +32,14 @@ func (s *Server) updateFeatureFlagsHandler(c *gin.Context) {
 		return
 	}
 
-	// Update flags
+	// Update flags, keeping the old ones for the audit log
+	previous := s.featureFlags.GetFlags()
 	err := s.featureFlags.UpdateFlags(req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update feature flags", "details": err.Error()})
 		return
 	}
+	s.recordAudit(c, auditActionFeatureFlags, "", gin.H{"previous": previous, "feature_flags": req})
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Feature flags updated successfully",
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"sync"
+
+	"github.com/neo/convinceme_backend/internal/database"
 )
 
 // FeatureFlags represents the feature flags for the application
@@ -27,6 +29,15 @@ type FeatureFlags struct {
 	EnableAdminDashboard bool `json:"enable_admin_dashboard"`
 }
 
+// ForRole resolves the flags that apply to a user with the given role.
+// Admin-only features are switched off for everyone else.
+func (f FeatureFlags) ForRole(role string) FeatureFlags {
+	if role != string(database.RoleAdmin) {
+		f.EnableAdminDashboard = false
+	}
+	return f
+}
+
 // FeatureFlagManager manages feature flags
 type FeatureFlagManager struct {
 	flags      FeatureFlags
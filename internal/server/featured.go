@@ -0,0 +1,109 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/types"
+)
+
+// maxFeaturedDebates caps how many manually featured debates the homepage gets
+const maxFeaturedDebates = 5
+
+// Where the featured debates came from
+const (
+	featuredSourceManual      = "featured"     // Featured by an admin
+	featuredSourceMostWatched = "most_watched" // Nothing was featured, so the active debate with the most clients
+	featuredSourceNone        = "none"         // Nothing was featured and no debate is running
+)
+
+// featuredDebates returns the debates featured by admins, newest first, falling back to the most-watched
+// active public debate when nothing is featured. It also returns where the debates came from.
+func (s *Server) featuredDebates() ([]*database.Debate, string, error) {
+	featured, _, err := s.db.ListDebates(database.DebateFilter{
+		Featured:   true,
+		Visibility: database.DebateVisibilityPublic,
+		SortBy:     "featured_at",
+		SortDir:    "desc",
+		Limit:      maxFeaturedDebates,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if len(featured) > 0 {
+		return featured, featuredSourceManual, nil
+	}
+
+	active, err := s.db.ListActiveDebates()
+	if err != nil {
+		return nil, "", err
+	}
+	var mostWatched *database.Debate
+	mostClients := -1
+	for _, debate := range active {
+		if debate.Status != types.DebateStatusActive || (debate.Visibility != "" && debate.Visibility != database.DebateVisibilityPublic) {
+			continue
+		}
+		session, exists := s.debateManager.GetDebate(debate.ID)
+		if !exists {
+			continue
+		}
+		if clients := session.GetPresence().Total; clients > mostClients {
+			mostWatched, mostClients = debate, clients
+		}
+	}
+	if mostWatched == nil {
+		return nil, featuredSourceNone, nil
+	}
+	return []*database.Debate{mostWatched}, featuredSourceMostWatched, nil
+}
+
+// getFeaturedDebatesHandler returns the debates to show on the homepage, with their live state
+func (s *Server) getFeaturedDebatesHandler(c *gin.Context) {
+	debates, source, err := s.featuredDebates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get featured debates", "details": err.Error()})
+		return
+	}
+
+	liveDebates, _ := s.withLiveState(debates)
+	c.JSON(http.StatusOK, gin.H{
+		"debates": liveDebates,
+		"source":  source,
+		"count":   len(liveDebates),
+	})
+}
+
+// setDebateFeaturedHandler features a debate on the homepage (PUT) or unfeatures it (DELETE)
+func (s *Server) setDebateFeaturedHandler(featured bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		debateID := c.Param("debateID")
+
+		debate, err := s.db.GetDebate(debateID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Debate not found", "details": err.Error()})
+			return
+		}
+		// Featured debates are shown to everyone, so only public ones qualify
+		if featured && debate.Visibility != "" && debate.Visibility != database.DebateVisibilityPublic {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only public debates can be featured"})
+			return
+		}
+
+		if err := s.db.SetDebateFeatured(debateID, featured); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update featured debate", "details": err.Error()})
+			return
+		}
+		if featured {
+			s.recordAudit(c, auditActionDebateFeature, debateID, nil)
+		} else {
+			s.recordAudit(c, auditActionDebateUnfeature, debateID, nil)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"debate_id": debateID,
+			"featured":  featured,
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// featuredDB returns a featured debate
+type featuredDB struct {
+	*TestMockDB
+}
+
+func (m *featuredDB) ListDebates(filter database.DebateFilter) ([]*database.Debate, int, error) {
+	if !filter.Featured {
+		return m.TestMockDB.ListDebates(filter)
+	}
+	featuredAt := time.Now()
+	return []*database.Debate{
+		{ID: "debate-3", Topic: "Featured Topic", Status: "finished", Featured: true, FeaturedAt: &featuredAt},
+	}, 1, nil
+}
+
+type featuredResponse struct {
+	Debates []struct {
+		ID          string `json:"id"`
+		Featured    bool   `json:"featured"`
+		ClientCount int    `json:"client_count"`
+	} `json:"debates"`
+	Source string `json:"source"`
+}
+
+func getFeatured(t *testing.T, server *Server) featuredResponse {
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/debates/featured", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response featuredResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func TestFeaturedDebates(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/debates/featured", server.getFeaturedDebatesHandler)
+	server.debateManager = &DebateManager{debates: map[string]*conversation.DebateSession{}}
+
+	// Nothing featured and nothing running
+	response := getFeatured(t, server)
+	assert.Equal(t, featuredSourceNone, response.Source)
+	assert.Empty(t, response.Debates)
+
+	// Nothing featured: the active debate with the most clients
+	conn1, conn2 := &websocket.Conn{}, &websocket.Conn{}
+	server.debateManager.debates["debate-1"] = &conversation.DebateSession{
+		DebateID:    "debate-1",
+		Clients:     map[*websocket.Conn]string{conn1: "player-1", conn2: "player-2"},
+		ClientRoles: map[*websocket.Conn]string{conn1: conversation.ClientRoleParticipant, conn2: conversation.ClientRoleSpectator},
+	}
+	response = getFeatured(t, server)
+	assert.Equal(t, featuredSourceMostWatched, response.Source)
+	require.Len(t, response.Debates, 1)
+	assert.Equal(t, "debate-1", response.Debates[0].ID)
+	assert.Equal(t, 2, response.Debates[0].ClientCount)
+
+	// Manually featured debates win over the fallback
+	server.db = &featuredDB{TestMockDB: &TestMockDB{}}
+	response = getFeatured(t, server)
+	assert.Equal(t, featuredSourceManual, response.Source)
+	require.Len(t, response.Debates, 1)
+	assert.Equal(t, "debate-3", response.Debates[0].ID)
+	assert.True(t, response.Debates[0].Featured)
+}
+
+func TestSetDebateFeaturedRequiresAdmin(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.setupAdminRoutes()
+
+	adminToken, err := server.auth.GenerateToken(auth.User{ID: "admin-1", Username: "admin", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+	userToken, err := server.auth.GenerateToken(auth.User{ID: "user-1", Username: "user", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	request := func(method, debateID, token string) int {
+		req := httptest.NewRequest(method, "/api/admin/debates/"+debateID+"/featured", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPut, "debate-1", userToken))
+	assert.Equal(t, http.StatusOK, request(http.MethodPut, "debate-1", adminToken))
+	assert.Equal(t, http.StatusOK, request(http.MethodDelete, "debate-1", adminToken))
+	assert.Equal(t, http.StatusNotFound, request(http.MethodPut, "missing-debate", adminToken))
+}
@@ -1,10 +1,13 @@
 package server
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/neo/convinceme_backend/internal/auth"
@@ -59,6 +62,45 @@ type FeedbackRequest struct {
 	ScreenSize string `json:"screen_size"`
 }
 
+// feedbackRateLimit caps feedback submissions per user, or per IP for anonymous feedback, per hour
+const feedbackRateLimit = 5
+
+// Length bounds for feedback fields, in characters
+const (
+	minFeedbackMessageLength = 3
+	maxFeedbackMessageLength = 5000
+	maxFeedbackPathLength    = 500
+	maxFeedbackClientLength  = 200 // Browser, device and screen size
+)
+
+// validateFeedbackRequest checks a feedback submission's type, rating and field lengths
+func validateFeedbackRequest(req *FeedbackRequest) error {
+	if !database.IsValidFeedbackType(req.Type) {
+		return fmt.Errorf("type must be one of auth, ui, performance, feature, bug or other")
+	}
+
+	message := utf8.RuneCountInString(strings.TrimSpace(req.Message))
+	if message < minFeedbackMessageLength || message > maxFeedbackMessageLength {
+		return fmt.Errorf("message must be between %d and %d characters", minFeedbackMessageLength, maxFeedbackMessageLength)
+	}
+
+	// A rating is optional, so zero means none was given
+	if req.Rating != 0 && (req.Rating < 1 || req.Rating > 5) {
+		return fmt.Errorf("rating must be between 1 and 5")
+	}
+
+	if utf8.RuneCountInString(req.Path) > maxFeedbackPathLength {
+		return fmt.Errorf("path exceeds %d characters", maxFeedbackPathLength)
+	}
+	for field, value := range map[string]string{"browser": req.Browser, "device": req.Device, "screen_size": req.ScreenSize} {
+		if utf8.RuneCountInString(value) > maxFeedbackClientLength {
+			return fmt.Errorf("%s exceeds %d characters", field, maxFeedbackClientLength)
+		}
+	}
+
+	return nil
+}
+
 // submitFeedbackHandler handles feedback submission
 func (s *Server) submitFeedbackHandler(c *gin.Context) {
 	// Parse request
@@ -67,6 +109,10 @@ func (s *Server) submitFeedbackHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
 		return
 	}
+	if err := validateFeedbackRequest(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feedback", "details": err.Error()})
+		return
+	}
 
 	// Get user ID if authenticated
 	var userID string
@@ -163,6 +209,39 @@ func (s *Server) getFeedbackHandler(c *gin.Context) {
 	SendPaginatedResponse(c, paginationParams, feedback)
 }
 
+// getMyFeedbackHandler gets the feedback submitted by the current user, newest first, with pagination.
+// It can be filtered by type and searched like the admin feedback list.
+func (s *Server) getMyFeedbackHandler(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	paginationParams := GetPaginationParams(c)
+	filterParams := GetFilterParams(c)
+
+	filter := database.FeedbackFilter{
+		Type:     c.Query("type"),
+		Search:   filterParams.Search,
+		Page:     paginationParams.Page,
+		PageSize: paginationParams.PageSize,
+	}
+
+	feedback, total, err := s.db.GetFeedbackByUserFiltered(userID, filter)
+	if err != nil {
+		log.Printf("Error getting feedback of user %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get feedback"})
+		return
+	}
+	if feedback == nil {
+		feedback = []*database.Feedback{}
+	}
+
+	paginationParams.Total = total
+	SendPaginatedResponse(c, paginationParams, feedback)
+}
+
 // getFeedbackByIDHandler gets feedback by ID
 func (s *Server) getFeedbackByIDHandler(c *gin.Context) {
 	// Get the current user role
@@ -232,6 +311,7 @@ func (s *Server) deleteFeedbackHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete feedback"})
 		return
 	}
+	s.recordAudit(c, auditActionFeedbackDelete, strconv.Itoa(id), nil)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Feedback deleted successfully"})
 }
@@ -241,8 +321,15 @@ func (s *Server) setupFeedbackRoutes() {
 	// Group all feedback routes under /api/feedback
 	feedbackGroup := s.router.Group("/api/feedback")
 	{
-		// Public route to submit feedback
-		feedbackGroup.POST("", s.submitFeedbackHandler)
+		// Public route to submit feedback, attributed to the user when authenticated
+		feedbackGroup.POST("",
+			s.auth.OptionalAuthMiddleware(),
+			ClientRateLimitMiddleware(feedbackRateLimit, time.Hour),
+			s.submitFeedbackHandler,
+		)
+
+		// The current user's own feedback
+		feedbackGroup.GET("/mine", s.auth.AuthMiddleware(), s.getMyFeedbackHandler)
 
 		// Admin routes (require authentication and admin role)
 		adminRoutes := feedbackGroup.Group("/")
@@ -3,8 +3,10 @@ package server
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -278,3 +280,123 @@ func TestGetFeedbackHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestGetMyFeedbackHandler(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+
+	token, err := server.auth.GenerateToken(auth.User{ID: "feedback-user", Username: "feedbackuser", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	get := func(query, token string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodGet, "/api/feedback/mine"+query, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	code, _ := get("", "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, response := get("", token)
+	require.Equal(t, http.StatusOK, code)
+	items := response["items"].([]interface{})
+	require.Len(t, items, 2)
+	assert.Equal(t, "feedback-user", items[0].(map[string]interface{})["user_id"])
+
+	code, response = get("?page=2&page_size=1", token)
+	require.Equal(t, http.StatusOK, code)
+	assert.Len(t, response["items"], 1)
+	pagination := response["pagination"].(map[string]interface{})
+	assert.Equal(t, float64(2), pagination["total_items"])
+	assert.Equal(t, false, pagination["has_next"])
+
+	code, response = get("?type=bug", token)
+	require.Equal(t, http.StatusOK, code)
+	items = response["items"].([]interface{})
+	require.Len(t, items, 1)
+	assert.Equal(t, "bug", items[0].(map[string]interface{})["type"])
+
+	code, response = get("?type=ui", token)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{}, response["items"])
+}
+
+// postFeedback submits feedback from the given client IP, authenticated when token is set
+func postFeedback(t *testing.T, server *Server, body map[string]interface{}, ip, token string) *httptest.ResponseRecorder {
+	jsonBody, err := json.Marshal(body)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/feedback", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = ip + ":1234"
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSubmitFeedbackValidation(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+
+	testCases := []struct {
+		name  string
+		body  map[string]interface{}
+		valid bool
+	}{
+		{"Valid", map[string]interface{}{"type": "bug", "message": "Audio stops after a minute", "rating": 1}, true},
+		{"No rating", map[string]interface{}{"type": "feature", "message": "Dark mode please"}, true},
+		{"Unknown type", map[string]interface{}{"type": "spam", "message": "Buy now"}, false},
+		{"Blank message", map[string]interface{}{"type": "ui", "message": "    "}, false},
+		{"Message too long", map[string]interface{}{"type": "ui", "message": strings.Repeat("a", maxFeedbackMessageLength+1)}, false},
+		{"Rating too high", map[string]interface{}{"type": "ui", "message": "Looks great", "rating": 6}, false},
+		{"Negative rating", map[string]interface{}{"type": "ui", "message": "Looks bad", "rating": -1}, false},
+		{"Oversized browser", map[string]interface{}{"type": "ui", "message": "Looks great", "browser": strings.Repeat("b", maxFeedbackClientLength+1)}, false},
+		{"Oversized screen size", map[string]interface{}{"type": "ui", "message": "Looks great", "screen_size": strings.Repeat("1", maxFeedbackClientLength+1)}, false},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Each case comes from its own IP so the rate limit doesn't interfere
+			w := postFeedback(t, server, tc.body, fmt.Sprintf("10.0.0.%d", i+1), "")
+			if tc.valid {
+				assert.Equal(t, http.StatusCreated, w.Code)
+			} else {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.Contains(t, w.Body.String(), "details")
+			}
+		})
+	}
+}
+
+func TestSubmitFeedbackRateLimit(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+
+	body := map[string]interface{}{"type": "other", "message": "Another thought"}
+	for i := 0; i < feedbackRateLimit; i++ {
+		require.Equal(t, http.StatusCreated, postFeedback(t, server, body, "10.0.1.1", "").Code)
+	}
+
+	// Anonymous feedback is limited per IP
+	w := postFeedback(t, server, body, "10.0.1.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusCreated, postFeedback(t, server, body, "10.0.1.2", "").Code)
+
+	// Authenticated feedback is limited per user, whatever the IP
+	token, err := server.auth.GenerateToken(auth.User{ID: "feedback-user", Username: "feedbackuser", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, postFeedback(t, server, body, "10.0.1.1", token).Code)
+	for i := 1; i < feedbackRateLimit; i++ {
+		require.Equal(t, http.StatusCreated, postFeedback(t, server, body, fmt.Sprintf("10.0.2.%d", i), token).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, postFeedback(t, server, body, "10.0.3.1", token).Code)
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Fields list endpoints let clients project their items to with ?fields=, by JSON name. Anything else
+// requested is ignored.
+var (
+	debateListFields = fieldSet(
+		"id", "topic", "status", "agent1_name", "agent2_name", "created_at", "ended_at", "winner", "visibility",
+		"created_by", "duration_seconds", "active_seconds", "scheduled_at", "end_reason", "first_speaker",
+		"first_speaker_coin_flip", "featured", "featured_at",
+		"client_count", "game_score", // Only present with ?live=true
+	)
+	topicListFields = fieldSet(
+		"id", "title", "description", "agent1_name", "agent1_role", "agent2_name", "agent2_role", "category", "created_at",
+	)
+)
+
+// fieldSet builds an allowlist of field names
+func fieldSet(fields ...string) map[string]bool {
+	set := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		set[field] = true
+	}
+	return set
+}
+
+// requestedFields returns the allowed fields named in the comma-separated fields query parameter, or nil if
+// the request doesn't ask for any of them
+func requestedFields(c *gin.Context, allowed map[string]bool) []string {
+	var fields []string
+	for _, field := range strings.Split(c.Query("fields"), ",") {
+		field = strings.TrimSpace(field)
+		if allowed[field] {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// projectFields trims each item of a list to the fields the request asks for with ?fields=id,topic,status,
+// to save bandwidth on slow connections. Items are projected after serialization, so a projected field
+// always looks exactly as it does in the full response, and fields omitted when empty stay omitted. Without
+// any allowed field requested, or if the items can't be projected, they're returned unchanged.
+func projectFields(c *gin.Context, items any, allowed map[string]bool) any {
+	fields := requestedFields(c, allowed)
+	if len(fields) == 0 {
+		return items
+	}
+
+	encoded, err := json.Marshal(items)
+	if err != nil {
+		return items
+	}
+	var full []map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &full); err != nil {
+		return items
+	}
+
+	projected := make([]map[string]json.RawMessage, 0, len(full))
+	for _, item := range full {
+		trimmed := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := item[field]; ok {
+				trimmed[field] = value
+			}
+		}
+		projected = append(projected, trimmed)
+	}
+	return projected
+}
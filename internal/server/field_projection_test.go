@@ -0,0 +1,77 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListFieldProjection(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/debates", server.listDebatesHandler)
+	server.router.GET("/api/topics", server.listTopicsHandler)
+	server.router.GET("/api/topics/category/:category", server.listTopicsByCategoryHandler)
+	server.setupV2Routes()
+
+	conn := &websocket.Conn{}
+	server.debateManager = &DebateManager{debates: map[string]*conversation.DebateSession{
+		"debate-1": {
+			DebateID:    "debate-1",
+			Clients:     map[*websocket.Conn]string{conn: "player-1"},
+			ClientRoles: map[*websocket.Conn]string{conn: conversation.ClientRoleParticipant},
+		},
+	}}
+
+	list := func(path, itemsKey string) []map[string]interface{} {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		var items []map[string]interface{}
+		require.NoError(t, json.Unmarshal(response[itemsKey], &items))
+		return items
+	}
+
+	testCases := []struct {
+		name         string
+		path         string
+		itemsKey     string
+		expectedKeys []string
+	}{
+		{name: "Debates", path: "/api/debates?fields=id,topic,status", itemsKey: "items", expectedKeys: []string{"id", "topic", "status"}},
+		{name: "Unknown and blank fields are ignored", path: "/api/debates?fields=id,%20status,,password_hash", itemsKey: "items", expectedKeys: []string{"id", "status"}},
+		{name: "Live debates", path: "/api/debates?status=active&live=true&fields=id,client_count", itemsKey: "debates", expectedKeys: []string{"id", "client_count"}},
+		{name: "Topics", path: "/api/topics?fields=id,title", itemsKey: "items", expectedKeys: []string{"id", "title"}},
+		{name: "Topics by category", path: "/api/topics/category/crypto?fields=title", itemsKey: "items", expectedKeys: []string{"title"}},
+		{name: "v2 topics", path: "/api/v2/topics?fields=id,category", itemsKey: "data", expectedKeys: []string{"id", "category"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			items := list(tc.path, tc.itemsKey)
+			require.NotEmpty(t, items)
+			for _, item := range items {
+				keys := make([]string, 0, len(item))
+				for key := range item {
+					keys = append(keys, key)
+				}
+				assert.ElementsMatch(t, tc.expectedKeys, keys)
+			}
+		})
+	}
+
+	// Without a known field the full items are returned
+	for _, path := range []string{"/api/topics", "/api/topics?fields=nope"} {
+		items := list(path, "items")
+		require.Len(t, items, 1)
+		assert.Len(t, items[0], len(topicListFields), path)
+	}
+}
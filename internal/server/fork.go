@@ -0,0 +1,157 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/logging"
+	"github.com/neo/convinceme_backend/internal/types"
+)
+
+// forkDebateHandler starts a new debate from a finished one's transcript, to explore how it could have gone
+// differently. The fork starts with the source's first from_turn transcript entries, copied to its own
+// transcript, and the HP they moved, then its loop continues the debate from there. The fork keeps the
+// source's topic, agents, settings and visibility, and records which debate and turn it branched from.
+// It gets its own seed, so its random choices can differ, and no scripted openings, which were delivered
+// before the turns it starts from.
+func (s *Server) forkDebateHandler(c *gin.Context) {
+	debateID := c.Param("debateID")
+
+	var req struct {
+		FromTurn int `json:"from_turn" binding:"required"` // Transcript entries the fork starts with, from 1
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	source, err := s.db.GetDebate(debateID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Debate not found", "details": err.Error()})
+		return
+	}
+	if source.Status != types.DebateStatusFinished {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only finished debates can be forked", "status": source.Status})
+		return
+	}
+
+	messages, err := s.db.GetDebateMessages(debateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get debate transcript", "details": err.Error()})
+		return
+	}
+	if req.FromTurn < 1 || req.FromTurn > len(messages) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("from_turn must be between 1 and %d, the debate's transcript entries", len(messages))})
+		return
+	}
+	messages = messages[:req.FromTurn]
+
+	// A fork must leave both agents standing, or there's nothing left to debate
+	gameScore := conversation.GameScore{Agent1Score: conversation.InitialHP, Agent2Score: conversation.InitialHP}
+	for _, msg := range messages {
+		gameScore.Agent1Score += msg.Agent1Delta
+		gameScore.Agent2Score += msg.Agent2Delta
+	}
+	if gameScore.Agent1Score <= 0 || gameScore.Agent2Score <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An agent has no HP left at that turn, fork from an earlier one", "game_score": gameScore})
+		return
+	}
+
+	agent1, exists1 := s.agents.Get(source.Agent1Name)
+	agent2, exists2 := s.agents.Get(source.Agent2Name)
+	if !exists1 || !exists2 {
+		c.JSON(http.StatusConflict, gin.H{"error": "The debate's agents are no longer available"})
+		return
+	}
+
+	userID, _ := auth.GetUserID(c)
+	config := s.debateManager.restoredConfig(source)
+	config.Seed = 0
+	config.OpeningStatements = nil
+	settings := database.DebateSettings{
+		Visibility:     source.Visibility,
+		CreatedBy:      userID,
+		ParentDebateID: debateID,
+		ForkedFromTurn: req.FromTurn,
+	}
+	forkID, err := s.debateManager.CreateDebateWithConfig(config, agent1, agent2, userID, settings)
+	if err != nil {
+		s.respondDebateCreateFailed(c, err)
+		return
+	}
+	session, exists := s.debateManager.GetDebate(forkID)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Debate forked but its session is gone"})
+		return
+	}
+
+	gameScore = session.RestoreHistory(s.copyTranscript(forkID, messages))
+	if session.UpdateStatus(types.DebateStatusActive) {
+		if err := s.db.UpdateDebateStatus(forkID, types.DebateStatusActive); err != nil {
+			logging.Error("Failed to record forked debate as active", map[string]interface{}{
+				"debate_id": forkID,
+				"error":     err.Error(),
+			})
+		}
+		s.debateManager.StartDebateLoop(session)
+	}
+
+	logging.LogDebateEvent("debate_forked", forkID, map[string]interface{}{
+		"triggered_by":     userID,
+		"parent_debate_id": debateID,
+		"forked_from_turn": req.FromTurn,
+	})
+	s.recordAudit(c, auditActionDebateFork, debateID, gin.H{
+		"fork_debate_id":   forkID,
+		"forked_from_turn": req.FromTurn,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{
+		"debate_id":        forkID,
+		"parent_debate_id": debateID,
+		"forked_from_turn": req.FromTurn,
+		"game_score": gin.H{
+			source.Agent1Name: gameScore.Agent1Score,
+			source.Agent2Name: gameScore.Agent2Score,
+		},
+	})
+}
+
+// copyTranscript saves copies of transcript entries under a forked debate, so its replay and history include
+// them, and returns them as the fork's history
+func (s *Server) copyTranscript(forkID string, messages []*database.DebateMessage) []conversation.DebateEntry {
+	entries := make([]conversation.DebateEntry, 0, len(messages))
+	for _, msg := range messages {
+		entry := transcriptEntry(msg)
+		entry.MessageID = s.recordDebateMessage(&database.DebateMessage{
+			DebateID:    forkID,
+			Speaker:     msg.Speaker,
+			Message:     msg.Message,
+			IsPlayer:    msg.IsPlayer,
+			Score:       msg.Score,
+			AudioURL:    msg.AudioURL,
+			Agent1Delta: msg.Agent1Delta,
+			Agent2Delta: msg.Agent2Delta,
+		})
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// transcriptEntry converts a stored transcript entry back into a history entry, with the HP it moved
+func transcriptEntry(msg *database.DebateMessage) conversation.DebateEntry {
+	return conversation.DebateEntry{
+		MessageID:    msg.ID,
+		Speaker:      msg.Speaker,
+		Message:      msg.Message,
+		Time:         msg.CreatedAt,
+		IsPlayer:     msg.IsPlayer,
+		AverageScore: msg.Score,
+		Agent1Delta:  msg.Agent1Delta,
+		Agent2Delta:  msg.Agent2Delta,
+	}
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/scoring"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupForkTestServer creates a test server backed by db that serves the fork endpoint, and returns a function
+// forking a debate through it
+func setupForkTestServer(t *testing.T, db database.DatabaseInterface) (*Server, string, func(debateID, body, token string) (int, map[string]interface{})) {
+	server, tempDir := setupTestServer(t)
+	server.db = db
+
+	agent1 := agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 1"})
+	agent2 := agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 2"})
+	server.agents = NewAgentRegistry(map[string]*agent.Agent{"Agent 1": agent1, "Agent 2": agent2})
+	server.debateManager = &DebateManager{
+		db:      db,
+		agents:  server.agents,
+		debates: map[string]*conversation.DebateSession{},
+		scorer:  scoring.NewOfflineScorer(),
+		server:  server,
+	}
+	server.router.POST("/api/debates/:debateID/fork",
+		server.auth.AuthMiddleware(),
+		server.auth.RequireRole(string(database.RoleAdmin)),
+		server.forkDebateHandler)
+
+	fork := func(debateID, body, token string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, "/api/debates/"+debateID+"/fork", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+	return server, tempDir, fork
+}
+
+func TestForkDebateHandler(t *testing.T) {
+	server, tempDir, fork := setupForkTestServer(t, &TestMockDB{})
+	defer teardownTestServer(tempDir)
+
+	adminToken, err := server.auth.GenerateToken(auth.User{ID: "admin-1", Username: "admin", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+	userToken, err := server.auth.GenerateToken(auth.User{ID: "user-1", Username: "user", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	code, _ := fork("finished-debate", `{"from_turn":1}`, userToken)
+	assert.Equal(t, http.StatusForbidden, code)
+	code, _ = fork("missing-debate", `{"from_turn":1}`, adminToken)
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = fork("running-debate", `{"from_turn":1}`, adminToken)
+	assert.Equal(t, http.StatusConflict, code, "only finished debates can be forked")
+
+	// The transcript has two entries
+	code, _ = fork("finished-debate", `{"from_turn":3}`, adminToken)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = fork("finished-debate", `{"from_turn":-1}`, adminToken)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Forking after Agent 1's opening turn keeps the 5 HP it moved
+	code, response := fork("finished-debate", `{"from_turn":1}`, adminToken)
+	require.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, "finished-debate", response["parent_debate_id"])
+	assert.Equal(t, float64(1), response["forked_from_turn"])
+	assert.Equal(t, map[string]interface{}{"Agent 1": float64(105), "Agent 2": float64(95)}, response["game_score"])
+
+	session, exists := server.debateManager.GetDebate(response["debate_id"].(string))
+	require.True(t, exists)
+	// Let the fork's loop exit once its intro delay is over
+	defer session.UpdateStatus(types.DebateStatusFinished)
+	assert.True(t, session.IsLoopRunning(), "the fork continues on its own")
+	assert.Equal(t, 1, session.GetTurnCount())
+	history := session.GetRecentHistory(10)
+	require.Len(t, history, 1)
+	assert.Equal(t, "Opening argument", history[0].Message)
+
+}
+
+// TestForkDebateKeepsSettings tests that a fork keeps the settings the source was created with
+func TestForkDebateKeepsSettings(t *testing.T) {
+	sourceDB := &forkSourceDB{TestMockDB: &TestMockDB{}, config: `{"MaxTurns":7,"Language":"es","Seed":42,"OpeningStatements":{"Agent 1":"Hello"}}`}
+	server, tempDir, fork := setupForkTestServer(t, sourceDB)
+	defer teardownTestServer(tempDir)
+
+	adminToken, err := server.auth.GenerateToken(auth.User{ID: "admin-1", Username: "admin", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+
+	code, response := fork("finished-debate", `{"from_turn":1}`, adminToken)
+	require.Equal(t, http.StatusCreated, code)
+	session, exists := server.debateManager.GetDebate(response["debate_id"].(string))
+	require.True(t, exists)
+	defer session.UpdateStatus(types.DebateStatusFinished)
+	assert.Equal(t, 7, session.Config.MaxTurns)
+	assert.Equal(t, types.LanguageSpanish, session.Config.Language)
+	assert.Equal(t, "Test Topic", session.Config.Topic)
+	assert.NotEqual(t, int64(42), session.Config.Seed, "the fork picks its own seed")
+	assert.Empty(t, session.Config.OpeningStatements)
+}
+
+// forkSourceDB returns debates with a stored config
+type forkSourceDB struct {
+	*TestMockDB
+	config string
+}
+
+func (db *forkSourceDB) GetDebate(id string) (*database.Debate, error) {
+	debate, err := db.TestMockDB.GetDebate(id)
+	if err == nil {
+		debate.Config = &db.config
+	}
+	return debate, err
+}
+
+func TestRestoreHistory(t *testing.T) {
+	agent1 := agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 1"})
+	agent2 := agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 2"})
+	session, err := conversation.NewDebateSession("fork", agent1, agent2, conversation.DefaultConfig(), "")
+	require.NoError(t, err)
+
+	score := 6.0
+	gameScore := session.RestoreHistory([]conversation.DebateEntry{
+		{Speaker: "Agent 1", Message: "Scripted opening"},
+		{Speaker: "Agent 2", Message: "Rebuttal", AverageScore: &score, Agent1Delta: -6, Agent2Delta: 6},
+		{Speaker: "player-1", Message: "Agent 1 is right", IsPlayer: true, AverageScore: &score, Agent1Delta: 3, Agent2Delta: -3},
+	})
+
+	assert.Equal(t, conversation.GameScore{Agent1Score: 97, Agent2Score: 103}, gameScore)
+	assert.Equal(t, 1, session.GetTurnCount(), "only scored agent entries are turns")
+	assert.Equal(t, "Agent 1", conversation.NextSpeaker(session).GetName(), "the agent that spoke last hands over")
+}
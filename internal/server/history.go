@@ -0,0 +1,93 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/database"
+)
+
+// Page sizes for the debate history endpoint
+const (
+	defaultHistoryPageSize = 50
+	maxHistoryPageSize     = 200
+)
+
+// Where a page of debate history came from
+const (
+	historySourcePersisted = "persisted" // The debate_messages table
+	historySourceMemory    = "memory"    // The running session, for debates with nothing persisted
+)
+
+// getDebateHistoryHandler returns a page of a debate's transcript in chronological order, with scores and
+// speaker flags. Without ?before it returns the latest entries; passing the returned next_before loads the
+// page before them, so clients can load older messages lazily as the user scrolls up.
+func (s *Server) getDebateHistoryHandler(c *gin.Context) {
+	debateID := c.Param("debateID")
+
+	if status, err := s.checkDebateAccess(c, debateID); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultHistoryPageSize)))
+	if err != nil || limit <= 0 {
+		limit = defaultHistoryPageSize
+	}
+	if limit > maxHistoryPageSize {
+		limit = maxHistoryPageSize
+	}
+
+	var before int64
+	if beforeParam := c.Query("before"); beforeParam != "" {
+		before, err = strconv.ParseInt(beforeParam, 10, 64)
+		if err != nil || before <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "before must be a message ID"})
+			return
+		}
+	}
+
+	messages, hasMore, err := s.db.GetDebateMessagesBefore(debateID, before, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get debate history", "details": err.Error()})
+		return
+	}
+
+	source := historySourcePersisted
+	if len(messages) == 0 && before == 0 {
+		// Nothing persisted for this debate, so serve what the running session still holds
+		if session, exists := s.debateManager.GetDebate(debateID); exists {
+			source = historySourceMemory
+			for _, entry := range session.GetRecentHistory(limit) {
+				messages = append(messages, &database.DebateMessage{
+					DebateID:    debateID,
+					Speaker:     entry.Speaker,
+					Message:     entry.Message,
+					IsPlayer:    entry.IsPlayer,
+					Score:       entry.AverageScore,
+					Agent1Delta: entry.Agent1Delta,
+					Agent2Delta: entry.Agent2Delta,
+					CreatedAt:   entry.Time,
+				})
+			}
+		}
+	}
+
+	entries := make([]*database.DebateMessage, 0, len(messages))
+	for _, msg := range messages {
+		entries = append(entries, s.withPlayableAudio(msg))
+	}
+
+	response := gin.H{
+		"debate_id": debateID,
+		"messages":  entries,
+		"count":     len(entries),
+		"has_more":  hasMore,
+		"source":    source,
+	}
+	if hasMore && len(entries) > 0 {
+		response["next_before"] = entries[0].ID
+	}
+	c.JSON(http.StatusOK, response)
+}
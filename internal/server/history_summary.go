@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/logging"
+)
+
+// Bounds for the context options accepted by ValidateDebateConfig
+const (
+	maxContextWindow   = 50
+	maxSummaryInterval = 20
+)
+
+// formatHistory renders history entries as "Speaker: message" lines
+func formatHistory(entries []conversation.DebateEntry) string {
+	var b strings.Builder
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "%s: %s\n", entry.Speaker, entry.Message)
+	}
+	return b.String()
+}
+
+// refreshHistorySummary folds the history that has fallen out of the context window into the session's running
+// summary, every SummaryInterval agent turns. The summary is written by the agent about to speak. Failures are
+// logged and leave the previous summary in place, so a turn never waits on a retry.
+func refreshHistorySummary(ctx context.Context, session *conversation.DebateSession, speaker *agent.Agent, turn int) {
+	interval := session.Config.SummaryInterval
+	if interval <= 0 || turn%interval != 0 {
+		return
+	}
+
+	previous, entries, upTo := session.HistoryToSummarize(session.Config.HistoryWindow())
+	if len(entries) == 0 {
+		return
+	}
+
+	summary, err := speaker.SummarizeDebate(ctx, session.Config.Topic, previous, formatHistory(entries), generationFallback(session.Config))
+	if err != nil {
+		logging.ErrorCtx(ctx, "Failed to summarize debate history", map[string]interface{}{
+			"turn":  turn,
+			"error": err.Error(),
+		})
+		return
+	}
+	session.SetHistorySummary(summary, upTo)
+	logging.DebugCtx(ctx, "Refreshed debate history summary", map[string]interface{}{
+		"turn":             turn,
+		"summarized_up_to": upTo,
+	})
+}
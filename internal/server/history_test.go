@@ -0,0 +1,84 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebateHistoryHandler(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/debates/:debateID/history", server.auth.OptionalAuthMiddleware(), server.getDebateHistoryHandler)
+
+	memoryOnly := &conversation.DebateSession{DebateID: "memory-only-debate", Status: "active"}
+	memoryOnly.AddHistoryEntry("Agent 1", "Opening", false)
+	memoryOnly.RecordTurn("Agent 1", false, 6, -6, 0, 1)
+	memoryOnly.AddHistoryEntry("player1", "Rebuttal", true)
+	server.debateManager = &DebateManager{debates: map[string]*conversation.DebateSession{"memory-only-debate": memoryOnly}}
+
+	get := func(path string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+	messageIDs := func(response map[string]interface{}) []float64 {
+		var ids []float64
+		for _, msg := range response["messages"].([]interface{}) {
+			ids = append(ids, msg.(map[string]interface{})["id"].(float64))
+		}
+		return ids
+	}
+
+	// The latest page comes first, in chronological order
+	code, response := get("/api/debates/long-debate/history")
+	require.Equal(t, http.StatusOK, code)
+	ids := messageIDs(response)
+	require.Len(t, ids, defaultHistoryPageSize)
+	assert.Equal(t, float64(71), ids[0])
+	assert.Equal(t, float64(120), ids[len(ids)-1])
+	assert.Equal(t, true, response["has_more"])
+	assert.Equal(t, float64(71), response["next_before"])
+
+	// Paging back with the cursor until the start of the debate
+	_, response = get("/api/debates/long-debate/history?limit=60&before=71")
+	ids = messageIDs(response)
+	assert.Equal(t, float64(11), ids[0])
+	assert.Equal(t, float64(70), ids[len(ids)-1])
+	_, response = get(fmt.Sprintf("/api/debates/long-debate/history?limit=60&before=%d", int(response["next_before"].(float64))))
+	assert.Len(t, response["messages"], 10)
+	assert.Equal(t, false, response["has_more"])
+	assert.NotContains(t, response, "next_before")
+
+	// Scores, speaker flags and still-playable audio are included
+	_, response = get("/api/debates/debate-1/history")
+	messages := response["messages"].([]interface{})
+	require.Len(t, messages, 2)
+	assert.Equal(t, 7.5, messages[0].(map[string]interface{})["score"])
+	assert.NotContains(t, messages[0], "audio_url", "expired audio should be dropped")
+	assert.Equal(t, true, messages[1].(map[string]interface{})["is_player"])
+	assert.Equal(t, float64(5), messages[0].(map[string]interface{})["agent1_delta"])
+	assert.Equal(t, float64(-5), messages[0].(map[string]interface{})["agent2_delta"])
+
+	// Debates with nothing persisted fall back to the running session's history
+	_, response = get("/api/debates/memory-only-debate/history")
+	assert.Equal(t, historySourceMemory, response["source"])
+	messages = response["messages"].([]interface{})
+	require.Len(t, messages, 2)
+	assert.Equal(t, "Rebuttal", messages[1].(map[string]interface{})["message"])
+	assert.Equal(t, float64(6), messages[0].(map[string]interface{})["agent1_delta"], "HP deltas are kept on history entries")
+	assert.Equal(t, float64(0), messages[1].(map[string]interface{})["agent2_delta"])
+
+	code, _ = get("/api/debates/long-debate/history?before=abc")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = get("/api/debates/missing-debate/history")
+	assert.Equal(t, http.StatusNotFound, code)
+}
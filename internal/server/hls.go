@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/neo/convinceme_backend/internal/logging"
+)
+
+const (
+	defaultHLSDir     = "static/hls"     // Served under /hls
+	hlsPlaylistName   = "playlist.m3u8"  // Playlist file inside each audio's directory
+	hlsSegmentSeconds = 4                // Target segment duration
+	hlsMinAudioBytes  = 64 * 1024        // Shorter clips are cheaper to serve as a single blob
+	hlsSegmentTimeout = 30 * time.Second // Upper bound on waiting for a free ffmpeg slot and running ffmpeg
+)
+
+// maxConcurrentFFmpeg caps the ffmpeg processes running at once, HLS segmentation and transcoding combined
+const maxConcurrentFFmpeg = 4
+
+// ffmpegSlots bounds the ffmpeg processes of the whole process, since they all compete for the same CPUs
+var ffmpegSlots = make(chan struct{}, maxConcurrentFFmpeg)
+
+// acquireFFmpeg waits for a free ffmpeg slot until ctx is done and returns the function that releases it
+func acquireFFmpeg(ctx context.Context) (func(), error) {
+	select {
+	case ffmpegSlots <- struct{}{}:
+		return func() { <-ffmpegSlots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("no ffmpeg slot available: %v", ctx.Err())
+	}
+}
+
+// segmentAudioHLS splits MP3 audio into AAC HLS segments under dir/<audioID>/ and returns the playlist URL.
+// It returns an error if ffmpeg is unavailable, busy for longer than hlsSegmentTimeout or segmentation fails, so
+// callers can fall back to the blob cache.
+func segmentAudioHLS(dir, audioID string, audioData []byte) (string, error) {
+	ffmpegPath, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return "", fmt.Errorf("ffmpeg not available: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hlsSegmentTimeout)
+	defer cancel()
+	release, err := acquireFFmpeg(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	outputDir := filepath.Join(dir, audioID)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create HLS directory: %v", err)
+	}
+
+	inputPath := filepath.Join(outputDir, "source.mp3")
+	if err := os.WriteFile(inputPath, audioData, 0644); err != nil {
+		os.RemoveAll(outputDir)
+		return "", fmt.Errorf("failed to write source audio: %v", err)
+	}
+	defer os.Remove(inputPath)
+
+	cmd := exec.CommandContext(ctx, ffmpegPath,
+		"-hide_banner", "-loglevel", "error",
+		"-i", inputPath,
+		"-c:a", "aac", "-b:a", "128k",
+		"-f", "hls",
+		"-hls_time", fmt.Sprintf("%d", hlsSegmentSeconds),
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", filepath.Join(outputDir, "segment_%03d.aac"),
+		filepath.Join(outputDir, hlsPlaylistName),
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		os.RemoveAll(outputDir)
+		return "", fmt.Errorf("ffmpeg segmentation failed: %v: %s", err, output)
+	}
+
+	return fmt.Sprintf("/hls/%s/%s", audioID, hlsPlaylistName), nil
+}
+
+// cleanupHLSSegments removes HLS directories older than the audio cache retention
+func cleanupHLSSegments(dir string, threshold time.Time) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(threshold) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			logging.Error("Failed to remove expired HLS segments", map[string]interface{}{
+				"audio_id": entry.Name(),
+				"error":    err,
+			})
+		}
+	}
+}
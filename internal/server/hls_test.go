@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheAudioFallsBackToBlob(t *testing.T) {
+	server := &Server{
+		audioCache: make(map[string]audioCache),
+		hlsDir:     t.TempDir(),
+	}
+
+	// Short clips skip segmentation and are served from the in-memory cache
+	url := server.CacheAudio([]byte("short clip"))
+	assert.True(t, strings.HasPrefix(url, "/api/audio/"))
+
+	audioID := strings.TrimPrefix(url, "/api/audio/")
+	server.cacheMutex.RLock()
+	_, exists := server.audioCache[audioID]
+	server.cacheMutex.RUnlock()
+	assert.True(t, exists)
+}
+
+func TestAcquireFFmpeg(t *testing.T) {
+	var releases []func()
+	for i := 0; i < maxConcurrentFFmpeg; i++ {
+		release, err := acquireFFmpeg(context.Background())
+		require.NoError(t, err)
+		releases = append(releases, release)
+	}
+
+	// With every slot taken, callers wait only until their context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := acquireFFmpeg(ctx)
+	assert.Error(t, err)
+
+	releases[0]()
+	release, err := acquireFFmpeg(context.Background())
+	require.NoError(t, err)
+	release()
+	for _, release := range releases[1:] {
+		release()
+	}
+}
+
+func TestCleanupHLSSegments(t *testing.T) {
+	dir := t.TempDir()
+	oldDir := filepath.Join(dir, "old-audio")
+	newDir := filepath.Join(dir, "new-audio")
+	require.NoError(t, os.Mkdir(oldDir, 0755))
+	require.NoError(t, os.Mkdir(newDir, 0755))
+
+	// Backdate the old directory past the retention window
+	old := time.Now().Add(-2 * DefaultAudioCacheTTL)
+	require.NoError(t, os.Chtimes(oldDir, old, old))
+
+	cleanupHLSSegments(dir, time.Now().Add(-DefaultAudioCacheTTL))
+
+	_, err := os.Stat(oldDir)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(newDir)
+	assert.NoError(t, err)
+}
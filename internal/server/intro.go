@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/logging"
+	"github.com/neo/convinceme_backend/internal/types"
+)
+
+// Bounds for the debate intro options accepted by ValidateDebateConfig
+const (
+	maxIntroMessageLength     = 500
+	maxIntroDelaySeconds      = 60
+	maxOpeningStatementLength = 1000
+)
+
+// introMessage returns the welcome broadcast when a debate starts, announcing the opening agent when a coin
+// flip picked it
+func introMessage(config conversation.DebateConfig, firstSpeaker string, coinFlip bool) string {
+	if config.IntroMessage == "" {
+		message := fmt.Sprintf("Welcome to the debate on: %s", config.Topic)
+		if coinFlip {
+			message += fmt.Sprintf(". %s won the coin flip and opens.", firstSpeaker)
+		}
+		return message
+	}
+	if coinFlip {
+		return fmt.Sprintf("%s %s won the coin flip and opens.", config.IntroMessage, firstSpeaker)
+	}
+	return config.IntroMessage
+}
+
+// deliverOpeningStatements has each agent deliver its scripted opening statement, in speaking order, before the
+// free-form turns. Statements are broadcast and spoken like turns, but aren't scored and don't move HP.
+func (m *DebateManager) deliverOpeningStatements(ctx context.Context, session *conversation.DebateSession) {
+	speakers := []*agent.Agent{session.Agent1, session.Agent2}
+	if session.FirstSpeakerAgent() == session.Agent2 {
+		speakers = []*agent.Agent{session.Agent2, session.Agent1}
+	}
+
+	for _, speaker := range speakers {
+		name := speaker.GetName()
+		statement := session.Config.OpeningStatements[name]
+		if statement == "" {
+			continue
+		}
+		if session.GetStatus() != types.DebateStatusActive {
+			return
+		}
+
+		voiceSettings := voiceSettingsForHP(speaker.VoiceSettings(), agentHP(session, name)).Resolve()
+		audioURL := (<-m.generateTurnAudio(ctx, session, speaker, statement, voiceSettings, 0)).url
+
+		session.AddHistoryEntry(name, statement, false)
+		messageID := m.server.recordDebateMessage(&database.DebateMessage{
+			DebateID: session.DebateID,
+			Speaker:  name,
+			Message:  statement,
+			AudioURL: audioURL,
+		})
+
+		message := gin.H{
+			"type":              "message",
+			"agent":             name,
+			"content":           statement,
+			"opening_statement": true,
+		}
+		if audioURL != "" {
+			message["audioUrl"] = audioURL
+		}
+		seq := session.Broadcast(message)
+		session.RecordTurn(name, false, 0, 0, messageID, seq)
+		if audioURL != "" {
+			session.Broadcast(gin.H{
+				"type":     "audio",
+				"audioUrl": audioURL,
+				"agent":    name,
+				"voice":    voiceSettings,
+			})
+		}
+		logging.InfoCtx(ctx, "Delivered opening statement", map[string]interface{}{
+			"agent_name": name,
+		})
+
+		waitForNextTurn(session, session.Config.TurnDelay)
+	}
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntroMessage(t *testing.T) {
+	config := conversation.DebateConfig{Topic: "Cats vs dogs"}
+	assert.Equal(t, "Welcome to the debate on: Cats vs dogs", introMessage(config, "Pepito", false))
+	assert.Equal(t, "Welcome to the debate on: Cats vs dogs. Pepito won the coin flip and opens.", introMessage(config, "Pepito", true))
+
+	config.IntroMessage = "Tonight's grudge match!"
+	assert.Equal(t, "Tonight's grudge match!", introMessage(config, "Pepito", false))
+	assert.Equal(t, "Tonight's grudge match! Pepito won the coin flip and opens.", introMessage(config, "Pepito", true))
+}
+
+func TestDeliverOpeningStatements(t *testing.T) {
+	config := conversation.DefaultConfig()
+	config.TurnDelay = 0
+	config.FirstSpeaker = conversation.FirstSpeakerAgent2
+	config.OpeningStatements = map[string]string{"Pepito": "Messi, obviously.", "Tony": "Ronaldo works harder."}
+	session, err := conversation.NewDebateSession("opening", agent.NewOfflineAgent(agent.AgentConfig{Name: "Pepito"}),
+		agent.NewOfflineAgent(agent.AgentConfig{Name: "Tony"}), config, "")
+	require.NoError(t, err)
+	session.UpdateStatus("active")
+
+	manager := &DebateManager{db: &TestMockDB{}, server: &Server{db: &TestMockDB{}}}
+	manager.deliverOpeningStatements(context.Background(), session)
+
+	// Statements follow the speaking order, and the first speaker still opens the free-form turns
+	history := session.GetRecentHistory(10)
+	require.Len(t, history, 2)
+	assert.Equal(t, "Tony", history[0].Speaker)
+	assert.Equal(t, "Pepito", history[1].Speaker)
+	assert.Nil(t, history[1].AverageScore, "opening statements are not scored")
+	assert.Equal(t, uint64(2), session.LastSeq(), "offline agents have no audio, so only the statements are broadcast")
+	assert.Equal(t, conversation.GameScore{Agent1Score: 100, Agent2Score: 100}, session.GetGameScore())
+	assert.Equal(t, "Tony", session.GetNextAgent().GetName())
+}
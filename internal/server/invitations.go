@@ -63,6 +63,26 @@ func (s *Server) listInvitationsHandler(c *gin.Context) {
 	})
 }
 
+// invitationStatsHandler summarizes how the current user's invitations have been used
+func (s *Server) invitationStatsHandler(c *gin.Context) {
+	// Get the current user ID
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	stats, err := s.db.GetInvitationStats(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get invitation stats: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stats": stats,
+	})
+}
+
 // deleteInvitationHandler deletes an invitation code
 func (s *Server) deleteInvitationHandler(c *gin.Context) {
 	// Get the current user ID
@@ -133,6 +153,7 @@ func (s *Server) setupInvitationRoutes() {
 		invitationGroup.Use(s.auth.AuthMiddleware())
 		invitationGroup.POST("", s.createInvitationHandler)
 		invitationGroup.GET("", s.listInvitationsHandler)
+		invitationGroup.GET("/stats", s.invitationStatsHandler)
 		invitationGroup.DELETE("/:id", s.deleteInvitationHandler)
 	}
 }
@@ -449,3 +449,72 @@ func TestValidateInvitationHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestInvitationStatsHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+
+	// The mock has invitation history for inviter-id only
+	inviterToken, err := server.auth.GenerateToken(auth.User{ID: "inviter-id", Username: "inviter", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+	newUserToken, err := server.auth.GenerateToken(auth.User{ID: "new-user-id", Username: "newuser", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name             string
+		token            string
+		expectedStatus   int
+		expectedTotal    int
+		expectedAccepted int
+	}{
+		{
+			name:             "Inviter with accepted codes",
+			token:            inviterToken,
+			expectedStatus:   http.StatusOK,
+			expectedTotal:    3,
+			expectedAccepted: 1,
+		},
+		{
+			name:             "User without invitations",
+			token:            newUserToken,
+			expectedStatus:   http.StatusOK,
+			expectedTotal:    0,
+			expectedAccepted: 0,
+		},
+		{
+			name:           "Unauthenticated",
+			token:          "",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/api/invitations/stats", nil)
+			require.NoError(t, err)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.expectedStatus == http.StatusOK {
+				var response struct {
+					Stats database.InvitationStats `json:"stats"`
+				}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+
+				assert.Equal(t, tc.expectedTotal, response.Stats.Total)
+				assert.Len(t, response.Stats.Accepted, tc.expectedAccepted)
+				if tc.expectedAccepted > 0 {
+					assert.Equal(t, "invitee", response.Stats.Accepted[0].Username)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/database"
+)
+
+// leaderboardCacheTTL is how long a debate's leaderboard is cached when nothing invalidates it
+const leaderboardCacheTTL = 30 * time.Second
+
+// maxLeaderboardSize is the number of top arguments cached per debate; pages past it are empty
+const maxLeaderboardSize = 100
+
+// leaderboardBroadcastSize is the number of arguments sent in leaderboard_update broadcasts
+const leaderboardBroadcastSize = 10
+
+// leaderboard returns a debate's top arguments, ranked by score, from the leaderboard cache.
+// The returned arguments are shared with the cache and must not be modified.
+func (s *Server) leaderboard(debateID string) ([]*database.Argument, error) {
+	value, err := s.leaderboardCache.GetOrCompute(debateID, func() (interface{}, error) {
+		return s.db.GetLeaderboard(debateID, maxLeaderboardSize)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]*database.Argument), nil
+}
+
+// invalidateLeaderboard drops a debate's cached leaderboard, after one of its arguments, scores or votes changed
+func (s *Server) invalidateLeaderboard(debateID string) {
+	s.leaderboardCache.Delete(debateID)
+}
+
+// broadcastLeaderboard sends the top of a debate's leaderboard to its clients, if the debate is running
+func (s *Server) broadcastLeaderboard(debateID string) {
+	session, exists := s.debateManager.GetDebate(debateID)
+	if !exists {
+		return
+	}
+
+	leaderboard, err := s.leaderboard(debateID)
+	if err != nil {
+		log.Printf("Error getting leaderboard for broadcast in debate %s: %v", debateID, err)
+		return
+	}
+	if len(leaderboard) > leaderboardBroadcastSize {
+		leaderboard = leaderboard[:leaderboardBroadcastSize]
+	}
+
+	session.Broadcast(gin.H{
+		"type":        "leaderboard_update",
+		"debate_id":   debateID,
+		"leaderboard": leaderboard,
+	})
+}
+
+// getLeaderboardHandler returns a page of the top-scoring arguments for a specific debate, with their score
+// breakdowns and vote counts. Authenticated callers also get their own vote on each argument as user_vote.
+// Debates without scored arguments yet get an empty leaderboard.
+func (s *Server) getLeaderboardHandler(c *gin.Context) {
+	debateID := c.Param("debateID")
+	if debateID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Debate ID is required"})
+		return
+	}
+
+	if status, err := s.checkDebateAccess(c, debateID); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get limit parameter, default to 10, capped at 100
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+	if limit > maxLeaderboardSize {
+		limit = maxLeaderboardSize
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	leaderboard, err := s.leaderboard(debateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get leaderboard", "details": err.Error()})
+		return
+	}
+
+	page := []*database.Argument{}
+	if offset < len(leaderboard) {
+		end := offset + limit
+		if end > len(leaderboard) {
+			end = len(leaderboard)
+		}
+		page = leaderboard[offset:end]
+	}
+
+	if userID, authenticated := auth.GetUserID(c); authenticated {
+		votes, err := s.db.GetUserVotesForDebate(userID, debateID)
+		if err != nil {
+			log.Printf("Failed to get votes of user %s in debate %s: %v", userID, debateID, err)
+		} else {
+			// Annotate copies, since the cached arguments are shared between callers
+			annotated := make([]*database.Argument, len(page))
+			for i, argument := range page {
+				argumentCopy := *argument
+				argumentCopy.UserVote = votes[argument.ID]
+				annotated[i] = &argumentCopy
+			}
+			page = annotated
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"debate_id":   debateID,
+		"leaderboard": page,
+		"limit":       limit,
+		"offset":      offset,
+		"total":       len(leaderboard),
+	})
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// leaderboardCountingDB counts GetLeaderboard queries
+type leaderboardCountingDB struct {
+	*TestMockDB
+	queries int
+}
+
+func (m *leaderboardCountingDB) GetLeaderboard(debateID string, limit int) ([]*database.Argument, error) {
+	m.queries++
+	return m.TestMockDB.GetLeaderboard(debateID, limit)
+}
+
+func TestLeaderboardCache(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+
+	db := &leaderboardCountingDB{TestMockDB: &TestMockDB{}}
+	server.db = db
+	server.leaderboardCache = newTTLCache(time.Minute)
+	server.router.GET("/api/debates/:debateID/leaderboard", server.auth.OptionalAuthMiddleware(), server.getLeaderboardHandler)
+
+	get := func(query, token string) map[string]interface{} {
+		req := httptest.NewRequest(http.MethodGet, "/api/debates/debate-1/leaderboard"+query, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	response := get("", "")
+	assert.Equal(t, float64(2), response["total"])
+	assert.Len(t, response["leaderboard"], 2)
+
+	// Paging is served from the cached leaderboard
+	response = get("?limit=1&offset=1", "")
+	leaderboard := response["leaderboard"].([]interface{})
+	require.Len(t, leaderboard, 1)
+	assert.Equal(t, float64(2), leaderboard[0].(map[string]interface{})["id"])
+	assert.Empty(t, get("?offset=5", "")["leaderboard"])
+	assert.Equal(t, 1, db.queries)
+
+	// The caller's vote is added to their response only, not to the cached arguments
+	token, err := server.auth.GenerateToken(auth.User{ID: "voter-1", Username: "voter", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+	leaderboard = get("", token)["leaderboard"].([]interface{})
+	assert.Equal(t, "upvote", leaderboard[0].(map[string]interface{})["user_vote"])
+	leaderboard = get("", "")["leaderboard"].([]interface{})
+	assert.NotContains(t, leaderboard[0].(map[string]interface{}), "user_vote")
+	assert.Equal(t, 1, db.queries)
+
+	// A new score or vote invalidates the debate's leaderboard
+	server.invalidateLeaderboard("debate-1")
+	get("", "")
+	assert.Equal(t, 2, db.queries)
+}
+
+func TestLeaderboardHandler(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/debates/:debateID/leaderboard", server.auth.OptionalAuthMiddleware(), server.getLeaderboardHandler)
+
+	get := func(path string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	code, _ := get("/api/debates/missing-debate/leaderboard")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// No scored arguments is an empty list, not an error or null
+	code, response := get("/api/debates/unscored-debate/leaderboard")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{}, response["leaderboard"])
+
+	code, response = get("/api/debates/debate-1/leaderboard?limit=500")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(maxLeaderboardSize), response["limit"])
+	top := response["leaderboard"].([]interface{})[0].(map[string]interface{})
+	score := top["score"].(map[string]interface{})
+	assert.Equal(t, float64(10), score["strength"])
+	assert.Contains(t, top, "upvotes")
+}
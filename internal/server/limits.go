@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/auth"
+)
+
+// BodySizeLimitMiddleware rejects request bodies larger than maxBytes with 413.
+// Routes listed in overrides (by their registered path, e.g. "/api/stt") use their own limit instead.
+func BodySizeLimitMiddleware(maxBytes int64, overrides map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := maxBytes
+		if override, exists := overrides[c.FullPath()]; exists {
+			limit = override
+		}
+
+		// Reject declared oversize bodies up front
+		if c.Request.ContentLength > limit {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":     "Request body too large",
+				"max_bytes": limit,
+			})
+			return
+		}
+
+		// Guard against chunked or mis-declared bodies while they are read
+		if c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		}
+
+		c.Next()
+	}
+}
+
+// TimeoutMiddleware puts a deadline on the request context, so LLM calls made with it can't hang indefinitely
+func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// userRateLimiter tracks recent request times per user over a sliding window
+type userRateLimiter struct {
+	limit     int
+	window    time.Duration
+	mu        sync.Mutex
+	requests  map[string][]time.Time
+	lastSweep time.Time // When keys without recent requests were last removed
+}
+
+// allow records a request for the user if they are under the limit, otherwise it returns how long until they may retry
+func (l *userRateLimiter) allow(userID string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Forget keys that haven't sent a request within the window, at most once per window, so callers that
+	// stop sending (e.g. anonymous IPs) don't accumulate
+	if now.Sub(l.lastSweep) >= l.window {
+		for key, times := range l.requests {
+			if len(times) == 0 || now.Sub(times[len(times)-1]) >= l.window {
+				delete(l.requests, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	// Drop requests that have left the window
+	recent := l.requests[userID][:0]
+	for _, t := range l.requests[userID] {
+		if now.Sub(t) < l.window {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= l.limit {
+		l.requests[userID] = recent
+		return false, l.window - now.Sub(recent[0])
+	}
+	l.requests[userID] = append(recent, now)
+	return true, 0
+}
+
+// newUserRateLimiter creates a rate limiter allowing limit requests per window for each key
+func newUserRateLimiter(limit int, window time.Duration) *userRateLimiter {
+	return &userRateLimiter{
+		limit:    limit,
+		window:   window,
+		requests: make(map[string][]time.Time),
+	}
+}
+
+// abortRateLimited answers 429 with a Retry-After header
+func abortRateLimited(c *gin.Context, limit int, retryAfter time.Duration) {
+	seconds := int(retryAfter.Seconds()) + 1
+	c.Header("Retry-After", fmt.Sprintf("%d", seconds))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+		"error":               "Rate limit exceeded",
+		"limit":               limit,
+		"retry_after_seconds": seconds,
+	})
+}
+
+// UserRateLimitMiddleware allows each authenticated user at most limit requests per window, answering 429 beyond that.
+// It must run after the auth middleware; requests without a user ID are passed through.
+func UserRateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	limiter := newUserRateLimiter(limit, window)
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			c.Next()
+			return
+		}
+
+		if ok, retryAfter := limiter.allow(userID, time.Now()); !ok {
+			abortRateLimited(c, limit, retryAfter)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ClientRateLimitMiddleware is UserRateLimitMiddleware for routes open to anonymous callers: authenticated users
+// are limited by user ID and anonymous callers by IP. It must run after the optional auth middleware.
+func ClientRateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	limiter := newUserRateLimiter(limit, window)
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if userID, exists := auth.GetUserID(c); exists {
+			key = "user:" + userID
+		}
+
+		if ok, retryAfter := limiter.allow(key, time.Now()); !ok {
+			abortRateLimited(c, limit, retryAfter)
+			return
+		}
+
+		c.Next()
+	}
+}
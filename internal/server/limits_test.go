@@ -0,0 +1,142 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodySizeLimitMiddleware(t *testing.T) {
+	// Set Gin to test mode
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(BodySizeLimitMiddleware(10, map[string]int64{"/upload": 100}))
+	readBody := func(c *gin.Context) {
+		if _, err := io.ReadAll(c.Request.Body); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusOK)
+	}
+	router.POST("/json", readBody)
+	router.POST("/upload", readBody)
+
+	testCases := []struct {
+		name           string
+		path           string
+		bodySize       int
+		expectedStatus int
+	}{
+		{
+			name:           "Body within default limit",
+			path:           "/json",
+			bodySize:       10,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Body over default limit",
+			path:           "/json",
+			bodySize:       11,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Body within route override",
+			path:           "/upload",
+			bodySize:       50,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Body over route override",
+			path:           "/upload",
+			bodySize:       101,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", tc.path, bytes.NewBuffer(make([]byte, tc.bodySize)))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestUserRateLimitMiddleware(t *testing.T) {
+	// Set Gin to test mode
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-User"); userID != "" {
+			c.Set("userID", userID)
+		}
+		c.Next()
+	})
+	router.POST("/limited", UserRateLimitMiddleware(2, time.Minute), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	send := func(userID string) int {
+		req, err := http.NewRequest("POST", "/limited", nil)
+		require.NoError(t, err)
+		if userID != "" {
+			req.Header.Set("X-User", userID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Each user gets their own allowance
+	assert.Equal(t, http.StatusOK, send("user-1"))
+	assert.Equal(t, http.StatusOK, send("user-1"))
+	assert.Equal(t, http.StatusTooManyRequests, send("user-1"))
+	assert.Equal(t, http.StatusOK, send("user-2"))
+
+	// Requests without a user aren't tracked
+	assert.Equal(t, http.StatusOK, send(""))
+}
+
+func TestUserRateLimiterWindow(t *testing.T) {
+	limiter := &userRateLimiter{limit: 1, window: time.Minute, requests: make(map[string][]time.Time)}
+	start := time.Now()
+
+	ok, _ := limiter.allow("user-1", start)
+	assert.True(t, ok)
+
+	ok, retryAfter := limiter.allow("user-1", start.Add(20*time.Second))
+	assert.False(t, ok)
+	assert.Equal(t, 40*time.Second, retryAfter)
+
+	// Once the first request leaves the window the user may try again
+	ok, _ = limiter.allow("user-1", start.Add(time.Minute))
+	assert.True(t, ok)
+}
+
+func TestUserRateLimiterSweep(t *testing.T) {
+	limiter := newUserRateLimiter(5, time.Minute)
+	start := time.Now()
+
+	limiter.allow("ip:10.0.0.1", start)
+	limiter.allow("ip:10.0.0.2", start.Add(30*time.Second))
+	assert.Len(t, limiter.requests, 2)
+
+	// Keys whose requests have all left the window are removed by the next sweep
+	limiter.allow("ip:10.0.0.3", start.Add(80*time.Second))
+	assert.Len(t, limiter.requests, 2)
+	assert.NotContains(t, limiter.requests, "ip:10.0.0.1")
+	assert.Contains(t, limiter.requests, "ip:10.0.0.2")
+}
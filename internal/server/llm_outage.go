@@ -0,0 +1,252 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/logging"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
+)
+
+// llmMaintenanceMessage is broadcast to the debates paused when the LLM provider stops serving the account
+const llmMaintenanceMessage = "The debate is paused for maintenance while the AI provider is unavailable. It will resume automatically."
+
+// llmProbeTimeout bounds each recovery check of the LLM provider
+const llmProbeTimeout = 30 * time.Second
+
+// ErrLLMUnavailable is returned when a debate can't be created because the LLM provider is rejecting the account
+var ErrLLMUnavailable = errors.New("debates are paused for maintenance while the AI provider is unavailable, try again later")
+
+// llmOutage tracks whether the LLM provider keeps rejecting the account, e.g. for an exhausted quota or a revoked
+// key. Debates can't be played without it, so after threshold consecutive quota or auth errors the server is
+// degraded: active debates are paused and new ones refused until a probe gets an answer again. Other errors,
+// like timeouts and rate limits, don't count.
+type llmOutage struct {
+	threshold int
+	now       func() time.Time
+
+	mu        sync.Mutex
+	failures  int // Consecutive quota or auth errors
+	degraded  bool
+	since     time.Time // When the server became degraded
+	lastError string
+	paused    []string // Debates paused for the outage, resumed when it ends
+}
+
+// llmOutageStatus is the LLM outage state as reported by readyz
+type llmOutageStatus struct {
+	Degraded            bool       `json:"degraded"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Since               *time.Time `json:"since,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	PausedDebates       int        `json:"paused_debates"`
+}
+
+// newLLMOutage creates a tracker for a healthy provider. A nil *llmOutage never degrades.
+func newLLMOutage(threshold int) *llmOutage {
+	return &llmOutage{threshold: threshold, now: time.Now}
+}
+
+// recordResult counts the outcome of an LLM call and reports whether it made the server degraded
+func (o *llmOutage) recordResult(err error) bool {
+	if o == nil {
+		return false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if !usage.IsQuotaOrAuthError(err) {
+		// Calls in flight when the outage began may still succeed, but only a probe ends it
+		if err == nil && !o.degraded {
+			o.failures = 0
+		}
+		return false
+	}
+
+	o.failures++
+	o.lastError = err.Error()
+	if o.degraded || o.failures < o.threshold {
+		return false
+	}
+	o.degraded = true
+	o.since = o.now()
+	logging.Error("LLM provider keeps rejecting the account, pausing debates", map[string]interface{}{
+		"consecutive_failures": o.failures,
+		"error":                o.lastError,
+	})
+	return true
+}
+
+// isDegraded reports whether debates are paused for an outage
+func (o *llmOutage) isDegraded() bool {
+	if o == nil {
+		return false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.degraded
+}
+
+// addPaused records debates paused for the outage
+func (o *llmOutage) addPaused(debateIDs []string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.paused = append(o.paused, debateIDs...)
+}
+
+// restorePaused marks the server degraded for debates that were paused for an outage before a restart, so the
+// probe resumes them once the provider answers again
+func (o *llmOutage) restorePaused(debateIDs []string) {
+	if o == nil || len(debateIDs) == 0 {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if !o.degraded {
+		o.degraded = true
+		o.since = o.now()
+		o.lastError = "debates were paused for an LLM outage before the restart"
+	}
+	o.paused = append(o.paused, debateIDs...)
+}
+
+// recover ends the outage, returning the debates it paused and how long it lasted
+func (o *llmOutage) recover() ([]string, time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	paused, lasted := o.paused, o.now().Sub(o.since)
+	o.degraded = false
+	o.failures = 0
+	o.lastError = ""
+	o.paused = nil
+	return paused, lasted
+}
+
+// status returns a snapshot of the outage state
+func (o *llmOutage) status() llmOutageStatus {
+	if o == nil {
+		return llmOutageStatus{}
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	status := llmOutageStatus{
+		Degraded:            o.degraded,
+		ConsecutiveFailures: o.failures,
+		LastError:           o.lastError,
+		PausedDebates:       len(o.paused),
+	}
+	if o.degraded {
+		since := o.since
+		status.Since = &since
+	}
+	return status
+}
+
+// recordLLMResult feeds the outcome of an agent or scorer LLM call to the outage tracker, pausing every active
+// debate once the provider has rejected the account too many times in a row
+func (m *DebateManager) recordLLMResult(err error) {
+	if m.llmOutage.recordResult(err) {
+		m.pauseForLLMOutage()
+	}
+}
+
+// pauseForLLMOutage pauses every active debate, whose loops stop at their next turn, and tells their clients why
+func (m *DebateManager) pauseForLLMOutage() {
+	m.debatesMutex.RLock()
+	sessions := make([]*conversation.DebateSession, 0, len(m.debates))
+	for _, session := range m.debates {
+		sessions = append(sessions, session)
+	}
+	m.debatesMutex.RUnlock()
+
+	var paused []string
+	for _, session := range sessions {
+		if session.GetStatus() != types.DebateStatusActive || !session.UpdateStatus(types.DebateStatusPaused) {
+			continue
+		}
+		if err := m.db.UpdateDebateStatus(session.DebateID, types.DebateStatusPaused); err != nil {
+			logging.Error("Failed to record debate paused for LLM outage", map[string]interface{}{
+				"debate_id": session.DebateID,
+				"error":     err.Error(),
+			})
+		}
+		session.Broadcast(gin.H{
+			"type":        "system",
+			"message":     llmMaintenanceMessage,
+			"maintenance": true,
+		})
+		paused = append(paused, session.DebateID)
+	}
+	m.llmOutage.addPaused(paused)
+
+	logging.Warn("Debates paused for LLM outage", map[string]interface{}{
+		"paused_debates": len(paused),
+	})
+}
+
+// StartLLMProbe periodically checks whether the LLM provider serves requests again during an outage
+func (m *DebateManager) StartLLMProbe(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			m.ProbeLLMRecovery()
+		}
+	}()
+}
+
+// ProbeLLMRecovery checks the LLM provider if the server is degraded, and once it answers, ends the outage and
+// resumes the debates paused for it. Paused debates get the time they were paused added to their deadline.
+// It reports whether the outage ended.
+func (m *DebateManager) ProbeLLMRecovery() bool {
+	if !m.llmOutage.isDegraded() || m.probeLLM == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), llmProbeTimeout)
+	defer cancel()
+	if err := m.probeLLM(ctx); err != nil {
+		logging.Warn("LLM provider still unavailable", map[string]interface{}{"error": err.Error()})
+		return false
+	}
+
+	paused, lasted := m.llmOutage.recover()
+	resumed := 0
+	for _, debateID := range paused {
+		session, exists := m.GetDebate(debateID)
+		// Debates ended by a moderator during the outage stay ended
+		if !exists || session.GetStatus() != types.DebateStatusPaused || !session.UpdateStatus(types.DebateStatusActive) {
+			continue
+		}
+		if err := m.db.UpdateDebateStatus(debateID, types.DebateStatusActive); err != nil {
+			logging.Error("Failed to record debate resumed after LLM outage", map[string]interface{}{
+				"debate_id": debateID,
+				"error":     err.Error(),
+			})
+		}
+		if deadline := session.GetDeadline(); !deadline.IsZero() {
+			session.SetDeadline(deadline.Add(lasted))
+			if err := m.db.SaveDebateDeadline(debateID, deadline.Add(lasted)); err != nil {
+				logging.Error("Failed to save debate deadline after LLM outage", map[string]interface{}{
+					"debate_id": debateID,
+					"error":     err.Error(),
+				})
+			}
+		}
+		m.StartDebateLoop(session)
+		resumed++
+	}
+
+	logging.Info("LLM provider recovered, resuming debates", map[string]interface{}{
+		"outage":          lasted.String(),
+		"resumed_debates": resumed,
+	})
+	return true
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsQuotaOrAuthError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "No error", err: nil, expected: false},
+		{name: "Exhausted quota", err: errors.New("failed to generate response: API returned unexpected status code: 429: You exceeded your current quota, please check your plan and billing details."), expected: true},
+		{name: "Invalid key", err: errors.New("scoring failed: API returned unexpected status code: 401: Incorrect API key provided: sk-abc."), expected: true},
+		{name: "Revoked key", err: errors.New("API returned unexpected status code: 403"), expected: true},
+		{name: "Rate limit", err: errors.New("API returned unexpected status code: 429: Rate limit reached for gpt-4o-mini"), expected: false},
+		{name: "Server error", err: errors.New("API returned unexpected status code: 500: The server had an error"), expected: false},
+		{name: "Timeout", err: context.DeadlineExceeded, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, usage.IsQuotaOrAuthError(tc.err))
+		})
+	}
+}
+
+func TestLLMOutagePausesAndResumesDebates(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/readyz", server.readyzHandler)
+
+	newSession := func(id string, status types.DebateStatus) *conversation.DebateSession {
+		session, err := conversation.NewDebateSession(id, agent.NewOfflineAgent(agent.AgentConfig{Name: "Pepito"}),
+			agent.NewOfflineAgent(agent.AgentConfig{Name: "Tony"}), conversation.DebateConfig{Topic: "Messi vs Ronaldo"}, "")
+		require.NoError(t, err)
+		session.UpdateStatus(status)
+		return session
+	}
+	active := newSession("active", types.DebateStatusActive)
+	waiting := newSession("waiting", types.DebateStatusWaiting)
+	deadline := time.Now().Add(10 * time.Minute)
+	active.SetDeadline(deadline)
+
+	now := time.Now()
+	outage := newLLMOutage(2)
+	outage.now = func() time.Time { return now }
+	probeErr := errors.New("API returned unexpected status code: 429: You exceeded your current quota")
+	server.debateManager = &DebateManager{
+		db:        server.db,
+		server:    server,
+		debates:   map[string]*conversation.DebateSession{"active": active, "waiting": waiting},
+		llmOutage: outage,
+		probeLLM:  func(ctx context.Context) error { return probeErr },
+	}
+	manager := server.debateManager
+
+	readyz := func() map[string]interface{} {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	// Only consecutive quota or auth errors count
+	manager.recordLLMResult(probeErr)
+	manager.recordLLMResult(nil)
+	manager.recordLLMResult(probeErr)
+	manager.recordLLMResult(errors.New("API returned unexpected status code: 500"))
+	assert.False(t, outage.isDegraded())
+	assert.Equal(t, types.DebateStatusActive, active.GetStatus())
+	assert.Equal(t, "ready", readyz()["status"])
+
+	manager.recordLLMResult(probeErr)
+	require.True(t, outage.isDegraded())
+	assert.Equal(t, types.DebateStatusPaused, active.GetStatus())
+	assert.Equal(t, types.DebateStatusWaiting, waiting.GetStatus(), "only active debates are paused")
+	response := readyz()
+	assert.Equal(t, "degraded", response["status"])
+	llm := response["llm"].(map[string]interface{})
+	assert.Equal(t, true, llm["degraded"])
+	assert.Equal(t, float64(1), llm["paused_debates"])
+
+	// New debates are refused while degraded
+	_, err := manager.CreateDebateWithConfig(conversation.DefaultConfig(), agent.NewOfflineAgent(agent.AgentConfig{Name: "Pepito"}),
+		agent.NewOfflineAgent(agent.AgentConfig{Name: "Tony"}), "creator-id", database.DebateSettings{})
+	assert.ErrorIs(t, err, ErrLLMUnavailable)
+
+	// Until a probe gets an answer, nothing changes
+	assert.False(t, manager.ProbeLLMRecovery())
+	assert.True(t, outage.isDegraded())
+
+	// Keep the resumed debate from running its loop in the test
+	require.True(t, active.TryStartLoop())
+	now = now.Add(5 * time.Minute)
+	probeErr = nil
+	assert.True(t, manager.ProbeLLMRecovery())
+	assert.False(t, outage.isDegraded())
+	assert.Equal(t, types.DebateStatusActive, active.GetStatus())
+	assert.Equal(t, deadline.Add(5*time.Minute), active.GetDeadline(), "the outage doesn't count against the debate's time")
+	assert.Equal(t, "ready", readyz()["status"])
+	assert.False(t, manager.ProbeLLMRecovery(), "there's nothing to probe once recovered")
+}
+
+func TestRespondDebateCreateFailedLLMUnavailable(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	server.respondDebateCreateFailed(c, ErrLLMUnavailable)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), `"degraded":true`)
+}
+
+func TestDebatesPausedForLLMOutageResumeAfterRestart(t *testing.T) {
+	mockDB := &transcriptDB{MockDatabaseForDebate: new(MockDatabaseForDebate)}
+	deadline := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	mockDB.On("ListActiveDebates").Return([]*database.Debate{
+		{ID: "paused", Topic: "Cats vs dogs", Status: "paused", Agent1Name: "Agent1", Agent2Name: "Agent2", Deadline: &deadline},
+	}, nil)
+	mockDB.On("UpdateDebateStatus", "paused", types.DebateStatusActive).Return(nil)
+
+	debateManager := &DebateManager{
+		db: mockDB,
+		agents: NewAgentRegistry(map[string]*agent.Agent{
+			"Agent1": agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent1"}),
+			"Agent2": agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent2"}),
+		}),
+		debates:   make(map[string]*conversation.DebateSession),
+		llmOutage: newLLMOutage(2),
+		probeLLM:  func(ctx context.Context) error { return nil },
+	}
+	now := time.Now()
+	debateManager.llmOutage.now = func() time.Time { return now }
+	require.NoError(t, debateManager.LoadActiveDebates())
+
+	session, exists := debateManager.GetDebate("paused")
+	require.True(t, exists)
+	assert.Equal(t, types.DebateStatusPaused, session.GetStatus())
+	assert.Equal(t, deadline, session.GetDeadline())
+	status := debateManager.llmOutage.status()
+	assert.True(t, status.Degraded, "the server stays degraded until the provider answers a probe")
+	assert.Equal(t, 1, status.PausedDebates)
+
+	// Keep the resumed debate from running its loop in the test
+	require.True(t, session.TryStartLoop())
+	assert.True(t, debateManager.ProbeLLMRecovery())
+	assert.Equal(t, types.DebateStatusActive, session.GetStatus())
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// lobbySubscriberBuffer is how many events a slow lobby subscriber may fall behind before events are dropped for it
+const lobbySubscriberBuffer = 16
+
+// lobbyHub fans lobby-wide events, such as a scheduled debate opening, out to connected lobby clients.
+// A nil *lobbyHub is valid and drops every event.
+type lobbyHub struct {
+	mu          sync.Mutex
+	subscribers map[chan gin.H]struct{}
+}
+
+// newLobbyHub creates an empty lobby hub
+func newLobbyHub() *lobbyHub {
+	return &lobbyHub{subscribers: make(map[chan gin.H]struct{})}
+}
+
+// Subscribe registers a new subscriber; call the returned function to unsubscribe
+func (h *lobbyHub) Subscribe() (<-chan gin.H, func()) {
+	ch := make(chan gin.H, lobbySubscriberBuffer)
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers, ch)
+		h.mu.Unlock()
+	}
+}
+
+// Publish sends an event to every subscriber without blocking on slow ones
+func (h *lobbyHub) Publish(event gin.H) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default: // Subscriber is full, drop the event for it
+		}
+	}
+}
+
+// lobbyEventsHandler streams lobby events to the client as server-sent events
+func (s *Server) lobbyEventsHandler(c *gin.Context) {
+	if s.lobby == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Lobby events are unavailable"})
+		return
+	}
+
+	events, unsubscribe := s.lobby.Subscribe()
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Writer.Flush()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return // Client went away
+		case event := <-events:
+			eventType, _ := event["type"].(string)
+			c.SSEvent(eventType, event)
+			c.Writer.Flush()
+		}
+	}
+}
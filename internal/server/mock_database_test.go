@@ -2,10 +2,14 @@ package server
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/neo/convinceme_backend/internal/database"
 	"github.com/neo/convinceme_backend/internal/scoring"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
 )
 
 // timePtr returns a pointer to the given time.Time
@@ -27,7 +31,7 @@ func (m *TestMockDB) Close() error {
 }
 
 // CreateDebate adds a new debate session to the database
-func (m *TestMockDB) CreateDebate(id, topic, status, agent1Name, agent2Name string) error {
+func (m *TestMockDB) CreateDebate(id, topic string, status types.DebateStatus, agent1Name, agent2Name string) error {
 	// For testing, just return nil (success)
 	return nil
 }
@@ -54,7 +58,7 @@ func (m *TestMockDB) GetUserByID(id string) (*database.User, error) {
 			EmailVerified: true,
 		}, nil
 	}
-	return nil, errors.New("user not found")
+	return nil, database.ErrUserNotFound
 }
 
 // GetUserByUsername gets a user by username
@@ -113,14 +117,28 @@ func (m *TestMockDB) GetUserByEmail(email string) (*database.User, error) {
 	return nil, errors.New("user not found")
 }
 
-// UpdateUser updates a user
-func (m *TestMockDB) UpdateUser(user *database.User) error {
+// UpdateUser updates a user. Mock users always have a zero UpdatedAt, so any other expected version is stale.
+func (m *TestMockDB) UpdateUser(user *database.User, expectedUpdatedAt time.Time) error {
+	if !expectedUpdatedAt.IsZero() {
+		return database.ErrUserModified
+	}
 	return nil
 }
 
 // DeleteUser deletes a user
 func (m *TestMockDB) DeleteUser(id string) error {
-	return nil
+	if id == "test-user-id" {
+		return nil
+	}
+	return database.ErrUserNotFound
+}
+
+// DeleteUserAnonymized deletes a user's account while keeping their debate content under a placeholder ID
+func (m *TestMockDB) DeleteUserAnonymized(id string) (string, error) {
+	if id == "test-user-id" {
+		return "deleted_user_0123456789ab", nil
+	}
+	return "", database.ErrUserNotFound
 }
 
 // VerifyPassword verifies a user's password
@@ -324,16 +342,54 @@ func (m *TestMockDB) CleanupExpiredInvitations() error {
 
 // GetDebate gets a debate by ID
 func (m *TestMockDB) GetDebate(id string) (*database.Debate, error) {
+	if id == "missing-debate" {
+		return nil, errors.New("debate not found")
+	}
+	createdBy := "creator-id"
+	status := types.DebateStatusActive
+	if id == "finished-debate" {
+		status = types.DebateStatusFinished
+	}
 	return &database.Debate{
 		ID:         id,
 		Topic:      "Test Topic",
-		Status:     "active",
+		Status:     status,
 		Agent1Name: "Agent 1",
 		Agent2Name: "Agent 2",
 		CreatedAt:  time.Now(),
+		Visibility: database.DebateVisibilityPublic,
+		CreatedBy:  &createdBy,
 	}, nil
 }
 
+// SaveDebateSettings saves a debate's optional settings
+func (m *TestMockDB) SaveDebateSettings(id string, settings database.DebateSettings) error {
+	return nil
+}
+
+// SaveDebateDeadline records when a debate's loop times out
+func (m *TestMockDB) SaveDebateDeadline(id string, deadline time.Time) error {
+	return nil
+}
+
+// SetDebateFeatured features or unfeatures a debate
+func (m *TestMockDB) SetDebateFeatured(id string, featured bool) error {
+	if id == "missing-debate" {
+		return errors.New("debate not found")
+	}
+	return nil
+}
+
+// AddDebateParticipant adds a user to a debate's allowlist
+func (m *TestMockDB) AddDebateParticipant(debateID, userID, invitedBy string) error {
+	return nil
+}
+
+// IsDebateParticipant checks whether a user is on a debate's allowlist
+func (m *TestMockDB) IsDebateParticipant(debateID, userID string) (bool, error) {
+	return userID == "test-user-id", nil
+}
+
 // ListActiveDebates lists all active debates
 func (m *TestMockDB) ListActiveDebates() ([]*database.Debate, error) {
 	return []*database.Debate{
@@ -358,6 +414,9 @@ func (m *TestMockDB) ListActiveDebates() ([]*database.Debate, error) {
 
 // ListDebates lists debates with pagination and filtering
 func (m *TestMockDB) ListDebates(filter database.DebateFilter) ([]*database.Debate, int, error) {
+	if filter.Featured {
+		return []*database.Debate{}, 0, nil // Nothing is featured
+	}
 	return []*database.Debate{
 		{
 			ID:         "debate-1",
@@ -370,16 +429,246 @@ func (m *TestMockDB) ListDebates(filter database.DebateFilter) ([]*database.Deba
 	}, 1, nil
 }
 
+// GetDueScheduledDebates lists scheduled debates whose start time has passed
+func (m *TestMockDB) GetDueScheduledDebates(now time.Time) ([]*database.Debate, error) {
+	return []*database.Debate{}, nil
+}
+
 // UpdateDebateStatus updates a debate's status
-func (m *TestMockDB) UpdateDebateStatus(id, status string) error {
+func (m *TestMockDB) UpdateDebateStatus(id string, status types.DebateStatus) error {
+	return nil
+}
+
+// UpdateDebateEnd updates a debate's end status, winner and end reason
+func (m *TestMockDB) UpdateDebateEnd(id string, status types.DebateStatus, winner, reason string) error {
 	return nil
 }
 
-// UpdateDebateEnd updates a debate's end status and winner
-func (m *TestMockDB) UpdateDebateEnd(id, status string, winner string) error {
+// SaveChatMessage saves a chat message
+func (m *TestMockDB) SaveChatMessage(debateID, playerID, username, message string) (int64, error) {
+	return 1, nil
+}
+
+// GetChatMessages gets recent chat messages for a debate
+func (m *TestMockDB) GetChatMessages(debateID string, limit int) ([]*database.ChatMessage, error) {
+	return []*database.ChatMessage{}, nil
+}
+
+// UpdateDebateActiveTime records a debate's active time
+func (m *TestMockDB) UpdateDebateActiveTime(id string, activeSeconds int64) error {
+	return nil
+}
+
+// GetAverageDebateDuration gets the average duration of ended debates
+func (m *TestMockDB) GetAverageDebateDuration(filter database.DebateFilter) (float64, error) {
+	return 300, nil
+}
+
+// GetDebateStats gets aggregate debate statistics
+func (m *TestMockDB) GetDebateStats(filter database.DebateFilter) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"total_count": 3,
+		"count_by_status": map[string]int{
+			"active":   1,
+			"finished": 2,
+		},
+		"average_duration_seconds": 300.0,
+		"most_active_agents":       []map[string]interface{}{{"agent": "Agent 1", "appearances": 3}},
+		"top_winning_agents":       []map[string]interface{}{{"agent": "Agent 1", "wins": 2}},
+		"debates_over_time":        map[string]int{"2023-01-01": 3},
+	}, nil
+}
+
+// GetAgentWinRates gets each agent's record across finished debates
+func (m *TestMockDB) GetAgentWinRates() ([]*database.AgentWinRate, error) {
+	return []*database.AgentWinRate{
+		{Agent: "Retired Agent", Appearances: 1, Wins: 1, Losses: 0, WinPercentage: 100},
+		{Agent: "Agent 1", Appearances: 5, Wins: 3, Losses: 2, WinPercentage: 60},
+		{Agent: "Agent 2", Appearances: 5, Wins: 2, Losses: 3, WinPercentage: 40},
+	}, nil
+}
+
+// GetScoreBreakdown gets the scoring criteria averages of a debate's arguments per side and per player
+func (m *TestMockDB) GetScoreBreakdown(debateID string) ([]*database.CriteriaBreakdown, error) {
+	return []*database.CriteriaBreakdown{
+		{Kind: database.BreakdownSide, Name: "agent1", Count: 2, Strength: 80, Relevance: 70, Logic: 40, Truth: 60, Humor: 90, Average: 6.8},
+		{Kind: database.BreakdownSide, Name: "neutral", Count: 1, SingleSample: true, Strength: 50, Relevance: 50, Logic: 50, Truth: 50, Humor: 50, Average: 5},
+		{Kind: database.BreakdownPlayer, Name: "alice", Count: 2, Strength: 80, Relevance: 70, Logic: 40, Truth: 60, Humor: 90, Average: 6.8},
+		{Kind: database.BreakdownPlayer, Name: "bob", Count: 1, SingleSample: true, Strength: 50, Relevance: 50, Logic: 50, Truth: 50, Humor: 50, Average: 5},
+	}, nil
+}
+
+// SaveDebateMessage saves a debate transcript entry
+func (m *TestMockDB) SaveDebateMessage(msg *database.DebateMessage) (int64, error) {
+	return 1, nil
+}
+
+// UpdateDebateMessage replaces a debate transcript entry
+func (m *TestMockDB) UpdateDebateMessage(msg *database.DebateMessage) error {
+	return nil
+}
+
+// GetDebateMessages gets a debate's transcript
+func (m *TestMockDB) GetDebateMessages(debateID string) ([]*database.DebateMessage, error) {
+	start := time.Now().Add(-time.Minute)
+	score := 7.5
+	return []*database.DebateMessage{
+		{ID: 1, DebateID: debateID, Speaker: "Agent 1", Message: "Opening argument", Score: &score, AudioURL: "/api/audio/expired", Agent1Delta: 5, Agent2Delta: -5, CreatedAt: start},
+		{ID: 2, DebateID: debateID, Speaker: "player1", Message: "Player argument", IsPlayer: true, Score: &score, CreatedAt: start.Add(2 * time.Second)},
+	}, nil
+}
+
+// GetDebateMessagesBefore pages back through a debate's transcript. "long-debate" has 120 messages with IDs 1-120
+// and "memory-only-debate" has none persisted; other debates have the transcript of GetDebateMessages.
+func (m *TestMockDB) GetDebateMessagesBefore(debateID string, beforeID int64, limit int) ([]*database.DebateMessage, bool, error) {
+	var all []*database.DebateMessage
+	switch debateID {
+	case "long-debate":
+		start := time.Now().Add(-time.Hour)
+		for id := int64(1); id <= 120; id++ {
+			all = append(all, &database.DebateMessage{
+				ID:        id,
+				DebateID:  debateID,
+				Speaker:   "Agent 1",
+				Message:   fmt.Sprintf("Argument %d", id),
+				CreatedAt: start.Add(time.Duration(id) * time.Second),
+			})
+		}
+	case "memory-only-debate":
+	default:
+		all, _ = m.GetDebateMessages(debateID)
+	}
+
+	var older []*database.DebateMessage
+	for _, msg := range all {
+		if beforeID == 0 || msg.ID < beforeID {
+			older = append(older, msg)
+		}
+	}
+	if len(older) > limit {
+		return older[len(older)-limit:], true, nil
+	}
+	return older, false, nil
+}
+
+// SaveDebateUsage saves a debate's usage
+func (m *TestMockDB) SaveDebateUsage(debateID string, u usage.Usage) error {
+	return nil
+}
+
+// CreateNotification stores a notification for a user
+func (m *TestMockDB) CreateNotification(userID, notificationType string, payload interface{}) (int64, error) {
+	return 1, nil
+}
+
+// GetNotifications returns a user's notifications and unread count
+func (m *TestMockDB) GetNotifications(userID string, unreadOnly bool, limit int) ([]*database.Notification, int, error) {
+	return []*database.Notification{}, 0, nil
+}
+
+// MarkNotificationRead marks a user's notification as read
+func (m *TestMockDB) MarkNotificationRead(id int64, userID string) error {
+	return nil
+}
+
+// GetDebateParticipants returns the authenticated users who argued in a debate
+func (m *TestMockDB) GetDebateParticipants(debateID string) ([]string, error) {
+	return []string{}, nil
+}
+
+// AddBookmark bookmarks a debate for a user
+func (m *TestMockDB) AddBookmark(userID, debateID string) error {
+	return nil
+}
+
+// RemoveBookmark removes a user's bookmark of a debate
+func (m *TestMockDB) RemoveBookmark(userID, debateID string) error {
+	return nil
+}
+
+// ListBookmarks returns a user's bookmarks and their total
+func (m *TestMockDB) ListBookmarks(userID string, offset, limit int) ([]*database.Bookmark, int, error) {
+	return []*database.Bookmark{}, 0, nil
+}
+
+// CountBookmarks returns how many users have bookmarked a debate
+func (m *TestMockDB) CountBookmarks(debateID string) (int, error) {
+	return 0, nil
+}
+
+// GetDebateBookmarkers returns the users who have bookmarked a debate
+func (m *TestMockDB) GetDebateBookmarkers(debateID string) ([]string, error) {
+	return []string{}, nil
+}
+
+// AddReaction counts a reaction, as the first one for its emoji
+func (m *TestMockDB) AddReaction(debateID string, messageID int64, emoji string) (int, error) {
+	return 1, nil
+}
+
+// GetReactionCounts returns a debate's reaction counts by transcript entry
+func (m *TestMockDB) GetReactionCounts(debateID string) (map[int64]map[string]int, error) {
+	return map[int64]map[string]int{}, nil
+}
+
+// LogAuditEvent mocks recording an admin action
+func (m *TestMockDB) LogAuditEvent(actorID, action, target string, details interface{}) error {
 	return nil
 }
 
+// ListAuditEvents mocks an empty audit log
+func (m *TestMockDB) ListAuditEvents(filter database.AuditFilter) ([]*database.AuditEvent, int, error) {
+	return []*database.AuditEvent{}, 0, nil
+}
+
+// GetDebateUsage gets the usage saved for a debate; only "finished-debate" has any
+func (m *TestMockDB) GetDebateUsage(debateID string) (*database.DebateUsage, error) {
+	if debateID != "finished-debate" {
+		return nil, fmt.Errorf("no usage recorded for debate %s", debateID)
+	}
+	return &database.DebateUsage{
+		DebateID:  debateID,
+		Usage:     usage.Usage{LLMCalls: 10, PromptTokens: 2000000, CompletionTokens: 1000000, TTSCharacters: 1000},
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// GetUsageTotals sums the usage saved across all debates
+func (m *TestMockDB) GetUsageTotals() (usage.Usage, error) {
+	return usage.Usage{LLMCalls: 10, PromptTokens: 2000000, CompletionTokens: 1000000, TTSCharacters: 1000}, nil
+}
+
+// GetUserReputation gets a user's reputation score
+func (m *TestMockDB) GetUserReputation(userID string) (float64, error) {
+	return 35, nil
+}
+
+// RecomputeReputations recalculates all users' reputations
+func (m *TestMockDB) RecomputeReputations() (int64, error) {
+	return 0, nil
+}
+
+// GetUserCountsByRole gets the number of users per role
+func (m *TestMockDB) GetUserCountsByRole() (map[string]int, error) {
+	return map[string]int{"user": 12, "moderator": 2, "admin": 1}, nil
+}
+
+// GetInvitationStats gets a summary of a user's invitation codes
+func (m *TestMockDB) GetInvitationStats(userID string) (*database.InvitationStats, error) {
+	if userID == "inviter-id" {
+		return &database.InvitationStats{
+			Total:   3,
+			Used:    1,
+			Expired: 1,
+			Pending: 1,
+			Accepted: []*database.InvitationAcceptance{
+				{Code: "test-invitation-code-3", UserID: "invitee-id", Username: "invitee", UsedAt: timePtr(time.Now())},
+			},
+		}, nil
+	}
+	return &database.InvitationStats{Accepted: []*database.InvitationAcceptance{}}, nil
+}
+
 // GetTopic gets a topic by ID
 func (m *TestMockDB) GetTopic(id int) (*database.Topic, error) {
 	return &database.Topic{
@@ -412,11 +701,29 @@ func (m *TestMockDB) GetTopics(filter database.TopicFilter) ([]*database.Topic,
 	}, 1, nil
 }
 
+// CreateTopics inserts topics, numbering them from 100; "Test Topic 1" already exists and is skipped
+func (m *TestMockDB) CreateTopics(topics []*database.Topic) ([]int, error) {
+	skipped := []int{}
+	for i, topic := range topics {
+		if strings.EqualFold(topic.Title, "Test Topic 1") {
+			skipped = append(skipped, i)
+			continue
+		}
+		topic.ID = 100 + i
+	}
+	return skipped, nil
+}
+
 // SaveArgument saves an argument
-func (m *TestMockDB) SaveArgument(playerID, topic, content, side, debateID string) (int64, error) {
+func (m *TestMockDB) SaveArgument(playerID, userID, topic, content, side, debateID string, parent database.ArgumentParent) (int64, error) {
 	return 1, nil
 }
 
+// GetReplyParent returns no content, as if every argument were standalone
+func (m *TestMockDB) GetReplyParent(debateID string, parent database.ArgumentParent) (string, error) {
+	return "", nil
+}
+
 // SaveScore saves a score for an argument
 func (m *TestMockDB) SaveScore(argumentID int64, debateID string, score *scoring.ArgumentScore) error {
 	return nil
@@ -455,6 +762,9 @@ func (m *TestMockDB) GetArgumentWithScore(id int64) (*database.Argument, error)
 
 // GetLeaderboard gets the top-scoring arguments for a specific debate
 func (m *TestMockDB) GetLeaderboard(debateID string, limit int) ([]*database.Argument, error) {
+	if debateID == "unscored-debate" {
+		return nil, nil
+	}
 	return []*database.Argument{
 		{
 			ID:        1,
@@ -479,6 +789,79 @@ func (m *TestMockDB) GetLeaderboard(debateID string, limit int) ([]*database.Arg
 	}, nil
 }
 
+// GetArgumentsByDebate gets a page of a debate's arguments
+func (m *TestMockDB) GetArgumentsByDebate(debateID string, filter database.ArgumentFilter) ([]*database.Argument, int, error) {
+	arguments := []*database.Argument{
+		{
+			ID:        1,
+			PlayerID:  "player-1",
+			Topic:     "Test Topic",
+			Content:   "First argument",
+			Side:      "pro",
+			DebateID:  &debateID,
+			CreatedAt: "2023-01-01T12:00:00Z",
+			Score:     &scoring.ArgumentScore{Average: 7.5, Strength: 8, Relevance: 7, Logic: 8, Truth: 7, Humor: 7},
+			Upvotes:   2,
+			VoteScore: 0.4,
+		},
+		{
+			ID:        2,
+			PlayerID:  "player-2",
+			Topic:     "Test Topic",
+			Content:   "Second argument",
+			Side:      "con",
+			DebateID:  &debateID,
+			CreatedAt: "2023-01-01T12:05:00Z",
+		},
+	}
+	return arguments, len(arguments), nil
+}
+
+// GetArgumentsByUser gets every argument a user has submitted
+func (m *TestMockDB) GetArgumentsByUser(userID string) ([]*database.Argument, error) {
+	if userID != "player-1" {
+		return []*database.Argument{}, nil
+	}
+	debateID := "test-debate"
+	return []*database.Argument{
+		{
+			ID:        1,
+			PlayerID:  userID,
+			Topic:     "Test Topic",
+			Content:   "First argument",
+			Side:      "pro",
+			DebateID:  &debateID,
+			CreatedAt: "2023-01-01T12:00:00Z",
+			Score:     &scoring.ArgumentScore{Average: 7.5, Strength: 8, Relevance: 7, Logic: 8, Truth: 7, Humor: 7},
+		},
+	}, nil
+}
+
+// UpdateArgument mocks editing an argument
+func (m *TestMockDB) UpdateArgument(id int64, userID, content string) error {
+	return nil
+}
+
+// UpdateScore mocks replacing an argument's score
+func (m *TestMockDB) UpdateScore(argumentID int64, score *scoring.ArgumentScore) error {
+	return nil
+}
+
+// GetScoreCritique mocks an argument whose critique hasn't been generated yet
+func (m *TestMockDB) GetScoreCritique(argumentID int64) (*scoring.ScoreCritique, error) {
+	return nil, nil
+}
+
+// SaveScoreCritique mocks caching a score critique
+func (m *TestMockDB) SaveScoreCritique(argumentID int64, critique *scoring.ScoreCritique) error {
+	return nil
+}
+
+// DeleteArgument mocks deleting an argument
+func (m *TestMockDB) DeleteArgument(id int64, userID string) error {
+	return nil
+}
+
 // SubmitVote mocks submitting a vote for an argument
 func (m *TestMockDB) SubmitVote(userID string, argumentID int64, debateID string, voteType string) error {
 	return nil // Successful vote submission
@@ -496,9 +879,30 @@ func (m *TestMockDB) HasUserPaidForComment(userID string, debateID string) (bool
 
 // GetUserVoteForArgument mocks getting user's vote for a specific argument
 func (m *TestMockDB) GetUserVoteForArgument(userID string, argumentID int64) (string, error) {
+	if userID == "voter-1" && argumentID == 1 {
+		return "upvote", nil
+	}
 	return "", nil // No existing vote
 }
 
+// GetUserVotesForDebate mocks getting a user's votes in a debate; voter-1 upvoted argument 1
+func (m *TestMockDB) GetUserVotesForDebate(userID, debateID string) (map[int64]string, error) {
+	if userID == "voter-1" {
+		return map[int64]string{1: "upvote"}, nil
+	}
+	return map[int64]string{}, nil
+}
+
+// GetVotesByUser mocks getting every vote a user has cast
+func (m *TestMockDB) GetVotesByUser(userID string) ([]*database.UserVote, error) {
+	if userID != "player-1" {
+		return []*database.UserVote{}, nil
+	}
+	return []*database.UserVote{
+		{ArgumentID: 2, DebateID: "test-debate", VoteType: "upvote", CreatedAt: time.Now()},
+	}, nil
+}
+
 // CanUserVote mocks checking if user can vote on an argument
 func (m *TestMockDB) CanUserVote(userID string, argumentID int64, debateID string) (bool, string, error) {
 	return true, "You have 2 votes remaining", nil // User can vote
@@ -564,6 +968,30 @@ func (m *TestMockDB) GetFeedbackByUser(userID string) ([]*database.Feedback, err
 	}, nil
 }
 
+// GetFeedbackByUserFiltered gets the feedback of GetFeedbackByUser, filtered by type and paged
+func (m *TestMockDB) GetFeedbackByUserFiltered(userID string, filter database.FeedbackFilter) ([]*database.Feedback, int, error) {
+	all, _ := m.GetFeedbackByUser(userID)
+	var matching []*database.Feedback
+	for _, feedback := range all {
+		if filter.Type == "" || string(feedback.Type) == filter.Type {
+			matching = append(matching, feedback)
+		}
+	}
+	total := len(matching)
+	if filter.Page > 0 && filter.PageSize > 0 {
+		start := (filter.Page - 1) * filter.PageSize
+		if start > total {
+			start = total
+		}
+		end := start + filter.PageSize
+		if end > total {
+			end = total
+		}
+		matching = matching[start:end]
+	}
+	return matching, total, nil
+}
+
 // GetAllFeedback gets all feedback with filtering and pagination
 func (m *TestMockDB) GetAllFeedback(filter database.FeedbackFilter) ([]*database.Feedback, int, error) {
 	userID := "test-user-id"
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/logging"
+)
+
+// maxNotificationLimit caps how many notifications a single request can list
+const maxNotificationLimit = 100
+
+// notifyDebateEnded tells every authenticated user who argued in or bookmarked a debate how it ended, once each.
+// Anonymous players have no inbox and are skipped. Failures are logged, never returned, so they can't hold up
+// ending the debate.
+func (s *Server) notifyDebateEnded(debateID, topic, winner, reason string) {
+	participants, err := s.db.GetDebateParticipants(debateID)
+	if err != nil {
+		logging.Error("Failed to get debate participants to notify", map[string]interface{}{
+			"debate_id": debateID,
+			"error":     err.Error(),
+		})
+		return
+	}
+	bookmarkers, err := s.db.GetDebateBookmarkers(debateID)
+	if err != nil {
+		// Participants are still told
+		logging.Error("Failed to get debate bookmarkers to notify", map[string]interface{}{
+			"debate_id": debateID,
+			"error":     err.Error(),
+		})
+	}
+	recipients := make([]string, 0, len(participants)+len(bookmarkers))
+	notified := make(map[string]bool, cap(recipients))
+	for _, userID := range append(participants, bookmarkers...) {
+		if !notified[userID] {
+			notified[userID] = true
+			recipients = append(recipients, userID)
+		}
+	}
+
+	payload := gin.H{
+		"debate_id":  debateID,
+		"topic":      topic,
+		"winner":     winner,
+		"draw":       winner == "",
+		"end_reason": reason,
+		"replay_url": replayURL(debateID),
+	}
+	for _, userID := range recipients {
+		if _, err := s.db.CreateNotification(userID, database.NotificationTypeDebateEnded, payload); err != nil {
+			logging.Error("Failed to create debate ended notification", map[string]interface{}{
+				"debate_id": debateID,
+				"user_id":   userID,
+				"error":     err.Error(),
+			})
+		}
+	}
+}
+
+// listNotificationsHandler lists the current user's notifications, newest first, with the unread count for
+// the lobby badge. ?unread=true lists only unread ones.
+func (s *Server) listNotificationsHandler(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	limit := parseIntWithDefault(c.Query("limit"), 0)
+	if limit > maxNotificationLimit {
+		limit = maxNotificationLimit
+	}
+
+	notifications, unread, err := s.db.GetNotifications(userID, c.Query("unread") == "true", limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get notifications", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"unread_count":  unread,
+	})
+}
+
+// markNotificationReadHandler marks one of the current user's notifications as read
+func (s *Server) markNotificationReadHandler(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	if err := s.db.MarkNotificationRead(id, userID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark notification as read", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Notification marked as read",
+	})
+}
+
+// setupNotificationRoutes sets up the current user's notification routes
+func (s *Server) setupNotificationRoutes() {
+	notificationGroup := s.router.Group("/api/notifications")
+	notificationGroup.Use(s.auth.AuthMiddleware())
+	{
+		notificationGroup.GET("", s.listNotificationsHandler)
+		notificationGroup.POST("/:id/read", s.markNotificationReadHandler)
+	}
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type notificationRecordingDB struct {
+	*TestMockDB
+	participants  []string
+	bookmarkers   []string
+	notifications map[string]gin.H
+	notified      int
+}
+
+func (m *notificationRecordingDB) GetDebateParticipants(debateID string) ([]string, error) {
+	return m.participants, nil
+}
+
+func (m *notificationRecordingDB) GetDebateBookmarkers(debateID string) ([]string, error) {
+	return m.bookmarkers, nil
+}
+
+func (m *notificationRecordingDB) CreateNotification(userID, notificationType string, payload interface{}) (int64, error) {
+	if notificationType == database.NotificationTypeDebateEnded {
+		m.notifications[userID] = payload.(gin.H)
+		m.notified++
+	}
+	return int64(len(m.notifications)), nil
+}
+
+func TestDebateEndNotifiesParticipants(t *testing.T) {
+	db := &notificationRecordingDB{
+		TestMockDB:    &TestMockDB{},
+		participants:  []string{"user-alice", "user-bob"},
+		notifications: map[string]gin.H{},
+	}
+	server := &Server{db: db}
+	session := &conversation.DebateSession{DebateID: "ended-debate", Status: "active", Config: conversation.DebateConfig{Topic: "Cats vs dogs"}}
+
+	recordDebateEnd(server, session, session.DebateID, "Agent 1", database.DebateEndReasonKnockout)
+
+	require.Len(t, db.notifications, 2)
+	payload := db.notifications["user-bob"]
+	assert.Equal(t, "ended-debate", payload["debate_id"])
+	assert.Equal(t, "Cats vs dogs", payload["topic"])
+	assert.Equal(t, "Agent 1", payload["winner"])
+	assert.Equal(t, false, payload["draw"])
+	assert.Equal(t, database.DebateEndReasonKnockout, payload["end_reason"])
+}
+
+func TestDebateEndNotifiesBookmarkers(t *testing.T) {
+	db := &notificationRecordingDB{
+		TestMockDB:    &TestMockDB{},
+		participants:  []string{"user-alice"},
+		bookmarkers:   []string{"user-alice", "user-carol"},
+		notifications: map[string]gin.H{},
+	}
+	server := &Server{db: db}
+	session := &conversation.DebateSession{DebateID: "ended-debate", Status: "active", Config: conversation.DebateConfig{Topic: "Cats vs dogs"}}
+
+	recordDebateEnd(server, session, session.DebateID, "", database.DebateEndReasonTimeout)
+
+	assert.Contains(t, db.notifications, "user-carol")
+	assert.Equal(t, 2, db.notified, "a participant who also bookmarked the debate is told once")
+}
+
+func TestDebateEndNotifiesOnce(t *testing.T) {
+	db := &notificationRecordingDB{
+		TestMockDB:    &TestMockDB{},
+		participants:  []string{"user-alice"},
+		notifications: map[string]gin.H{},
+	}
+	server := &Server{db: db}
+	session := &conversation.DebateSession{DebateID: "ended-debate", Status: "active", Config: conversation.DebateConfig{Topic: "Cats vs dogs"}}
+
+	// A knockout and a moderator ending the debate at the same time
+	assert.True(t, handleGameOver(server, session, session.DebateID, "Agent 1", database.DebateEndReasonKnockout, nil))
+	assert.False(t, handleGameOver(server, session, session.DebateID, "", database.DebateEndReasonAdmin, nil))
+	assert.False(t, recordDebateEnd(server, session, session.DebateID, "", database.DebateEndReasonTimeout))
+
+	assert.Equal(t, 1, db.notified)
+	assert.Equal(t, database.DebateEndReasonKnockout, db.notifications["user-alice"]["end_reason"])
+}
+
+func TestNotificationHandlers(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.setupNotificationRoutes()
+
+	token, err := server.auth.GenerateToken(auth.User{ID: "user-1", Username: "user", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	request := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, request(http.MethodGet, "/api/notifications", "").Code)
+
+	w := request(http.MethodGet, "/api/notifications?unread=true", token)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Contains(t, response, "notifications")
+	assert.Equal(t, float64(0), response["unread_count"])
+
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, "/api/notifications/1/read", token).Code)
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodPost, "/api/notifications/abc/read", token).Code)
+}
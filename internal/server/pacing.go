@@ -0,0 +1,159 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/types"
+)
+
+// pacingCheckInterval is how often a paced pause between turns checks whether the debate stopped or timed out,
+// so a long off-peak pause doesn't hold up pausing or ending the debate
+const pacingCheckInterval = time.Second
+
+// PacingWindow slows debates down during a daily time window
+type PacingWindow struct {
+	Start      time.Duration // Offset from midnight
+	End        time.Duration // Offset from midnight, before Start for windows that cross midnight
+	Multiplier float64       // Applied to TurnDelay while the window applies
+}
+
+// contains reports whether the time of day offset falls within the window
+func (w PacingWindow) contains(offset time.Duration) bool {
+	if w.Start <= w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// PacingSchedule stretches the pause between agent turns during off-peak hours and while nobody is watching,
+// so always-on debates stay alive at a fraction of the cost
+type PacingSchedule struct {
+	Windows        []PacingWindow // The first window containing the current time applies
+	IdleMultiplier float64        // Additionally applied when no clients are connected, 0 or 1 for none
+	Location       *time.Location // Time zone of the windows, nil for the server's local time
+}
+
+// Pace returns how much to stretch a debate's turn delay at now, with the given number of connected clients.
+// A nil schedule always returns the normal pace.
+func (p *PacingSchedule) Pace(turnDelay time.Duration, now time.Time, clients int) conversation.TurnPace {
+	pace := conversation.TurnPace{Multiplier: 1}
+	if p != nil {
+		if p.Location != nil {
+			now = now.In(p.Location)
+		}
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		offset := now.Sub(midnight)
+		for _, window := range p.Windows {
+			if window.contains(offset) {
+				pace.Multiplier = window.Multiplier
+				pace.QuietHours = true
+				break
+			}
+		}
+		if clients == 0 && p.IdleMultiplier > 0 && p.IdleMultiplier != 1 {
+			pace.Multiplier *= p.IdleMultiplier
+			pace.NoViewers = true
+		}
+	}
+	pace.TurnDelay = time.Duration(float64(turnDelay) * pace.Multiplier)
+	return pace
+}
+
+// ParsePacingWindows parses a comma-separated list of daily windows with their turn delay multipliers,
+// e.g. "22:00-07:00=4,12:00-13:30=2". An empty spec has no windows.
+func ParsePacingWindows(spec string) ([]PacingWindow, error) {
+	var windows []PacingWindow
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		span, multiplierText, found := strings.Cut(part, "=")
+		if !found {
+			return nil, fmt.Errorf("pacing window %q must look like 22:00-07:00=4", part)
+		}
+		startText, endText, found := strings.Cut(span, "-")
+		if !found {
+			return nil, fmt.Errorf("pacing window %q must look like 22:00-07:00=4", part)
+		}
+
+		start, err := parseTimeOfDay(startText)
+		if err != nil {
+			return nil, fmt.Errorf("pacing window %q: %v", part, err)
+		}
+		end, err := parseTimeOfDay(endText)
+		if err != nil {
+			return nil, fmt.Errorf("pacing window %q: %v", part, err)
+		}
+		if start == end {
+			return nil, fmt.Errorf("pacing window %q: start and end must differ", part)
+		}
+		multiplier, err := strconv.ParseFloat(strings.TrimSpace(multiplierText), 64)
+		if err != nil || multiplier <= 0 {
+			return nil, fmt.Errorf("pacing window %q: multiplier must be a positive number", part)
+		}
+
+		windows = append(windows, PacingWindow{Start: start, End: end, Multiplier: multiplier})
+	}
+	return windows, nil
+}
+
+// parseTimeOfDay parses an HH:MM time of day into its offset from midnight
+func parseTimeOfDay(text string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(text))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", text)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// turnPacePayload describes a debate's pace for clients
+func turnPacePayload(pace conversation.TurnPace) gin.H {
+	return gin.H{
+		"multiplier":    pace.Multiplier,
+		"turn_delay_ms": pace.TurnDelay.Milliseconds(),
+		"quiet_hours":   pace.QuietHours,
+		"no_viewers":    pace.NoViewers,
+	}
+}
+
+// paceNextTurn works out the pause before a debate's next turn from the pacing schedule and its audience,
+// and tells clients when the pace changes so long gaps don't look like a stalled debate
+func (m *DebateManager) paceNextTurn(session *conversation.DebateSession) time.Duration {
+	var schedule *PacingSchedule
+	if m.server != nil {
+		schedule = m.server.config.GetTurnPacing()
+	}
+	pace := schedule.Pace(session.Config.TurnDelay, time.Now(), session.GetPresence().Total)
+	if session.SetTurnPace(pace) {
+		payload := turnPacePayload(pace)
+		payload["type"] = "pace_update"
+		payload["debate_id"] = session.DebateID
+		session.Broadcast(payload)
+	}
+	return pace.TurnDelay
+}
+
+// waitForNextTurn sleeps for delay, waking early once the debate is no longer active or reaches its deadline
+func waitForNextTurn(session *conversation.DebateSession, delay time.Duration) {
+	end := time.Now().Add(delay)
+	for {
+		remaining := time.Until(end)
+		if remaining <= 0 || session.GetStatus() != types.DebateStatusActive {
+			return
+		}
+		if deadline := session.GetDeadline(); !deadline.IsZero() && !time.Now().Before(deadline) {
+			return
+		}
+		if remaining > pacingCheckInterval {
+			remaining = pacingCheckInterval
+		}
+		time.Sleep(remaining)
+	}
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePacingWindows(t *testing.T) {
+	windows, err := ParsePacingWindows(" 22:00-07:00=4, 12:00-13:30=1.5 ")
+	require.NoError(t, err)
+	assert.Equal(t, []PacingWindow{
+		{Start: 22 * time.Hour, End: 7 * time.Hour, Multiplier: 4},
+		{Start: 12 * time.Hour, End: 13*time.Hour + 30*time.Minute, Multiplier: 1.5},
+	}, windows)
+
+	windows, err = ParsePacingWindows("")
+	require.NoError(t, err)
+	assert.Empty(t, windows)
+
+	for _, invalid := range []string{"22:00-07:00", "22:00=4", "25:00-07:00=4", "22:00-22:00=4", "22:00-07:00=0", "22:00-07:00=fast"} {
+		_, err := ParsePacingWindows(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestPacingSchedulePace(t *testing.T) {
+	schedule := &PacingSchedule{
+		Windows: []PacingWindow{
+			{Start: 22 * time.Hour, End: 7 * time.Hour, Multiplier: 4},
+			{Start: 12 * time.Hour, End: 13 * time.Hour, Multiplier: 2},
+		},
+		IdleMultiplier: 3,
+		Location:       time.UTC,
+	}
+	at := func(hour, minute int) time.Time { return time.Date(2026, 3, 1, hour, minute, 0, 0, time.UTC) }
+	delay := 2 * time.Second
+
+	// Outside any window, with viewers, the pace is unchanged
+	assert.Equal(t, conversation.TurnPace{Multiplier: 1, TurnDelay: delay}, schedule.Pace(delay, at(9, 0), 5))
+
+	// Windows that cross midnight apply on both sides of it
+	assert.Equal(t, conversation.TurnPace{Multiplier: 4, TurnDelay: 8 * time.Second, QuietHours: true}, schedule.Pace(delay, at(23, 30), 5))
+	assert.Equal(t, conversation.TurnPace{Multiplier: 4, TurnDelay: 8 * time.Second, QuietHours: true}, schedule.Pace(delay, at(6, 59), 5))
+	assert.Equal(t, float64(1), schedule.Pace(delay, at(7, 0), 5).Multiplier)
+	assert.Equal(t, float64(2), schedule.Pace(delay, at(12, 30), 5).Multiplier)
+
+	// Nobody watching slows the debate down further
+	assert.Equal(t, conversation.TurnPace{Multiplier: 12, TurnDelay: 24 * time.Second, QuietHours: true, NoViewers: true}, schedule.Pace(delay, at(23, 30), 0))
+	assert.Equal(t, conversation.TurnPace{Multiplier: 3, TurnDelay: 6 * time.Second, NoViewers: true}, schedule.Pace(delay, at(9, 0), 0))
+
+	// No schedule is the current behavior
+	var none *PacingSchedule
+	assert.Equal(t, conversation.TurnPace{Multiplier: 1, TurnDelay: delay}, none.Pace(delay, at(23, 30), 0))
+}
+
+func TestPaceNextTurn(t *testing.T) {
+	session := &conversation.DebateSession{DebateID: "paced", Status: "active", Config: conversation.DebateConfig{TurnDelay: time.Second}}
+	manager := &DebateManager{server: &Server{config: &Config{TurnPacing: &PacingSchedule{IdleMultiplier: 5}}}}
+
+	// Reconnecting clients get the normal pace until the loop has paced a turn
+	assert.Equal(t, float64(1), session.GetTurnPace().Multiplier)
+
+	assert.Equal(t, 5*time.Second, manager.paceNextTurn(session))
+	assert.True(t, session.GetTurnPace().NoViewers)
+	seq := session.LastSeq()
+	assert.Equal(t, uint64(1), seq, "a pace change is broadcast")
+
+	manager.paceNextTurn(session)
+	assert.Equal(t, seq, session.LastSeq(), "an unchanged pace isn't broadcast again")
+
+	// A long paced pause ends early once the debate stops
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		session.UpdateStatus("paused")
+	}()
+	start := time.Now()
+	waitForNextTurn(session, time.Minute)
+	assert.Less(t, time.Since(start), 3*pacingCheckInterval)
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+)
+
+// defaultAgentPair returns the agents used for quick debates: the configured pairing if set,
+// otherwise the pair from GetOrderedAgentNames. Either name is empty if no pairing is available.
+func (s *Server) defaultAgentPair() (agent1Name, agent2Name string) {
+	if s.config != nil && s.config.DefaultAgent1 != "" && s.config.DefaultAgent2 != "" {
+		return s.config.DefaultAgent1, s.config.DefaultAgent2
+	}
+	return s.GetOrderedAgentNames()
+}
+
+// randomTopic picks one of the pre-generated topics at random
+func (s *Server) randomTopic() (*database.Topic, error) {
+	_, total, err := s.db.GetTopics(database.TopicFilter{Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("no topics available")
+	}
+
+	topics, _, err := s.db.GetTopics(database.TopicFilter{Offset: rand.Intn(total), Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("no topics available")
+	}
+	return topics[0], nil
+}
+
+// quickDebateHandler creates a public debate between the default agent pairing, for a one-click "start a debate" button.
+// The topic is optional; without one a random pre-generated topic is used.
+func (s *Server) quickDebateHandler(c *gin.Context) {
+	var req struct {
+		Topic   string `json:"topic"`    // Optional: free-form topic
+		TopicID int    `json:"topic_id"` // Optional: use a pre-generated topic's title
+	}
+	// An empty body is fine, everything is optional
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	topic := strings.TrimSpace(req.Topic)
+	if req.TopicID > 0 {
+		t, err := s.db.GetTopic(req.TopicID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Topic with ID %d not found: %v", req.TopicID, err)})
+			return
+		}
+		topic = t.Title
+	}
+	if topic == "" {
+		t, err := s.randomTopic()
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "No topic given and no topics available to pick from", "details": err.Error()})
+			return
+		}
+		topic = t.Title
+	}
+
+	agent1Name, agent2Name := s.defaultAgentPair()
+	agent1, exists1 := s.agents.Get(agent1Name)
+	agent2, exists2 := s.agents.Get(agent2Name)
+	if !exists1 || !exists2 || agent1Name == agent2Name {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "No default agent pairing is available for quick debates"})
+		return
+	}
+
+	config := conversation.DefaultConfig()
+	config.Topic = topic
+	userID, _ := auth.GetUserID(c)
+	settings := database.DebateSettings{
+		Visibility: database.DebateVisibilityPublic,
+		CreatedBy:  userID,
+	}
+	debateID, err := s.debateManager.CreateDebateWithConfig(config, agent1, agent2, userID, settings)
+	if err != nil {
+		s.respondDebateCreateFailed(c, err)
+		return
+	}
+
+	debate, err := s.db.GetDebate(debateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Debate created but failed to retrieve details: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Debate created successfully",
+		"debate":  debate,
+	})
+}
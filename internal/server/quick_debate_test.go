@@ -0,0 +1,105 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuickDebateHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.POST("/api/debates/quick", server.auth.OptionalAuthMiddleware(), server.quickDebateHandler)
+
+	server.agents = NewAgentRegistry(map[string]*agent.Agent{"Agent 1": {}, "Agent 2": {}})
+	server.debateManager = &DebateManager{
+		db:      server.db,
+		agents:  server.agents,
+		debates: make(map[string]*conversation.DebateSession),
+		server:  server,
+	}
+
+	testCases := []struct {
+		name           string
+		body           string
+		agent1         string
+		agent2         string
+		expectedStatus int
+		expectedTopic  string
+	}{
+		{
+			name:           "Topic with configured pairing",
+			body:           `{"topic": "Is pineapple good on pizza?"}`,
+			agent1:         "Agent 1",
+			agent2:         "Agent 2",
+			expectedStatus: http.StatusCreated,
+			expectedTopic:  "Is pineapple good on pizza?",
+		},
+		{
+			name:           "No body picks a random topic",
+			body:           "",
+			agent1:         "Agent 1",
+			agent2:         "Agent 2",
+			expectedStatus: http.StatusCreated,
+			expectedTopic:  "Test Topic 1",
+		},
+		{
+			name:           "Pre-generated topic",
+			body:           `{"topic_id": 1}`,
+			agent1:         "Agent 1",
+			agent2:         "Agent 2",
+			expectedStatus: http.StatusCreated,
+			expectedTopic:  "Test Topic",
+		},
+		{
+			name:           "No pairing available",
+			body:           `{"topic": "Is pineapple good on pizza?"}`,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "Invalid body",
+			body:           `{"topic": 5}`,
+			agent1:         "Agent 1",
+			agent2:         "Agent 2",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server.config.DefaultAgent1 = tc.agent1
+			server.config.DefaultAgent2 = tc.agent2
+
+			req, err := http.NewRequest("POST", "/api/debates/quick", bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.expectedStatus == http.StatusCreated {
+				var response struct {
+					Debate struct {
+						ID string `json:"id"`
+					} `json:"debate"`
+				}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+
+				session, exists := server.debateManager.GetDebate(response.Debate.ID)
+				require.True(t, exists)
+				assert.Equal(t, tc.expectedTopic, session.Config.Topic)
+			}
+		})
+	}
+}
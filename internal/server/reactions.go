@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/logging"
+)
+
+// reactionRateLimit caps the reactions a client can send per minute
+const reactionRateLimit = 20
+
+// reactionEmojis are the emoji clients can react with
+var reactionEmojis = map[string]bool{
+	"👍": true, "👎": true, "👏": true, "🔥": true, "😂": true, "😮": true, "🤔": true, "💯": true, "❤️": true,
+}
+
+// handleReaction rate limits and broadcasts an emoji reaction. Reactions are for every viewer, spectators
+// included, and unlike arguments they are never scored, applied to HP, added to the history or counted on the
+// leaderboard. A reaction to a transcript entry (message_id) is also counted per entry and emoji.
+// Reactions aren't sequenced or replayed, so a burst of them can't push turns out of the replay buffer.
+// It returns the updated list of the client's recent reaction timestamps.
+func (s *Server) handleReaction(ctx context.Context, ws jsonWriter, session *conversation.DebateSession, playerID string, msg ConversationMessage, reactionTimes []time.Time) []time.Time {
+	if !reactionEmojis[msg.Emoji] {
+		ws.WriteJSON(gin.H{
+			"type":    "error",
+			"message": "Unsupported reaction emoji",
+		})
+		return reactionTimes
+	}
+
+	// Drop timestamps older than the one-minute window
+	now := time.Now()
+	recent := reactionTimes[:0]
+	for _, t := range reactionTimes {
+		if now.Sub(t) < time.Minute {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= reactionRateLimit {
+		ws.WriteJSON(gin.H{
+			"type":    "error",
+			"message": "You're sending reactions too quickly",
+		})
+		return recent
+	}
+	recent = append(recent, now)
+
+	reaction := gin.H{
+		"type":      "reaction",
+		"emoji":     msg.Emoji,
+		"player":    session.GetUserName(playerID),
+		"player_id": playerID,
+		"timestamp": now,
+	}
+	if msg.MessageID != 0 {
+		count, err := s.db.AddReaction(session.DebateID, msg.MessageID, msg.Emoji)
+		if errors.Is(err, database.ErrReactionTargetNotFound) {
+			ws.WriteJSON(gin.H{
+				"type":    "error",
+				"message": "Reactions must target a message of this debate",
+			})
+			return recent
+		}
+		if err != nil {
+			logging.ErrorCtx(ctx, "Failed to save reaction", map[string]interface{}{
+				"error":      err,
+				"message_id": msg.MessageID,
+			})
+		} else {
+			reaction["count"] = count
+		}
+		reaction["message_id"] = msg.MessageID
+	}
+
+	session.BroadcastEphemeral(reaction)
+	return recent
+}
+
+// getReactionsHandler returns how often each emoji was used to react to a debate's transcript entries, keyed by
+// the entries' message IDs, e.g. to show reaction counts in a replay
+func (s *Server) getReactionsHandler(c *gin.Context) {
+	debateID := c.Param("debateID")
+
+	if status, err := s.checkDebateAccess(c, debateID); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	counts, err := s.db.GetReactionCounts(debateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get reactions", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"debate_id": debateID,
+		"reactions": counts,
+	})
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reactingDB counts reactions in memory; only message 7 is in the debate
+type reactingDB struct {
+	*TestMockDB
+	counts map[string]int
+}
+
+func (m *reactingDB) AddReaction(debateID string, messageID int64, emoji string) (int, error) {
+	if messageID != 7 {
+		return 0, database.ErrReactionTargetNotFound
+	}
+	m.counts[emoji]++
+	return m.counts[emoji], nil
+}
+
+// recordingWriter keeps the messages written to a single client
+type recordingWriter struct {
+	messages []interface{}
+}
+
+func (w *recordingWriter) WriteJSON(v interface{}) error {
+	w.messages = append(w.messages, v)
+	return nil
+}
+
+func TestHandleReaction(t *testing.T) {
+	serverConns := make(chan *websocket.Conn, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		serverConns <- ws
+	}))
+	defer srv.Close()
+
+	viewer, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer viewer.Close()
+	serverConn := <-serverConns
+	defer serverConn.Close()
+
+	db := &reactingDB{TestMockDB: &TestMockDB{}, counts: map[string]int{}}
+	s := &Server{db: db}
+	session := &conversation.DebateSession{
+		DebateID:  "debate-1",
+		Clients:   map[*websocket.Conn]string{serverConn: "player-1"},
+		UserNames: map[string]string{"player-1": "alice"},
+	}
+	sender := &recordingWriter{}
+	readBroadcast := func() map[string]interface{} {
+		var msg map[string]interface{}
+		require.NoError(t, viewer.ReadJSON(&msg))
+		return msg
+	}
+
+	// A reaction without a target is only broadcast
+	times := s.handleReaction(context.Background(), sender, session, "player-1", ConversationMessage{Type: "reaction", Emoji: "🔥"}, nil)
+	msg := readBroadcast()
+	assert.Equal(t, "reaction", msg["type"])
+	assert.Equal(t, "🔥", msg["emoji"])
+	assert.Equal(t, "alice", msg["player"])
+	assert.NotContains(t, msg, "count")
+	assert.NotContains(t, msg, "seq", "reactions aren't replayed")
+
+	// A reaction to a transcript entry carries the entry's count for the emoji
+	times = s.handleReaction(context.Background(), sender, session, "player-1", ConversationMessage{Type: "reaction", Emoji: "👏", MessageID: 7}, times)
+	times = s.handleReaction(context.Background(), sender, session, "player-1", ConversationMessage{Type: "reaction", Emoji: "👏", MessageID: 7}, times)
+	readBroadcast()
+	msg = readBroadcast()
+	assert.Equal(t, float64(7), msg["message_id"])
+	assert.Equal(t, float64(2), msg["count"])
+	assert.Empty(t, sender.messages)
+
+	// Unknown emoji and entries of other debates are rejected to the sender alone
+	times = s.handleReaction(context.Background(), sender, session, "player-1", ConversationMessage{Type: "reaction", Emoji: "bad"}, times)
+	times = s.handleReaction(context.Background(), sender, session, "player-1", ConversationMessage{Type: "reaction", Emoji: "👍", MessageID: 8}, times)
+	assert.Len(t, sender.messages, 2)
+	assert.Equal(t, uint64(0), session.LastSeq(), "reactions don't take up the replay buffer")
+
+	// Reactions never reach the debate's history
+	assert.Empty(t, session.GetRecentHistory(10))
+
+	// Each client can only react so often
+	broadcasts := 0
+	for len(times) < reactionRateLimit {
+		times = s.handleReaction(context.Background(), sender, session, "player-1", ConversationMessage{Type: "reaction", Emoji: "😂"}, times)
+		broadcasts++
+	}
+	s.handleReaction(context.Background(), sender, session, "player-1", ConversationMessage{Type: "reaction", Emoji: "😂"}, times)
+	session.Broadcast(gin.H{"type": "game_score"})
+	for i := 0; i < broadcasts; i++ {
+		assert.Equal(t, "reaction", readBroadcast()["type"])
+	}
+	assert.Equal(t, "game_score", readBroadcast()["type"], "reactions over the limit aren't broadcast")
+	assert.Len(t, sender.messages, 3)
+}
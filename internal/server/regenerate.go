@@ -0,0 +1,158 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/logging"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
+)
+
+// regenerateLastTurnHandler replaces a paused debate's last agent turn with a freshly generated one.
+// The old turn's HP change is reversed, the new response is scored and applied in its place, and clients
+// get a correction message naming the seq of the message it replaces. Player arguments are never regenerated.
+func (s *Server) regenerateLastTurnHandler(c *gin.Context) {
+	debateID := c.Param("debateID")
+
+	session, exists := s.debateManager.GetDebate(debateID)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Debate not found"})
+		return
+	}
+	// The loop must not take a turn while the last one is being replaced
+	if status := session.GetStatus(); status != types.DebateStatusPaused {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only paused debates can have their last turn regenerated", "status": status})
+		return
+	}
+
+	previous, index, found := session.LastAgentEntry()
+	if !found {
+		c.JSON(http.StatusConflict, gin.H{"error": "The debate has no agent turn to regenerate"})
+		return
+	}
+	if previous.AverageScore == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "The last agent turn is a scripted opening statement and can't be regenerated"})
+		return
+	}
+	var speaker *agent.Agent
+	switch previous.Speaker {
+	case session.Agent1.GetName():
+		speaker = session.Agent1
+	case session.Agent2.GetName():
+		speaker = session.Agent2
+	default:
+		c.JSON(http.StatusConflict, gin.H{"error": "The last turn was not made by one of the debate's agents", "speaker": previous.Speaker})
+		return
+	}
+	agentName := speaker.GetName()
+	ctx := agent.WithMemoryScope(usage.WithRecorder(c.Request.Context(), session), session.DebateID)
+
+	// Nothing changes until the new turn has been generated and scored
+	prompt := agentTurnPrompt(session, agentName, session.HistoryBefore(index, session.Config.HistoryWindow()))
+	response, err := speaker.GenerateResponse(ctx, session.Config.Topic, prompt, generationFallback(session.Config))
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to generate a new response", "details": err.Error()})
+		return
+	}
+
+	// Speak with the agent's HP from before the turn being replaced
+	hpBefore := agentHP(session, agentName) - previous.Agent1Delta
+	if agentName == session.Agent2.GetName() {
+		hpBefore = agentHP(session, agentName) - previous.Agent2Delta
+	}
+	voiceSettings := voiceSettingsForHP(speaker.VoiceSettings(), hpBefore).Resolve()
+	audioResult := s.debateManager.generateTurnAudio(ctx, session, speaker, response, voiceSettings, index)
+
+	score, err := s.debateManager.scorer.ScoreArgumentInStyle(ctx, response, session.Config.Topic, session.Config.Language, session.Config.ResponseStyle)
+	if err != nil {
+		<-audioResult
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to score the new response", "details": err.Error()})
+		return
+	}
+	scorePoints, _ := session.Config.HPTuning.AgentPoints(score.Average, session.GetLastAgentScore(opponentName(session, agentName)))
+	agent1Delta, agent2Delta := agentTurnDeltas(session, agentName, scorePoints)
+	audioURL := (<-audioResult).url
+
+	turnScore := score.Average
+	replacement := previous
+	replacement.Message = response
+	replacement.AverageScore = &turnScore
+	replacement.Agent1Delta = agent1Delta
+	replacement.Agent2Delta = agent2Delta
+	gameScore, err := session.ReplaceAgentEntry(index, previous, replacement)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Failed to replace the last turn", "details": err.Error()})
+		return
+	}
+
+	if previous.MessageID > 0 {
+		err := s.db.UpdateDebateMessage(&database.DebateMessage{
+			ID:          previous.MessageID,
+			Message:     response,
+			Score:       &turnScore,
+			AudioURL:    audioURL,
+			Agent1Delta: agent1Delta,
+			Agent2Delta: agent2Delta,
+		})
+		if err != nil {
+			logging.Error("Failed to update regenerated debate message", map[string]interface{}{
+				"debate_id":  debateID,
+				"message_id": previous.MessageID,
+				"error":      err.Error(),
+			})
+		}
+	}
+
+	correction := gin.H{
+		"type":         "correction",
+		"replaces_seq": previous.Seq,
+		"agent":        agentName,
+		"content":      response,
+		"scores": gin.H{
+			"argument": score,
+		},
+	}
+	if audioURL != "" {
+		correction["audioUrl"] = audioURL
+	}
+	seq := session.Broadcast(correction)
+	// A later regeneration replaces the correction, not the original message
+	session.RecordTurn(agentName, false, agent1Delta, agent2Delta, previous.MessageID, seq)
+	session.Broadcast(s.debateManager.gameScoreMessage(session, gameScore))
+
+	userID, _ := auth.GetUserID(c)
+	logging.LogDebateEvent("agent_turn_regenerated", debateID, map[string]interface{}{
+		"triggered_by": userID,
+		"agent_name":   agentName,
+		"old_score":    previous.AverageScore,
+		"new_score":    score.Average,
+	})
+	s.recordAudit(c, auditActionDebateRegenerate, debateID, gin.H{
+		"agent_name": agentName,
+		"old_score":  previous.AverageScore,
+		"new_score":  score.Average,
+	})
+
+	// The new turn can knock out an agent the old one didn't
+	if !session.Config.IsJudged() && (gameScore.Agent1Score <= 0 || gameScore.Agent2Score <= 0) {
+		winner := session.Agent1.GetName()
+		if gameScore.Agent1Score <= 0 {
+			winner = session.Agent2.GetName()
+		}
+		handleGameOver(s, session, debateID, winner, database.DebateEndReasonKnockout, nil)
+	}
+
+	entry, _, _ := session.LastAgentEntry()
+	c.JSON(http.StatusOK, gin.H{
+		"debate_id":    debateID,
+		"entry":        entry,
+		"score":        score,
+		"replaces_seq": previous.Seq,
+		"seq":          seq,
+		"game_score":   gameScore,
+	})
+}
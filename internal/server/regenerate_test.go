@@ -0,0 +1,113 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/scoring"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegenerateLastTurnHandler(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+
+	agent1 := agent.NewOfflineAgent(agent.AgentConfig{Name: "Pepito"})
+	agent2 := agent.NewOfflineAgent(agent.AgentConfig{Name: "Tony"})
+	session := &conversation.DebateSession{
+		DebateID:  "regen-debate",
+		Agent1:    agent1,
+		Agent2:    agent2,
+		Status:    "active",
+		GameScore: conversation.GameScore{Agent1Score: 100, Agent2Score: 100},
+		Config: conversation.DebateConfig{
+			Topic:    "Messi vs Ronaldo",
+			HPTuning: conversation.HPTuning{AgentMultiplier: 1},
+		},
+	}
+	server.agents = NewAgentRegistry(map[string]*agent.Agent{"Pepito": agent1, "Tony": agent2})
+	server.debateManager = &DebateManager{
+		db:      server.db,
+		agents:  server.agents,
+		debates: map[string]*conversation.DebateSession{session.DebateID: session},
+		scorer:  scoring.NewOfflineScorer(),
+		server:  server,
+	}
+	server.router.POST("/api/debates/:debateID/regenerate-last",
+		server.auth.AuthMiddleware(),
+		server.auth.RequireRole(string(database.RoleAdmin)),
+		server.regenerateLastTurnHandler)
+
+	adminToken, err := server.auth.GenerateToken(auth.User{ID: "admin-1", Username: "admin", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+	userToken, err := server.auth.GenerateToken(auth.User{ID: "user-1", Username: "user", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	regenerate := func(debateID, token string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, "/api/debates/"+debateID+"/regenerate-last", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	code, _ := regenerate("missing-debate", adminToken)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// A player's argument is never regenerated
+	session.AddHistoryEntry("player-1", "Messi has more Ballon d'Ors", true)
+	session.UpdateStatus("paused")
+	code, _ = regenerate(session.DebateID, adminToken)
+	assert.Equal(t, http.StatusConflict, code)
+
+	// Pepito's turn moved 4 HP from Tony to Pepito
+	session.AddHistoryEntry("Pepito", "Messi is simply the greatest.", false)
+	session.UpdateLastHistoryEntryScore(4)
+	session.UpdateGameScore(4, -4)
+	seq := session.Broadcast(map[string]string{"type": "message"})
+	session.RecordTurn("Pepito", false, 4, -4, 0, seq)
+	session.AddHistoryEntry("player-2", "Ronaldo scores in every league", true)
+
+	code, _ = regenerate(session.DebateID, userToken)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	// The loop must be paused, so the turn can't change under it
+	session.UpdateStatus("active")
+	code, _ = regenerate(session.DebateID, adminToken)
+	assert.Equal(t, http.StatusConflict, code)
+	session.UpdateStatus("paused")
+
+	code, response := regenerate(session.DebateID, adminToken)
+	require.Equal(t, http.StatusOK, code, response)
+	assert.Equal(t, float64(seq), response["replaces_seq"])
+
+	entry, index, found := session.LastAgentEntry()
+	require.True(t, found)
+	assert.Equal(t, 1, index, "the regenerated turn keeps its place before the later player argument")
+	assert.NotEqual(t, "Messi is simply the greatest.", entry.Message)
+	require.NotNil(t, entry.AverageScore)
+	assert.Equal(t, session.LastSeq()-1, entry.Seq, "the turn now points at its correction, broadcast before the game score")
+	assert.Greater(t, entry.Seq, seq)
+
+	// The old turn's HP is reversed and the new one's applied
+	points := int(*entry.AverageScore)
+	assert.Equal(t, conversation.GameScore{Agent1Score: 100 + points, Agent2Score: 100 - points}, session.GetGameScore())
+	assert.Equal(t, points, entry.Agent1Delta)
+	assert.Equal(t, types.DebateStatusPaused, session.GetStatus())
+
+	// Regenerating again replaces the correction
+	code, response = regenerate(session.DebateID, adminToken)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(entry.Seq), response["replaces_seq"])
+	assert.Len(t, session.GetRecentHistory(10), 3)
+}
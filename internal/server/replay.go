@@ -0,0 +1,153 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/logging"
+	"github.com/neo/convinceme_backend/internal/types"
+)
+
+// maxReplayGap caps the pause between replayed messages, so long lulls don't stall a stream
+const maxReplayGap = 10 * time.Second
+
+// replayEntry is a transcript message annotated with its original timing
+type replayEntry struct {
+	*database.DebateMessage
+	OffsetSeconds float64 `json:"offset_seconds"` // Time since the first message
+	DeltaSeconds  float64 `json:"delta_seconds"`  // Time since the previous message
+}
+
+// recordDebateMessage persists a transcript entry for replays and returns its ID.
+// Errors are logged rather than failing the turn, and return 0.
+func (s *Server) recordDebateMessage(msg *database.DebateMessage) int64 {
+	id, err := s.db.SaveDebateMessage(msg)
+	if err != nil {
+		logging.Error("Failed to save debate message", map[string]interface{}{
+			"error":     err,
+			"debate_id": msg.DebateID,
+			"speaker":   msg.Speaker,
+		})
+		return 0
+	}
+	return id
+}
+
+// isAudioCached reports whether an audio URL handed out by CacheAudio can still be played
+func (s *Server) isAudioCached(audioURL string) bool {
+	switch {
+	case strings.HasPrefix(audioURL, "/api/audio/"):
+		s.cacheMutex.RLock()
+		defer s.cacheMutex.RUnlock()
+		_, exists := s.audioCache[strings.TrimPrefix(audioURL, "/api/audio/")]
+		return exists
+	case strings.HasPrefix(audioURL, "/hls/") && s.hlsDir != "":
+		_, err := os.Stat(filepath.Join(s.hlsDir, strings.TrimPrefix(audioURL, "/hls/")))
+		return err == nil
+	}
+	return false
+}
+
+// withPlayableAudio returns the message, or a copy of it without its audio URL if that audio has expired
+func (s *Server) withPlayableAudio(msg *database.DebateMessage) *database.DebateMessage {
+	if msg.AudioURL == "" || s.isAudioCached(msg.AudioURL) {
+		return msg
+	}
+	msgCopy := *msg
+	msgCopy.AudioURL = ""
+	return &msgCopy
+}
+
+// buildReplay annotates transcript messages with their timing, dropping audio URLs that have expired
+func (s *Server) buildReplay(messages []*database.DebateMessage) []replayEntry {
+	entries := make([]replayEntry, 0, len(messages))
+	for i, msg := range messages {
+		entry := replayEntry{DebateMessage: s.withPlayableAudio(msg)}
+		if i > 0 {
+			entry.OffsetSeconds = msg.CreatedAt.Sub(messages[0].CreatedAt).Seconds()
+			entry.DeltaSeconds = msg.CreatedAt.Sub(messages[i-1].CreatedAt).Seconds()
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// getDebateReplayHandler returns a finished debate's transcript with its original timing.
+// With ?stream=true the messages are sent as server-sent events on their original cadence, divided by ?speed.
+func (s *Server) getDebateReplayHandler(c *gin.Context) {
+	debateID := c.Param("debateID")
+
+	if status, err := s.checkDebateAccess(c, debateID); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	debate, err := s.db.GetDebate(debateID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Debate not found", "details": err.Error()})
+		return
+	}
+	if debate.Status != types.DebateStatusFinished {
+		c.JSON(http.StatusTooEarly, gin.H{"error": "Replays are only available for finished debates"})
+		return
+	}
+
+	speed, err := strconv.ParseFloat(c.DefaultQuery("speed", "1"), 64)
+	if err != nil || speed < 0.25 || speed > 20 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "speed must be between 0.25 and 20"})
+		return
+	}
+
+	messages, err := s.db.GetDebateMessages(debateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get debate messages", "details": err.Error()})
+		return
+	}
+	entries := s.buildReplay(messages)
+
+	if c.Query("stream") != "true" {
+		c.JSON(http.StatusOK, gin.H{
+			"debate_id": debateID,
+			"winner":    debate.Winner,
+			"messages":  entries,
+			"count":     len(entries),
+		})
+		return
+	}
+
+	// Stream each message after its original delay
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	for _, entry := range entries {
+		delay := time.Duration(entry.DeltaSeconds / speed * float64(time.Second))
+		if delay > maxReplayGap {
+			delay = maxReplayGap
+		}
+
+		select {
+		case <-c.Request.Context().Done():
+			return // Client went away
+		case <-time.After(delay):
+		}
+
+		c.SSEvent("message", entry)
+		c.Writer.Flush()
+	}
+
+	c.SSEvent("end", gin.H{"debate_id": debateID, "winner": debate.Winner, "count": len(entries)})
+	c.Writer.Flush()
+}
+
+// replayURL returns the replay path for a debate
+func replayURL(debateID string) string {
+	return fmt.Sprintf("/api/debates/%s/replay", debateID)
+}
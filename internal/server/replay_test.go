@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDebateReplayHandler(t *testing.T) {
+	// Set up test server
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/debates/:debateID/replay", server.auth.OptionalAuthMiddleware(), server.getDebateReplayHandler)
+
+	testCases := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{
+			name:           "Finished debate",
+			path:           "/api/debates/finished-debate/replay",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Debate still running",
+			path:           "/api/debates/running-debate/replay",
+			expectedStatus: http.StatusTooEarly,
+		},
+		{
+			name:           "Invalid speed",
+			path:           "/api/debates/finished-debate/replay?speed=100",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tc.path, nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+
+	t.Run("Replay timing and expired audio", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/api/debates/finished-debate/replay", nil)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Messages []struct {
+				DeltaSeconds float64 `json:"delta_seconds"`
+				AudioURL     string  `json:"audio_url"`
+			} `json:"messages"`
+		}
+		err = json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+
+		require.Len(t, response.Messages, 2)
+		assert.Empty(t, response.Messages[0].AudioURL, "audio that is no longer cached is dropped")
+		assert.InDelta(t, 2.0, response.Messages[1].DeltaSeconds, 0.001)
+	})
+
+	t.Run("Streamed replay", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/api/debates/finished-debate/replay?stream=true&speed=20", nil)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "event:message")
+		assert.Contains(t, w.Body.String(), "event:end")
+	})
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"time"
+
+	"github.com/neo/convinceme_backend/internal/logging"
+)
+
+// reputationRecomputeInterval is how often user reputations are refreshed from recent arguments and votes
+const reputationRecomputeInterval = time.Hour
+
+// StartReputationRecompute periodically recalculates user reputations used to weight votes
+func (s *Server) StartReputationRecompute(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		s.recomputeReputations()
+		for range ticker.C {
+			s.recomputeReputations()
+		}
+	}()
+}
+
+// recomputeReputations runs a single reputation refresh, logging rather than failing on error
+func (s *Server) recomputeReputations() {
+	start := time.Now()
+	updated, err := s.db.RecomputeReputations()
+	if err != nil {
+		logging.Error("Failed to recompute user reputations", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	logging.Info("Recomputed user reputations", map[string]interface{}{
+		"users":       updated,
+		"duration_ms": time.Since(start).Milliseconds(),
+	})
+}
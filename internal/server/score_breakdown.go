@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/database"
+)
+
+// getScoreBreakdownHandler reports how a debate's scored arguments did on each scoring criterion, averaged per
+// agent over the arguments made for it and per player over their own, e.g. to show in a post-debate summary
+// that one side wins on humor but loses on logic. Neutral arguments are averaged on their own. Each average
+// comes with its count, and averages of a single argument are flagged as single_sample.
+func (s *Server) getScoreBreakdownHandler(c *gin.Context) {
+	debateID := c.Param("debateID")
+
+	if status, err := s.checkDebateAccess(c, debateID); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	debate, err := s.db.GetDebate(debateID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Debate not found", "details": err.Error()})
+		return
+	}
+
+	breakdowns, err := s.db.GetScoreBreakdown(debateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get score breakdown", "details": err.Error()})
+		return
+	}
+
+	agents := make(map[string]*database.CriteriaBreakdown)
+	var neutral *database.CriteriaBreakdown
+	players := make([]*database.CriteriaBreakdown, 0)
+	for _, breakdown := range breakdowns {
+		if breakdown.Kind == database.BreakdownPlayer {
+			players = append(players, breakdown)
+			continue
+		}
+		switch breakdown.Name {
+		case sideAgent1:
+			agents[debate.Agent1Name] = breakdown
+		case sideAgent2:
+			agents[debate.Agent2Name] = breakdown
+		case sideNeutral:
+			neutral = breakdown
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"debate_id": debateID,
+		"agents":    agents,
+		"neutral":   neutral,
+		"players":   players,
+	})
+}
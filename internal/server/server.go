@@ -15,7 +15,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/neo/convinceme_backend/internal/audio"
 	"github.com/neo/convinceme_backend/internal/auth"
 	"github.com/neo/convinceme_backend/internal/conversation"
 	"github.com/neo/convinceme_backend/internal/logging"
@@ -26,13 +25,17 @@ import (
 	"github.com/neo/convinceme_backend/internal/agent"
 	"github.com/neo/convinceme_backend/internal/database"
 	"github.com/neo/convinceme_backend/internal/scoring"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
 )
 
 type Server struct {
 	router        *gin.Engine
-	agents        map[string]*agent.Agent
+	agents        *AgentRegistry // Loaded agents, shared with the debate manager
 	audioCache    map[string]audioCache
 	cacheMutex    sync.RWMutex
+	cacheStats    audioCacheCounters // Hit, miss and store counters for the audio cache
+	hlsDir        string             // Directory for HLS audio segments, empty to serve audio only as blobs
 	useHTTPS      bool
 	config        *Config
 	scorer        *scoring.Scorer
@@ -40,6 +43,19 @@ type Server struct {
 	debateManager *DebateManager      // Manages all debate sessions
 	auth          *auth.Auth          // Authentication handler
 	featureFlags  *FeatureFlagManager // Feature flag manager
+
+	debateStatsCache *ttlCache // Cached admin debate statistics
+	dashboardCache   *ttlCache // Cached admin dashboard sections, keyed by section
+	leaderboardCache *ttlCache // Cached debate leaderboards, keyed by debate ID
+	lobby            *lobbyHub // Lobby-wide event stream
+	startedAt        time.Time // When the server was created, for uptime reporting
+
+	submissions submissionDeduper // Players' latest arguments, to drop rapid resubmissions
+	critiques   critiqueJobs      // Score critiques being generated
+	transcodes  audioTranscodes   // Audio transcodes in flight, shared by concurrent requests
+
+	// Converts cached audio for ?format= requests, nil to use ffmpeg
+	transcodeAudio func(data []byte, format audioFormat) ([]byte, error)
 }
 
 // DebateEntry struct remains here for now, might move if logging moves entirely
@@ -57,12 +73,39 @@ type ConversationMessage struct {
 	Topic    string `json:"topic"`
 	Message  string `json:"message"`
 	Type     string `json:"type"`
-	Side     string `json:"side"`
+	Side     string `json:"side"` // agent1, agent2 or neutral; an agent's name is also accepted
+	// Optional client-chosen ID, echoed back in the ack or nack for this message
+	ClientMsgID string `json:"client_msg_id,omitempty"`
+	// Last broadcast seq the client saw, sent with a "resume" message after reconnecting
+	LastSeq uint64 `json:"last_seq,omitempty"`
+	// Optional argument ID, or agent turn message ID, of the earlier point an argument replies to
+	ReplyTo        int64 `json:"reply_to,omitempty"`
+	ReplyToMessage int64 `json:"reply_to_message,omitempty"`
+	// Emoji of a "reaction" message, and the transcript entry it reacts to, if any
+	Emoji     string `json:"emoji,omitempty"`
+	MessageID int64  `json:"message_id,omitempty"`
 }
 
 type audioCache struct {
-	data      []byte
-	timestamp time.Time
+	data       []byte
+	timestamp  time.Time // When the audio was cached
+	lastAccess time.Time // When the audio was last fetched, zero if never
+
+	contentType string            // Detected when the audio is cached, empty to detect it when served
+	variants    map[string][]byte // Transcoded copies by ?format= name, guarded by cacheMutex
+}
+
+// audioFetchGrace is the minimum time never-fetched audio is kept, even with a shorter TTL, so clients that
+// receive a broadcast late (e.g. via replay after reconnecting) can still fetch its audio
+const audioFetchGrace = 10 * time.Minute
+
+// expired reports whether the audio may be evicted: ttl has passed since it was last fetched (or cached),
+// and audio that was never fetched is also kept for at least audioFetchGrace
+func (a audioCache) expired(now time.Time, ttl time.Duration) bool {
+	if a.lastAccess.IsZero() {
+		return now.Sub(a.timestamp) > ttl && now.Sub(a.timestamp) > audioFetchGrace
+	}
+	return now.Sub(a.lastAccess) > ttl
 }
 
 var upgrader = websocket.Upgrader{
@@ -72,6 +115,14 @@ var upgrader = websocket.Upgrader{
 	EnableCompression: true,
 }
 
+// newScorer creates the argument scorer, or a deterministic stub in offline mode
+func newScorer(apiKey string, config *Config) (*scoring.Scorer, error) {
+	if config.IsOffline() {
+		return scoring.NewOfflineScorer(), nil
+	}
+	return scoring.NewScorer(apiKey, config.GetAPIEndpoints())
+}
+
 // Define constants for agent roles
 const (
 	TIGER_AGENT = "'La Pulga Protector' Pepito"
@@ -81,7 +132,7 @@ const (
 
 func NewServer(agents map[string]*agent.Agent, db *database.Database, apiKey string, useHTTPS bool, config *Config) *Server {
 	// Initialize player queue tracking (Scorer remains part of Server for now)
-	scorer, err := scoring.NewScorer(apiKey)
+	scorer, err := newScorer(apiKey, config)
 	if err != nil {
 		log.Printf("Warning: Failed to initialize scorer: %v", err)
 	}
@@ -97,12 +148,22 @@ func NewServer(agents map[string]*agent.Agent, db *database.Database, apiKey str
 
 	// Create a new router without default middleware
 	router := gin.New()
+	if err := router.SetTrustedProxies(config.TrustedProxies); err != nil {
+		logging.Error("Invalid trusted proxies, using the connection's address as the client IP", map[string]interface{}{
+			"trusted_proxies": config.TrustedProxies,
+			"error":           err,
+		})
+		router.SetTrustedProxies(nil)
+	}
 
 	// Add custom middleware
 	router.Use(RequestIDMiddleware())
 	router.Use(LoggingMiddleware())
 	router.Use(RecoveryMiddleware())
 	router.Use(ErrorHandler())
+	router.Use(BodySizeLimitMiddleware(config.GetMaxBodyBytes(), map[string]int64{
+		"/api/stt": config.GetMaxUploadBytes(), // Audio uploads are much larger than JSON bodies
+	}))
 
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
@@ -110,7 +171,7 @@ func NewServer(agents map[string]*agent.Agent, db *database.Database, apiKey str
 		c.Writer.Header().Set("Cross-Origin-Embedder-Policy", "require-corp")
 		c.Writer.Header().Set("Cross-Origin-Opener-Policy", "same-origin")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Range")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, HEAD")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, HEAD")
 		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Range, Content-Type")
 
 		if c.Request.Method == "OPTIONS" {
@@ -152,39 +213,91 @@ func NewServer(agents map[string]*agent.Agent, db *database.Database, apiKey str
 
 	server := &Server{
 		router:       router,
-		agents:       agents, // Keep agents map for reference if needed by manager/server
+		agents:       NewAgentRegistry(agents),
 		audioCache:   make(map[string]audioCache),
+		hlsDir:       defaultHLSDir,
 		useHTTPS:     useHTTPS,
 		config:       config,
 		scorer:       scorer, // Scorer might be passed to sessions later
 		db:           db,
 		auth:         authHandler,  // Authentication handler
 		featureFlags: featureFlags, // Feature flag manager
+
+		debateStatsCache: newTTLCache(time.Minute),
+		dashboardCache:   newTTLCache(dashboardCacheTTL),
+		leaderboardCache: newTTLCache(leaderboardCacheTTL),
+		lobby:            newLobbyHub(),
+		startedAt:        time.Now(),
 		// Removed initialization of conversation-specific fields
 	}
 
 	// Initialize Debate Manager with server reference
-	debateManager := NewDebateManager(db, agents, apiKey, server)
+	debateManager := NewDebateManager(db, server.agents, apiKey, server)
 	server.debateManager = debateManager
 
+	// Open scheduled debates when their start time arrives
+	debateManager.StartScheduledDebatePromoter(scheduledDebateCheckInterval)
+
+	// Resume active debates whose loop isn't running, e.g. after a restart or a crash
+	debateManager.StartDebateLoopReconciler(debateLoopCheckInterval)
+
+	// Resume debates paused because the LLM provider rejected the account, once it answers again
+	debateManager.StartLLMProbe(config.GetLLMProbeInterval())
+
 	// --- Update Routes ---
+	router.GET("/readyz", server.readyzHandler) // Readiness, with the state of the TTS circuit breaker and any LLM outage
 	// router.GET("/ws/conversation", server.handleConversationWebSocket) // Old route
-	router.GET("/ws/debate/:debateID", server.handleDebateWebSocket) // New route
-	router.GET("/api/audio/:id", server.handleAudioStream)           // Remains mostly the same
+	router.GET("/ws/debate/:debateID", server.auth.OptionalAuthMiddleware(), server.handleDebateWebSocket) // New route
+	router.GET("/api/audio/:id", server.handleAudioStream)                                                 // Remains mostly the same
 	// router.POST("/api/conversation/start", server.startConversation) // To be replaced or modified
-	router.POST("/api/debates", server.createDebateHandler) // New endpoint to create debates
-	router.POST("/api/stt", audio.HandleSTT)
+	router.POST("/api/debates", server.auth.OptionalAuthMiddleware(), server.createDebateHandler)                  // New endpoint to create debates
+	router.POST("/api/debates/quick", server.auth.OptionalAuthMiddleware(), server.quickDebateHandler)             // One-click debate with the default agents
+	router.POST("/api/debates/validate", server.auth.OptionalAuthMiddleware(), server.validateDebateConfigHandler) // Check a debate config without creating it
 	router.GET("/api/agents", server.listAgents)
-	router.GET("/api/arguments", server.getArguments)                              // May need debateID filter later
-	router.GET("/api/arguments/:id", server.getArgument)                           // May need debateID context later
-	router.GET("/api/debates", server.listDebatesHandler)                          // New endpoint to list debates
-	router.GET("/api/debates/:debateID", server.getDebateHandler)                  // New endpoint to get specific debate details
-	router.GET("/api/debates/:debateID/leaderboard", server.getLeaderboardHandler) // New endpoint to get debate leaderboard
-
-	// Protected voting endpoint - requires authentication
-	voteGroup := router.Group("/api/arguments")
-	voteGroup.Use(server.auth.AuthMiddleware())
-	voteGroup.POST("/:argumentID/vote", server.submitVoteHandler) // New endpoint to submit votes on arguments
+	router.GET("/api/lobby/events", server.lobbyEventsHandler)                                                                   // Server-sent lobby events, e.g. scheduled debates opening
+	router.GET("/api/agents/winrates", server.getAgentWinRatesHandler)                                                           // Agent win-rate leaderboard
+	router.GET("/api/agents/availability", server.auth.OptionalAuthMiddleware(), server.getAgentAvailabilityHandler)             // Which agents are in debates that haven't finished
+	router.GET("/api/agents/:name", server.getAgentHandler)                                                                      // One agent's public profile
+	router.GET("/api/arguments", server.getArguments)                                                                            // May need debateID filter later
+	router.GET("/api/arguments/:argumentID", server.getArgument)                                                                 // May need debateID context later
+	router.GET("/api/debates", server.listDebatesHandler)                                                                        // New endpoint to list debates
+	router.GET("/api/debates/featured", server.getFeaturedDebatesHandler)                                                        // Featured debates for the homepage
+	router.GET("/api/debates/batch", server.auth.OptionalAuthMiddleware(), server.getDebatesBatchHandler)                        // Several debates by ID, e.g. for lobby cards
+	router.GET("/api/debates/capacity", server.getDebateCapacityHandler)                                                         // Running debates against the server's maximum
+	router.GET("/api/debates/:debateID", server.auth.OptionalAuthMiddleware(), server.getDebateHandler)                          // New endpoint to get specific debate details
+	router.GET("/api/debates/:debateID/leaderboard", server.auth.OptionalAuthMiddleware(), server.getLeaderboardHandler)         // Debate leaderboard, with the caller's votes
+	router.GET("/api/debates/:debateID/arguments", server.auth.OptionalAuthMiddleware(), server.getDebateArgumentsHandler)       // All arguments in a debate, with the caller's votes
+	router.GET("/api/debates/:debateID/chat", server.auth.OptionalAuthMiddleware(), server.getChatHandler)                       // Recent chat messages for a debate
+	router.GET("/api/debates/:debateID/reactions", server.auth.OptionalAuthMiddleware(), server.getReactionsHandler)             // Reaction counts per transcript entry
+	router.GET("/api/debates/:debateID/history", server.auth.OptionalAuthMiddleware(), server.getDebateHistoryHandler)           // Older transcript entries, paged back with ?before=
+	router.GET("/api/debates/:debateID/scores/:agent", server.auth.OptionalAuthMiddleware(), server.getAgentScoreHistoryHandler) // Per-turn score history for an agent
+	router.GET("/api/debates/:debateID/replay", server.auth.OptionalAuthMiddleware(), server.getDebateReplayHandler)             // Replay a finished debate
+	router.GET("/api/debates/:debateID/state", server.auth.OptionalAuthMiddleware(), server.getDebateStateHandler)               // Full state snapshot of a running debate
+
+	// Per-agent and per-player averages of each scoring criterion
+	router.GET("/api/debates/:debateID/score-breakdown", server.auth.OptionalAuthMiddleware(), server.getScoreBreakdownHandler)
+
+	// Protected debate management endpoints - require authentication
+	debateAuthGroup := router.Group("/api/debates")
+	debateAuthGroup.Use(server.auth.AuthMiddleware())
+	debateAuthGroup.POST("/:debateID/invite", server.inviteToDebateHandler) // Creator adds users to a private debate
+	debateAuthGroup.POST("/:debateID/score-preview",
+		UserRateLimitMiddleware(scorePreviewRateLimit, time.Minute),
+		TimeoutMiddleware(config.GetLLMTimeout()),
+		server.scorePreviewHandler) // Dry-run scoring that doesn't count toward the game
+	debateAuthGroup.POST("/:debateID/restart-loop",
+		server.auth.RequireRole(string(database.RoleAdmin)),
+		server.restartDebateLoopHandler) // Admin: resume an active debate whose loop stopped
+	debateAuthGroup.POST("/:debateID/regenerate-last",
+		server.auth.RequireRole(string(database.RoleAdmin)),
+		TimeoutMiddleware(config.GetLLMTimeout()),
+		server.regenerateLastTurnHandler) // Admin: replace a paused debate's last agent turn
+	debateAuthGroup.POST("/:debateID/fork",
+		server.auth.RequireRole(string(database.RoleAdmin)),
+		server.forkDebateHandler) // Admin: continue a finished debate from one of its turns as a new debate
+	debateAuthGroup.POST("/:debateID/end",
+		server.auth.RequireRole(string(database.RoleModerator)),
+		server.endDebateHandler) // Moderator: end a debate now, with an optional winner
 
 	// router.GET("/api/gameScore", server.getGameScore) // Game score is now per-debate
 
@@ -205,21 +318,34 @@ func NewServer(agents map[string]*agent.Agent, db *database.Database, apiKey str
 	// Setup feedback routes
 	server.setupFeedbackRoutes()
 
+	// Setup notification routes
+	server.setupNotificationRoutes()
+
+	// Setup bookmark routes
+	server.setupBookmarkRoutes()
+
+	// Setup speech-to-text route
+	server.setupSTTRoutes()
+
+	// Setup protected argument routes (voting, editing, deleting)
+	server.setupArgumentRoutes()
+
+	// Setup admin routes
+	server.setupAdminRoutes()
+
+	// Setup versioned API routes; /api/* keeps the v1 response shapes
+	server.setupV2Routes()
+
+	// Keep vote weights in line with users' recent performance
+	server.StartReputationRecompute(reputationRecomputeInterval)
+
 	// Update static file routes
 	router.StaticFile("/", "./static/lobby.html") // Use lobby as main page
 	router.StaticFile("/lobby.html", "./static/lobby.html")
 	router.StaticFile("/debate.html", "./static/debate.html")
 	router.StaticFile("/test.html", "./test.html") // Add test.html for testing
 	router.Static("/static", "./static")
-	router.Static("/hls", "./static/hls")
-
-	router.Use(func(c *gin.Context) {
-		if c.Request.URL.Path[:4] == "/hls" {
-			if len(c.Request.URL.Path) > 5 && c.Request.URL.Path[len(c.Request.URL.Path)-5:] == ".aac" {
-				c.Header("Content-Type", "audio/aac")
-			}
-		}
-	})
+	router.Group("/hls", hlsContentTypeMiddleware()).Static("/", "./static/hls")
 
 	log.Printf("Server initialized with %d agents", len(agents))
 	return server
@@ -227,57 +353,38 @@ func NewServer(agents map[string]*agent.Agent, db *database.Database, apiKey str
 
 // --- New API Handlers (Stubs) ---
 
-func (s *Server) createDebateHandler(c *gin.Context) {
-	// Extract request data
-	var req struct {
-		Topic     string `json:"topic"`
-		Agent1    string `json:"agent1"`
-		Agent2    string `json:"agent2"`
-		CreatedBy string `json:"created_by"`
-		TopicID   int    `json:"topic_id"` // Optional: Use a pre-generated topic
-	}
+// scheduledDebateCheckInterval is how often scheduled debates are checked for their start time
+const scheduledDebateCheckInterval = 15 * time.Second
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
-		return
-	}
+// debateLoopCheckInterval is how often active debates are checked for a stopped loop
+const debateLoopCheckInterval = 30 * time.Second
 
-	// If a topic ID is provided, use the pre-generated topic
-	if req.TopicID > 0 {
-		topic, err := s.db.GetTopic(req.TopicID)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Topic with ID %d not found: %v", req.TopicID, err)})
-			return
-		}
-
-		// Override the request with the pre-generated topic details
-		req.Topic = topic.Title
-		req.Agent1 = topic.Agent1Name
-		req.Agent2 = topic.Agent2Name
-	}
-
-	// Validate agents exist
-	agent1, exists := s.agents[req.Agent1]
-	if !exists {
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Agent '%s' not found", req.Agent1)})
-		return
-	}
+// Bounds for the per-debate timeouts accepted by ValidateDebateConfig
+const (
+	minDebateDurationSeconds   = 60          // 1 minute
+	maxDebateDurationSeconds   = 2 * 60 * 60 // 2 hours
+	minDebateInactivitySeconds = 30
+	maxDebateInactivitySeconds = 30 * 60 // 30 minutes
+)
 
-	agent2, exists := s.agents[req.Agent2]
-	if !exists {
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Agent '%s' not found", req.Agent2)})
+func (s *Server) createDebateHandler(c *gin.Context) {
+	var req DebateConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
 		return
 	}
 
-	if req.Agent1 == req.Agent2 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot create debate with the same agent on both sides"})
+	validated, errs := s.ValidateDebateConfig(c, req)
+	if len(errs) > 0 {
+		// Only /api/debates/validate lists every error; creating keeps its single-error body
+		c.JSON(errs[0].status, gin.H{"error": errs[0].Message})
 		return
 	}
 
 	// Create debate via manager
-	debateID, err := s.debateManager.CreateDebate(req.Topic, agent1, agent2, req.CreatedBy)
+	debateID, err := s.debateManager.CreateDebateWithConfig(validated.Config, validated.Agent1, validated.Agent2, validated.CreatedBy, validated.Settings)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create debate: %v", err)})
+		s.respondDebateCreateFailed(c, err)
 		return
 	}
 
@@ -295,43 +402,63 @@ func (s *Server) createDebateHandler(c *gin.Context) {
 }
 
 func (s *Server) listDebatesHandler(c *gin.Context) {
+	s.listDebatesWith(c, v1Responder{})
+}
+
+// listDebatesWith lists public debates and writes them with the given responder
+func (s *Server) listDebatesWith(c *gin.Context, r responder) {
 	// Get pagination parameters
 	paginationParams := GetPaginationParams(c)
 
 	// Get filter parameters
 	filterParams := GetFilterParams(c)
 
-	// Special case for 'active' status to include 'waiting' debates
-	if filterParams.Status == "active" || filterParams.Status == "waiting" {
+	// Special case for 'active' status to include 'waiting' and 'scheduled' debates
+	switch types.DebateStatus(filterParams.Status) {
+	case types.DebateStatusActive, types.DebateStatusWaiting, types.DebateStatusScheduled:
 		// Use the existing method that handles both 'active' and 'waiting' statuses
 		debates, err := s.db.ListActiveDebates()
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to list debates: %v", err)})
+			r.InlineError(c, http.StatusInternalServerError, "Failed to list debates", err)
+			return
+		}
+
+		// Unlisted and private debates are never shown in public listings
+		publicDebates := make([]*database.Debate, 0, len(debates))
+		for _, debate := range debates {
+			if debate.Visibility == "" || debate.Visibility == database.DebateVisibilityPublic {
+				publicDebates = append(publicDebates, debate)
+			}
+		}
+		debates = publicDebates
+
+		// Live connection counts and scores are opt-in, to skip the session lookups when not needed
+		if c.Query("live") == "true" {
+			liveDebates, clientCount := s.withLiveState(debates)
+			r.List(c, "debates", projectFields(c, liveDebates, debateListFields), gin.H{"count": len(debates), "client_count": clientCount}, nil)
 			return
 		}
 
 		// Since we're not using pagination here, just return all debates
-		c.JSON(http.StatusOK, gin.H{
-			"debates": debates,
-			"count":   len(debates),
-		})
+		r.List(c, "debates", projectFields(c, debates, debateListFields), gin.H{"count": len(debates)}, nil)
 		return
 	}
 
 	// Convert to database filter
 	filter := database.DebateFilter{
-		Status:  filterParams.Status,
-		Search:  filterParams.Search,
-		SortBy:  filterParams.SortBy,
-		SortDir: filterParams.SortDir,
-		Offset:  paginationParams.CalculateOffset(),
-		Limit:   paginationParams.PageSize,
+		Status:     filterParams.Status,
+		Search:     filterParams.Search,
+		Visibility: database.DebateVisibilityPublic,
+		SortBy:     filterParams.SortBy,
+		SortDir:    filterParams.SortDir,
+		Offset:     paginationParams.CalculateOffset(),
+		Limit:      paginationParams.PageSize,
 	}
 
 	// Get debates with pagination and filtering
 	debates, total, err := s.db.ListDebates(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to list debates: %v", err)})
+		r.InlineError(c, http.StatusInternalServerError, "Failed to list debates", err)
 		return
 	}
 
@@ -339,73 +466,509 @@ func (s *Server) listDebatesHandler(c *gin.Context) {
 	paginationParams.Total = total
 
 	// Send paginated response
-	SendPaginatedResponse(c, paginationParams, debates)
+	r.List(c, "debates", projectFields(c, debates, debateListFields), nil, &paginationParams)
+}
+
+// liveDebate is a listed debate with the live state of its in-memory session
+type liveDebate struct {
+	*database.Debate
+	ClientCount int            `json:"client_count"`
+	GameScore   map[string]int `json:"game_score,omitempty"` // Current HP by agent name, omitted if the debate isn't loaded
+}
+
+// withLiveState attaches the connected client count and current HP from each debate's session.
+// Debates that aren't loaded in memory show no clients. It also returns the total clients across all debates.
+func (s *Server) withLiveState(debates []*database.Debate) ([]liveDebate, int) {
+	liveDebates := make([]liveDebate, 0, len(debates))
+	total := 0
+	for _, debate := range debates {
+		live := liveDebate{Debate: debate}
+		if session, exists := s.debateManager.GetDebate(debate.ID); exists {
+			gameScore := session.GetGameScore()
+			live.ClientCount = session.GetPresence().Total
+			live.GameScore = map[string]int{
+				debate.Agent1Name: gameScore.Agent1Score,
+				debate.Agent2Name: gameScore.Agent2Score,
+			}
+		}
+		total += live.ClientCount
+		liveDebates = append(liveDebates, live)
+	}
+	return liveDebates, total
 }
 
 func (s *Server) getDebateHandler(c *gin.Context) {
+	s.getDebateWith(c, v1Responder{})
+}
+
+// getDebateWith loads a debate with its real-time state and writes it with the given responder. Private debates
+// are only shown to their creator and invitees.
+func (s *Server) getDebateWith(c *gin.Context, r responder) {
 	debateID := c.Param("debateID")
 	if debateID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Debate ID is required"})
+		r.Error(c, http.StatusBadRequest, "Debate ID is required", nil)
+		return
+	}
+	if status, err := s.checkDebateAccess(c, debateID); err != nil {
+		r.Error(c, status, err.Error(), nil)
 		return
 	}
 
 	debate, err := s.db.GetDebate(debateID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Debate not found", "details": err.Error()})
+		r.Error(c, http.StatusNotFound, "Debate not found", err)
 		return
 	}
 
 	// Get additional real-time information from active session if available
+	response := gin.H{"bookmark_count": s.debateBookmarkCount(debate.ID)}
+	if realTime := s.debateRealTime(c, debate); realTime != nil {
+		response["real_time"] = realTime
+	}
+
+	r.Item(c, "debate", debate, response)
+}
+
+// debateRealTime describes the live state of a debate's in-memory session, or returns nil if it isn't loaded
+func (s *Server) debateRealTime(c *gin.Context, debate *database.Debate) gin.H {
+	session, exists := s.debateManager.GetDebate(debate.ID)
+	if !exists {
+		return nil
+	}
+
+	gameScore := session.GetGameScore()
+	status := session.GetStatus()
+	presence := session.GetPresence()
+
+	realTime := gin.H{
+		"game_score": gin.H{
+			debate.Agent1Name: gameScore.Agent1Score,
+			debate.Agent2Name: gameScore.Agent2Score,
+		},
+		"status":         status,
+		"response_style": session.Config.ResponseStyle,
+		"client_count":   presence.Total,
+		"presence":       presence,
+		"active_seconds": int64(session.GetActiveDuration().Seconds()),
+		"timeouts":       debateTimeouts(session),
+		"turns": gin.H{
+			"current": session.GetTurnCount(),
+			"max":     session.Config.MaxTurns, // 0 or less means unlimited
+		},
+	}
+
+	// Spend is admin-only, like the usage endpoint
+	if role, _ := auth.GetUserRole(c); role == string(database.RoleAdmin) {
+		realTime["budget"] = gin.H{
+			"cost_usd":     s.debateCost(session),
+			"max_cost_usd": session.Config.MaxCostUSD, // 0 means unlimited
+		}
+	}
+	return realTime
+}
+
+// debateTimeouts describes a running debate's configured timeouts and, once the loop has started,
+// when it will time out so clients can show a countdown
+func debateTimeouts(session *conversation.DebateSession) gin.H {
+	timeouts := gin.H{
+		"max_duration_seconds":   int64(session.Config.MaxDuration.Seconds()),
+		"max_inactivity_seconds": int64(session.Config.MaxInactivity.Seconds()),
+	}
+
+	if deadline := session.GetDeadline(); !deadline.IsZero() {
+		remaining := time.Until(deadline)
+		if remaining < 0 {
+			remaining = 0
+		}
+		timeouts["ends_at"] = deadline
+		timeouts["remaining_seconds"] = int64(remaining.Seconds())
+	}
+	return timeouts
+}
+
+// getDebateArgumentsHandler returns a page of a debate's arguments with scores and vote counts.
+// Authenticated callers also get their own vote on each argument as user_vote.
+func (s *Server) getDebateArgumentsHandler(c *gin.Context) {
+	debateID := c.Param("debateID")
+
+	if status, err := s.checkDebateAccess(c, debateID); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	paginationParams := GetPaginationParams(c)
+	filterParams := GetFilterParams(c)
+	if !database.IsValidArgumentSort(filterParams.SortBy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort_by must be one of created_at, score or vote_score"})
+		return
+	}
+
+	filter := database.ArgumentFilter{
+		SortBy:  filterParams.SortBy,
+		SortDir: filterParams.SortDir,
+		Offset:  paginationParams.CalculateOffset(),
+		Limit:   paginationParams.PageSize,
+	}
+
+	arguments, total, err := s.db.GetArgumentsByDebate(debateID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get arguments", "details": err.Error()})
+		return
+	}
+
+	if userID, authenticated := auth.GetUserID(c); authenticated {
+		votes, err := s.db.GetUserVotesForDebate(userID, debateID)
+		if err != nil {
+			log.Printf("Failed to get votes of user %s in debate %s: %v", userID, debateID, err)
+		}
+		for _, argument := range arguments {
+			argument.UserVote = votes[argument.ID]
+		}
+	}
+
+	paginationParams.Total = total
+	SendPaginatedResponse(c, paginationParams, arguments)
+}
+
+// getAgentScoreHistoryHandler returns an agent's per-turn argument scores in a running debate
+func (s *Server) getAgentScoreHistoryHandler(c *gin.Context) {
+	debateID := c.Param("debateID")
+	agentName := c.Param("agent")
+
+	if status, err := s.checkDebateAccess(c, debateID); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
 	session, exists := s.debateManager.GetDebate(debateID)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Debate not found or not active"})
+		return
+	}
 
-	response := gin.H{"debate": debate}
+	if !session.HasAgent(agentName) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Agent '%s' is not part of this debate", agentName)})
+		return
+	}
 
-	if exists {
-		// Add real-time information
-		gameScore := session.GetGameScore()
-		status := session.GetStatus()
-		_, clientCount := session.CheckStatusAndClients()
+	c.JSON(http.StatusOK, gin.H{
+		"debate_id": debateID,
+		"agent":     agentName,
+		"scores":    session.GetAgentScoreHistory(agentName),
+	})
+}
 
-		response["real_time"] = gin.H{
-			"game_score": gin.H{
-				debate.Agent1Name: gameScore.Agent1Score,
-				debate.Agent2Name: gameScore.Agent2Score,
-			},
-			"status":       status,
-			"client_count": clientCount,
+// maxChatMessageLength caps the length of a single chat message
+const maxChatMessageLength = 500
+
+// handleChatMessage rate limits, optionally persists, and broadcasts a chat message.
+// It returns the updated list of the client's recent chat timestamps.
+func (s *Server) handleChatMessage(ctx context.Context, ws jsonWriter, session *conversation.DebateSession, playerID string, msg ConversationMessage, chatTimes []time.Time) []time.Time {
+	message := strings.TrimSpace(msg.Message)
+	if message == "" {
+		return chatTimes
+	}
+	if len(message) > maxChatMessageLength {
+		ws.WriteJSON(gin.H{
+			"type":    "error",
+			"message": fmt.Sprintf("Chat messages are limited to %d characters", maxChatMessageLength),
+		})
+		return chatTimes
+	}
+
+	// Drop timestamps older than the one-minute window
+	now := time.Now()
+	recent := chatTimes[:0]
+	for _, t := range chatTimes {
+		if now.Sub(t) < time.Minute {
+			recent = append(recent, t)
 		}
 	}
+	if limit := session.Config.ChatRateLimit; limit > 0 && len(recent) >= limit {
+		ws.WriteJSON(gin.H{
+			"type":    "error",
+			"message": "You're sending chat messages too quickly",
+		})
+		return recent
+	}
+	recent = append(recent, now)
 
-	c.JSON(http.StatusOK, response)
+	if msg.Username != "" {
+		session.SetUserName(playerID, msg.Username)
+	}
+	displayName := session.GetUserName(playerID)
+
+	if session.Config.PersistChat {
+		if _, err := s.db.SaveChatMessage(session.DebateID, playerID, displayName, message); err != nil {
+			logging.ErrorCtx(ctx, "Failed to save chat message", map[string]interface{}{
+				"error": err,
+			})
+		}
+	}
+
+	session.Broadcast(gin.H{
+		"type":      "chat",
+		"player_id": playerID,
+		"username":  displayName,
+		"message":   message,
+		"timestamp": now,
+	})
+
+	return recent
 }
 
-// getLeaderboardHandler returns the top-scoring arguments for a specific debate
-func (s *Server) getLeaderboardHandler(c *gin.Context) {
+// Reasons a player argument is rejected with a nack
+const (
+	nackReasonEmpty     = "empty"         // The message had no content
+	nackReasonSpectator = "spectator"     // Spectators cannot submit arguments
+	nackReasonSide      = "invalid_side"  // The side was not one of the debate's agents or neutral
+	nackReasonDuplicate = "duplicate"     // The player sent the same argument moments ago
+	nackReasonNotActive = "not_active"    // The debate is paused or has ended
+	nackReasonReply     = "invalid_reply" // The argument replies to an argument or agent turn that isn't in the debate
+)
+
+// Reasons a player argument is rejected by checkArgumentContent
+const (
+	nackReasonTooShort = "too_short" // Under the debate's minimum argument length
+	nackReasonTooLong  = "too_long"  // Over the debate's maximum argument length
+	nackReasonSpam     = "spam"      // Repeated characters or an all-caps flood
+)
+
+// Sides a player argument can support
+const (
+	sideAgent1  = "agent1"
+	sideAgent2  = "agent2"
+	sideNeutral = "neutral" // No HP changes
+)
+
+// maxCrowdFactor bounds the crowd_factor accepted by ValidateDebateConfig
+const maxCrowdFactor = 1.0
+
+// normalizeSide maps a player's side to sideAgent1, sideAgent2 or sideNeutral. Besides those values, the exact
+// name of either agent is accepted (case-insensitively), and an empty side is neutral. Anything else is invalid.
+func normalizeSide(side, agent1Name, agent2Name string) (string, bool) {
+	side = strings.TrimSpace(side)
+	switch {
+	case side == "" || strings.EqualFold(side, sideNeutral):
+		return sideNeutral, true
+	case strings.EqualFold(side, sideAgent1) || strings.EqualFold(side, agent1Name):
+		return sideAgent1, true
+	case strings.EqualFold(side, sideAgent2) || strings.EqualFold(side, agent2Name):
+		return sideAgent2, true
+	}
+	return "", false
+}
+
+// sideAgents returns the names of the agents supported and opposed by a normalized side, both empty if neutral
+func sideAgents(side, agent1Name, agent2Name string) (supported, opposed string) {
+	switch side {
+	case sideAgent1:
+		return agent1Name, agent2Name
+	case sideAgent2:
+		return agent2Name, agent1Name
+	}
+	return "", ""
+}
+
+// jsonWriter writes JSON messages to a websocket client
+type jsonWriter interface {
+	WriteJSON(v interface{}) error
+}
+
+// sessionClient writes to a client connected to a debate through the session, which queues the messages in
+// order with its broadcasts, as only one writer may use the connection
+type sessionClient struct {
+	session *conversation.DebateSession
+	conn    *websocket.Conn
+}
+
+// WriteJSON queues a message for the client
+func (c sessionClient) WriteJSON(v interface{}) error {
+	return c.session.Send(c.conn, v)
+}
+
+// sendArgumentAck tells the sending client its argument was saved and scored, before the result is broadcast.
+// Clients that didn't set a client_msg_id get no ack.
+func sendArgumentAck(ws jsonWriter, clientMsgID string, argumentID int64, score *scoring.ArgumentScore) error {
+	if clientMsgID == "" {
+		return nil
+	}
+	return ws.WriteJSON(gin.H{
+		"type":          "ack",
+		"client_msg_id": clientMsgID,
+		"argument_id":   argumentID,
+		"score":         score,
+	})
+}
+
+// sendArgumentNack tells the sending client its argument was rejected and why.
+// Clients that didn't set a client_msg_id get no nack.
+func sendArgumentNack(ws jsonWriter, clientMsgID, reason string) error {
+	if clientMsgID == "" {
+		return nil
+	}
+	return ws.WriteJSON(gin.H{
+		"type":          "nack",
+		"client_msg_id": clientMsgID,
+		"reason":        reason,
+	})
+}
+
+// Close codes for rejected WebSocket connections, from the 4000-4999 range reserved for applications.
+// They mirror the matching HTTP statuses, so clients can tell policy rejections from network drops.
+const (
+	closeCodeForbidden      = 4403 // Private debate the client isn't allowed into
+	closeCodeDebateNotFound = 4404 // No running debate with that ID
+	closeCodeDebateFull     = 4409 // The debate has reached its cap for the client's role
+)
+
+// closeWebSocket sends a close frame with code and reason, so the client learns why the connection ended
+func closeWebSocket(ws *websocket.Conn, code int, reason string) error {
+	return ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+}
+
+// rejectWebSocket refuses a WebSocket connection. Upgrade requests are upgraded only to be closed straight away
+// with code and reason; anything else gets a plain HTTP error with status.
+func rejectWebSocket(c *gin.Context, status, code int, reason string) {
+	if !websocket.IsWebSocketUpgrade(c.Request) {
+		c.JSON(status, gin.H{"error": reason})
+		return
+	}
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+	closeWebSocket(ws, code, reason)
+}
+
+// webSocketUserID resolves the authenticated user for a WebSocket request, accepting a token query param
+// since browsers cannot set the Authorization header on WebSocket connections
+func (s *Server) webSocketUserID(c *gin.Context) (string, bool) {
+	if userID, exists := auth.GetUserID(c); exists {
+		return userID, true
+	}
+	if token := c.Query("token"); token != "" {
+		if claims, err := s.auth.ValidateToken(token); err == nil {
+			return claims.UserID, true
+		}
+	}
+	return "", false
+}
+
+// checkDebateAccess verifies the requesting user may join a debate, returning an HTTP status on failure
+func (s *Server) checkDebateAccess(c *gin.Context, debateID string) (int, error) {
+	debate, err := s.db.GetDebate(debateID)
+	if err != nil {
+		return http.StatusNotFound, fmt.Errorf("debate not found")
+	}
+	if debate.Visibility != database.DebateVisibilityPrivate {
+		return http.StatusOK, nil
+	}
+
+	userID, authenticated := s.webSocketUserID(c)
+	if !authenticated {
+		return http.StatusUnauthorized, fmt.Errorf("authentication required to join a private debate")
+	}
+	if debate.CreatedBy != nil && *debate.CreatedBy == userID {
+		return http.StatusOK, nil
+	}
+
+	invited, err := s.db.IsDebateParticipant(debateID, userID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to check debate access")
+	}
+	if !invited {
+		return http.StatusForbidden, fmt.Errorf("you have not been invited to this debate")
+	}
+	return http.StatusOK, nil
+}
+
+// inviteToDebateHandler lets the creator of a debate add users to its allowlist
+func (s *Server) inviteToDebateHandler(c *gin.Context) {
 	debateID := c.Param("debateID")
-	if debateID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Debate ID is required"})
+
+	var req struct {
+		UserID   string `json:"user_id"`
+		Username string `json:"username"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+	if req.UserID == "" && req.Username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Either user_id or username is required"})
 		return
 	}
 
-	// Get limit parameter, default to 10
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 || limit > 100 {
-		limit = 10 // Default to 10, max 100
+	// Get user ID from authentication context
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
 	}
 
-	// Get leaderboard from database
-	arguments, err := s.db.GetLeaderboard(debateID, limit)
+	debate, err := s.db.GetDebate(debateID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get leaderboard", "details": err.Error()})
+		c.JSON(http.StatusNotFound, gin.H{"error": "Debate not found", "details": err.Error()})
+		return
+	}
+
+	if debate.CreatedBy == nil || *debate.CreatedBy != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the debate creator can invite users"})
+		return
+	}
+
+	// Resolve the invitee
+	var invitee *database.User
+	if req.UserID != "" {
+		invitee, err = s.db.GetUserByID(req.UserID)
+	} else {
+		invitee, err = s.db.GetUserByUsername(req.Username)
+	}
+	if err != nil || invitee == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if err := s.db.AddDebateParticipant(debateID, invitee.ID, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to invite user", "details": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"debate_id":   debateID,
-		"leaderboard": arguments,
-		"limit":       limit,
-		"total":       len(arguments),
+		"message":   "User invited successfully",
+		"debate_id": debateID,
+		"user_id":   invitee.ID,
+		"username":  invitee.Username,
+	})
+}
+
+// getChatHandler returns the most recent chat messages for a debate
+func (s *Server) getChatHandler(c *gin.Context) {
+	debateID := c.Param("debateID")
+
+	if status, err := s.checkDebateAccess(c, debateID); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get limit parameter, default to 50
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 50 // Default to 50, max 200
+	}
+
+	messages, err := s.db.GetChatMessages(debateID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get chat messages", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"debate_id": debateID,
+		"messages":  messages,
+		"count":     len(messages),
 	})
 }
 
@@ -462,20 +1025,9 @@ func (s *Server) submitVoteHandler(c *gin.Context) {
 		return
 	}
 
-	// Get updated leaderboard and broadcast it
-	leaderboard, err := s.db.GetLeaderboard(req.DebateID, 10)
-	if err != nil {
-		log.Printf("Error getting leaderboard for broadcast after vote: %v", err)
-	} else {
-		// Find the debate session and broadcast the update
-		if session, exists := s.debateManager.GetDebate(req.DebateID); exists {
-			session.Broadcast(gin.H{
-				"type":        "leaderboard_update",
-				"debate_id":   req.DebateID,
-				"leaderboard": leaderboard,
-			})
-		}
-	}
+	// The vote changes the argument's ranking, so refresh and broadcast the leaderboard
+	s.invalidateLeaderboard(req.DebateID)
+	s.broadcastLeaderboard(req.DebateID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -490,20 +1042,44 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 	debateID := c.Param("debateID")
 	clientIP := c.ClientIP()
 
+	// Clients join as participants unless they explicitly ask to spectate
+	role := c.DefaultQuery("role", conversation.ClientRoleParticipant)
+
 	logging.LogWebSocketEvent("connection_attempt", debateID, "", map[string]interface{}{
 		"client_ip":  clientIP,
 		"user_agent": c.GetHeader("User-Agent"),
+		"role":       role,
 	})
 
+	if !conversation.IsValidClientRole(role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role. Must be 'participant' or 'spectator'"})
+		return
+	}
+
 	// 1. Get DebateSession from manager
 	session, exists := s.debateManager.GetDebate(debateID)
 	if !exists {
 		logging.LogWebSocketEvent("debate_not_found", debateID, "", map[string]interface{}{
 			"client_ip": clientIP,
 		})
-		// Optionally send an error back before closing if possible,
-		// but Upgrade might fail anyway if we write before upgrading.
-		// For simplicity, just return. The client will see a failed connection.
+		rejectWebSocket(c, http.StatusNotFound, closeCodeDebateNotFound, "debate not found")
+		return
+	}
+
+	// Private debates are only joinable by their creator and invited users
+	if status, err := s.checkDebateAccess(c, debateID); err != nil {
+		logging.LogWebSocketEvent("access_denied", debateID, "", map[string]interface{}{
+			"client_ip": clientIP,
+			"error":     err,
+		})
+		code := closeCodeForbidden
+		switch status {
+		case http.StatusNotFound:
+			code = closeCodeDebateNotFound
+		case http.StatusInternalServerError:
+			code = websocket.CloseInternalServerErr
+		}
+		rejectWebSocket(c, status, code, err.Error())
 		return
 	}
 
@@ -518,16 +1094,47 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 	}
 	defer ws.Close()
 
-	// Generate a unique Player ID for this connection
-	// TODO: Replace with actual user authentication/ID if available
-	playerID := fmt.Sprintf("player_%s", uuid.New().String()[:8])
+	// Oversize messages fail the read and close the connection
+	ws.SetReadLimit(s.config.GetWebSocketMaxMessageBytes())
+
+	// Scheduled debates can't be joined until the scheduler opens them
+	if session.GetStatus() == types.DebateStatusScheduled {
+		logging.LogWebSocketEvent("debate_not_started", debateID, "", map[string]interface{}{
+			"client_ip": clientIP,
+		})
+		closeWebSocket(ws, websocket.CloseTryAgainLater, "not started yet")
+		return
+	}
+
+	// Authenticated users play under their user ID; anonymous connections get a unique Player ID
+	playerID, authenticated := s.webSocketUserID(c)
+	if !authenticated {
+		playerID = fmt.Sprintf("player_%s", uuid.New().String()[:8])
+	}
+
+	// Every log entry for this connection carries the request, debate and player IDs
+	logCtx := logging.WithFields(c.Request.Context(), map[string]interface{}{
+		"debate_id": debateID,
+		"player_id": playerID,
+	})
 
 	logging.LogWebSocketEvent("connection_established", debateID, playerID, map[string]interface{}{
 		"client_ip": clientIP,
+		"role":      role,
 	})
 
-	// 3. Add client to session
-	session.AddClient(ws, playerID)
+	// 3. Add client to session, respecting the per-role connection caps
+	if err := session.AddClientWithRole(ws, playerID, role); err != nil {
+		logging.LogWebSocketEvent("join_rejected", debateID, playerID, map[string]interface{}{
+			"error": err,
+			"role":  role,
+		})
+		closeWebSocket(ws, closeCodeDebateFull, "debate is full")
+		return
+	}
+
+	// From now on the client is written to through the session, in order with its broadcasts
+	client := sessionClient{session: session, conn: ws}
 
 	// 4. Send current debate state to new client (for reconnections)
 	status := session.GetStatus()
@@ -544,13 +1151,15 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 		},
 		"debate_id": debateID,
 		"player_id": playerID,
+		"role":      role,
+		"version":   CurrentAPIVersion,
+		"seq":       session.LastSeq(), // Reconnecting clients that saw an earlier seq should send a "resume"
+		"pace":      turnPacePayload(session.GetTurnPace()),
 	}
 
-	if err := ws.WriteJSON(welcomeMsg); err != nil {
-		logging.Error("Failed to send welcome message", map[string]interface{}{
-			"error":     err,
-			"debate_id": debateID,
-			"player_id": playerID,
+	if err := client.WriteJSON(welcomeMsg); err != nil {
+		logging.ErrorCtx(logCtx, "Failed to send welcome message", map[string]interface{}{
+			"error": err,
 		})
 	}
 
@@ -564,32 +1173,37 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 			"isPlayer":  entry.IsPlayer,
 			"isHistory": true, // Mark as historical message
 		}
-		if err := ws.WriteJSON(historyMsg); err != nil {
-			logging.Error("Failed to send history message", map[string]interface{}{
-				"error":     err,
-				"debate_id": debateID,
-				"player_id": playerID,
+		if err := client.WriteJSON(historyMsg); err != nil {
+			logging.ErrorCtx(logCtx, "Failed to send history message", map[string]interface{}{
+				"error": err,
 			})
 			break // Stop sending history if there's an error
 		}
 	}
 
-	// 5. If first client for a 'waiting' debate, start the debate loop
-	if session.GetStatus() == "waiting" {
+	// 5. If first client for a 'waiting' debate, start the debate loop. An 'active' debate without a loop,
+	// e.g. one loaded at startup whose loop stopped, is resumed now instead of at the next reconciliation.
+	// StartDebateLoop never starts a second loop, so clients connecting at the same time are safe.
+	if status := session.GetStatus(); status == types.DebateStatusActive && !session.IsLoopRunning() {
+		if s.debateManager.StartDebateLoop(session) {
+			logging.LogDebateEvent("debate_loop_restarted", debateID, map[string]interface{}{
+				"triggered_by": playerID,
+			})
+		}
+	} else if status == types.DebateStatusWaiting {
 		logging.LogDebateEvent("status_change", debateID, map[string]interface{}{
-			"from_status":  "waiting",
-			"to_status":    "active",
+			"from_status":  types.DebateStatusWaiting,
+			"to_status":    types.DebateStatusActive,
 			"triggered_by": playerID,
 		})
 
-		session.UpdateStatus("active")
+		session.UpdateStatus(types.DebateStatusActive)
 		// Update DB status as well
-		err := s.db.UpdateDebateStatus(debateID, "active")
+		err := s.db.UpdateDebateStatus(debateID, types.DebateStatusActive)
 		if err != nil {
-			logging.Error("Failed to update debate status in database", map[string]interface{}{
-				"error":     err,
-				"debate_id": debateID,
-				"status":    "active",
+			logging.ErrorCtx(logCtx, "Failed to update debate status in database", map[string]interface{}{
+				"error":  err,
+				"status": types.DebateStatusActive,
 			})
 			// Handle error - maybe close connection?
 		}
@@ -609,10 +1223,9 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 		// 2. New clients to join ongoing debates
 		// 3. Robust handling of network issues
 
-		if remainingClients == 0 && session.GetStatus() == "active" {
-			logging.Info("Debate continues without observers", map[string]interface{}{
-				"debate_id": debateID,
-				"status":    "active_unobserved",
+		if remainingClients == 0 && session.GetStatus() == types.DebateStatusActive {
+			logging.InfoCtx(logCtx, "Debate continues without observers", map[string]interface{}{
+				"status": "active_unobserved",
 			})
 			// Debates only stop when:
 			// 1. A winner is determined (HP reaches 0)
@@ -622,6 +1235,9 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 		}
 	}()
 
+	// Timestamps of this client's recent chat messages and reactions, used for rate limiting
+	var chatTimes, reactionTimes []time.Time
+
 	// 6. Handle incoming messages for this client/session with better error recovery
 	for {
 		var msg ConversationMessage
@@ -632,16 +1248,12 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 		err := ws.ReadJSON(&msg)
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				logging.Error("Unexpected WebSocket error", map[string]interface{}{
-					"error":     err,
-					"player_id": playerID,
-					"debate_id": debateID,
+				logging.ErrorCtx(logCtx, "Unexpected WebSocket error", map[string]interface{}{
+					"error": err,
 				})
 			} else {
-				logging.Info("WebSocket connection closed", map[string]interface{}{
-					"player_id": playerID,
-					"debate_id": debateID,
-					"reason":    "normal_close",
+				logging.InfoCtx(logCtx, "WebSocket connection closed", map[string]interface{}{
+					"reason": "normal_close",
 				})
 			}
 			break // Exit loop on error/close
@@ -650,22 +1262,18 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 		// Reset read deadline after successful read
 		ws.SetReadDeadline(time.Time{})
 
-		logging.Info("Received player message", map[string]interface{}{
-			"player_id": playerID,
-			"debate_id": debateID,
-			"message":   msg.Message,
-			"username":  msg.Username,
-			"type":      msg.Type,
+		logging.InfoCtx(logCtx, "Received player message", map[string]interface{}{
+			"message":  msg.Message,
+			"username": msg.Username,
+			"type":     msg.Type,
 		})
 
 		// Handle special message types for state synchronization
 		if msg.Type == "get_state" {
 			debateInfo, err := s.debateManager.GetDebateInfo(debateID)
 			if err != nil {
-				logging.Error("Failed to get debate info", map[string]interface{}{
-					"error":     err,
-					"debate_id": debateID,
-					"player_id": playerID,
+				logging.ErrorCtx(logCtx, "Failed to get debate info", map[string]interface{}{
+					"error": err,
 				})
 				continue
 			}
@@ -675,30 +1283,88 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 				"debate_info": debateInfo,
 			}
 
-			if err := ws.WriteJSON(stateMsg); err != nil {
-				logging.Error("Failed to send state update", map[string]interface{}{
-					"error":     err,
-					"debate_id": debateID,
-					"player_id": playerID,
+			if err := client.WriteJSON(stateMsg); err != nil {
+				logging.ErrorCtx(logCtx, "Failed to send state update", map[string]interface{}{
+					"error": err,
 				})
 			}
 			continue // Don't process as regular message
 		}
 
+		// Replay broadcasts a reconnecting client missed while it was away
+		if msg.Type == "resume" {
+			replayed, complete, err := session.ReplaySince(ws, msg.LastSeq)
+			if err != nil {
+				logging.ErrorCtx(logCtx, "Failed to replay missed broadcasts", map[string]interface{}{
+					"error":    err,
+					"last_seq": msg.LastSeq,
+				})
+				continue
+			}
+			// An incomplete replay means events were lost, so the client should also request the full state
+			client.WriteJSON(gin.H{
+				"type":     "resumed",
+				"replayed": replayed,
+				"complete": complete,
+				"seq":      session.LastSeq(),
+			})
+			continue // Don't process as regular message
+		}
+
 		// Handle username setting - this allows the client to set their display name
 		if msg.Type == "set_username" && msg.Username != "" {
 			session.SetUserName(playerID, msg.Username)
-			logging.Info("Set username for player", map[string]interface{}{
-				"player_id": playerID,
-				"username":  msg.Username,
-				"debate_id": debateID,
+			logging.InfoCtx(logCtx, "Set username for player", map[string]interface{}{
+				"username": msg.Username,
 			})
 			continue // Don't process as regular message
 		}
 
-		// Process the player message
-		if msg.Message == "" {
-			continue // Skip empty messages
+		// Chat messages are broadcast banter: never scored, saved as arguments, or applied to HP
+		if msg.Type == "chat" {
+			chatTimes = s.handleChatMessage(logCtx, client, session, playerID, msg, chatTimes)
+			continue
+		}
+
+		// Reactions are lightweight emoji for every viewer, kept apart from scored arguments
+		if msg.Type == "reaction" {
+			reactionTimes = s.handleReaction(logCtx, client, session, playerID, msg, reactionTimes)
+			continue
+		}
+
+		// Spectators are read-only and cannot submit scored arguments
+		if role == conversation.ClientRoleSpectator {
+			if msg.Message != "" {
+				client.WriteJSON(gin.H{
+					"type":    "error",
+					"message": "Spectators cannot submit arguments",
+				})
+				sendArgumentNack(client, msg.ClientMsgID, nackReasonSpectator)
+			}
+			continue
+		}
+
+		// Arguments only count while the debate runs, never while it's paused for an outage or after it ended
+		if msg.Message != "" && session.GetStatus() != types.DebateStatusActive {
+			client.WriteJSON(gin.H{
+				"type":    "error",
+				"message": "Arguments can only be submitted while the debate is running",
+			})
+			sendArgumentNack(client, msg.ClientMsgID, nackReasonNotActive)
+			continue
+		}
+
+		// Process the player message, rejecting empty, badly sized or spammy arguments before they're scored
+		msg.Message = strings.TrimSpace(msg.Message)
+		if reason, message := checkArgumentContent(msg.Message, session.Config); reason != "" {
+			if reason != nackReasonEmpty {
+				client.WriteJSON(gin.H{
+					"type":    "error",
+					"message": message,
+				})
+			}
+			sendArgumentNack(client, msg.ClientMsgID, reason)
+			continue
 		}
 
 		// Set username if provided with the message
@@ -706,6 +1372,41 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 			session.SetUserName(playerID, msg.Username)
 		}
 
+		// Validate the side before scoring, so a misattributed argument never touches HP
+		side, validSide := normalizeSide(msg.Side, session.Agent1.GetName(), session.Agent2.GetName())
+		if !validSide {
+			client.WriteJSON(gin.H{
+				"type":    "error",
+				"message": fmt.Sprintf("Invalid side '%s': must be %s, %s or %s", msg.Side, sideAgent1, sideAgent2, sideNeutral),
+			})
+			sendArgumentNack(client, msg.ClientMsgID, nackReasonSide)
+			continue
+		}
+
+		// A reply must answer an argument or agent turn of this debate; the judge scores it against that point
+		parent := database.ArgumentParent{ArgumentID: msg.ReplyTo, MessageID: msg.ReplyToMessage}
+		parentContent, err := s.db.GetReplyParent(debateID, parent)
+		if err != nil {
+			logging.InfoCtx(logCtx, "Rejecting reply to an unknown argument or agent turn", map[string]interface{}{
+				"reply_to":         msg.ReplyTo,
+				"reply_to_message": msg.ReplyToMessage,
+				"error":            err.Error(),
+			})
+			client.WriteJSON(gin.H{
+				"type":    "error",
+				"message": "Replies must answer one argument or agent turn of this debate",
+			})
+			sendArgumentNack(client, msg.ClientMsgID, nackReasonReply)
+			continue
+		}
+
+		// Drop an argument the player just sent, e.g. on a double-click, so it isn't scored or applied to HP twice
+		if s.submissions.isDuplicate(debateID, playerID, msg.Message, s.config.GetDuplicateSubmissionWindow(), time.Now()) {
+			logging.InfoCtx(logCtx, "Ignoring duplicate player argument", nil)
+			sendArgumentNack(client, msg.ClientMsgID, nackReasonDuplicate)
+			continue
+		}
+
 		// Get the display name for this player
 		displayName := session.GetUserName(playerID)
 
@@ -713,7 +1414,8 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 		session.HandlePlayerInterruption(displayName, msg.Message)
 
 		// 2. Score the argument
-		score, err := s.scorer.ScoreArgument(context.Background(), msg.Message, session.Config.Topic)
+		score, err := s.scorer.ScoreReplyInStyle(usage.WithRecorder(context.Background(), session), msg.Message, parentContent, session.Config.Topic, session.Config.Language, session.Config.ResponseStyle)
+		s.debateManager.recordLLMResult(err)
 		if err != nil {
 			log.Printf("Error scoring player argument in debate %s: %v", debateID, err)
 			// Create a default score
@@ -728,8 +1430,13 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 			}
 		}
 
-		// 3. Save argument to database with debate ID - use displayName for storage
-		argumentID, err := s.db.SaveArgument(displayName, session.Config.Topic, msg.Message, msg.Side, debateID)
+		// 3. Save argument to database with debate ID - use displayName for storage, and the user ID when
+		// authenticated so the player can be notified when the debate ends
+		argumentUserID := ""
+		if authenticated {
+			argumentUserID = playerID
+		}
+		argumentID, err := s.db.SaveArgument(displayName, argumentUserID, session.Config.Topic, msg.Message, side, debateID, parent)
 		if err != nil {
 			log.Printf("Error saving player argument to database: %v", err)
 		} else {
@@ -738,27 +1445,18 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 			if err != nil {
 				log.Printf("Error saving argument score to database: %v", err)
 			}
+			s.invalidateLeaderboard(debateID)
 		}
 
 		// 4. Update game score based on player message using comparative performance
-		// Calculate player's average score (same scale as agents: 1-10)
-		playerAverageScore := float64(score.Strength+score.Relevance+score.Logic+score.Truth+score.Humor) / 5.0
-
-		// Determine which side the player is supporting first
-		var supportedAgent, opposedAgent string
-
-		// Check if side matches agent names directly (frontend sends agent names)
-		if msg.Side == "agent1" || msg.Side == session.Agent1.GetName() || strings.Contains(strings.ToLower(msg.Message), strings.ToLower(session.Agent1.GetName())) {
-			supportedAgent = session.Agent1.GetName()
-			opposedAgent = session.Agent2.GetName()
-		} else if msg.Side == "agent2" || msg.Side == session.Agent2.GetName() || strings.Contains(strings.ToLower(msg.Message), strings.ToLower(session.Agent2.GetName())) {
-			supportedAgent = session.Agent2.GetName()
-			opposedAgent = session.Agent1.GetName()
-		} else {
-			// No clear side - no HP changes for neutral comments
-			supportedAgent = ""
-			opposedAgent = ""
-		}
+		// Player's average score (same scale and style weighting as agents: 1-10)
+		playerAverageScore := score.Average
+
+		// Determine which side the player is supporting, only from the validated side field.
+		// Neutral comments get no HP changes.
+		supportedAgent, opposedAgent := sideAgents(side, session.Agent1.GetName(), session.Agent2.GetName())
+		session.SetClientSide(ws, supportedAgent)
+		session.RecordChallenge(opposedAgent)
 
 		log.Printf("Player side assignment - msg.Side: '%s', Agent1: '%s', Agent2: '%s', Supported: '%s', Opposed: '%s'",
 			msg.Side, session.Agent1.GetName(), session.Agent2.GetName(), supportedAgent, opposedAgent)
@@ -767,7 +1465,19 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 
 		if supportedAgent != "" && opposedAgent != "" {
 			// Direct scoring: player's score points go to supported agent, deducted from opposed agent
-			scorePoints := int(playerAverageScore) // Convert 0-10 score to integer points
+			opposedScore := session.GetLastAgentScore(opposedAgent)
+			scorePoints, noDamageReason := session.Config.HPTuning.PlayerPoints(playerAverageScore, opposedScore) // Convert 0-100 score to integer points
+			// Scale by the crowd behind the supported agent, if the debate opted in
+			scorePoints = session.CrowdAdjustedPoints(scorePoints, supportedAgent)
+			if noDamageReason == conversation.NoDamageBelowMinScore || noDamageReason == conversation.NoDamageDeadband {
+				logging.InfoCtx(logCtx, "Player argument dealt no damage", map[string]interface{}{
+					"player":        displayName,
+					"score":         playerAverageScore,
+					"opposed_agent": opposedAgent,
+					"opposed_score": opposedScore,
+					"reason":        noDamageReason,
+				})
+			}
 
 			// Apply score: supported agent gets +points, opposed agent gets -points
 			if supportedAgent == session.Agent1.GetName() {
@@ -788,8 +1498,27 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 
 		gameScore := session.UpdateGameScore(agent1Delta, agent2Delta)
 
-		// 5. Broadcast the player message with score
-		session.Broadcast(gin.H{
+		// Persist the argument, with the HP it moved, for replays
+		argumentScore := score.Average
+		messageID := s.recordDebateMessage(&database.DebateMessage{
+			DebateID:    debateID,
+			Speaker:     displayName,
+			Message:     msg.Message,
+			IsPlayer:    true,
+			Score:       &argumentScore,
+			Agent1Delta: agent1Delta,
+			Agent2Delta: agent2Delta,
+		})
+
+		// Confirm to the sender before the broadcast, so the ack can't arrive after their own message
+		if err := sendArgumentAck(client, msg.ClientMsgID, argumentID, score); err != nil {
+			logging.ErrorCtx(logCtx, "Failed to send argument ack", map[string]interface{}{
+				"error": err,
+			})
+		}
+
+		// 5. Broadcast the player message with score, and what it replies to so clients can thread it
+		playerMessage := gin.H{
 			"type":     "message",
 			"agent":    displayName, // Show full player ID
 			"content":  msg.Message,
@@ -797,93 +1526,170 @@ func (s *Server) handleDebateWebSocket(c *gin.Context) {
 			"scores": gin.H{
 				"argument": score,
 			},
-		})
+		}
+		if argumentID != 0 {
+			playerMessage["argument_id"] = argumentID
+		}
+		if parent.ArgumentID != 0 {
+			playerMessage["reply_to"] = parent.ArgumentID
+		}
+		if parent.MessageID != 0 {
+			playerMessage["reply_to_message"] = parent.MessageID
+		}
+		seq := session.Broadcast(playerMessage)
+		session.RecordTurn(displayName, true, agent1Delta, agent2Delta, messageID, seq)
 
 		// 6. Broadcast updated game score
-		session.Broadcast(gin.H{
-			"type": "game_score",
-			"gameScore": gin.H{
-				session.Agent1.GetName(): s.debateManager.NormalizeScore(gameScore.Agent1Score),
-				session.Agent2.GetName(): s.debateManager.NormalizeScore(gameScore.Agent2Score),
-			},
-			"internalScore": gin.H{
-				session.Agent1.GetName(): gameScore.Agent1Score,
-				session.Agent2.GetName(): gameScore.Agent2Score,
-			},
-		})
+		session.Broadcast(s.debateManager.gameScoreMessage(session, gameScore))
 
 		// 7. Broadcast updated leaderboard
-		leaderboard, err := s.db.GetLeaderboard(debateID, 10)
-		if err != nil {
-			log.Printf("Error getting leaderboard for broadcast in debate %s: %v", debateID, err)
-		} else {
-			session.Broadcast(gin.H{
-				"type":        "leaderboard_update",
-				"debate_id":   debateID,
-				"leaderboard": leaderboard,
-			})
-		}
+		s.broadcastLeaderboard(debateID)
 
-		// 8. Check for game over condition
-		if gameScore.Agent1Score <= 0 {
+		// 8. Check for game over condition (judged debates are decided by the judge instead)
+		judged := session.Config.IsJudged()
+		if !judged && gameScore.Agent1Score <= 0 {
 			winner := session.Agent2.GetName()
-			handleGameOver(s, session, debateID, winner)
-		} else if gameScore.Agent2Score <= 0 {
+			handleGameOver(s, session, debateID, winner, database.DebateEndReasonKnockout, nil)
+		} else if !judged && gameScore.Agent2Score <= 0 {
 			winner := session.Agent1.GetName()
-			handleGameOver(s, session, debateID, winner)
+			handleGameOver(s, session, debateID, winner, database.DebateEndReasonKnockout, nil)
 		}
 	}
 }
 
 // --- Refactored/Commented/Removed Methods ---
 
+// handleAudioStream serves cached audio with its actual content type. An optional ?format= (mp3, opus or aac)
+// transcodes it for browsers that can't play the original; the original is served when it's already in that
+// format. Only content types in the configured allowlist are served.
 func (s *Server) handleAudioStream(c *gin.Context) {
-	// This function remains largely the same, as audio caching might stay global for simplicity
 	audioID := c.Param("id")
 
-	s.cacheMutex.RLock()
+	formatName := strings.ToLower(c.Query("format"))
+	format, knownFormat := audioFormats[formatName]
+	if formatName != "" && !knownFormat {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported audio format '%s', use mp3, opus or aac", formatName)})
+		return
+	}
+
+	// Look up and bump the last access under one lock, so a sweep can't evict the audio in between
+	s.cacheMutex.Lock()
 	cache, exists := s.audioCache[audioID]
-	s.cacheMutex.RUnlock()
+	var variant []byte
+	var hasVariant bool
+	if exists {
+		cache.lastAccess = time.Now()
+		s.audioCache[audioID] = cache
+		variant, hasVariant = cache.variants[formatName]
+	}
+	s.cacheMutex.Unlock()
 
 	if !exists {
+		s.cacheStats.misses.Add(1)
 		c.JSON(http.StatusNotFound, gin.H{"error": "Audio not found"})
 		return
 	}
+	s.cacheStats.hits.Add(1)
+	defer func() { go s.cleanupCache() }()
 
-	c.Header("Content-Type", "audio/mpeg")
-	c.Header("Content-Length", fmt.Sprintf("%d", len(cache.data)))
-	c.Header("Cache-Control", "public, max-age=31536000")
+	contentType := cache.contentType
+	if contentType == "" {
+		contentType = detectAudioContentType(cache.data)
+	}
+	allowed := s.config.GetAllowedAudioTypes()
 
-	c.Data(http.StatusOK, "audio/mpeg", cache.data)
+	if !knownFormat || format.contentType == contentType {
+		if !audioTypeAllowed(allowed, contentType) {
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{
+				"error":        "Audio is not in an allowed format, request it with ?format=",
+				"content_type": contentType,
+			})
+			return
+		}
+		serveAudio(c, contentType, cache.data)
+		return
+	}
 
-	go s.cleanupCache()
+	if !audioTypeAllowed(allowed, format.contentType) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": fmt.Sprintf("Audio format '%s' is not allowed", formatName)})
+		return
+	}
+	if !hasVariant {
+		var err error
+		variant, err = s.transcodeCachedAudio(audioID, cache, formatName, format)
+		if err != nil {
+			logging.Error("Failed to transcode audio", map[string]interface{}{
+				"audio_id": audioID,
+				"format":   formatName,
+				"error":    err.Error(),
+			})
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Audio transcoding unavailable, request the original format", "details": err.Error()})
+			return
+		}
+	}
+	serveAudio(c, format.contentType, variant)
 }
 
+// audioSweepInterval limits how often cleanupCache actually sweeps, since it's triggered on every fetch and store
+const audioSweepInterval = time.Minute
+
+// cleanupCache evicts expired audio and HLS segments, at most once per audioSweepInterval.
+// It only takes cacheMutex itself and never calls back into code that locks it, so it can't deadlock with fetches.
 func (s *Server) cleanupCache() {
+	now := time.Now()
+	last := s.cacheStats.lastSweep.Load()
+	if now.UnixNano()-last < int64(audioSweepInterval) || !s.cacheStats.lastSweep.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+
 	s.cacheMutex.Lock()
 	defer s.cacheMutex.Unlock()
 
-	threshold := time.Now().Add(-1 * time.Hour)
+	ttl := s.config.GetAudioCacheTTL()
+	threshold := now.Add(-ttl)
 	for id, cache := range s.audioCache {
-		if cache.timestamp.Before(threshold) {
+		if cache.expired(now, ttl) {
 			delete(s.audioCache, id)
 		}
 	}
+
+	// Expire HLS segments on the same schedule
+	if s.hlsDir != "" {
+		cleanupHLSSegments(s.hlsDir, threshold)
+	}
 }
 
-// CacheAudio stores audio data in the cache and returns the URL to access it
+// CacheAudio stores audio and returns the URL to access it.
+// Longer clips are segmented into an HLS playlist for progressive playback when ffmpeg is available;
+// otherwise the audio is kept in the in-memory cache and served whole.
 func (s *Server) CacheAudio(audioData []byte) string {
-	s.cacheMutex.Lock()
-	defer s.cacheMutex.Unlock()
-
 	// Generate unique ID for the audio
 	audioID := uuid.New().String()
 
+	// Expire old audio, since HLS playback never goes through handleAudioStream
+	go s.cleanupCache()
+
+	if s.hlsDir != "" && len(audioData) >= hlsMinAudioBytes {
+		playlistURL, err := segmentAudioHLS(s.hlsDir, audioID, audioData)
+		if err == nil {
+			return playlistURL
+		}
+		logging.Debug("HLS segmentation unavailable, serving audio as a blob", map[string]interface{}{
+			"audio_id": audioID,
+			"error":    err.Error(),
+		})
+	}
+
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
 	// Store in cache
 	s.audioCache[audioID] = audioCache{
-		data:      audioData,
-		timestamp: time.Now(),
+		data:        audioData,
+		timestamp:   time.Now(),
+		contentType: detectAudioContentType(audioData),
 	}
+	s.cacheStats.stores.Add(1)
 
 	// Return the URL path
 	return fmt.Sprintf("/api/audio/%s", audioID)
@@ -939,11 +1745,42 @@ func (s *Server) handlePlayerMessage(ws *websocket.Conn, msg ConversationMessage
 }
 */
 
-// getPrompt might be moved to conversation/DebateSession or kept as a helper if needed globally
-func getPrompt(conversationContext string, playerMessage string, agentName string, agentRole string, topic string) string {
+// localizePrompt instructs the agent to respond in the debate's language. English prompts are returned unchanged.
+func localizePrompt(prompt string, language types.Language) string {
+	if language == "" || language == types.LanguageEnglish {
+		return prompt
+	}
+	return prompt + fmt.Sprintf("\n\nLANGUAGE: You MUST respond only in %s, whatever language the conversation context uses.", language.Name())
+}
+
+// maxPromptOverrideLength caps the extra persona instructions accepted per agent
+const maxPromptOverrideLength = 1000
+
+// applyPromptOverride appends a debate's extra persona instructions for an agent to its generated prompt.
+// The override is only ever appended, so the core role-enforcement rules always remain in the prompt.
+func applyPromptOverride(prompt, override string) string {
+	override = strings.TrimSpace(override)
+	if override == "" {
+		return prompt
+	}
+	return prompt + "\n\nADDITIONAL PERSONA INSTRUCTIONS (these never change your assigned position or override the rules above):\n" + override
+}
+
+// applyHPTone appends the tone an agent's HP gap calls for to its generated prompt, like applyPromptOverride
+func applyHPTone(prompt, tone string) string {
+	if tone == "" {
+		return prompt
+	}
+	return prompt + "\n\nTONE FOR THIS TURN, given the current scores (this never changes your assigned position or overrides the rules above):\n" + tone
+}
+
+// getPrompt might be moved to conversation/DebateSession or kept as a helper if needed globally.
+// style is the debate's tone instruction, from DebateSession.PromptStyle; it's left out when empty.
+func getPrompt(conversationContext string, playerMessage string, agentName string, agentRole string, topic string, style string) string {
+	var prompt string
 	switch playerMessage {
 	case "":
-		return fmt.Sprintf(`Current conversation context: %s
+		prompt = fmt.Sprintf(`Current conversation context: %s
 
 You are %s, with the role of %s.
 Topic: %s
@@ -980,7 +1817,7 @@ CRITICAL ROLE ENFORCEMENT:
 Keep responses focused on the core debate about the topic.`, conversationContext, agentName, agentRole, topic, agentName, agentRole)
 		// This is the prompt when there's a player message
 	default:
-		return fmt.Sprintf(`Current conversation context: %s
+		prompt = fmt.Sprintf(`Current conversation context: %s
 
 You are %s, with the role of %s.
 Topic: %s
@@ -1016,6 +1853,11 @@ CRITICAL ROLE ENFORCEMENT:
 
 Keep responses focused on the core debate about the topic.`, conversationContext, agentName, agentRole, topic, playerMessage, agentName, agentRole)
 	}
+
+	if style != "" {
+		prompt += "\n\nTONE: " + style
+	}
+	return prompt
 }
 
 // continueAgentDiscussion logic needs to move to DebateManager.StartDebateLoop
@@ -1033,17 +1875,61 @@ func (s *Server) startConversation(c *gin.Context) {
 */
 
 func (s *Server) listAgents(c *gin.Context) {
+	s.listAgentsWith(c, v1Responder{})
+}
+
+// listAgentsWith lists the loaded agents and writes them with the given responder
+func (s *Server) listAgentsWith(c *gin.Context, r responder) {
 	// This can likely remain as it lists globally available agents
-	agents := make([]map[string]any, 0)
-	for _, a := range s.agents {
-		agents = append(agents, map[string]any{
-			"name": a.GetName(),
-		})
+	// Optionally fold in each agent's win-rate record
+	var statsByAgent map[string]*database.AgentWinRate
+	if c.Query("include_stats") == "true" {
+		rates, err := s.db.GetAgentWinRates()
+		if err != nil {
+			r.Error(c, http.StatusInternalServerError, "Failed to get agent win rates", err)
+			return
+		}
+		statsByAgent = make(map[string]*database.AgentWinRate, len(rates))
+		for _, rate := range rates {
+			statsByAgent[rate.Agent] = rate
+		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"agents": agents,
-	})
+	agents := make([]agentListEntry, 0)
+	for _, a := range s.agents.List() {
+		entry := agentListEntry{AgentProfile: a.GetProfile()}
+		if statsByAgent != nil {
+			if rate, exists := statsByAgent[a.GetName()]; exists {
+				entry.Stats = rate
+			} else {
+				entry.Stats = &database.AgentWinRate{Agent: a.GetName()}
+			}
+		}
+		agents = append(agents, entry)
+	}
+
+	r.List(c, "agents", agents, nil, nil)
+}
+
+// agentListEntry is an agent's profile as listed by listAgents, with its win-rate record when requested
+type agentListEntry struct {
+	agent.AgentProfile
+	Stats *database.AgentWinRate `json:"stats,omitempty"`
+}
+
+func (s *Server) getAgentHandler(c *gin.Context) {
+	s.getAgentWith(c, v1Responder{})
+}
+
+// getAgentWith writes a single agent's profile, e.g. for the create-debate agent cards
+func (s *Server) getAgentWith(c *gin.Context, r responder) {
+	name := c.Param("name")
+	a, exists := s.agents.Get(name)
+	if !exists {
+		r.Error(c, http.StatusNotFound, fmt.Sprintf("Agent %s not found", name), nil)
+		return
+	}
+	r.Item(c, "agent", a.GetProfile(), nil)
 }
 
 func (s *Server) getArguments(c *gin.Context) {
@@ -1057,7 +1943,7 @@ func (s *Server) getArguments(c *gin.Context) {
 
 func (s *Server) getArgument(c *gin.Context) {
 	// This might need modification later if arguments are strictly tied to debates
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	id, err := strconv.ParseInt(c.Param("argumentID"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid argument ID"})
 		return
@@ -1121,7 +2007,7 @@ func (s *Server) runHTTPS(addr string) error {
 // GetOrderedAgentNames returns agent names in a consistent order
 func (s *Server) GetOrderedAgentNames() (agent1Name, agent2Name string) {
 	// First look for Tiger Agent, then Bear Agent
-	for name, agent := range s.agents {
+	for name, agent := range s.agents.List() {
 		switch agent.GetName() {
 		case TIGER_AGENT:
 			agent1Name = name
@@ -1139,28 +2025,115 @@ func (s *Server) GetOrderedAgentNames() (agent1Name, agent2Name string) {
 }
 
 // handleGameOver handles the game over condition for a debate
-func handleGameOver(s *Server, session *conversation.DebateSession, debateID string, winner string) {
-	log.Printf("Game over in debate %s. Winner: %s", debateID, winner)
+// Any extra fields are merged into the game_over broadcast.
+// It reports false, and announces nothing, if the debate had already finished.
+func handleGameOver(s *Server, session *conversation.DebateSession, debateID, winner, reason string, extra gin.H) bool {
+	if !recordDebateEnd(s, session, debateID, winner, reason) {
+		return false
+	}
+	log.Printf("Game over in debate %s. Winner: %s, reason: %s", debateID, winner, reason)
+
+	// Broadcast game over message
+	gameOverMsg := gin.H{
+		"type":       "game_over",
+		"winner":     winner,
+		"end_reason": reason,
+		"message":    fmt.Sprintf("Game over! %s has won the debate!", winner),
+		"replay_url": replayURL(debateID),
+	}
+	if winner == "" {
+		gameOverMsg["message"] = "Game over! The debate ended in a draw."
+		gameOverMsg["draw"] = true
+	}
+	for k, v := range extra {
+		gameOverMsg[k] = v
+	}
+	session.Broadcast(gameOverMsg)
+	return true
+}
 
+// recordDebateEnd marks a debate as finished in memory, stops its loop and persists its result and active time.
+// winner is empty if the debate ended without one. Only the first of several callers ending a debate at once,
+// e.g. a knockout racing a moderator, records and announces the end; the rest get false and must do nothing.
+func recordDebateEnd(s *Server, session *conversation.DebateSession, debateID, winner, reason string) bool {
 	// Update status in memory
-	session.UpdateStatus("finished")
+	if !session.Finish() {
+		return false
+	}
 
 	// Update database
-	err := s.db.UpdateDebateEnd(debateID, "finished", winner)
+	err := s.db.UpdateDebateEnd(debateID, types.DebateStatusFinished, winner, reason)
 	if err != nil {
 		log.Printf("Error updating debate end in database: %v", err)
 	}
 
-	// Broadcast game over message
-	session.Broadcast(gin.H{
-		"type":    "game_over",
-		"winner":  winner,
-		"message": fmt.Sprintf("Game over! %s has won the debate!", winner),
+	// Record the time actually spent debating, excluding pauses
+	activeSeconds := int64(session.GetActiveDuration().Seconds())
+	if err := s.db.UpdateDebateActiveTime(debateID, activeSeconds); err != nil {
+		log.Printf("Error updating debate active time in database: %v", err)
+	}
+
+	// Persist the LLM and TTS usage for cost reporting
+	if err := s.db.SaveDebateUsage(debateID, session.GetUsage()); err != nil {
+		log.Printf("Error saving debate usage in database: %v", err)
+	}
+
+	s.notifyDebateEnded(debateID, session.Config.Topic, winner, reason)
+	return true
+}
+
+// hpLeader returns the agent with more HP, or nil if both have the same HP
+func hpLeader(session *conversation.DebateSession) *agent.Agent {
+	gameScore := session.GetGameScore()
+	switch {
+	case gameScore.Agent1Score > gameScore.Agent2Score:
+		return session.Agent1
+	case gameScore.Agent2Score > gameScore.Agent1Score:
+		return session.Agent2
+	default:
+		return nil
+	}
+}
+
+// handleHPLeaderGameOver ends a debate that was cut short without a knockout, e.g. at MaxTurns.
+// The agent with more HP wins; equal HP is a draw.
+func handleHPLeaderGameOver(s *Server, session *conversation.DebateSession, debateID, reason string) {
+	winner := ""
+	if winningAgent := hpLeader(session); winningAgent != nil {
+		winner = winningAgent.GetName()
+	}
+	handleGameOver(s, session, debateID, winner, reason, gin.H{
+		"decided_by": conversation.WinConditionHP,
+	})
+}
+
+// handleJudgedGameOver ends a judged debate with the conviction judge's verdict.
+// If the judge is unavailable, the agent with more HP wins instead, and equal HP is a draw.
+func handleJudgedGameOver(ctx context.Context, s *Server, session *conversation.DebateSession, debateID string) {
+	winner, metrics, err := session.JudgeWinner(ctx)
+	if err != nil {
+		log.Printf("Judge failed to decide debate %s, falling back to HP: %v", debateID, err)
+		handleHPLeaderGameOver(s, session, debateID, database.DebateEndReasonMaxTurns)
+		return
+	}
+
+	handleGameOver(s, session, debateID, winner, database.DebateEndReasonJudge, gin.H{
+		"decided_by":       conversation.WinConditionJudge,
+		"analysis_summary": metrics.AnalysisSummary,
+		"conviction": gin.H{
+			session.Agent1.GetName(): metrics.Agent1Score,
+			session.Agent2.GetName(): metrics.Agent2Score,
+		},
 	})
 }
 
 // listTopicsHandler returns a list of all available pre-generated topics with pagination and filtering
 func (s *Server) listTopicsHandler(c *gin.Context) {
+	s.listTopicsWith(c, v1Responder{})
+}
+
+// listTopicsWith lists topics and writes them with the given responder
+func (s *Server) listTopicsWith(c *gin.Context, r responder) {
 	// Get pagination parameters
 	paginationParams := GetPaginationParams(c)
 
@@ -1180,7 +2153,7 @@ func (s *Server) listTopicsHandler(c *gin.Context) {
 	// Get topics with pagination and filtering
 	topics, total, err := s.db.GetTopics(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get topics: %v", err)})
+		r.InlineError(c, http.StatusInternalServerError, "Failed to get topics", err)
 		return
 	}
 
@@ -1188,7 +2161,7 @@ func (s *Server) listTopicsHandler(c *gin.Context) {
 	paginationParams.Total = total
 
 	// Send paginated response
-	SendPaginatedResponse(c, paginationParams, topics)
+	r.List(c, "topics", projectFields(c, topics, topicListFields), nil, &paginationParams)
 }
 
 // listTopicsByCategoryHandler returns topics filtered by category with pagination
@@ -1229,29 +2202,34 @@ func (s *Server) listTopicsByCategoryHandler(c *gin.Context) {
 	paginationParams.Total = total
 
 	// Send paginated response with category info
-	response := BuildPaginationResponse(c, paginationParams, topics)
+	response := BuildPaginationResponse(c, paginationParams, projectFields(c, topics, topicListFields))
 	response["category"] = category
 	c.JSON(http.StatusOK, response)
 }
 
 // getTopicHandler returns details for a specific topic
 func (s *Server) getTopicHandler(c *gin.Context) {
+	s.getTopicWith(c, v1Responder{})
+}
+
+// getTopicWith loads a topic and writes it with the given responder
+func (s *Server) getTopicWith(c *gin.Context, r responder) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid topic ID"})
+		r.Error(c, http.StatusBadRequest, "Invalid topic ID", nil)
 		return
 	}
 
 	topic, err := s.db.GetTopic(id)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Topic with ID %d not found", id)})
+			r.Error(c, http.StatusNotFound, fmt.Sprintf("Topic with ID %d not found", id), nil)
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get topic: %v", err)})
+			r.InlineError(c, http.StatusInternalServerError, "Failed to get topic", err)
 		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"topic": topic})
+	r.Item(c, "topic", topic, nil)
 }
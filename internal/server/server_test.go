@@ -1,13 +1,27 @@
 package server
 
 import (
+	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/conversation"
 	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/scoring"
+	"github.com/neo/convinceme_backend/internal/types"
+	"github.com/neo/convinceme_backend/internal/usage"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestHealthRoute tests the health route
@@ -40,6 +54,65 @@ func TestHealthRoute(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "ok")
 }
 
+// TestNewServerRoutes builds the full router, since gin panics at registration when two routes name the same
+// path segment's wildcard differently
+func TestNewServerRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Migrations are read relative to the working directory, as when the server runs from the repository root
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(filepath.Join("..", "..")))
+	defer os.Chdir(wd)
+
+	db, err := database.New(t.TempDir())
+	require.NoError(t, err)
+	defer db.Close()
+
+	var server *Server
+	require.NotPanics(t, func() {
+		server = NewServer(map[string]*agent.Agent{}, db, "", false, &Config{JWTSecret: "test-secret", OfflineMode: true})
+	})
+
+	// Argument routes share one wildcard name across the public and protected groups
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/arguments/not-a-number", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/arguments/1/explain", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+// TestNewServerTrustedProxies tests that X-Forwarded-For only sets the client IP used for per-IP rate limits when
+// the request came through a configured proxy
+func TestNewServerTrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(filepath.Join("..", "..")))
+	defer os.Chdir(wd)
+
+	db, err := database.New(t.TempDir())
+	require.NoError(t, err)
+	defer db.Close()
+
+	clientIP := func(trustedProxies []string) string {
+		server := NewServer(map[string]*agent.Agent{}, db, "", false, &Config{JWTSecret: "test-secret", OfflineMode: true, TrustedProxies: trustedProxies})
+		server.router.GET("/test/client-ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+		req := httptest.NewRequest(http.MethodGet, "/test/client-ip", nil)
+		req.RemoteAddr = "203.0.113.5:40000"
+		req.Header.Set("X-Forwarded-For", "198.51.100.7")
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	assert.Equal(t, "203.0.113.5", clientIP(nil), "a forwarded IP from an untrusted peer is ignored")
+	assert.Equal(t, "198.51.100.7", clientIP([]string{"203.0.113.0/24"}))
+	assert.Equal(t, "203.0.113.5", clientIP([]string{"not-an-ip"}), "invalid proxies trust none")
+}
+
 // Skip the TestGetTopicHandler test for now
 // We'll need to implement a proper mock for the database
 
@@ -52,3 +125,600 @@ type MockDB struct {
 func (m *MockDB) GetTopic(id int) (*database.Topic, error) {
 	return m.topicToReturn, nil
 }
+
+func TestLocalizePrompt(t *testing.T) {
+	prompt := "Make your argument."
+
+	// English prompts are left untouched so existing behavior is unchanged
+	assert.Equal(t, prompt, localizePrompt(prompt, types.LanguageEnglish))
+	assert.Equal(t, prompt, localizePrompt(prompt, ""))
+
+	localized := localizePrompt(prompt, types.LanguageSpanish)
+	assert.Contains(t, localized, prompt)
+	assert.Contains(t, localized, "respond only in Spanish")
+}
+
+func TestApplyPromptOverride(t *testing.T) {
+	prompt := getPrompt("", "", "Agent 1", "Debate Participant", "Test Topic", "")
+
+	// No override leaves the generated prompt untouched
+	assert.Equal(t, prompt, applyPromptOverride(prompt, ""))
+	assert.Equal(t, prompt, applyPromptOverride(prompt, "   "))
+
+	// Overrides are appended after the role-enforcement rules rather than replacing them
+	overridden := applyPromptOverride(prompt, "Speak like a pirate.")
+	assert.True(t, strings.HasPrefix(overridden, prompt))
+	assert.Contains(t, overridden, "CRITICAL ROLE ENFORCEMENT")
+	assert.True(t, strings.HasSuffix(overridden, "Speak like a pirate."))
+}
+
+// TestNormalizeSide tests that sides are limited to the two agents or neutral
+func TestNormalizeSide(t *testing.T) {
+	const agent1, agent2 = "'La Pulga Protector' Pepito", "'Siuuuu Sensei' Sergio"
+
+	tests := []struct {
+		side      string
+		want      string
+		supported string
+		valid     bool
+	}{
+		{side: "agent1", want: sideAgent1, supported: agent1, valid: true},
+		{side: " Agent2 ", want: sideAgent2, supported: agent2, valid: true},
+		{side: agent2, want: sideAgent2, supported: agent2, valid: true},
+		{side: "", want: sideNeutral, valid: true},
+		{side: "neutral", want: sideNeutral, valid: true},
+		{side: "Sergio", valid: false}, // Part of a name is not enough
+		{side: "pro", valid: false},
+	}
+	for _, tt := range tests {
+		side, valid := normalizeSide(tt.side, agent1, agent2)
+		assert.Equal(t, tt.valid, valid, "side %q", tt.side)
+		if !valid {
+			continue
+		}
+		assert.Equal(t, tt.want, side, "side %q", tt.side)
+		supported, _ := sideAgents(side, agent1, agent2)
+		assert.Equal(t, tt.supported, supported, "side %q", tt.side)
+	}
+}
+
+// TestSideIgnoresAgentNamesInMessage tests that the supported agent comes from the side, not the message text
+func TestSideIgnoresAgentNamesInMessage(t *testing.T) {
+	const agent1, agent2 = "'La Pulga Protector' Pepito", "'Siuuuu Sensei' Sergio"
+
+	// A player backing agent1 who names the opponent in passing still supports agent1
+	msg := ConversationMessage{
+		Message: "Unlike " + agent2 + ", Pepito actually has a point here",
+		Side:    "agent1",
+	}
+	side, valid := normalizeSide(msg.Side, agent1, agent2)
+	require.True(t, valid)
+	supported, opposed := sideAgents(side, agent1, agent2)
+	assert.Equal(t, agent1, supported)
+	assert.Equal(t, agent2, opposed)
+
+	// Without a side, mentioning an agent doesn't pick one
+	msg = ConversationMessage{Message: "I think " + agent1 + " is wrong"}
+	side, valid = normalizeSide(msg.Side, agent1, agent2)
+	require.True(t, valid)
+	supported, opposed = sideAgents(side, agent1, agent2)
+	assert.Empty(t, supported)
+	assert.Empty(t, opposed)
+}
+
+// TestSendArgumentAck tests that acks and nacks echo the client message ID and are skipped without one
+func TestSendArgumentAck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		score := &scoring.ArgumentScore{Average: 7.5}
+		sendArgumentAck(ws, "", 41, score) // No client_msg_id: nothing is sent
+		sendArgumentAck(ws, "msg-1", 42, score)
+		sendArgumentNack(ws, "", nackReasonEmpty)
+		sendArgumentNack(ws, "msg-2", nackReasonEmpty)
+	}))
+	defer srv.Close()
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	var ack map[string]interface{}
+	require.NoError(t, client.ReadJSON(&ack))
+	assert.Equal(t, "ack", ack["type"])
+	assert.Equal(t, "msg-1", ack["client_msg_id"])
+	assert.Equal(t, float64(42), ack["argument_id"])
+
+	var nack map[string]interface{}
+	require.NoError(t, client.ReadJSON(&nack))
+	assert.Equal(t, "nack", nack["type"])
+	assert.Equal(t, "msg-2", nack["client_msg_id"])
+	assert.Equal(t, nackReasonEmpty, nack["reason"])
+}
+
+// TestBroadcastDropsSlowClient tests that a client that stops reading is dropped once its queue fills up,
+// without holding up broadcasts to the other clients
+func TestBroadcastDropsSlowClient(t *testing.T) {
+	session := &conversation.DebateSession{
+		DebateID:    "slow-client",
+		Clients:     make(map[*websocket.Conn]string),
+		ClientRoles: make(map[*websocket.Conn]string),
+	}
+	joined := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		player := r.URL.Query().Get("player")
+		if player == "slow" {
+			// Small socket buffers make the slow client's writer block after a few messages
+			ws.UnderlyingConn().(*net.TCPConn).SetWriteBuffer(4096)
+		}
+		session.AddClient(ws, player)
+		joined <- struct{}{}
+	}))
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	slowDialer := websocket.Dialer{NetDial: func(network, addr string) (net.Conn, error) {
+		conn, err := net.Dial(network, addr)
+		if err == nil {
+			conn.(*net.TCPConn).SetReadBuffer(4096)
+		}
+		return conn, err
+	}}
+	slow, _, err := slowDialer.Dial(url+"?player=slow", nil)
+	require.NoError(t, err)
+	defer slow.Close()
+	<-joined
+	fast, _, err := websocket.DefaultDialer.Dial(url+"?player=fast", nil)
+	require.NoError(t, err)
+	defer fast.Close()
+	<-joined
+
+	// The fast client reads each broadcast as it comes, while the slow one never reads
+	received := make(chan struct{})
+	go func() {
+		for {
+			if _, _, err := fast.ReadMessage(); err != nil {
+				return
+			}
+			received <- struct{}{}
+		}
+	}()
+
+	dropped := conversation.DroppedSlowClients()
+	payload := strings.Repeat("x", 4<<10)
+	for i := 0; i < 400; i++ {
+		session.Broadcast(gin.H{"type": "message", "content": payload})
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("the fast client didn't get broadcast %d", i+1)
+		}
+	}
+
+	assert.Equal(t, 1, session.GetPresence().Total, "the slow client was dropped")
+	assert.Equal(t, dropped+1, conversation.DroppedSlowClients())
+}
+
+// TestListDebatesLiveState tests that ?live=true attaches client counts and scores from in-memory sessions
+func TestListDebatesLiveState(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/debates", server.listDebatesHandler)
+
+	conn := &websocket.Conn{}
+	server.debateManager = &DebateManager{debates: map[string]*conversation.DebateSession{
+		"debate-1": {
+			DebateID:    "debate-1",
+			Clients:     map[*websocket.Conn]string{conn: "player-1"},
+			ClientRoles: map[*websocket.Conn]string{conn: conversation.ClientRoleParticipant},
+			GameScore:   conversation.GameScore{Agent1Score: 80, Agent2Score: 65},
+		},
+	}}
+
+	req, _ := http.NewRequest("GET", "/api/debates?status=active&live=true", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Debates []struct {
+			ID          string         `json:"id"`
+			ClientCount int            `json:"client_count"`
+			GameScore   map[string]int `json:"game_score"`
+		} `json:"debates"`
+		ClientCount int `json:"client_count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Debates, 2)
+	assert.Equal(t, 1, response.ClientCount)
+
+	assert.Equal(t, "debate-1", response.Debates[0].ID)
+	assert.Equal(t, 1, response.Debates[0].ClientCount)
+	assert.Equal(t, map[string]int{"Agent 1": 80, "Agent 2": 65}, response.Debates[0].GameScore)
+
+	// Not loaded in memory: no clients and no live score
+	assert.Equal(t, 0, response.Debates[1].ClientCount)
+	assert.Nil(t, response.Debates[1].GameScore)
+}
+
+// TestGetDebateBudget tests that a running debate's spend and budget are only shown to admins
+func TestGetDebateBudget(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.GET("/api/debates/:debateID", server.auth.OptionalAuthMiddleware(), server.getDebateHandler)
+
+	session := &conversation.DebateSession{DebateID: "debate-1", Status: "active", Config: conversation.DebateConfig{MaxCostUSD: 1}}
+	session.AddUsage(usage.Usage{CompletionTokens: 1000000})
+	server.debateManager = &DebateManager{debates: map[string]*conversation.DebateSession{"debate-1": session}}
+
+	adminToken, err := server.auth.GenerateToken(auth.User{ID: "admin-id", Username: "admin", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		token         string
+		expectsBudget bool
+	}{
+		{name: "Admin sees the budget", token: adminToken, expectsBudget: true},
+		{name: "Anonymous user does not", expectsBudget: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/debates/debate-1", nil)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			realTime := response["real_time"].(map[string]interface{})
+			budget, ok := realTime["budget"].(map[string]interface{})
+			require.Equal(t, tc.expectsBudget, ok)
+			if tc.expectsBudget {
+				assert.InDelta(t, 0.6, budget["cost_usd"], 1e-9)
+				assert.Equal(t, float64(1), budget["max_cost_usd"])
+			}
+		})
+	}
+}
+
+// TestBroadcastSeqAndReplay tests that broadcasts carry increasing seq numbers and can be replayed after a given seq
+func TestBroadcastSeqAndReplay(t *testing.T) {
+	serverConns := make(chan *websocket.Conn, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		serverConns <- ws
+	}))
+	defer srv.Close()
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer client.Close()
+	serverConn := <-serverConns
+	defer serverConn.Close()
+
+	session := &conversation.DebateSession{
+		DebateID: "debate-1",
+		Clients:  map[*websocket.Conn]string{serverConn: "player-1"},
+	}
+	for i := 1; i <= 3; i++ {
+		session.Broadcast(gin.H{"type": "game_score", "turn": i})
+	}
+	assert.Equal(t, uint64(3), session.LastSeq())
+
+	readSeq := func() float64 {
+		var msg map[string]interface{}
+		require.NoError(t, client.ReadJSON(&msg))
+		return msg["seq"].(float64)
+	}
+	for i := 1; i <= 3; i++ {
+		assert.Equal(t, float64(i), readSeq())
+	}
+
+	// A client that last saw seq 1 gets 2 and 3 again
+	replayed, complete, err := session.ReplaySince(serverConn, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, replayed)
+	assert.True(t, complete)
+	assert.Equal(t, float64(2), readSeq())
+	assert.Equal(t, float64(3), readSeq())
+
+	// Nothing to replay for an up-to-date client
+	replayed, complete, err = session.ReplaySince(serverConn, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 0, replayed)
+	assert.True(t, complete)
+}
+
+// TestWebSocketCloseCodes tests that rejected WebSocket connections are closed with an application close code
+func TestWebSocketCloseCodes(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+
+	full := &conversation.DebateSession{
+		DebateID:    "full-debate",
+		Status:      "active",
+		Config:      conversation.DebateConfig{MaxParticipants: 1},
+		Clients:     map[*websocket.Conn]string{nil: "player-1"},
+		ClientRoles: map[*websocket.Conn]string{nil: conversation.ClientRoleParticipant},
+	}
+	server.debateManager = &DebateManager{debates: map[string]*conversation.DebateSession{"full-debate": full}}
+	server.router.GET("/ws/debate/:debateID", server.handleDebateWebSocket)
+
+	srv := httptest.NewServer(server.router)
+	defer srv.Close()
+
+	tests := []struct {
+		debateID string
+		code     int
+	}{
+		{debateID: "unknown-debate", code: closeCodeDebateNotFound},
+		{debateID: "full-debate", code: closeCodeDebateFull},
+	}
+	for _, tt := range tests {
+		client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws/debate/"+tt.debateID, nil)
+		require.NoError(t, err)
+
+		_, _, err = client.ReadMessage()
+		client.Close()
+		var closeErr *websocket.CloseError
+		require.ErrorAs(t, err, &closeErr, tt.debateID)
+		assert.Equal(t, tt.code, closeErr.Code, tt.debateID)
+	}
+}
+
+// TestArgumentsRejectedUnlessActive tests that arguments sent to a paused or finished debate are nacked unscored
+func TestArgumentsRejectedUnlessActive(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+
+	newSession := func(id string, statuses ...types.DebateStatus) *conversation.DebateSession {
+		session, err := conversation.NewDebateSession(id, agent.NewOfflineAgent(agent.AgentConfig{Name: "Pepito"}),
+			agent.NewOfflineAgent(agent.AgentConfig{Name: "Tony"}), conversation.DebateConfig{Topic: "Messi vs Ronaldo"}, "")
+		require.NoError(t, err)
+		for _, status := range statuses {
+			require.True(t, session.UpdateStatus(status))
+		}
+		return session
+	}
+	server.debateManager = &DebateManager{debates: map[string]*conversation.DebateSession{
+		"paused-debate":   newSession("paused-debate", types.DebateStatusActive, types.DebateStatusPaused),
+		"finished-debate": newSession("finished-debate", types.DebateStatusActive, types.DebateStatusFinished),
+	}}
+	server.router.GET("/ws/debate/:debateID", server.handleDebateWebSocket)
+
+	srv := httptest.NewServer(server.router)
+	defer srv.Close()
+
+	for _, debateID := range []string{"paused-debate", "finished-debate"} {
+		client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws/debate/"+debateID, nil)
+		require.NoError(t, err)
+		require.NoError(t, client.WriteJSON(ConversationMessage{Message: "Messi has won more Ballon d'Ors", Side: sideAgent1, ClientMsgID: "msg-1"}))
+
+		client.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var reply map[string]interface{}
+		for reply["type"] != "nack" {
+			reply = nil
+			require.NoError(t, client.ReadJSON(&reply), debateID)
+			require.NotEqual(t, "ack", reply["type"], debateID)
+		}
+		assert.Equal(t, nackReasonNotActive, reply["reason"], debateID)
+		assert.Equal(t, "msg-1", reply["client_msg_id"], debateID)
+		client.Close()
+	}
+}
+
+// TestRestartDebateLoopHandler tests that admins can restart only the loop of an active debate that has none running
+func TestRestartDebateLoopHandler(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.POST("/api/debates/:debateID/restart-loop",
+		server.auth.AuthMiddleware(), server.auth.RequireRole(string(database.RoleAdmin)), server.restartDebateLoopHandler)
+
+	running := &conversation.DebateSession{DebateID: "running", Status: "active"}
+	require.True(t, running.TryStartLoop())
+	server.debateManager = &DebateManager{
+		db:     server.db,
+		server: server,
+		debates: map[string]*conversation.DebateSession{
+			"stalled":  {DebateID: "stalled", Status: "active"},
+			"running":  running,
+			"finished": {DebateID: "finished", Status: "finished"},
+		},
+	}
+
+	adminToken, err := server.auth.GenerateToken(auth.User{ID: "admin-id", Username: "admin", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+	userToken, err := server.auth.GenerateToken(auth.User{ID: "user-id", Username: "user", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		debateID string
+		token    string
+		status   int
+	}{
+		{debateID: "stalled", token: userToken, status: http.StatusForbidden},
+		{debateID: "unknown", token: adminToken, status: http.StatusNotFound},
+		{debateID: "running", token: adminToken, status: http.StatusConflict},
+		{debateID: "finished", token: adminToken, status: http.StatusConflict},
+		{debateID: "stalled", token: adminToken, status: http.StatusOK},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodPost, "/api/debates/"+tc.debateID+"/restart-loop", nil)
+		req.Header.Set("Authorization", "Bearer "+tc.token)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		assert.Equal(t, tc.status, w.Code, tc.debateID)
+	}
+}
+
+// endRecordingDB records the debate ends persisted by handlers
+type endRecordingDB struct {
+	*TestMockDB
+	ends []string
+}
+
+func (m *endRecordingDB) UpdateDebateEnd(id string, status types.DebateStatus, winner, reason string) error {
+	m.ends = append(m.ends, strings.Join([]string{id, string(status), winner, reason}, "|"))
+	return nil
+}
+
+// TestEndDebateHandler tests that moderators can end a debate once, with the HP leader winning by default
+func TestEndDebateHandler(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	db := &endRecordingDB{TestMockDB: &TestMockDB{}}
+	server.db = db
+	server.router.POST("/api/debates/:debateID/end",
+		server.auth.AuthMiddleware(), server.auth.RequireRole(string(database.RoleModerator)), server.endDebateHandler)
+
+	live, err := conversation.NewDebateSession("live", agent.NewOfflineAgent(agent.AgentConfig{Name: "Pepito"}),
+		agent.NewOfflineAgent(agent.AgentConfig{Name: "Tony"}), conversation.DebateConfig{Topic: "Messi vs Ronaldo"}, "")
+	require.NoError(t, err)
+	live.UpdateStatus("active")
+	live.UpdateGameScore(-20, 20)
+	server.debateManager = &DebateManager{
+		db:      server.db,
+		server:  server,
+		debates: map[string]*conversation.DebateSession{"live": live},
+	}
+
+	moderatorToken, err := server.auth.GenerateToken(auth.User{ID: "moderator-id", Username: "moderator", Role: string(database.RoleModerator)})
+	require.NoError(t, err)
+	userToken, err := server.auth.GenerateToken(auth.User{ID: "user-id", Username: "user", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	end := func(debateID, token, body string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, "/api/debates/"+debateID+"/end", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	code, _ := end("live", userToken, "")
+	assert.Equal(t, http.StatusForbidden, code)
+	code, _ = end("live", moderatorToken, `{"winner":"Nobody"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = end("missing-debate", moderatorToken, "")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, types.DebateStatusActive, live.GetStatus())
+
+	code, response := end("live", moderatorToken, `{"reason":"Stuck repeating itself"}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "Tony", response["winner"], "the HP leader wins by default")
+	assert.Equal(t, types.DebateStatusFinished, live.GetStatus())
+	assert.False(t, live.UpdateStatus(types.DebateStatusActive), "a finished debate can't be restarted")
+	assert.Equal(t, types.DebateStatusFinished, live.GetStatus())
+	select {
+	case <-live.GetStopChannel():
+	default:
+		t.Fatal("ending the debate should signal its loop to stop")
+	}
+
+	// Ending it again is a no-op
+	code, response = end("live", moderatorToken, `{"winner":"Pepito"}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, response["already_finished"])
+
+	// Debates without a session are ended in the database only
+	code, response = end("finished-debate", moderatorToken, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, response["already_finished"])
+	code, response = end("stuck-debate", moderatorToken, `{"winner":"draw"}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, response["draw"])
+
+	assert.Equal(t, []string{
+		"live|finished|Tony|" + database.DebateEndReasonAdmin,
+		"stuck-debate|finished||" + database.DebateEndReasonAdmin,
+	}, db.ends)
+}
+
+// privateDebateDB is a TestMockDB whose debates are all private, so only their creator and test-user-id may see them
+type privateDebateDB struct {
+	*TestMockDB
+}
+
+func (m *privateDebateDB) GetDebate(id string) (*database.Debate, error) {
+	debate, err := m.TestMockDB.GetDebate(id)
+	if err != nil {
+		return nil, err
+	}
+	debate.Visibility = database.DebateVisibilityPrivate
+	return debate, nil
+}
+
+func TestGetChatHandlerPrivateDebate(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.db = &privateDebateDB{TestMockDB: &TestMockDB{}}
+	server.router.GET("/api/debates/:debateID/chat", server.auth.OptionalAuthMiddleware(), server.getChatHandler)
+
+	invitedToken, err := server.auth.GenerateToken(auth.User{ID: "test-user-id", Username: "testuser", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+	strangerToken, err := server.auth.GenerateToken(auth.User{ID: "stranger-id", Username: "stranger", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	chat := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/debates/private/chat", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, chat(""))
+	assert.Equal(t, http.StatusForbidden, chat(strangerToken))
+	assert.Equal(t, http.StatusOK, chat(invitedToken))
+}
+
+func TestGetDebatePrivateDebate(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.db = &privateDebateDB{TestMockDB: &TestMockDB{}}
+	server.debateManager = &DebateManager{debates: map[string]*conversation.DebateSession{}}
+	server.router.GET("/api/debates/:debateID", server.auth.OptionalAuthMiddleware(), server.getDebateHandler)
+
+	invitedToken, err := server.auth.GenerateToken(auth.User{ID: "test-user-id", Username: "testuser", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+	strangerToken, err := server.auth.GenerateToken(auth.User{ID: "stranger-id", Username: "stranger", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	get := func(debateID, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/debates/"+debateID, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, get("private", "").Code)
+	w := get("private", strangerToken)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NotContains(t, w.Body.String(), "topic", "a non-invitee learns nothing about the debate")
+	assert.Equal(t, http.StatusOK, get("private", invitedToken).Code)
+	assert.Equal(t, http.StatusNotFound, get("missing-debate", invitedToken).Code)
+}
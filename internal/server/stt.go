@@ -0,0 +1,21 @@
+package server
+
+import (
+	"time"
+
+	"github.com/neo/convinceme_backend/internal/audio"
+)
+
+// sttRateLimit caps speech-to-text uploads per user, or per IP for anonymous players, per minute. Each upload
+// is a paid transcription call, so the limit is much stricter than for JSON endpoints.
+const sttRateLimit = 5
+
+// setupSTTRoutes sets up the speech-to-text route. Its body size is capped by the global body limit's
+// "/api/stt" override, so oversized uploads are rejected with 413 before they are read.
+func (s *Server) setupSTTRoutes() {
+	s.router.POST("/api/stt",
+		s.auth.OptionalAuthMiddleware(),
+		ClientRateLimitMiddleware(sttRateLimit, time.Minute),
+		TimeoutMiddleware(s.config.GetLLMTimeout()),
+		audio.HandleSTT)
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSTTRouteLimits(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.router.Use(BodySizeLimitMiddleware(DefaultMaxBodyBytes, map[string]int64{"/api/stt": 1024}))
+	server.setupSTTRoutes()
+
+	upload := func(remoteAddr string, content []byte) int {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("audio", "argument.txt")
+		require.NoError(t, err)
+		_, err = part.Write(content)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/stt", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, upload("192.0.2.1:1234", bytes.Repeat([]byte("a"), 2048)))
+
+	// Uploads that aren't audio are rejected before reaching the transcription API, but still count
+	for i := 0; i < sttRateLimit; i++ {
+		assert.Equal(t, http.StatusUnsupportedMediaType, upload("192.0.2.2:1234", []byte("not audio")))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, upload("192.0.2.2:1234", []byte("not audio")))
+}
@@ -0,0 +1,242 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/neo/convinceme_backend/internal/logging"
+)
+
+// Bounds for topics accepted by importTopicsHandler
+const (
+	maxTopicImportRows        = 500
+	maxTopicImportBytes       = 1 << 20 // Room for maxTopicImportRows rows of typical length
+	maxTopicTitleLength       = 300
+	maxTopicDescriptionLength = 2000
+)
+
+// topicImportColumns are the CSV columns importTopicsHandler reads, by header name
+var topicImportColumns = []string{"title", "description", "agent1_name", "agent1_role", "agent2_name", "agent2_role", "category"}
+
+// Per-row outcomes of a topic import
+const (
+	topicImportCreated = "created"
+	topicImportSkipped = "skipped" // A topic with the same title already exists
+	topicImportInvalid = "invalid"
+)
+
+// topicImportResult is what happened to one row of a topic import. Row is the row's index in the request.
+type topicImportResult struct {
+	Row    int    `json:"row"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	ID     int    `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// importTopicsHandler adds topics to the catalog in bulk from a JSON array of topics or, with a text/csv
+// content type, a CSV file with a header row. Invalid rows and titles already in the catalog are reported
+// per row; the valid ones are inserted together.
+func (s *Server) importTopicsHandler(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxTopicImportBytes)
+
+	var topics []*database.Topic
+	var err error
+	if c.ContentType() == "text/csv" {
+		topics, err = parseTopicCSV(c.Request.Body)
+	} else {
+		err = json.NewDecoder(c.Request.Body).Decode(&topics)
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large", "max_bytes": tooLarge.Limit})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid topic import", "details": err.Error()})
+		return
+	}
+	if len(topics) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No topics to import"})
+		return
+	}
+	if len(topics) > maxTopicImportRows {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d topics can be imported at once", maxTopicImportRows)})
+		return
+	}
+
+	results := make([]topicImportResult, len(topics))
+	valid := make([]*database.Topic, 0, len(topics))
+	validRows := make([]int, 0, len(topics))
+	for i, topic := range topics {
+		results[i] = topicImportResult{Row: i}
+		if topic == nil {
+			results[i].Status = topicImportInvalid
+			results[i].Error = "topic is empty"
+			continue
+		}
+		if err := s.normalizeImportedTopic(topic); err != nil {
+			results[i].Title = topic.Title
+			results[i].Status = topicImportInvalid
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].Title = topic.Title
+		valid = append(valid, topic)
+		validRows = append(validRows, i)
+	}
+
+	skipped := []int{}
+	if len(valid) > 0 {
+		skipped, err = s.db.CreateTopics(valid)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import topics", "details": err.Error()})
+			return
+		}
+	}
+	isSkipped := make(map[int]bool, len(skipped))
+	for _, i := range skipped {
+		isSkipped[i] = true
+	}
+
+	counts := map[string]int{topicImportCreated: 0, topicImportSkipped: 0, topicImportInvalid: len(topics) - len(valid)}
+	for i, topic := range valid {
+		result := &results[validRows[i]]
+		if isSkipped[i] {
+			result.Status = topicImportSkipped
+			result.Error = "a topic with this title already exists"
+		} else {
+			result.Status = topicImportCreated
+			result.ID = topic.ID
+		}
+		counts[result.Status]++
+	}
+
+	userID, _ := auth.GetUserID(c)
+	logging.Info("Imported topics", map[string]interface{}{
+		"triggered_by": userID,
+		"created":      counts[topicImportCreated],
+		"skipped":      counts[topicImportSkipped],
+		"invalid":      counts[topicImportInvalid],
+	})
+	s.recordAudit(c, auditActionTopicsImport, "", gin.H{
+		"created": counts[topicImportCreated],
+		"skipped": counts[topicImportSkipped],
+		"invalid": counts[topicImportInvalid],
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": results,
+		"created": counts[topicImportCreated],
+		"skipped": counts[topicImportSkipped],
+		"invalid": counts[topicImportInvalid],
+	})
+}
+
+// normalizeImportedTopic trims an imported topic and checks it can be debated. An empty agent role falls back
+// to the role in the agent's config.
+func (s *Server) normalizeImportedTopic(topic *database.Topic) error {
+	topic.ID = 0
+	topic.Title = strings.TrimSpace(topic.Title)
+	topic.Description = strings.TrimSpace(topic.Description)
+	topic.Category = strings.TrimSpace(topic.Category)
+	topic.Agent1Name = strings.TrimSpace(topic.Agent1Name)
+	topic.Agent2Name = strings.TrimSpace(topic.Agent2Name)
+	topic.Agent1Role = strings.TrimSpace(topic.Agent1Role)
+	topic.Agent2Role = strings.TrimSpace(topic.Agent2Role)
+
+	if topic.Title == "" {
+		return errors.New("title is required")
+	}
+	if len(topic.Title) > maxTopicTitleLength {
+		return fmt.Errorf("title must be at most %d characters", maxTopicTitleLength)
+	}
+	if len(topic.Description) > maxTopicDescriptionLength {
+		return fmt.Errorf("description must be at most %d characters", maxTopicDescriptionLength)
+	}
+	if topic.Agent1Name == topic.Agent2Name {
+		return errors.New("agent1_name and agent2_name must be different agents")
+	}
+
+	for _, side := range []struct {
+		field string
+		name  string
+		role  *string
+	}{
+		{"agent1", topic.Agent1Name, &topic.Agent1Role},
+		{"agent2", topic.Agent2Name, &topic.Agent2Role},
+	} {
+		if side.name == "" {
+			return fmt.Errorf("%s_name is required", side.field)
+		}
+		a, exists := s.agents.Get(side.name)
+		if !exists {
+			return fmt.Errorf("%s_name: agent %s not found", side.field, side.name)
+		}
+		if *side.role == "" {
+			*side.role = a.GetRole()
+		}
+		if *side.role == "" {
+			return fmt.Errorf("%s_role is required", side.field)
+		}
+	}
+	return nil
+}
+
+// parseTopicCSV reads topics from CSV with a header row naming the topicImportColumns. Columns may come in any
+// order; unknown columns are ignored and missing ones are left empty.
+func parseTopicCSV(r io.Reader) ([]*database.Topic, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("CSV header must include the columns %s", strings.Join(topicImportColumns, ", "))
+	}
+
+	var topics []*database.Topic
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		topics = append(topics, &database.Topic{
+			Title:       field("title"),
+			Description: field("description"),
+			Agent1Name:  field("agent1_name"),
+			Agent1Role:  field("agent1_role"),
+			Agent2Name:  field("agent2_name"),
+			Agent2Role:  field("agent2_role"),
+			Category:    field("category"),
+		})
+	}
+	return topics, nil
+}
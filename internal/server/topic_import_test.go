@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/auth"
+	"github.com/neo/convinceme_backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportTopicsHandler(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.agents = NewAgentRegistry(map[string]*agent.Agent{
+		"Agent 1": agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 1", Role: "Cat person"}),
+		"Agent 2": agent.NewOfflineAgent(agent.AgentConfig{Name: "Agent 2"}),
+	})
+	server.router.POST("/api/admin/topics/import",
+		server.auth.AuthMiddleware(),
+		server.auth.RequireRole(string(database.RoleAdmin)),
+		server.importTopicsHandler)
+
+	adminToken, err := server.auth.GenerateToken(auth.User{ID: "admin-1", Username: "admin", Role: string(database.RoleAdmin)})
+	require.NoError(t, err)
+	userToken, err := server.auth.GenerateToken(auth.User{ID: "user-1", Username: "user", Role: string(database.RoleUser)})
+	require.NoError(t, err)
+
+	importTopics := func(contentType, body, token string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/topics/import", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	code, _ := importTopics("application/json", `[{"title":"Cats vs dogs","agent1_name":"Agent 1","agent2_name":"Agent 2","agent2_role":"Dog person"}]`, userToken)
+	assert.Equal(t, http.StatusForbidden, code)
+	code, _ = importTopics("application/json", `[]`, adminToken)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = importTopics("application/json", `{"title":"Not an array"}`, adminToken)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Oversized bodies are cut off while they're read, whatever their format
+	padding := strings.Repeat("x", int(maxTopicImportBytes))
+	code, response := importTopics("application/json", `[{"title":"`+padding+`"}]`, adminToken)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+	assert.Equal(t, float64(maxTopicImportBytes), response["max_bytes"])
+	code, _ = importTopics("text/csv", "title,description,agent1_name,agent1_role,agent2_name,agent2_role,category\n"+padding, adminToken)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+
+	code, response = importTopics("application/json", `[
+		{"title":" Cats vs dogs ","agent1_name":"Agent 1","agent2_name":"Agent 2","agent2_role":"Dog person"},
+		{"title":"Test Topic 1","agent1_name":"Agent 1","agent2_name":"Agent 2","agent2_role":"Dog person"},
+		{"title":"Tea vs coffee","agent1_name":"Agent 1","agent2_name":"Nobody","agent2_role":"Coffee person"},
+		{"title":"Summer vs winter","agent1_name":"Agent 1","agent2_name":"Agent 2"}
+	]`, adminToken)
+	require.Equal(t, http.StatusOK, code, response)
+	assert.Equal(t, float64(1), response["created"])
+	assert.Equal(t, float64(1), response["skipped"])
+	assert.Equal(t, float64(2), response["invalid"])
+
+	results := response["results"].([]interface{})
+	require.Len(t, results, 4)
+	created := results[0].(map[string]interface{})
+	assert.Equal(t, "created", created["status"])
+	assert.Equal(t, "Cats vs dogs", created["title"])
+	assert.NotZero(t, created["id"])
+	assert.Equal(t, "skipped", results[1].(map[string]interface{})["status"])
+	assert.Contains(t, results[2].(map[string]interface{})["error"], "agent Nobody not found")
+	assert.Contains(t, results[3].(map[string]interface{})["error"], "agent2_role is required", "Agent 2 has no configured role to fall back on")
+
+	// CSV columns can come in any order
+	code, response = importTopics("text/csv", "agent1_name,agent2_name,title,agent2_role\nAgent 1,Agent 2,\"Pineapple on pizza, yes or no\",Purist\n", adminToken)
+	require.Equal(t, http.StatusOK, code, response)
+	assert.Equal(t, float64(1), response["created"])
+	assert.Equal(t, "Pineapple on pizza, yes or no", response["results"].([]interface{})[0].(map[string]interface{})["title"])
+}
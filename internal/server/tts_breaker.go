@@ -0,0 +1,235 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/neo/convinceme_backend/internal/logging"
+	"github.com/neo/convinceme_backend/internal/types"
+)
+
+// States of the TTS circuit breaker
+const (
+	ttsBreakerClosed   = "closed"    // Audio is generated as usual
+	ttsBreakerOpen     = "open"      // Audio is skipped until the cooldown elapses
+	ttsBreakerHalfOpen = "half_open" // A single probe request is checking whether the provider is back
+)
+
+// audioUnavailableMessage is broadcast to running debates when the TTS breaker opens
+const audioUnavailableMessage = "Audio temporarily unavailable"
+
+// ttsBreaker stops calling the TTS provider while it is failing, so turns don't wait on requests bound to fail.
+// After threshold consecutive failures within window it opens and audio is skipped; once cooldown has passed,
+// one request is let through as a probe, and the breaker closes if it succeeds or opens again if it fails.
+// A probe that reports neither, e.g. because its turn was cancelled, is abandoned so another request can probe.
+type ttsBreaker struct {
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu           sync.Mutex
+	state        string
+	failures     int       // Consecutive failures since firstFailure
+	firstFailure time.Time // When the current run of failures began
+	openedAt     time.Time // When the breaker last opened
+	probeAt      time.Time // When the current probe was let through, while half-open
+	trips        int       // Times the breaker has opened from closed
+}
+
+// ttsBreakerStatus is the TTS breaker's state as reported by readyz
+type ttsBreakerStatus struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Trips               int        `json:"trips"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"` // When the next probe may be sent, while open
+}
+
+// newTTSBreaker creates a closed breaker. A nil *ttsBreaker never opens.
+func newTTSBreaker(threshold int, window, cooldown time.Duration) *ttsBreaker {
+	return &ttsBreaker{
+		threshold: threshold,
+		window:    window,
+		cooldown:  cooldown,
+		now:       time.Now,
+		state:     ttsBreakerClosed,
+	}
+}
+
+// allow reports whether audio should be generated now. Once the cooldown has elapsed, the first caller gets
+// to probe the provider and the rest keep skipping audio until the probe's outcome is recorded.
+func (b *ttsBreaker) allow() bool {
+	allowed, _ := b.acquire()
+	return allowed
+}
+
+// acquire is allow that also reports whether the caller is the probe, which must then record its outcome or
+// call abandonProbe. A probe that reports nothing within the cooldown is given up on, and the next caller probes.
+func (b *ttsBreaker) acquire() (allowed, probe bool) {
+	if b == nil {
+		return true, false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	switch b.state {
+	case ttsBreakerOpen:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return false, false
+		}
+		b.state = ttsBreakerHalfOpen
+		b.probeAt = now
+		return true, true
+	case ttsBreakerHalfOpen:
+		if now.Sub(b.probeAt) < b.cooldown {
+			return false, false
+		}
+		logging.Warn("TTS probe reported no outcome, probing again", map[string]interface{}{
+			"probe_started_at": b.probeAt,
+		})
+		b.probeAt = now
+		return true, true
+	}
+	return true, false
+}
+
+// abandonProbe reopens a half-open breaker whose probe ended without an outcome, e.g. because its turn was
+// cancelled or it panicked, so the next caller probes right away instead of audio staying off
+func (b *ttsBreaker) abandonProbe() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == ttsBreakerHalfOpen {
+		b.state = ttsBreakerOpen
+		b.openedAt = b.now().Add(-b.cooldown)
+	}
+}
+
+// recordSuccess closes the breaker and forgets past failures
+func (b *ttsBreaker) recordSuccess() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != ttsBreakerClosed {
+		logging.Info("TTS provider recovered, generating audio again")
+	}
+	b.state = ttsBreakerClosed
+	b.failures = 0
+}
+
+// recordFailure counts a failed TTS request and reports whether it tripped a closed breaker open.
+// A failed probe reopens the breaker for another cooldown without counting as a new trip.
+func (b *ttsBreaker) recordFailure() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	switch b.state {
+	case ttsBreakerHalfOpen:
+		b.state = ttsBreakerOpen
+		b.openedAt = now
+		return false
+	case ttsBreakerOpen:
+		return false
+	}
+
+	if b.failures == 0 || now.Sub(b.firstFailure) > b.window {
+		b.failures = 0
+		b.firstFailure = now
+	}
+	b.failures++
+	if b.failures < b.threshold {
+		return false
+	}
+	b.state = ttsBreakerOpen
+	b.openedAt = now
+	b.trips++
+	logging.Warn("TTS provider keeps failing, skipping audio", map[string]interface{}{
+		"consecutive_failures": b.failures,
+		"cooldown":             b.cooldown.String(),
+	})
+	return true
+}
+
+// status returns a snapshot of the breaker's state
+func (b *ttsBreaker) status() ttsBreakerStatus {
+	if b == nil {
+		return ttsBreakerStatus{State: ttsBreakerClosed}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := ttsBreakerStatus{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		Trips:               b.trips,
+	}
+	if b.state != ttsBreakerClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	if b.state == ttsBreakerOpen {
+		retryAt := b.openedAt.Add(b.cooldown)
+		status.RetryAt = &retryAt
+	}
+	return status
+}
+
+// announceAudioUnavailable tells every running debate that turns are text-only for now
+func (m *DebateManager) announceAudioUnavailable() {
+	m.debatesMutex.RLock()
+	sessions := make([]*conversation.DebateSession, 0, len(m.debates))
+	for _, session := range m.debates {
+		sessions = append(sessions, session)
+	}
+	m.debatesMutex.RUnlock()
+
+	for _, session := range sessions {
+		status := session.GetStatus()
+		if status != types.DebateStatusActive && status != types.DebateStatusPaused {
+			continue
+		}
+		session.Broadcast(gin.H{
+			"type":    "system",
+			"message": audioUnavailableMessage,
+		})
+	}
+}
+
+// readyzHandler reports whether the server is ready to serve debates, with the state of the TTS breaker and of
+// any LLM outage, and how many slow websocket clients were dropped. Audio being unavailable degrades debates to
+// text-only, and an LLM outage pauses them, rather than making the server unready.
+func (s *Server) readyzHandler(c *gin.Context) {
+	status := "ready"
+	response := gin.H{}
+	if s.debateManager != nil {
+		tts := s.debateManager.ttsBreaker.status()
+		if tts.State != ttsBreakerClosed {
+			status = "degraded"
+		}
+		response["tts"] = tts
+
+		llm := s.debateManager.llmOutage.status()
+		if llm.Degraded {
+			status = "degraded"
+		}
+		response["llm"] = llm
+	}
+	response["websocket"] = gin.H{"dropped_slow_clients": conversation.DroppedSlowClients()}
+	response["status"] = status
+	c.JSON(http.StatusOK, response)
+}
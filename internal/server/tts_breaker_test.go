@@ -0,0 +1,106 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTTSBreaker(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	breaker := newTTSBreaker(3, time.Minute, 30*time.Second)
+	breaker.now = func() time.Time { return now }
+
+	// Failures spread wider than the window don't open the breaker
+	assert.False(t, breaker.recordFailure())
+	assert.False(t, breaker.recordFailure())
+	now = now.Add(2 * time.Minute)
+	assert.False(t, breaker.recordFailure())
+	assert.True(t, breaker.allow())
+
+	// A success resets the count
+	breaker.recordSuccess()
+	assert.False(t, breaker.recordFailure())
+	assert.False(t, breaker.recordFailure())
+	assert.True(t, breaker.recordFailure(), "the third failure in a row opens the breaker")
+	assert.False(t, breaker.allow())
+	assert.Equal(t, ttsBreakerOpen, breaker.status().State)
+	assert.Equal(t, now.Add(30*time.Second), *breaker.status().RetryAt)
+
+	// After the cooldown only one probe goes through, and a failed probe reopens the breaker
+	now = now.Add(30 * time.Second)
+	assert.True(t, breaker.allow())
+	assert.False(t, breaker.allow())
+	assert.False(t, breaker.recordFailure(), "a failed probe isn't a new trip")
+	assert.False(t, breaker.allow())
+
+	now = now.Add(30 * time.Second)
+	assert.True(t, breaker.allow())
+	breaker.recordSuccess()
+	assert.True(t, breaker.allow())
+	status := breaker.status()
+	assert.Equal(t, ttsBreakerClosed, status.State)
+	assert.Equal(t, 1, status.Trips)
+	assert.Nil(t, status.OpenedAt)
+
+	// A probe that ends without an outcome lets the next caller probe at once
+	breaker.recordFailure()
+	breaker.recordFailure()
+	assert.True(t, breaker.recordFailure())
+	now = now.Add(30 * time.Second)
+	allowed, probe := breaker.acquire()
+	assert.True(t, allowed)
+	assert.True(t, probe)
+	breaker.abandonProbe()
+	allowed, probe = breaker.acquire()
+	assert.True(t, allowed)
+	assert.True(t, probe)
+
+	// A probe that never reports at all, e.g. one stuck in a request, is given up on after the cooldown
+	assert.False(t, breaker.allow())
+	now = now.Add(30 * time.Second)
+	assert.True(t, breaker.allow())
+	breaker.recordSuccess()
+	assert.Equal(t, ttsBreakerClosed, breaker.status().State)
+
+	// A debate manager built without a breaker always generates audio
+	var none *ttsBreaker
+	assert.True(t, none.allow())
+	assert.False(t, none.recordFailure())
+}
+
+func TestReadyzHandler(t *testing.T) {
+	server, tempDir := setupTestServer(t)
+	defer teardownTestServer(tempDir)
+	server.debateManager = &DebateManager{
+		debates:    map[string]*conversation.DebateSession{},
+		ttsBreaker: newTTSBreaker(1, time.Minute, time.Minute),
+	}
+	server.router.GET("/readyz", server.readyzHandler)
+
+	readyz := func() map[string]interface{} {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	response := readyz()
+	assert.Equal(t, "ready", response["status"])
+	assert.Equal(t, ttsBreakerClosed, response["tts"].(map[string]interface{})["state"])
+
+	assert.True(t, server.debateManager.ttsBreaker.recordFailure())
+	response = readyz()
+	assert.Equal(t, "degraded", response["status"])
+	tts := response["tts"].(map[string]interface{})
+	assert.Equal(t, ttsBreakerOpen, tts["state"])
+	assert.Contains(t, tts, "retry_at")
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/neo/convinceme_backend/internal/agent"
+	"github.com/neo/convinceme_backend/internal/conversation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTurnOrderSession(t *testing.T, turnOrder, firstSpeaker string) *conversation.DebateSession {
+	config := conversation.DefaultConfig()
+	config.TurnOrder = turnOrder
+	config.FirstSpeaker = firstSpeaker
+	session, err := conversation.NewDebateSession("turn-order", agent.NewOfflineAgent(agent.AgentConfig{Name: "Pepito"}),
+		agent.NewOfflineAgent(agent.AgentConfig{Name: "Tony"}), config, "")
+	require.NoError(t, err)
+	return session
+}
+
+// speakers takes n turns and returns who spoke
+func speakers(session *conversation.DebateSession, n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = conversation.NextSpeaker(session).GetName()
+	}
+	return names
+}
+
+// longestStreak returns the most turns in a row taken by one agent
+func longestStreak(names []string) int {
+	longest, streak := 0, 0
+	for i, name := range names {
+		if i > 0 && name == names[i-1] {
+			streak++
+		} else {
+			streak = 1
+		}
+		if streak > longest {
+			longest = streak
+		}
+	}
+	return longest
+}
+
+func TestNextSpeaker(t *testing.T) {
+	// Strict alternation is the default, and an unknown order falls back to it
+	for _, order := range []string{"", conversation.TurnOrderAlternate, "chaos"} {
+		assert.Equal(t, []string{"Tony", "Pepito", "Tony", "Pepito"}, speakers(newTurnOrderSession(t, order, conversation.FirstSpeakerAgent2), 4))
+	}
+
+	// The agent a player argued against responds, even if it just spoke, but never more than twice in a row
+	session := newTurnOrderSession(t, conversation.TurnOrderChallenged, "")
+	assert.Equal(t, []string{"Pepito", "Tony"}, speakers(session, 2))
+	session.RecordChallenge("Tony")
+	assert.Equal(t, "Tony", conversation.NextSpeaker(session).GetName())
+	session.RecordChallenge("Tony")
+	assert.Equal(t, "Pepito", conversation.NextSpeaker(session).GetName(), "Tony already took two turns in a row")
+	assert.Equal(t, "Tony", conversation.NextSpeaker(session).GetName(), "a challenge is answered by the next turn")
+	session.RecordChallenge("")
+	assert.Equal(t, "Pepito", conversation.NextSpeaker(session).GetName(), "neutral arguments challenge no one")
+
+	// A challenge before the opening turn doesn't take it from the first speaker
+	session = newTurnOrderSession(t, conversation.TurnOrderChallenged, conversation.FirstSpeakerAgent2)
+	session.RecordChallenge("Pepito")
+	assert.Equal(t, "Tony", conversation.NextSpeaker(session).GetName())
+
+	// Random turns give both agents the floor, with no agent taking more than two in a row
+	session = newTurnOrderSession(t, conversation.TurnOrderRandom, "")
+	names := speakers(session, 400)
+	assert.Equal(t, "Pepito", names[0])
+	assert.LessOrEqual(t, longestStreak(names), 2)
+	assert.Contains(t, names, "Tony")
+
+	// At an HP tie the comeback order alternates; otherwise the agent behind speaks more
+	session = newTurnOrderSession(t, conversation.TurnOrderComeback, "")
+	assert.Equal(t, []string{"Pepito", "Tony", "Pepito", "Tony"}, speakers(session, 4))
+	session.UpdateGameScore(-60, 0)
+	names = speakers(session, 4000)
+	pepito := 0
+	for _, name := range names {
+		if name == "Pepito" {
+			pepito++
+		}
+	}
+	assert.Greater(t, pepito, 2200, "Pepito is 60 HP behind")
+	assert.LessOrEqual(t, longestStreak(names), 2)
+}
+
+func TestNextSpeakerConcurrentWithScoring(t *testing.T) {
+	session := newTurnOrderSession(t, conversation.TurnOrderComeback, "")
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			speakers(session, 100)
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				session.UpdateGameScore(1, -1)
+				session.RecordChallenge("Tony")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestDebateSeed(t *testing.T) {
+	newSeeded := func(seed int64) *conversation.DebateSession {
+		config := conversation.DefaultConfig()
+		config.TurnOrder = conversation.TurnOrderRandom
+		config.FirstSpeaker = conversation.FirstSpeakerRandom
+		config.Seed = seed
+		session, err := conversation.NewDebateSession("seeded", agent.NewOfflineAgent(agent.AgentConfig{Name: "Pepito"}),
+			agent.NewOfflineAgent(agent.AgentConfig{Name: "Tony"}), config, "")
+		require.NoError(t, err)
+		return session
+	}
+
+	// The same seed replays the same coin flip and turn order
+	first, second := newSeeded(42), newSeeded(42)
+	assert.Equal(t, first.FirstSpeaker(), second.FirstSpeaker())
+	assert.Equal(t, speakers(first, 50), speakers(second, 50))
+
+	// Unseeded debates get a seed of their own, so they can be replayed too
+	unseeded := newSeeded(0)
+	assert.NotZero(t, unseeded.Config.Seed)
+	replay := newSeeded(unseeded.Config.Seed)
+	assert.Equal(t, unseeded.FirstSpeaker(), replay.FirstSpeaker())
+	assert.Equal(t, speakers(unseeded, 50), speakers(replay, 50))
+}
@@ -0,0 +1,49 @@
+package types
+
+// DebateStatus is where a debate is in its lifecycle
+type DebateStatus string
+
+const (
+	DebateStatusScheduled DebateStatus = "scheduled" // Created with a future start time; it can't be joined until then
+	DebateStatusWaiting   DebateStatus = "waiting"   // Open and waiting for its first client to start the loop
+	DebateStatusActive    DebateStatus = "active"    // The debate loop is running, or will be resumed
+	DebateStatusPaused    DebateStatus = "paused"    // Stopped mid-debate; it can be resumed
+	DebateStatusFinished  DebateStatus = "finished"  // Ended, for good
+)
+
+// debateStatusTransitions lists the statuses each status may move to
+var debateStatusTransitions = map[DebateStatus][]DebateStatus{
+	DebateStatusScheduled: {DebateStatusWaiting, DebateStatusActive, DebateStatusFinished},
+	DebateStatusWaiting:   {DebateStatusScheduled, DebateStatusActive, DebateStatusFinished},
+	DebateStatusActive:    {DebateStatusPaused, DebateStatusFinished},
+	DebateStatusPaused:    {DebateStatusActive, DebateStatusFinished},
+	DebateStatusFinished:  {},
+}
+
+// IsValid checks if the DebateStatus is a known status
+func (s DebateStatus) IsValid() bool {
+	_, known := debateStatusTransitions[s]
+	return known
+}
+
+// CanTransitionTo reports whether a debate in status s may move to next. Staying in the same status is allowed;
+// a finished debate never moves again.
+func (s DebateStatus) CanTransitionTo(next DebateStatus) bool {
+	if !s.IsValid() || !next.IsValid() {
+		return false
+	}
+	if s == next {
+		return true
+	}
+	for _, allowed := range debateStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// String converts the enum to string
+func (s DebateStatus) String() string {
+	return string(s)
+}
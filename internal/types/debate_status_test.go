@@ -0,0 +1,25 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebateStatusTransitions(t *testing.T) {
+	assert.True(t, DebateStatusPaused.IsValid())
+	assert.False(t, DebateStatus("archived").IsValid())
+	assert.False(t, DebateStatus("").IsValid())
+
+	assert.True(t, DebateStatusScheduled.CanTransitionTo(DebateStatusWaiting))
+	assert.True(t, DebateStatusWaiting.CanTransitionTo(DebateStatusActive))
+	assert.True(t, DebateStatusActive.CanTransitionTo(DebateStatusPaused))
+	assert.True(t, DebateStatusPaused.CanTransitionTo(DebateStatusActive))
+	assert.True(t, DebateStatusActive.CanTransitionTo(DebateStatusActive))
+	assert.True(t, DebateStatusWaiting.CanTransitionTo(DebateStatusFinished))
+
+	assert.False(t, DebateStatusFinished.CanTransitionTo(DebateStatusActive), "a finished debate never moves again")
+	assert.False(t, DebateStatusActive.CanTransitionTo(DebateStatusWaiting))
+	assert.False(t, DebateStatusWaiting.CanTransitionTo(DebateStatusPaused))
+	assert.False(t, DebateStatusActive.CanTransitionTo(DebateStatus("archived")))
+}
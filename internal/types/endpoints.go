@@ -0,0 +1,68 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Base URLs of the real APIs, used when no override is configured
+const (
+	DefaultOpenAIBaseURL     = "https://api.openai.com/v1"
+	DefaultElevenLabsBaseURL = "https://api.elevenlabs.io/v1"
+)
+
+// APIEndpoints are where the OpenAI and ElevenLabs clients send their requests, so they can go through an
+// LLM gateway, an OpenAI-compatible local model or a mock server. Empty fields use the real endpoints.
+type APIEndpoints struct {
+	OpenAIBaseURL      string // Including the version path, e.g. https://gateway.example.com/v1
+	OpenAIOrganization string // Sent as the OpenAI-Organization header when set
+	ElevenLabsBaseURL  string // Including the version path, e.g. https://gateway.example.com/elevenlabs/v1
+}
+
+// OpenAI returns the OpenAI base URL to use, without a trailing slash
+func (e APIEndpoints) OpenAI() string {
+	return baseURLOrDefault(e.OpenAIBaseURL, DefaultOpenAIBaseURL)
+}
+
+// ElevenLabs returns the ElevenLabs base URL to use, without a trailing slash
+func (e APIEndpoints) ElevenLabs() string {
+	return baseURLOrDefault(e.ElevenLabsBaseURL, DefaultElevenLabsBaseURL)
+}
+
+// Validate checks that the configured base URLs are absolute http or https URLs
+func (e APIEndpoints) Validate() error {
+	if err := validateBaseURL(e.OpenAIBaseURL); err != nil {
+		return fmt.Errorf("invalid OpenAI base URL: %v", err)
+	}
+	if err := validateBaseURL(e.ElevenLabsBaseURL); err != nil {
+		return fmt.Errorf("invalid ElevenLabs base URL: %v", err)
+	}
+	return nil
+}
+
+func baseURLOrDefault(baseURL, fallback string) string {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		return fallback
+	}
+	return baseURL
+}
+
+func validateBaseURL(baseURL string) error {
+	baseURL = strings.TrimSpace(baseURL)
+	if baseURL == "" {
+		return nil
+	}
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("%q must start with http:// or https://", baseURL)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("%q has no host", baseURL)
+	}
+	return nil
+}
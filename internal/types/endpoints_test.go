@@ -0,0 +1,28 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIEndpoints(t *testing.T) {
+	var defaults APIEndpoints
+	assert.Equal(t, DefaultOpenAIBaseURL, defaults.OpenAI())
+	assert.Equal(t, DefaultElevenLabsBaseURL, defaults.ElevenLabs())
+	assert.NoError(t, defaults.Validate())
+
+	custom := APIEndpoints{OpenAIBaseURL: "http://localhost:8000/v1/", ElevenLabsBaseURL: "https://gateway.example.com/elevenlabs/v1"}
+	assert.Equal(t, "http://localhost:8000/v1", custom.OpenAI())
+	assert.Equal(t, "https://gateway.example.com/elevenlabs/v1", custom.ElevenLabs())
+	assert.NoError(t, custom.Validate())
+
+	for _, invalid := range []APIEndpoints{
+		{OpenAIBaseURL: "localhost:8000/v1"},
+		{OpenAIBaseURL: "ftp://gateway.example.com"},
+		{ElevenLabsBaseURL: "https://"},
+		{ElevenLabsBaseURL: "http://bad host/v1"},
+	} {
+		assert.Error(t, invalid.Validate(), "%+v", invalid)
+	}
+}
@@ -19,6 +19,50 @@ const (
 	VoiceFinn  Voice = "finn"
 )
 
+// Language represents a supported ISO 639-1 language code
+type Language string
+
+const (
+	LanguageEnglish    Language = "en"
+	LanguageSpanish    Language = "es"
+	LanguagePortuguese Language = "pt"
+	LanguageFrench     Language = "fr"
+	LanguageGerman     Language = "de"
+	LanguageItalian    Language = "it"
+)
+
+// IsValid checks if the Language is supported
+func (l Language) IsValid() bool {
+	switch l {
+	case LanguageEnglish, LanguageSpanish, LanguagePortuguese,
+		LanguageFrench, LanguageGerman, LanguageItalian:
+		return true
+	}
+	return false
+}
+
+// String converts the enum to string
+func (l Language) String() string {
+	return string(l)
+}
+
+// Name returns the English name of the language, for use in prompts
+func (l Language) Name() string {
+	switch l {
+	case LanguageSpanish:
+		return "Spanish"
+	case LanguagePortuguese:
+		return "Portuguese"
+	case LanguageFrench:
+		return "French"
+	case LanguageGerman:
+		return "German"
+	case LanguageItalian:
+		return "Italian"
+	}
+	return "English"
+}
+
 // IsValid checks if the ResponseStyle is valid
 func (s ResponseStyle) IsValid() bool {
 	switch s {
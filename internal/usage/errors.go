@@ -0,0 +1,32 @@
+package usage
+
+import "strings"
+
+// accountErrorMarkers are fragments of LLM provider errors that mean the account can't be used at all
+var accountErrorMarkers = []string{
+	"status code: 401",   // Missing or invalid API key
+	"status code: 402",   // Payment required
+	"status code: 403",   // Key revoked or not allowed to use the model
+	"insufficient_quota", // Error code for an exhausted quota
+	"exceeded your current quota",
+	"billing",
+	"invalid_api_key",
+	"incorrect api key",
+}
+
+// IsQuotaOrAuthError reports whether err, from an agent or scorer LLM call, means the provider account can't
+// serve requests: its quota or billing is exhausted or its API key is rejected. Unlike rate limits and timeouts
+// these persist until someone intervenes, so retrying every turn is pointless. The LLM client only reports
+// errors as text, so they're classified by message.
+func IsQuotaOrAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	for _, marker := range accountErrorMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+// Package usage tracks LLM and TTS consumption so its cost can be attributed to debates
+package usage
+
+import (
+	"context"
+	"unicode/utf8"
+)
+
+// Usage counts the LLM and TTS work done on behalf of a debate
+type Usage struct {
+	LLMCalls         int64 `json:"llm_calls"`
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+	TTSCharacters    int64 `json:"tts_characters"`
+}
+
+// Add accumulates other into u
+func (u *Usage) Add(other Usage) {
+	u.LLMCalls += other.LLMCalls
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TTSCharacters += other.TTSCharacters
+}
+
+// charsPerToken is OpenAI's rule of thumb for English text
+const charsPerToken = 4
+
+// EstimateTokens approximates the token count of text.
+// The LLM client doesn't report token usage, so all token counts are estimates.
+func EstimateTokens(text string) int64 {
+	chars := int64(utf8.RuneCountInString(text))
+	return (chars + charsPerToken - 1) / charsPerToken
+}
+
+// LLMCall returns the usage of a single completion
+func LLMCall(prompt, completion string) Usage {
+	return Usage{
+		LLMCalls:         1,
+		PromptTokens:     EstimateTokens(prompt),
+		CompletionTokens: EstimateTokens(completion),
+	}
+}
+
+// TTS returns the usage of synthesizing text to speech, which is billed per character
+func TTS(text string) Usage {
+	return Usage{TTSCharacters: int64(utf8.RuneCountInString(text))}
+}
+
+// Recorder accumulates usage, e.g. a debate session
+type Recorder interface {
+	AddUsage(u Usage)
+}
+
+// recorderKey is the context key for the Recorder bound with WithRecorder
+type recorderKey struct{}
+
+// WithRecorder returns a copy of ctx that Record reports usage to
+func WithRecorder(ctx context.Context, r Recorder) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// Record reports usage to the Recorder bound to ctx, if any
+func Record(ctx context.Context, u Usage) {
+	if ctx == nil {
+		return
+	}
+	if r, ok := ctx.Value(recorderKey{}).(Recorder); ok && r != nil {
+		r.AddUsage(u)
+	}
+}
+
+// Rates are the prices used to estimate the cost of usage, in USD
+type Rates struct {
+	PromptPerMillionTokens     float64 `json:"prompt_per_million_tokens"`
+	CompletionPerMillionTokens float64 `json:"completion_per_million_tokens"`
+	TTSPerThousandCharacters   float64 `json:"tts_per_thousand_characters"`
+}
+
+// DefaultModelRates are OpenAI list prices by model at the time of writing. Usage isn't counted per model, so
+// all of it is priced at one model's rates.
+var DefaultModelRates = map[string]Rates{
+	"gpt-4o-mini":  {PromptPerMillionTokens: 0.15, CompletionPerMillionTokens: 0.60},
+	"gpt-4o":       {PromptPerMillionTokens: 2.50, CompletionPerMillionTokens: 10.00},
+	"gpt-4.1":      {PromptPerMillionTokens: 2.00, CompletionPerMillionTokens: 8.00},
+	"gpt-4.1-mini": {PromptPerMillionTokens: 0.40, CompletionPerMillionTokens: 1.60},
+	"gpt-4.1-nano": {PromptPerMillionTokens: 0.10, CompletionPerMillionTokens: 0.40},
+}
+
+// DefaultTTSPerThousandCharacters is the ElevenLabs list price at the time of writing
+const DefaultTTSPerThousandCharacters = 0.30
+
+// DefaultRates returns the list prices for usage of a model, reporting false if there are none for it
+func DefaultRates(model string) (Rates, bool) {
+	rates, exists := DefaultModelRates[model]
+	rates.TTSPerThousandCharacters = DefaultTTSPerThousandCharacters
+	return rates, exists
+}
+
+// Cost is the estimated cost of some usage, in USD
+type Cost struct {
+	LLM   float64 `json:"llm"`
+	TTS   float64 `json:"tts"`
+	Total float64 `json:"total"`
+}
+
+// Cost estimates the cost of u at these rates
+func (r Rates) Cost(u Usage) Cost {
+	llm := float64(u.PromptTokens)*r.PromptPerMillionTokens/1e6 +
+		float64(u.CompletionTokens)*r.CompletionPerMillionTokens/1e6
+	tts := float64(u.TTSCharacters) * r.TTSPerThousandCharacters / 1e3
+	return Cost{LLM: llm, TTS: tts, Total: llm + tts}
+}
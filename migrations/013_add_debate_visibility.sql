@@ -0,0 +1,20 @@
+-- Add visibility and invite-only access to debates
+
+-- Visibility controls listing and joining: 'public', 'unlisted' or 'private'
+ALTER TABLE debates ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public';
+ALTER TABLE debates ADD COLUMN created_by TEXT NULL; -- User ID of the debate creator
+
+CREATE INDEX IF NOT EXISTS idx_debates_visibility ON debates(visibility);
+
+-- Allowlist of users invited to private debates
+CREATE TABLE IF NOT EXISTS debate_participants (
+    debate_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    invited_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (debate_id, user_id),
+    FOREIGN KEY (debate_id) REFERENCES debates(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_debate_participants_user ON debate_participants(user_id);
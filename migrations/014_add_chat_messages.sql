@@ -0,0 +1,13 @@
+-- Add per-debate chat, kept separate from scored arguments
+
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    debate_id TEXT NOT NULL,
+    player_id TEXT NOT NULL,  -- Connection player ID
+    username TEXT NOT NULL,   -- Display name at the time of sending
+    message TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (debate_id) REFERENCES debates(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_debate ON chat_messages(debate_id, created_at);
@@ -0,0 +1,3 @@
+-- Track how long each debate was actually running, excluding paused periods
+
+ALTER TABLE debates ADD COLUMN active_seconds INTEGER NULL;
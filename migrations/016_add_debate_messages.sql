@@ -0,0 +1,15 @@
+-- Persist the full debate transcript (agent turns and player arguments) for replays
+
+CREATE TABLE IF NOT EXISTS debate_messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    debate_id TEXT NOT NULL,
+    speaker TEXT NOT NULL,             -- Agent name or player display name
+    message TEXT NOT NULL,
+    is_player BOOLEAN NOT NULL DEFAULT 0,
+    score REAL,                        -- Average argument score, NULL if unscored
+    audio_url TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (debate_id) REFERENCES debates(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_debate_messages_debate ON debate_messages(debate_id, created_at);
@@ -0,0 +1,4 @@
+-- Add a reputation score used to weight users' votes
+
+ALTER TABLE users ADD COLUMN reputation REAL NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN reputation_updated_at TIMESTAMP;
@@ -0,0 +1,6 @@
+-- Allow debates to be announced ahead of their start time
+
+ALTER TABLE debates ADD COLUMN scheduled_at TIMESTAMP;
+
+-- The scheduler looks up due debates by status and start time
+CREATE INDEX IF NOT EXISTS idx_debates_status_scheduled_at ON debates(status, scheduled_at);
@@ -0,0 +1,3 @@
+-- Record why a debate ended (knockout, timeout, inactivity, max_turns or judge)
+
+ALTER TABLE debates ADD COLUMN end_reason TEXT;
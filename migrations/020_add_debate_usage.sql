@@ -0,0 +1,11 @@
+-- Persist each debate's LLM and TTS usage when it ends, for cost reporting. Token counts are estimates.
+
+CREATE TABLE IF NOT EXISTS debate_usage (
+    debate_id TEXT PRIMARY KEY,
+    llm_calls INTEGER NOT NULL DEFAULT 0,
+    prompt_tokens INTEGER NOT NULL DEFAULT 0,
+    completion_tokens INTEGER NOT NULL DEFAULT 0,
+    tts_characters INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (debate_id) REFERENCES debates(id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+-- Record which agent opened a debate (agent1 or agent2) and whether a coin flip decided it
+
+ALTER TABLE debates ADD COLUMN first_speaker TEXT;
+ALTER TABLE debates ADD COLUMN first_speaker_coin_flip BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+-- Let admins feature debates on the homepage
+
+ALTER TABLE debates ADD COLUMN featured BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE debates ADD COLUMN featured_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_debates_featured ON debates(featured, featured_at);
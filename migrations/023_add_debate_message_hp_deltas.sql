@@ -0,0 +1,4 @@
+-- Record how much HP each transcript entry moved, so a debate's HP can be reconstructed from its transcript
+
+ALTER TABLE debate_messages ADD COLUMN agent1_delta INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE debate_messages ADD COLUMN agent2_delta INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,17 @@
+-- Notify users about their debates, e.g. when one they argued in ends.
+-- Arguments record the authenticated user who made them, so participants can be found; anonymous arguments leave it NULL.
+
+ALTER TABLE arguments ADD COLUMN user_id TEXT;
+CREATE INDEX IF NOT EXISTS idx_arguments_debate_user ON arguments(debate_id, user_id);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL,
+    type TEXT NOT NULL,
+    payload TEXT NOT NULL DEFAULT '{}', -- JSON details for the notification type
+    read_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id, read_at, created_at);
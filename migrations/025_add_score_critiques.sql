@@ -0,0 +1,4 @@
+-- Cache the detailed critique of an argument's score, generated on request since each one is a paid LLM call.
+-- It is cleared whenever the argument is re-scored.
+
+ALTER TABLE scores ADD COLUMN critique TEXT; -- JSON scoring.ScoreCritique, NULL until requested
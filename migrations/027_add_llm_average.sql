@@ -0,0 +1,7 @@
+-- Keep each argument's LLM score apart from its vote-blended average, so the blend can be recomputed when its
+-- settings change.
+
+ALTER TABLE scores ADD COLUMN llm_average REAL;
+
+-- Until now the average was always the LLM score plus the argument's vote_score
+UPDATE scores SET llm_average = average - COALESCE((SELECT vote_score FROM arguments WHERE arguments.id = scores.argument_id), 0);
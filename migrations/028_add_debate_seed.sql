@@ -0,0 +1,4 @@
+-- The seed of each debate's random choices (coin flip, random turn orders), so a debate can be reproduced.
+-- NULL for debates created before it was recorded.
+
+ALTER TABLE debates ADD COLUMN seed INTEGER;
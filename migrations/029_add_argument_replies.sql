@@ -0,0 +1,5 @@
+-- Let a player argument reply to an earlier argument or agent turn of the same debate, so clients can render
+-- threads. At most one of the two is set.
+
+ALTER TABLE arguments ADD COLUMN parent_id INTEGER REFERENCES arguments(id);
+ALTER TABLE arguments ADD COLUMN parent_message_id INTEGER REFERENCES debate_messages(id);
@@ -0,0 +1,7 @@
+-- Debates forked from another debate's transcript record where they branched off, for "what if" replays.
+-- NULL for debates that weren't forked.
+
+ALTER TABLE debates ADD COLUMN parent_debate_id TEXT;
+ALTER TABLE debates ADD COLUMN forked_from_turn INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_debates_parent ON debates(parent_debate_id);
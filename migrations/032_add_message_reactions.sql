@@ -0,0 +1,11 @@
+-- Count spectators' emoji reactions to transcript entries. Reactions are never scored and don't touch HP.
+
+CREATE TABLE IF NOT EXISTS message_reactions (
+    debate_id TEXT NOT NULL,
+    message_id INTEGER NOT NULL,
+    emoji TEXT NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (debate_id, message_id, emoji),
+    FOREIGN KEY (debate_id) REFERENCES debates(id) ON DELETE CASCADE,
+    FOREIGN KEY (message_id) REFERENCES debate_messages(id) ON DELETE CASCADE
+);
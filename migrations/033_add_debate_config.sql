@@ -0,0 +1,6 @@
+-- Each debate's validated config as JSON, and the deadline its loop must finish by once it has started, so a
+-- debate restored after a restart keeps its settings and time limit.
+-- NULL for debates created before they were recorded, which are restored with the default settings.
+
+ALTER TABLE debates ADD COLUMN config TEXT;
+ALTER TABLE debates ADD COLUMN deadline TIMESTAMP;
@@ -0,0 +1,10 @@
+-- The score blend argument averages were last computed with, so a restart with the same blend doesn't rewrite
+-- every score. It holds at most one row.
+
+CREATE TABLE IF NOT EXISTS score_blend (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    formula TEXT NOT NULL,
+    vote_factor REAL NOT NULL,
+    max_vote_score REAL NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);